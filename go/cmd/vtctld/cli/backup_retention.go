@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	backupRetentionCheckInterval time.Duration
+	backupRetentionDryRun        bool
+)
+
+func init() {
+	Main.Flags().DurationVar(&backupRetentionCheckInterval, "backup-retention-check-interval", backupRetentionCheckInterval, "How often the backup retention policies of the keyspaces are applied. Disabled if zero or lower.")
+	Main.Flags().BoolVar(&backupRetentionDryRun, "backup-retention-dry-run", backupRetentionDryRun, "Only log the backups that the backup retention policies would remove, do not remove them.")
+}
+
+func initBackupRetention(ctx context.Context) {
+	if backupRetentionCheckInterval <= 0 {
+		return
+	}
+
+	timer := timer.NewTimer(backupRetentionCheckInterval)
+	timer.Start(func() {
+		vtctld := grpcvtctldserver.NewVtctldServer(env, ts)
+		keyspaces, err := ts.GetKeyspaces(ctx)
+		if err != nil {
+			log.Errorf("Backup retention failed to get keyspaces, error: %v", err)
+			return
+		}
+		for _, keyspace := range keyspaces {
+			ki, err := ts.GetKeyspace(ctx, keyspace)
+			if err != nil {
+				log.Errorf("Backup retention failed to get keyspace %v, error: %v", keyspace, err)
+				continue
+			}
+			if ki.BackupRetentionPolicy == nil {
+				continue
+			}
+			resp, err := vtctld.ApplyBackupRetentionPolicy(ctx, &vtctldatapb.ApplyBackupRetentionPolicyRequest{
+				Keyspace: keyspace,
+				DryRun:   backupRetentionDryRun,
+			})
+			if err != nil {
+				log.Errorf("Backup retention failed for keyspace %v, error: %v", keyspace, err)
+				continue
+			}
+			for _, bi := range resp.RemovedBackups {
+				if backupRetentionDryRun {
+					log.Infof("Backup retention would remove backup %v/%v", bi.Directory, bi.Name)
+				} else {
+					log.Infof("Backup retention removed backup %v/%v", bi.Directory, bi.Name)
+				}
+			}
+		}
+	})
+	servenv.OnClose(func() { timer.Stop() })
+}
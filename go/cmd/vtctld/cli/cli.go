@@ -78,8 +78,9 @@ func run(cmd *cobra.Command, args []string) error {
 	// Register http debug/health
 	vtctld.RegisterDebugHealthHandler(ts)
 
-	// Start schema manager service.
+	// Start schema manager and backup retention services.
 	initSchema(cmd.Context())
+	initBackupRetention(cmd.Context())
 
 	// And run the server.
 	servenv.RunDefault()
@@ -33,6 +33,18 @@ import (
 )
 
 var (
+	// ApplyBackupRetentionPolicy makes an ApplyBackupRetentionPolicy gRPC call to a vtctld.
+	ApplyBackupRetentionPolicy = &cobra.Command{
+		Use:   "ApplyBackupRetentionPolicy [--dry-run] <keyspace>",
+		Short: "Removes the backups of every shard of the given keyspace that are not kept by its backup retention policy.",
+		Long: `Removes the backups of every shard of the given keyspace that are not kept by its backup retention policy.
+
+The backup retention policy of a keyspace is set with SetKeyspaceBackupRetentionPolicy. A full backup that a kept incremental backup depends on is never removed.
+With --dry-run, the backups that would be removed are listed but not removed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandApplyBackupRetentionPolicy,
+	}
 	// Backup makes a Backup gRPC call to a vtctld.
 	Backup = &cobra.Command{
 		Use:                   "Backup [--concurrency <concurrency>] [--allow-primary] [--incremental-from-pos=<pos>|<backup-name>|auto] [--upgrade-safe] [--backup-engine=enginename] <tablet_alias>",
@@ -76,8 +88,45 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// ValidateBackup makes a ValidateBackup gRPC call to a vtctld.
+	ValidateBackup = &cobra.Command{
+		Use:   "ValidateBackup [--concurrency <concurrency>] <keyspace/shard> <backup name>",
+		Short: "Restores every file of the given backup into a scratch directory of vtctld, and checks them against the hashes recorded in the backup's MANIFEST.",
+		Long: `Restores every file of the given backup into a scratch directory of vtctld, and checks them against the hashes recorded in the backup's MANIFEST.
+
+The files are deleted as soon as they are checked. Only backups of the builtin backup engine can be validated.
+The command fails if any file of the backup is not valid.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandValidateBackup,
+	}
 )
 
+var applyBackupRetentionPolicyOptions = struct {
+	DryRun bool
+}{}
+
+func commandApplyBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ApplyBackupRetentionPolicy(commandCtx, &vtctldatapb.ApplyBackupRetentionPolicyRequest{
+		Keyspace: keyspace,
+		DryRun:   applyBackupRetentionPolicyOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var backupOptions = struct {
 	AllowPrimary       bool
 	BackupEngine       string
@@ -287,7 +336,46 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var validateBackupOptions = struct {
+	Concurrency uint32
+}{}
+
+func commandValidateBackup(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	name := cmd.Flags().Arg(1)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ValidateBackup(commandCtx, &vtctldatapb.ValidateBackupRequest{
+		Keyspace:    keyspace,
+		Shard:       shard,
+		Name:        name,
+		Concurrency: validateBackupOptions.Concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	if !resp.Valid {
+		return fmt.Errorf("backup %v/%v is not valid", cmd.Flags().Arg(0), name)
+	}
+	return nil
+}
+
 func init() {
+	ApplyBackupRetentionPolicy.Flags().BoolVar(&applyBackupRetentionPolicyOptions.DryRun, "dry-run", false, "Only list the backups that would be removed, do not remove them.")
+	Root.AddCommand(ApplyBackupRetentionPolicy)
+
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
 	Backup.Flags().StringVar(&backupOptions.IncrementalFromPos, "incremental-from-pos", "", "Position, or name of backup from which to create an incremental backup. Default: empty. If given, then this backup becomes an incremental backup from given position or given backup. If value is 'auto', this backup will be taken from the last successful backup position.")
@@ -314,4 +402,7 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	ValidateBackup.Flags().Uint32Var(&validateBackupOptions.Concurrency, "concurrency", 4, "Specifies the number of files to restore simultaneously.")
+	Root.AddCommand(ValidateBackup)
 }
@@ -96,6 +96,20 @@ Otherwise, the keyspace must be empty (have no shards), or returns an error.`,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandRemoveKeyspaceCell,
 	}
+	// SetKeyspaceBackupRetentionPolicy makes a SetKeyspaceBackupRetentionPolicy gRPC call to a vtctld.
+	SetKeyspaceBackupRetentionPolicy = &cobra.Command{
+		Use:   "SetKeyspaceBackupRetentionPolicy [--keep-full=<count>] [--keep-weekly=<count>] [--point-in-time-recovery-window=<duration>] [--clear] <keyspace name>",
+		Short: "Sets the backup retention policy of the specified keyspace.",
+		Long: `Sets the backup retention policy of the specified keyspace.
+The policy is applied to the backups of every shard of the keyspace by ApplyBackupRetentionPolicy, or periodically by vtctld when --backup-retention-check-interval is set.
+The most recent full backup is always kept, as well as every backup that a kept incremental backup depends on.
+
+To keep the last 3 full backups of the customer keyspace, one full backup per week for 4 weeks, and enough backups to restore to any point in time of the last 2 days, you would use the following command:
+SetKeyspaceBackupRetentionPolicy --keep-full=3 --keep-weekly=4 --point-in-time-recovery-window=48h customer`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetKeyspaceBackupRetentionPolicy,
+	}
 	// SetKeyspaceDurabilityPolicy makes a SetKeyspaceDurabilityPolicy gRPC call to a vtcltd.
 	SetKeyspaceDurabilityPolicy = &cobra.Command{
 		Use:   "SetKeyspaceDurabilityPolicy [--durability-policy=policy_name] <keyspace name>",
@@ -317,6 +331,48 @@ func commandRemoveKeyspaceCell(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var setKeyspaceBackupRetentionPolicyOptions = struct {
+	KeepFull                  uint32
+	KeepWeekly                uint32
+	PointInTimeRecoveryWindow time.Duration
+	Clear                     bool
+}{}
+
+func commandSetKeyspaceBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+
+	var retentionPolicy *topodatapb.BackupRetentionPolicy
+	if !setKeyspaceBackupRetentionPolicyOptions.Clear {
+		retentionPolicy = &topodatapb.BackupRetentionPolicy{
+			KeepFull:   setKeyspaceBackupRetentionPolicyOptions.KeepFull,
+			KeepWeekly: setKeyspaceBackupRetentionPolicyOptions.KeepWeekly,
+		}
+		if setKeyspaceBackupRetentionPolicyOptions.PointInTimeRecoveryWindow != 0 {
+			retentionPolicy.PointInTimeRecoveryWindow = protoutil.DurationToProto(setKeyspaceBackupRetentionPolicyOptions.PointInTimeRecoveryWindow)
+		}
+	} else if cmd.Flags().Changed("keep-full") || cmd.Flags().Changed("keep-weekly") || cmd.Flags().Changed("point-in-time-recovery-window") {
+		return errors.New("--clear cannot be used with the other flags")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SetKeyspaceBackupRetentionPolicy(commandCtx, &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+		Keyspace:              keyspace,
+		BackupRetentionPolicy: retentionPolicy,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var setKeyspaceDurabilityPolicyOptions = struct {
 	DurabilityPolicy string
 }{}
@@ -385,6 +441,12 @@ func init() {
 	RemoveKeyspaceCell.Flags().BoolVarP(&removeKeyspaceCellOptions.Recursive, "recursive", "r", false, "Also delete all tablets in that cell beloning to the specified keyspace.")
 	Root.AddCommand(RemoveKeyspaceCell)
 
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepFull, "keep-full", 1, "Number of most recent full backups to keep. The most recent full backup is always kept.")
+	SetKeyspaceBackupRetentionPolicy.Flags().Uint32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepWeekly, "keep-weekly", 0, "Number of weeks for which the most recent full backup of the week is kept.")
+	SetKeyspaceBackupRetentionPolicy.Flags().DurationVar(&setKeyspaceBackupRetentionPolicyOptions.PointInTimeRecoveryWindow, "point-in-time-recovery-window", 0, "Keep the full and incremental backups needed to restore to any point in time of this window.")
+	SetKeyspaceBackupRetentionPolicy.Flags().BoolVar(&setKeyspaceBackupRetentionPolicyOptions.Clear, "clear", false, "Remove the backup retention policy of the keyspace. Its backups are then never removed by vtctld.")
+	Root.AddCommand(SetKeyspaceBackupRetentionPolicy)
+
 	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.DurabilityPolicy, "durability-policy", policy.DurabilityNone, "Type of durability to enforce for this keyspace. Default is none. Other values include 'semi_sync' and others as dictated by registered plugins.")
 	Root.AddCommand(SetKeyspaceDurabilityPolicy)
 
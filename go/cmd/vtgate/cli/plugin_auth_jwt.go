@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+// This plugin imports jwtauthserver to register the JWT implementation of AuthServer.
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/mysql/jwtauthserver"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtgate"
+)
+
+var jwtAuthConfig = jwtauthserver.Config{
+	UsernameClaim: "sub",
+	GroupsClaim:   "groups",
+	Leeway:        time.Minute,
+}
+
+func init() {
+	Main.Flags().StringVar(&jwtAuthConfig.JWKSFile, "mysql_auth_jwt_jwks_file", jwtAuthConfig.JWKSFile, "Path to the JWKS file with the keys used to verify JWTs sent as mysql_clear_password. The file is reloaded when it changes.")
+	Main.Flags().StringVar(&jwtAuthConfig.Issuer, "mysql_auth_jwt_issuer", jwtAuthConfig.Issuer, "If set, the iss claim of the JWT must match this value.")
+	Main.Flags().StringVar(&jwtAuthConfig.Audience, "mysql_auth_jwt_audience", jwtAuthConfig.Audience, "If set, the aud claim of the JWT must contain this value.")
+	Main.Flags().StringVar(&jwtAuthConfig.UsernameClaim, "mysql_auth_jwt_username_claim", jwtAuthConfig.UsernameClaim, "JWT claim holding the Vitess username; it must match the MySQL user. Nested claims are separated by dots.")
+	Main.Flags().StringVar(&jwtAuthConfig.GroupsClaim, "mysql_auth_jwt_groups_claim", jwtAuthConfig.GroupsClaim, "JWT claim holding the groups of the user. Nested claims are separated by dots.")
+	Main.Flags().DurationVar(&jwtAuthConfig.Leeway, "mysql_auth_jwt_leeway", jwtAuthConfig.Leeway, "Allowed clock skew when validating the exp and nbf claims of the JWT.")
+
+	vtgate.RegisterPluginInitializer(func() {
+		// The JWKS file is watched until the auth server shuts down with vtgate.
+		ctx, cancel := context.WithCancel(context.Background())
+		servenv.OnClose(cancel)
+		jwtauthserver.Init(ctx, jwtAuthConfig)
+	})
+}
@@ -18,6 +18,7 @@ package logstats
 
 import (
 	"io"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
@@ -188,6 +189,20 @@ func (log *Logger) Strings(strs []string) {
 	log.b = append(log.b, ']')
 }
 
+// StringMap writes the map as a JSON object, sorted by key.
+func (log *Logger) StringMap(m map[string]string) {
+	log.b = append(log.b, '{')
+	for i, k := range slices.Sorted(maps.Keys(m)) {
+		if i > 0 {
+			log.b = append(log.b, ',')
+		}
+		log.b = strconv.AppendQuote(log.b, k)
+		log.b = append(log.b, ':')
+		log.b = strconv.AppendQuote(log.b, m[k])
+	}
+	log.b = append(log.b, '}')
+}
+
 func (log *Logger) Flush(w io.Writer) (err error) {
 	if log.json {
 		log.b = append(log.b, '}')
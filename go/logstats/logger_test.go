@@ -230,6 +230,20 @@ func (*mockWriter) Write(p []byte) (int, error) {
 	return 1, nil
 }
 
+func TestStringMap(t *testing.T) {
+	tl := Logger{}
+	tl.Init(false)
+
+	tl.StringMap(map[string]string{"key2": "testValue2", "key1": "testValue1"})
+	assert.Equal(t, []byte("{\"key1\":\"testValue1\",\"key2\":\"testValue2\"}"), tl.b)
+
+	tl.b = []byte{}
+	tl.Init(false)
+
+	tl.StringMap(nil)
+	assert.Equal(t, []byte("{}"), tl.b)
+}
+
 func TestFlush(t *testing.T) {
 	tl := NewLogger()
 	tl.Init(true)
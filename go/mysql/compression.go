@@ -0,0 +1,359 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file contains the implementation of the compressed client/server
+// protocol, which is used after the handshake when the client and the
+// server agreed on CLIENT_COMPRESS or CLIENT_ZSTD_COMPRESSION_ALGORITHM.
+// See: https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html
+//
+// Each compressed packet is made of a 7 bytes header followed by the
+// payload, which contains regular packets (including their own headers):
+//	3         length of the compressed payload
+//	1         compressed sequence id
+//	3         length of the payload before compression, or 0 if
+//	          the payload is sent uncompressed
+
+// CompressionAlgorithm is an algorithm of the compressed client/server protocol.
+type CompressionAlgorithm string
+
+const (
+	// CompressionNone means the connection is not compressed.
+	CompressionNone CompressionAlgorithm = ""
+
+	// CompressionZlib is the classic compressed protocol, negotiated with
+	// CLIENT_COMPRESS.
+	CompressionZlib CompressionAlgorithm = "zlib"
+
+	// CompressionZstd is the compressed protocol of MySQL 8.0.18+,
+	// negotiated with CLIENT_ZSTD_COMPRESSION_ALGORITHM.
+	CompressionZstd CompressionAlgorithm = "zstd"
+)
+
+const (
+	// DefaultZlibCompressionLevel is the compression level used by MySQL for zlib.
+	DefaultZlibCompressionLevel = 6
+
+	// DefaultZstdCompressionLevel is the default value of --zstd-compression-level in MySQL.
+	DefaultZstdCompressionLevel = 3
+
+	// compressedHeaderSize is the size of the header of a compressed packet.
+	compressedHeaderSize = 7
+
+	// minCompressLength is MIN_COMPRESS_LENGTH: payloads smaller than
+	// this are always sent uncompressed.
+	minCompressLength = 50
+)
+
+var (
+	// zstdDecoder is a concurrent stateless decoder shared by all connections.
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+
+	// zstdEncoders caches one concurrent stateless encoder per zstd.EncoderLevel.
+	zstdEncoders sync.Map
+)
+
+// ParseCompressionAlgorithm parses the name of a compression algorithm.
+// Both "" and "uncompressed" mean no compression.
+func ParseCompressionAlgorithm(name string) (CompressionAlgorithm, error) {
+	switch name {
+	case "", "uncompressed":
+		return CompressionNone, nil
+	case string(CompressionZlib):
+		return CompressionZlib, nil
+	case string(CompressionZstd):
+		return CompressionZstd, nil
+	}
+	return CompressionNone, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown compression algorithm: %s", name)
+}
+
+// CompressionLevel returns the compression level to use for the algorithm:
+// the given level if it is valid for the algorithm, or the default level
+// of the algorithm if the given level is 0.
+func CompressionLevel(algorithm CompressionAlgorithm, level int) (int, error) {
+	switch algorithm {
+	case CompressionZlib:
+		if level == 0 {
+			return DefaultZlibCompressionLevel, nil
+		}
+		if level >= zlib.BestSpeed && level <= zlib.BestCompression {
+			return level, nil
+		}
+	case CompressionZstd:
+		if level == 0 {
+			return DefaultZstdCompressionLevel, nil
+		}
+		if level >= 1 && level <= 22 {
+			return level, nil
+		}
+	default:
+		return 0, nil
+	}
+	return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "compression level %d is not valid for %s", level, algorithm)
+}
+
+// compressor implements the compressed protocol for a Conn. The regular
+// packet reading and writing code goes through it: it is the io.Reader
+// returned by getReader, and the io.Writer under the bufferedWriter.
+type compressor struct {
+	algorithm CompressionAlgorithm
+	level     int
+
+	// sequence is the compressed sequence id. It is independent from
+	// the sequence of the packets inside the compressed packets, and
+	// is reset at the beginning of each command, with Conn.sequence.
+	sequence uint8
+
+	// src is where the compressed packets are read from.
+	src io.Reader
+	// payload holds the compressed payload of the last packet read.
+	payload []byte
+	// uncompressed holds the decompressed payload of the last packet read.
+	uncompressed []byte
+	// data is the payload of the last packet read, either payload or
+	// uncompressed, and pos is how much of it has already been read.
+	data []byte
+	pos  int
+	// zlibReader is reused for all the zlib payloads we read.
+	zlibReader io.ReadCloser
+
+	// dst is where the compressed packets are written to.
+	dst io.Writer
+	// out holds the compressed packet being written.
+	out bytes.Buffer
+	// zlibWriter is reused for all the zlib payloads we write.
+	zlibWriter *zlib.Writer
+}
+
+func newCompressor(algorithm CompressionAlgorithm, level int, src io.Reader, dst io.Writer) (*compressor, error) {
+	level, err := CompressionLevel(algorithm, level)
+	if err != nil {
+		return nil, err
+	}
+	cp := &compressor{
+		algorithm: algorithm,
+		level:     level,
+		src:       src,
+		dst:       dst,
+	}
+	switch algorithm {
+	case CompressionZlib:
+		cp.zlibWriter, err = zlib.NewWriterLevel(&cp.out, level)
+	case CompressionZstd:
+		_, err = zstdDecoder()
+	default:
+		err = vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unknown compression algorithm: %s", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Read implements io.Reader. It reads the uncompressed payloads of the
+// compressed packets, one after the other.
+func (cp *compressor) Read(p []byte) (int, error) {
+	for cp.pos == len(cp.data) {
+		if err := cp.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, cp.data[cp.pos:])
+	cp.pos += n
+	return n, nil
+}
+
+func (cp *compressor) readCompressedPacket() error {
+	var header [compressedHeaderSize]byte
+	if _, err := io.ReadFull(cp.src, header[:]); err != nil {
+		// The error is returned as is, so the callers can
+		// recognize io.EOF and closed connections.
+		return err
+	}
+
+	sequence := header[3]
+	if sequence != cp.sequence {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid compressed sequence, expected %v got %v", cp.sequence, sequence)
+	}
+	cp.sequence++
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+
+	cp.payload = growBuffer(cp.payload, length)
+	if _, err := io.ReadFull(cp.src, cp.payload); err != nil {
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", length)
+	}
+	cp.pos = 0
+
+	// The payload was small enough or not compressible, and was sent as is.
+	if uncompressedLength == 0 {
+		cp.data = cp.payload
+		return nil
+	}
+
+	var err error
+	switch cp.algorithm {
+	case CompressionZlib:
+		cp.uncompressed = growBuffer(cp.uncompressed, uncompressedLength)
+		err = cp.inflate()
+	case CompressionZstd:
+		var decoder *zstd.Decoder
+		if decoder, err = zstdDecoder(); err == nil {
+			cp.uncompressed, err = decoder.DecodeAll(cp.payload, cp.uncompressed[:0])
+		}
+		if err == nil && len(cp.uncompressed) != uncompressedLength {
+			err = vterrors.Errorf(vtrpcpb.Code_INTERNAL, "zstd payload of length %v, expected %v", len(cp.uncompressed), uncompressedLength)
+		}
+	}
+	if err != nil {
+		cp.data = nil
+		return vterrors.Wrapf(err, "cannot decompress %s packet", cp.algorithm)
+	}
+	cp.data = cp.uncompressed
+	return nil
+}
+
+func (cp *compressor) inflate() error {
+	payload := bytes.NewReader(cp.payload)
+	if cp.zlibReader == nil {
+		r, err := zlib.NewReader(payload)
+		if err != nil {
+			return err
+		}
+		cp.zlibReader = r
+	} else if err := cp.zlibReader.(zlib.Resetter).Reset(payload, nil); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(cp.zlibReader, cp.uncompressed)
+	return err
+}
+
+// Write implements io.Writer. The data is sent in as many compressed
+// packets as needed.
+func (cp *compressor) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > MaxPacketSize {
+			chunk = chunk[:MaxPacketSize]
+		}
+		if err := cp.writeCompressedPacket(chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+func (cp *compressor) writeCompressedPacket(data []byte) error {
+	// Reserve room for the header, it is filled in below.
+	var header [compressedHeaderSize]byte
+	cp.out.Reset()
+	cp.out.Write(header[:])
+
+	uncompressedLength := 0
+	if len(data) >= minCompressLength {
+		if err := cp.compress(data); err != nil {
+			return vterrors.Wrapf(err, "cannot compress %s packet", cp.algorithm)
+		}
+		uncompressedLength = len(data)
+	}
+	// Send the data as is when it is too small or when compression
+	// doesn't make it smaller.
+	if uncompressedLength == 0 || cp.out.Len()-compressedHeaderSize >= len(data) {
+		cp.out.Truncate(compressedHeaderSize)
+		cp.out.Write(data)
+		uncompressedLength = 0
+	}
+
+	packet := cp.out.Bytes()
+	length := len(packet) - compressedHeaderSize
+	packet[0] = byte(length)
+	packet[1] = byte(length >> 8)
+	packet[2] = byte(length >> 16)
+	packet[3] = cp.sequence
+	packet[4] = byte(uncompressedLength)
+	packet[5] = byte(uncompressedLength >> 8)
+	packet[6] = byte(uncompressedLength >> 16)
+
+	if n, err := cp.dst.Write(packet); err != nil {
+		return err
+	} else if n != len(packet) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(packet))
+	}
+	cp.sequence++
+	return nil
+}
+
+func (cp *compressor) compress(data []byte) error {
+	switch cp.algorithm {
+	case CompressionZlib:
+		cp.zlibWriter.Reset(&cp.out)
+		if _, err := cp.zlibWriter.Write(data); err != nil {
+			return err
+		}
+		return cp.zlibWriter.Close()
+	case CompressionZstd:
+		encoder, err := zstdEncoder(cp.level)
+		if err != nil {
+			return err
+		}
+		out := encoder.EncodeAll(data, cp.out.AvailableBuffer())
+		cp.out.Write(out)
+		return nil
+	}
+	return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unknown compression algorithm: %s", cp.algorithm)
+}
+
+// zstdEncoder returns the shared encoder for the given zstd level.
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	encoderLevel := zstd.EncoderLevelFromZstd(level)
+	if encoder, ok := zstdEncoders.Load(encoderLevel); ok {
+		return encoder.(*zstd.Encoder), nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel))
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := zstdEncoders.LoadOrStore(encoderLevel, encoder)
+	return actual.(*zstd.Encoder), nil
+}
+
+// growBuffer returns a slice of the given length, reusing buf if it is large enough.
+func growBuffer(buf []byte, length int) []byte {
+	if cap(buf) < length {
+		return make([]byte, length)
+	}
+	return buf[:length]
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+)
+
+func TestCompressionLevel(t *testing.T) {
+	testCases := []struct {
+		algorithm CompressionAlgorithm
+		level     int
+		want      int
+		wantErr   string
+	}{
+		{algorithm: CompressionNone, level: 0, want: 0},
+		{algorithm: CompressionZlib, level: 0, want: DefaultZlibCompressionLevel},
+		{algorithm: CompressionZlib, level: 9, want: 9},
+		{algorithm: CompressionZlib, level: 10, wantErr: "compression level 10 is not valid for zlib"},
+		{algorithm: CompressionZstd, level: 0, want: DefaultZstdCompressionLevel},
+		{algorithm: CompressionZstd, level: 22, want: 22},
+		{algorithm: CompressionZstd, level: -1, wantErr: "compression level -1 is not valid for zstd"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s-%d", tc.algorithm, tc.level), func(t *testing.T) {
+			level, err := CompressionLevel(tc.algorithm, tc.level)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, level)
+		})
+	}
+
+	_, err := ParseCompressionAlgorithm("lz4")
+	assert.ErrorContains(t, err, "unknown compression algorithm: lz4")
+}
+
+func TestCompressor(t *testing.T) {
+	random := make([]byte, 100000)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	payloads := [][]byte{
+		[]byte("short"),
+		bytes.Repeat([]byte("compressible "), 1000),
+		random,
+		bytes.Repeat([]byte{0}, 3*MaxPacketSize/2),
+	}
+
+	for _, algorithm := range []CompressionAlgorithm{CompressionZlib, CompressionZstd} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			var wire bytes.Buffer
+			writer, err := newCompressor(algorithm, 0, nil, &wire)
+			require.NoError(t, err)
+			reader, err := newCompressor(algorithm, 0, &wire, nil)
+			require.NoError(t, err)
+
+			for _, payload := range payloads {
+				n, err := writer.Write(payload)
+				require.NoError(t, err)
+				require.Equal(t, len(payload), n)
+
+				got := make([]byte, len(payload))
+				_, err = io.ReadFull(reader, got)
+				require.NoError(t, err)
+				require.True(t, bytes.Equal(payload, got), "payload of length %d was corrupted", len(payload))
+			}
+			require.Zero(t, wire.Len())
+			assert.EqualValues(t, 5, writer.sequence)
+			assert.EqualValues(t, 5, reader.sequence)
+
+			// Short payloads are sent uncompressed.
+			writer.sequence = 0
+			_, err = writer.Write([]byte("short"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte{5, 0, 0, 0, 0, 0, 0, 's', 'h', 'o', 'r', 't'}, wire.Bytes())
+
+			// The compressed sequence is checked.
+			_, err = reader.Read(make([]byte, 5))
+			assert.ErrorContains(t, err, "invalid compressed sequence, expected 5 got 0")
+		})
+	}
+}
+
+func TestCompressedServer(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	th := &testHandler{}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	defer authServer.close()
+
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	l.CompressionAlgorithms = []CompressionAlgorithm{CompressionZlib, CompressionZstd}
+	l.ZlibCompressionLevel = 1
+	host, port := getHostPort(t, l.Addr())
+	params := &ConnParams{
+		Host:   host,
+		Port:   port,
+		Uname:  "user1",
+		Pass:   "password1",
+		DbName: "db1",
+	}
+	go l.Accept()
+	defer cleanupListener(ctx, l, params)
+
+	testCases := []struct {
+		algorithm CompressionAlgorithm
+		level     int
+		wantLevel int
+	}{
+		{algorithm: CompressionNone},
+		{algorithm: CompressionZlib, level: 9, wantLevel: 1},
+		{algorithm: CompressionZstd, wantLevel: DefaultZstdCompressionLevel},
+		{algorithm: CompressionZstd, level: 19, wantLevel: 19},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s-%d", tc.algorithm, tc.level), func(t *testing.T) {
+			params := *params
+			params.Compression = tc.algorithm
+			params.CompressionLevel = tc.level
+
+			c, err := Connect(ctx, &params)
+			require.NoError(t, err)
+			defer c.Close()
+
+			result, err := c.ExecuteFetch("schema echo", 10, true)
+			require.NoError(t, err)
+			assert.Equal(t, "db1", result.Rows[0][0].ToString())
+
+			sConn := th.LastConn()
+			if tc.algorithm == CompressionNone {
+				assert.Nil(t, c.compression)
+				assert.Nil(t, sConn.compression)
+			} else {
+				require.NotNil(t, c.compression)
+				require.NotNil(t, sConn.compression)
+				assert.Equal(t, tc.algorithm, c.compression.algorithm)
+				assert.Equal(t, tc.algorithm, sConn.compression.algorithm)
+				assert.Equal(t, tc.wantLevel, sConn.compression.level)
+			}
+
+			result, err = c.ExecuteFetch("select rows", 10, true)
+			require.NoError(t, err)
+			assert.Equal(t, selectRowsResult.Rows, result.Rows)
+
+			// Large enough to span several compressed packets and
+			// several packets, both ways.
+			query := benchmarkQueryPrefix + strings.Repeat("large query ", 2*MaxPacketSize/12)
+			result, err = c.ExecuteFetch(query, 10, true)
+			require.NoError(t, err)
+			assert.Equal(t, query, result.Rows[0][0].ToString())
+
+			require.NoError(t, c.Ping())
+		})
+	}
+
+	t.Run("not advertised", func(t *testing.T) {
+		l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+		require.NoError(t, err)
+		l.CompressionAlgorithms = []CompressionAlgorithm{CompressionZlib}
+		params := *params
+		params.Host, params.Port = getHostPort(t, l.Addr())
+		params.Compression = CompressionZstd
+		go l.Accept()
+		defer cleanupListener(ctx, l, &params)
+
+		c, err := Connect(ctx, &params)
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, err = c.ExecuteFetch("select rows", 10, true)
+		require.NoError(t, err)
+		assert.Nil(t, c.compression)
+		assert.Nil(t, th.LastConn().compression)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		params := *params
+		params.Compression = CompressionZstd
+		params.CompressionLevel = 23
+		_, err := Connect(ctx, &params)
+		assert.ErrorContains(t, err, "compression level 23 is not valid for zstd")
+	})
+}
@@ -194,6 +194,14 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compression implements the compressed protocol. It is nil
+	// unless compression was negotiated during the handshake.
+	compression *compressor
+
+	// zstdCompressionLevel is the zstd compression level the client
+	// asked for in its handshake. It is only used by the server.
+	zstdCompressionLevel int
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -207,6 +215,15 @@ type Conn struct {
 	// This is currently used for testing.
 	keepAliveOn bool
 
+	// QueryAttributes are the query attributes the client sent with the
+	// COM_QUERY or COM_STMT_EXECUTE being executed, keyed by name. It is
+	// only used by the server, and is nil if the client sent none.
+	QueryAttributes map[string]string
+
+	// cursor is the cursor opened by the last COM_STMT_EXECUTE, if
+	// its rows were not all fetched yet. It is only used by the server.
+	cursor *cursor
+
 	// mu protects the fields below
 	mu sync.Mutex
 	// cancel keep the cancel function for the current executing query.
@@ -227,6 +244,10 @@ type PrepareData struct {
 	BindVars    map[string]*querypb.BindVariable
 	StatementID uint32
 	ParamsCount uint16
+	// CursorType is the cursor type the statement is executed with.
+	// When a cursor is requested, the client fetches the rows in
+	// batches, so handlers should stream the results.
+	CursorType byte
 }
 
 // execResult is an enum signifying the result of executing a query
@@ -323,7 +344,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -371,14 +392,46 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, or the compressor
+// reading from them if compression is enabled.
 func (c *Conn) getReader() io.Reader {
+	if c.compression != nil {
+		return c.compression
+	}
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the unbuffered writer for connection. It is the net.Conn,
+// or the compressor writing to it if compression is enabled.
+func (c *Conn) getWriter() io.Writer {
+	if c.compression != nil {
+		return c.compression
+	}
+	return c.conn
+}
+
+// enableCompression switches the connection to the compressed protocol.
+// Both sides do it right after the OK packet that ends the handshake.
+func (c *Conn) enableCompression(algorithm CompressionAlgorithm, level int) error {
+	compression, err := newCompressor(algorithm, level, c.getReader(), c.conn)
+	if err != nil {
+		return err
+	}
+	c.compression = compression
+	return nil
+}
+
+// resetSequence resets the sequence numbers at the beginning of a new command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	if c.compression != nil {
+		c.compression.sequence = 0
+	}
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
@@ -401,7 +454,13 @@ func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 
 	sequence := c.header[3]
 	if sequence != c.sequence {
-		return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence, expected %v got %v", c.sequence, sequence)
+		// With the compressed protocol, MySQL re-synchronizes the packet
+		// sequence with the compressed sequence whenever it flushes its
+		// buffer, so only the compressed sequence can be checked.
+		if c.compression == nil {
+			return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence, expected %v got %v", c.sequence, sequence)
+		}
+		c.sequence = sequence
 	}
 
 	c.sequence++
@@ -611,7 +670,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -715,7 +774,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -888,7 +947,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -905,6 +964,17 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		return false
 	}
 
+	// The handler of an open cursor may use the connection state, so the
+	// cursor is closed before any command that calls the handler, and the
+	// other commands pause it. The query attributes only apply to the
+	// command that sent them.
+	switch data[0] {
+	case ComStmtFetch, ComStmtClose, ComStmtReset, ComStmtSendLongData, ComPing:
+	default:
+		c.closeCursor()
+		c.QueryAttributes = nil
+	}
+
 	switch data[0] {
 	case ComQuit:
 		c.recycleReadPacket()
@@ -930,13 +1000,22 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		stmtID, ok := c.parseComStmtClose(data)
 		c.recycleReadPacket()
 		if ok {
+			if c.cursor != nil && c.cursor.stmtID == stmtID {
+				c.closeCursor()
+			}
+			resume := c.pauseCursor()
 			delete(c.PrepareData, stmtID)
+			resume()
 		}
+	case ComStmtFetch:
+		return c.handleComStmtFetch(handler, data)
 	case ComStmtReset:
 		return c.handleComStmtReset(data)
 	case ComResetConnection:
 		c.handleComResetConnection(handler)
 		return true
+	case ComChangeUser:
+		return c.handleComChangeUser(handler, data)
 	case ComFieldList:
 		c.recycleReadPacket()
 		if !c.writeErrorAndLog(sqlerror.ERUnknownComError, sqlerror.SSNetError, "command handling not implemented yet: %v", data[0]) {
@@ -1037,6 +1116,96 @@ func (c *Conn) handleComResetConnection(handler Handler) {
 	}
 }
 
+func (c *Conn) handleComChangeUser(handler Handler, data []byte) bool {
+	user, authMethod, authResponse, schemaName, characterSet, err := c.parseComChangeUser(data)
+	c.recycleReadPacket()
+	if err != nil {
+		log.Errorf("Cannot parse COM_CHANGE_USER from %s: %v", c, err)
+		c.writeErrorAndLog(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "error parsing COM_CHANGE_USER: %v", err)
+		return false
+	}
+
+	// As for the handshake, the error was sent to the client if the
+	// authentication failed, and the connection is closed.
+	userData, ok := c.listener.authenticate(c, user, authMethod, authResponse, c.salt)
+	if !ok {
+		return false
+	}
+
+	prevUser, prevUserData, prevCharacterSet, prevSchemaName, prevPrepareData := c.User, c.UserData, c.CharacterSet, c.schemaName, c.PrepareData
+
+	// The new user gets a clean connection.
+	done := c.changeUserState(handler)
+	c.PrepareData = make(map[uint32]*PrepareData)
+	c.setUser(user, userData)
+	if characterSet != 0 {
+		c.CharacterSet = characterSet
+	}
+
+	c.schemaName = schemaName
+	if err := c.useSchema(handler); err != nil {
+		// As MySQL does, the connection stays authenticated as the previous user.
+		c.setUser(prevUser, prevUserData)
+		c.CharacterSet = prevCharacterSet
+		c.schemaName = prevSchemaName
+		c.PrepareData = prevPrepareData
+		done(false)
+		return c.writeErrorPacketFromError(err) == nil
+	}
+	done(true)
+
+	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
+		log.Errorf("Error writing ComChangeUser result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+// changeUserState gives the connection a clean state for the new user of a
+// COM_CHANGE_USER request, and returns the function to call once the change is
+// done. Without a ChangeUserHandler, the previous state can't be restored when
+// the change fails, so the previous user gets a clean connection to its schema.
+func (c *Conn) changeUserState(handler Handler) func(success bool) {
+	if h, ok := handler.(ChangeUserHandler); ok {
+		return h.ComChangeUser(c)
+	}
+	handler.ComResetConnection(c)
+	return func(success bool) {
+		if success {
+			return
+		}
+		handler.ComResetConnection(c)
+		c.PrepareData = make(map[uint32]*PrepareData)
+		if err := c.useSchema(handler); err != nil {
+			log.Warningf("Cannot use the previous schema %v after a failed COM_CHANGE_USER from %s: %v", c.schemaName, c, err)
+			c.schemaName = ""
+		}
+	}
+}
+
+// setUser switches the connection to another authenticated user.
+func (c *Conn) setUser(user string, userData Getter) {
+	if c.User != "" {
+		connCountPerUser.Add(c.User, -1)
+	}
+	c.User = user
+	c.UserData = userData
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+	}
+}
+
+// useSchema makes the handler use the default schema of the connection, if
+// any.
+func (c *Conn) useSchema(handler Handler) error {
+	if c.schemaName == "" {
+		return nil
+	}
+	return handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
+		return nil
+	})
+}
+
 func (c *Conn) handleComStmtReset(data []byte) bool {
 	stmtID, ok := c.parseComStmtReset(data)
 	c.recycleReadPacket()
@@ -1047,6 +1216,11 @@ func (c *Conn) handleComStmtReset(data []byte) bool {
 		}
 	}
 
+	if c.cursor != nil && c.cursor.stmtID == stmtID {
+		c.closeCursor()
+	}
+	defer c.pauseCursor()()
+
 	prepare, ok := c.PrepareData[stmtID]
 	if !ok {
 		log.Error("Commands were executed in an improper order from client %v, packet: %v", c.ConnectionID, data)
@@ -1071,6 +1245,7 @@ func (c *Conn) handleComStmtReset(data []byte) bool {
 func (c *Conn) handleComStmtSendLongData(data []byte) bool {
 	stmtID, paramID, chunk, ok := c.parseComStmtSendLongData(data)
 	c.recycleReadPacket()
+	defer c.pauseCursor()()
 	if !ok {
 		err := fmt.Errorf("error parsing statement send long data from client %v, returning error: %v", c.ConnectionID, data)
 		return c.writeErrorPacketFromErrorAndLog(err)
@@ -1107,7 +1282,7 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		}
 	}()
 	queryStart := time.Now()
-	stmtID, _, err := c.parseComStmtExecute(c.PrepareData, data)
+	stmtID, cursorType, err := c.parseComStmtExecute(c.PrepareData, data)
 	c.recycleReadPacket()
 
 	if stmtID != uint32(0) {
@@ -1122,10 +1297,14 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		return c.writeErrorPacketFromErrorAndLog(err)
 	}
 
+	prepare := c.PrepareData[stmtID]
+	if cursorType&CursorTypeReadOnly != 0 {
+		return c.executeWithCursor(handler, prepare, cursorType, queryStart)
+	}
+
 	fieldSent := false
 	// sendFinished is set if the response should just be an OK packet.
 	sendFinished := false
-	prepare := c.PrepareData[stmtID]
 	err = handler.ComStmtExecute(c, prepare, func(qr *sqltypes.Result) error {
 		if sendFinished {
 			// Failsafe: Unreachable if server is well-behaved.
@@ -1298,6 +1477,7 @@ func (c *Conn) handleComSetOption(data []byte) bool {
 
 func (c *Conn) handleComPing() bool {
 	c.recycleReadPacket()
+	defer c.pauseCursor()()
 	// Return error if listener was shut down and OK otherwise
 	if c.listener.shutdown.Load() {
 		if !c.writeErrorAndLog(sqlerror.ERServerShutdown, sqlerror.SSNetError, "Server shutdown in progress") {
@@ -1324,11 +1504,13 @@ func (c *Conn) handleComQuery(handler Handler, data []byte) (kontinue bool) {
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
 
 	var queries []string
-	var err error
 	if c.Capabilities&CapabilityClientMultiStatements != 0 {
 		queries, err = handler.Env().Parser().SplitStatementToPieces(query)
 		if err != nil {
@@ -64,6 +64,15 @@ type ConnParams struct {
 	// FlushDelay is the delay after which buffered response will be flushed to the client.
 	FlushDelay time.Duration
 
+	// Compression is the algorithm of the compressed protocol to use once
+	// connected. The connection is not compressed if it is not set, or if
+	// the server doesn't support it.
+	Compression CompressionAlgorithm
+
+	// CompressionLevel is the compression level to use with Compression.
+	// If it is not set, the default level of the algorithm is used.
+	CompressionLevel int
+
 	TruncateErrLen int
 }
 
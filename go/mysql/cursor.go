@@ -0,0 +1,290 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// errCursorClosed is returned to the handler when the cursor it streams
+// results to was closed before all the rows were fetched.
+var errCursorClosed = errors.New("cursor closed")
+
+// cursor is a read-only cursor opened by a COM_STMT_EXECUTE request.
+//
+// The handler executes the statement in its own goroutine, and each call
+// to its callback blocks until the client fetched all the rows of the
+// result, with COM_STMT_FETCH requests. So the results are never buffered,
+// and the handler can't reuse a result while its rows are sent.
+//
+// A connection has at most one open cursor. As the handler may use the
+// connection state while it runs, the cursor is closed before any other
+// command that calls the handler. The few commands that leave it open are
+// serialized with the handler by running.
+type cursor struct {
+	stmtID uint32
+	fields []*querypb.Field
+
+	// results receives the results from the callback, which then waits
+	// on consumed before it returns.
+	results  chan *sqltypes.Result
+	consumed chan struct{}
+	// quit is closed to make the callback fail, and stop the handler.
+	quit chan struct{}
+	// done is closed when the handler returns, err is its result.
+	done chan struct{}
+	err  error
+
+	// running is held by the handler while it runs, and released while it
+	// waits in its callback.
+	running sync.Mutex
+
+	// pending are the rows of the current result not sent yet.
+	pending [][]sqltypes.Value
+	// waiting is set when the callback waits for the current result
+	// to be consumed.
+	waiting bool
+}
+
+func newCursor(stmtID uint32) *cursor {
+	return &cursor{
+		stmtID:   stmtID,
+		results:  make(chan *sqltypes.Result),
+		consumed: make(chan struct{}),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// callback is the callback of the handler executing the statement.
+func (cur *cursor) callback(qr *sqltypes.Result) error {
+	cur.running.Unlock()
+	defer cur.running.Lock()
+
+	select {
+	case cur.results <- qr:
+	case <-cur.quit:
+		return errCursorClosed
+	}
+	select {
+	case <-cur.consumed:
+		return nil
+	case <-cur.quit:
+		return errCursorClosed
+	}
+}
+
+// next releases the current result, and waits for the next one.
+// It returns nil when the handler returned, with its error in cur.err.
+func (cur *cursor) next() *sqltypes.Result {
+	if cur.waiting {
+		cur.waiting = false
+		cur.consumed <- struct{}{}
+	}
+	select {
+	case qr := <-cur.results:
+		cur.waiting = true
+		cur.pending = qr.Rows
+		return qr
+	case <-cur.done:
+		cur.pending = nil
+		return nil
+	}
+}
+
+// closeCursor closes the open cursor, if any. It stops the handler, and
+// waits for it to return.
+func (c *Conn) closeCursor() {
+	cur := c.cursor
+	if cur == nil {
+		return
+	}
+	c.cursor = nil
+	close(cur.quit)
+	c.CancelCtx()
+	<-cur.done
+}
+
+// pauseCursor waits until the handler of the open cursor, if any, waits in
+// its callback, and keeps it there until the returned function is called.
+// The commands that leave the cursor open use it, so they never run
+// concurrently with the handler.
+func (c *Conn) pauseCursor() (resume func()) {
+	cur := c.cursor
+	if cur == nil {
+		return func() {}
+	}
+	cur.running.Lock()
+	return cur.running.Unlock
+}
+
+// executeWithCursor executes a prepared statement the client wants to
+// fetch the rows of with a cursor. If the statement returns rows, only
+// the fields are sent, and the cursor stays open for COM_STMT_FETCH.
+func (c *Conn) executeWithCursor(handler Handler, prepare *PrepareData, cursorType byte, queryStart time.Time) bool {
+	// The handler runs after the bind variables of prepare are reset
+	// for the next execution, so it gets its own copy.
+	p := *prepare
+	p.CursorType = cursorType
+
+	cur := newCursor(p.StatementID)
+	go func() {
+		defer close(cur.done)
+		cur.running.Lock()
+		defer cur.running.Unlock()
+		cur.err = handler.ComStmtExecute(c, &p, cur.callback)
+	}()
+
+	qr := cur.next()
+	if qr == nil {
+		// This is just a failsafe. Should never happen.
+		err := cur.err
+		if err == nil {
+			err = sqlerror.NewSQLErrorFromError(errors.New("unexpected: query ended without no results and no error"))
+		}
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	if len(qr.Fields) == 0 {
+		// No rows, so no cursor. Let the handler finish first, so the
+		// OK packet has the final status flags.
+		affectedRows, insertID, sessionStateChanges := qr.RowsAffected, qr.InsertID, qr.SessionStateChanges
+		for cur.next() != nil {
+		}
+		if cur.err != nil {
+			return c.writeErrorPacketFromErrorAndLog(cur.err)
+		}
+		if err := c.writeOKPacket(&PacketOK{
+			affectedRows:     affectedRows,
+			lastInsertID:     insertID,
+			statusFlags:      c.StatusFlags,
+			sessionStateData: sessionStateChanges,
+		}); err != nil {
+			log.Errorf("Error writing result to %s: %v", c, err)
+			return false
+		}
+		timings.Record(queryTimingKey, queryStart)
+		return true
+	}
+
+	cur.fields = qr.Fields
+	c.cursor = cur
+	if err := c.writeFieldsWithCursor(qr); err != nil {
+		log.Errorf("Error writing fields to %s: %v", c, err)
+		return false
+	}
+	timings.Record(queryTimingKey, queryStart)
+	return true
+}
+
+func (c *Conn) handleComStmtFetch(handler Handler, data []byte) (kontinue bool) {
+	c.startWriterBuffering()
+	defer func() {
+		if err := c.endWriterBuffering(); err != nil {
+			log.Errorf("conn %v: flush() failed: %v", c.ID(), err)
+			kontinue = false
+		}
+	}()
+
+	stmtID, numRows, ok := c.parseComStmtFetch(data)
+	c.recycleReadPacket()
+	if !ok {
+		return c.writeErrorAndLog(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "error parsing COM_STMT_FETCH")
+	}
+
+	cur := c.cursor
+	if cur == nil || cur.stmtID != stmtID {
+		return c.writeErrorAndLog(sqlerror.ERStmtHasNoOpenCursor, sqlerror.SSUnknownSQLState, "The statement (%d) has no open cursor.", stmtID)
+	}
+
+	for sent := 0; sent < int(numRows); {
+		if len(cur.pending) == 0 {
+			if cur.next() == nil {
+				// All the rows were fetched.
+				c.cursor = nil
+				if cur.err != nil {
+					return c.writeErrorPacketFromErrorAndLog(cur.err)
+				}
+				if err := c.writeFetchEnd(ServerStatusLastRowSent, handler.WarningCount(c)); err != nil {
+					log.Errorf("Error writing result to %s: %v", c, err)
+					return false
+				}
+				return true
+			}
+			continue
+		}
+
+		n := min(int(numRows)-sent, len(cur.pending))
+		if err := c.writeBinaryRows(&sqltypes.Result{Fields: cur.fields, Rows: cur.pending[:n]}); err != nil {
+			log.Errorf("Error writing rows to %s: %v", c, err)
+			return false
+		}
+		cur.pending = cur.pending[n:]
+		sent += n
+	}
+
+	if err := c.writeFetchEnd(0, 0); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+// writeFieldsWithCursor sends the fields of a result with an open cursor.
+// The fields are always followed by an EOF packet, that tells the client
+// to fetch the rows.
+func (c *Conn) writeFieldsWithCursor(result *sqltypes.Result) error {
+	if err := c.sendColumnCount(uint64(len(result.Fields))); err != nil {
+		return err
+	}
+	for _, field := range result.Fields {
+		if err := c.writeColumnDefinition(field); err != nil {
+			return err
+		}
+	}
+	return c.writeFetchEnd(0, 0)
+}
+
+// writeFetchEnd sends the EOF packet, or the OK packet with an EOF
+// header, that ends the rows of a cursor.
+func (c *Conn) writeFetchEnd(flags uint16, warnings uint16) error {
+	flags |= c.StatusFlags | ServerStatusCursorExists
+	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
+		return c.writeEOFPacket(flags, warnings)
+	}
+	return c.writeOKPacketWithEOFHeader(&PacketOK{
+		statusFlags: flags,
+		warnings:    warnings,
+	})
+}
+
+func (c *Conn) parseComStmtFetch(data []byte) (uint32, uint32, bool) {
+	stmtID, pos, ok := readUint32(data, 1)
+	if !ok {
+		return 0, 0, false
+	}
+	numRows, _, ok := readUint32(data, pos)
+	return stmtID, numRows, ok
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// cursorHandler streams its results to the ComStmtExecute callback.
+type cursorHandler struct {
+	testRun
+	results    []*sqltypes.Result
+	cursorType byte
+	stopped    error
+	// statusFlags are set on the connection before each result, as the
+	// vtgate handler does.
+	statusFlags bool
+}
+
+func (h *cursorHandler) ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+	h.cursorType = prepare.CursorType
+	for _, qr := range h.results {
+		if h.statusFlags {
+			c.StatusFlags ^= ServerStatusInTrans
+		}
+		if err := callback(qr); err != nil {
+			h.stopped = err
+			return err
+		}
+	}
+	return nil
+}
+
+func stmtExecutePacket(stmtID uint32, cursorType byte) []byte {
+	data := make([]byte, packetHeaderSize+1+4+1+4)
+	pos := writeByte(data, packetHeaderSize, ComStmtExecute)
+	pos = writeUint32(data, pos, stmtID)
+	pos = writeByte(data, pos, cursorType)
+	writeUint32(data, pos, 1)
+	return data
+}
+
+func stmtFetchPacket(stmtID uint32, numRows uint32) []byte {
+	data := make([]byte, packetHeaderSize+1+4+4)
+	pos := writeByte(data, packetHeaderSize, ComStmtFetch)
+	pos = writeUint32(data, pos, stmtID)
+	writeUint32(data, pos, numRows)
+	return data
+}
+
+func TestCursor(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select name from t"}
+
+	row := func(name string) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewVarChar(name)}
+	}
+	handler := &cursorHandler{
+		testRun: testRun{t: t},
+		results: []*sqltypes.Result{
+			{Fields: []*querypb.Field{{Name: "name", Type: querypb.Type_VARCHAR}}, Rows: [][]sqltypes.Value{row("a")}},
+			{Rows: [][]sqltypes.Value{row("b"), row("c")}},
+			{Rows: [][]sqltypes.Value{row("d")}},
+		},
+	}
+
+	send := func(packet []byte) {
+		cConn.resetSequence()
+		require.NoError(t, cConn.writePacket(packet))
+		require.True(t, sConn.handleNextCommand(handler))
+	}
+	readEOF := func() uint16 {
+		data, err := cConn.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, byte(EOFPacket), data[0], "expected EOF packet, got %v", data)
+		_, flags, err := parseEOFPacket(data)
+		require.NoError(t, err)
+		return flags
+	}
+	fetch := func(numRows uint32) ([]string, uint16) {
+		send(stmtFetchPacket(1, numRows))
+		var names []string
+		for {
+			data, err := cConn.readPacket()
+			require.NoError(t, err)
+			if data[0] == EOFPacket {
+				_, flags, err := parseEOFPacket(data)
+				require.NoError(t, err)
+				return names, flags
+			}
+			// Binary row: header, NULL bitmap, and the value.
+			name, _, ok := readLenEncString(data, 2)
+			require.True(t, ok)
+			names = append(names, name)
+		}
+	}
+
+	// Only the fields are sent.
+	send(stmtExecutePacket(1, CursorTypeReadOnly))
+	assert.Equal(t, CursorTypeReadOnly, handler.cursorType)
+	data, err := cConn.readPacket()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, data)
+	field := &querypb.Field{}
+	require.NoError(t, cConn.readColumnDefinition(field, 0))
+	assert.Equal(t, "name", field.Name)
+	assert.Equal(t, ServerStatusCursorExists, readEOF())
+
+	// The rows are fetched across the results.
+	names, flags := fetch(2)
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Equal(t, ServerStatusCursorExists, flags)
+
+	names, flags = fetch(10)
+	assert.Equal(t, []string{"c", "d"}, names)
+	assert.Equal(t, ServerStatusCursorExists|ServerStatusLastRowSent, flags)
+	assert.Nil(t, sConn.cursor)
+	assert.NoError(t, handler.stopped)
+
+	send(stmtFetchPacket(1, 10))
+	data, err = cConn.readPacket()
+	require.NoError(t, err)
+	assert.EqualError(t, ParseErrorPacket(data), "The statement (1) has no open cursor. (errno 1421) (sqlstate HY000)")
+
+	// Closing the statement stops the handler.
+	send(stmtExecutePacket(1, CursorTypeReadOnly))
+	_, err = cConn.readPacket()
+	require.NoError(t, err)
+	require.NoError(t, cConn.readColumnDefinition(field, 0))
+	readEOF()
+	names, _ = fetch(1)
+	assert.Equal(t, []string{"a"}, names)
+	require.NotNil(t, sConn.cursor)
+
+	closePacket := make([]byte, packetHeaderSize+1+4)
+	writeUint32(closePacket, writeByte(closePacket, packetHeaderSize, ComStmtClose), 1)
+	send(closePacket)
+	assert.Nil(t, sConn.cursor)
+	assert.ErrorIs(t, handler.stopped, errCursorClosed)
+
+	// A statement that returns no rows doesn't open a cursor.
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "update t set name = 'a'"}
+	handler.results = []*sqltypes.Result{{RowsAffected: 3}}
+	handler.stopped = nil
+	send(stmtExecutePacket(1, CursorTypeReadOnly))
+	data, err = cConn.readPacket()
+	require.NoError(t, err)
+	require.Equal(t, byte(OKPacket), data[0])
+	var ok PacketOK
+	require.NoError(t, cConn.parseOKPacket(&ok, data))
+	assert.EqualValues(t, 3, ok.affectedRows)
+	assert.Nil(t, sConn.cursor)
+	assert.NoError(t, handler.stopped)
+
+	// Without a cursor, fetching fails.
+	send(stmtFetchPacket(1, 1))
+	data, err = cConn.readPacket()
+	require.NoError(t, err)
+	assert.Equal(t, sqlerror.ERStmtHasNoOpenCursor, ParseErrorPacket(data).(*sqlerror.SQLError).Num)
+}
+
+// TestCursorConcurrentCommands checks that the commands that leave a cursor
+// open don't run concurrently with its handler. Run with -race.
+func TestCursorConcurrentCommands(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select name from t"}
+	sConn.PrepareData[2] = &PrepareData{StatementID: 2, PrepareStmt: "select name from t where id = ?", ParamsCount: 1, BindVars: map[string]*querypb.BindVariable{}}
+	sConn.PrepareData[3] = &PrepareData{StatementID: 3, PrepareStmt: "select 1"}
+	// handleComPing checks whether the listener is shut down.
+	sConn.listener = &Listener{}
+
+	handler := &cursorHandler{
+		testRun:     testRun{t: t},
+		statusFlags: true,
+		results:     []*sqltypes.Result{{Fields: []*querypb.Field{{Name: "name", Type: querypb.Type_VARCHAR}}}},
+	}
+	for i := 0; i < 50; i++ {
+		handler.results = append(handler.results, &sqltypes.Result{Rows: [][]sqltypes.Value{{sqltypes.NewVarChar("a")}}})
+	}
+
+	send := func(packet []byte) {
+		cConn.resetSequence()
+		require.NoError(t, cConn.writePacket(packet))
+		require.True(t, sConn.handleNextCommand(handler))
+	}
+	readPacket := func() []byte {
+		data, err := cConn.readPacket()
+		require.NoError(t, err)
+		return data
+	}
+
+	send(stmtExecutePacket(1, CursorTypeReadOnly))
+	readPacket()
+	require.NoError(t, cConn.readColumnDefinition(&querypb.Field{}, 0))
+	readPacket()
+
+	ping := make([]byte, packetHeaderSize+1)
+	writeByte(ping, packetHeaderSize, ComPing)
+	longData := make([]byte, packetHeaderSize+1+4+2+1)
+	pos := writeByte(longData, packetHeaderSize, ComStmtSendLongData)
+	pos = writeUint32(longData, pos, 2)
+	pos = writeUint16(longData, pos, 0)
+	writeByte(longData, pos, 'x')
+	closeOther := make([]byte, packetHeaderSize+1+4)
+	writeUint32(closeOther, writeByte(closeOther, packetHeaderSize, ComStmtClose), 3)
+
+	for i := 0; i < 10; i++ {
+		send(stmtFetchPacket(1, 1))
+		readPacket()
+		readPacket()
+
+		send(ping)
+		assert.Equal(t, byte(OKPacket), readPacket()[0])
+
+		send(longData)
+	}
+	send(closeOther)
+	require.NotNil(t, sConn.cursor)
+	assert.NotContains(t, sConn.PrepareData, uint32(3))
+	assert.Equal(t, strings.Repeat("x", 10), string(sConn.PrepareData[2].BindVars["v1"].Value))
+
+	send(stmtFetchPacket(1, 100))
+	for data := readPacket(); data[0] != EOFPacket; data = readPacket() {
+	}
+	assert.Nil(t, sConn.cursor)
+	assert.NoError(t, handler.stopped)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jwtauthserver implements an AuthServer that authenticates clients
+// with a signed JSON Web Token sent as a clear text password.
+package jwtauthserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/log"
+)
+
+// Config holds the settings of the JWT AuthServer.
+type Config struct {
+	// JWKSFile is the path to the JSON Web Key Set used to verify tokens.
+	// The file is reloaded whenever it changes.
+	JWKSFile string
+	// Issuer, if set, must match the "iss" claim of the token.
+	Issuer string
+	// Audience, if set, must be contained in the "aud" claim of the token.
+	Audience string
+	// UsernameClaim is the claim that holds the Vitess username.
+	UsernameClaim string
+	// GroupsClaim is the claim that holds the groups of the user.
+	GroupsClaim string
+	// Leeway is the allowed clock skew when validating the token times.
+	Leeway time.Duration
+}
+
+// AuthServerJWT implements AuthServer by validating a JWT sent
+// with mysql_clear_password against a local JWKS file.
+type AuthServerJWT struct {
+	methods []mysql.AuthMethod
+	config  Config
+
+	mu   sync.Mutex
+	keys []*verificationKey
+
+	// watcher reloads the JWKS file until the context of the server is done.
+	watcher *fsnotify.Watcher
+
+	// now is used to validate the token times. It can be overridden in tests.
+	now func() time.Time
+}
+
+// Init is public so it can be called from plugin_auth_jwt.go (go/cmd/vtgate).
+// The JWKS file stops being watched when ctx is done.
+func Init(ctx context.Context, config Config) {
+	if config.JWKSFile == "" {
+		log.Infof("Not configuring AuthServerJWT, as --mysql_auth_jwt_jwks_file is empty.")
+		return
+	}
+	if config.UsernameClaim == "" {
+		log.Exitf("If using JWT auth server, --mysql_auth_jwt_username_claim is required.")
+	}
+
+	asj, err := newAuthServerJWT(ctx, config)
+	if err != nil {
+		log.Exitf("%s", err)
+	}
+	mysql.RegisterAuthServer("jwt", asj)
+}
+
+func newAuthServerJWT(ctx context.Context, config Config) (*AuthServerJWT, error) {
+	asj := &AuthServerJWT{
+		config: config,
+		now:    time.Now,
+	}
+	asj.methods = []mysql.AuthMethod{mysql.NewMysqlClearAuthMethod(asj, asj)}
+
+	if err := asj.reload(); err != nil {
+		return nil, err
+	}
+	if err := asj.watch(ctx); err != nil {
+		return nil, err
+	}
+	return asj, nil
+}
+
+// AuthMethods returns the list of registered auth methods
+// implemented by this auth server.
+func (asj *AuthServerJWT) AuthMethods() []mysql.AuthMethod {
+	return asj.methods
+}
+
+// DefaultAuthMethodDescription returns MysqlClearPassword as the default
+// authentication method for the auth server implementation.
+func (asj *AuthServerJWT) DefaultAuthMethodDescription() mysql.AuthMethodDescription {
+	return mysql.MysqlClearPassword
+}
+
+// HandleUser is part of the UserValidator interface. We
+// handle any user here since we don't check up front.
+func (asj *AuthServerJWT) HandleUser(user string) bool {
+	return true
+}
+
+// UserEntryWithPassword is part of the PlaintextStorage interface.
+// The password is the token, and the connection must use TLS.
+func (asj *AuthServerJWT) UserEntryWithPassword(conn *mysql.Conn, user string, password string, remoteAddr net.Addr) (mysql.Getter, error) {
+	if !conn.TLSEnabled() {
+		log.Warningf("JWT authentication for user '%v' from %v rejected: TLS is required", user, remoteAddr)
+		return nil, accessDenied(user)
+	}
+
+	userData, err := asj.validate(user, password)
+	if err != nil {
+		log.Warningf("JWT authentication for user '%v' from %v failed: %v", user, remoteAddr, err)
+		return nil, accessDenied(user)
+	}
+	return userData, nil
+}
+
+// validate verifies the token and maps its claims to the user data.
+// The MySQL user name has to match the username claim.
+func (asj *AuthServerJWT) validate(user, token string) (*mysql.StaticUserData, error) {
+	asj.mu.Lock()
+	keys := asj.keys
+	asj.mu.Unlock()
+
+	c, err := verifyToken(token, keys)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.validate(asj.now(), asj.config.Leeway, asj.config.Issuer, asj.config.Audience); err != nil {
+		return nil, err
+	}
+
+	username, err := c.string(asj.config.UsernameClaim)
+	if err != nil {
+		return nil, err
+	}
+	if username != user {
+		return nil, fmt.Errorf("MySQL connection username '%v' does not match token username '%v'", user, username)
+	}
+
+	var groups []string
+	if asj.config.GroupsClaim != "" {
+		if groups, err = c.strings(asj.config.GroupsClaim); err != nil {
+			return nil, err
+		}
+	}
+	return &mysql.StaticUserData{Username: username, Groups: groups}, nil
+}
+
+// reload reads the JWKS file. On error the previously loaded keys are kept.
+func (asj *AuthServerJWT) reload() error {
+	data, err := os.ReadFile(asj.config.JWKSFile)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file %v: %v", asj.config.JWKSFile, err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("failed to load JWKS file %v: %v", asj.config.JWKSFile, err)
+	}
+
+	asj.mu.Lock()
+	asj.keys = keys
+	asj.mu.Unlock()
+	return nil
+}
+
+// watch reloads the JWKS file when it changes, until ctx is done. The parent
+// directory is watched so that a file atomically replaced through a rename
+// is picked up as well.
+func (asj *AuthServerJWT) watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("unable to create fsnotify watcher: %v", err)
+	}
+	fileName := filepath.Base(asj.config.JWKSFile)
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(evt.Name) != fileName || !(evt.Has(fsnotify.Write) || evt.Has(fsnotify.Create)) {
+					continue
+				}
+				if err := asj.reload(); err != nil {
+					log.Errorf("%v", err)
+				} else {
+					log.Infof("Reloaded JWKS from %v", asj.config.JWKSFile)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Errorf("Error watching %v: %v", asj.config.JWKSFile, err)
+			}
+		}
+	}()
+
+	if err := watcher.Add(filepath.Dir(asj.config.JWKSFile)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("unable to set up watcher for %v: %v", asj.config.JWKSFile, err)
+	}
+	asj.watcher = watcher
+	return nil
+}
+
+func accessDenied(user string) error {
+	return sqlerror.NewSQLErrorf(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+}
@@ -0,0 +1,349 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jwtauthserver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]any {
+	return map[string]any{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   b64(key.N.Bytes()),
+		"e":   b64(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]any {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	return map[string]any{
+		"kty": "EC",
+		"kid": kid,
+		"crv": key.Curve.Params().Name,
+		"x":   b64(key.X.FillBytes(make([]byte, size))),
+		"y":   b64(key.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]any) {
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func signToken(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	header, err := json.Marshal(map[string]any{"alg": alg, "typ": "JWT", "kid": kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := b64(header) + "." + b64(payload)
+
+	sa := signingAlgorithms[alg]
+	h := sa.hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if sa.pss {
+			signature, err = rsa.SignPSS(rand.Reader, k, sa.hash, digest, nil)
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, k, sa.hash, digest)
+		}
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		require.NoError(t, err)
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signed + "." + b64(signature)
+}
+
+func tlsConn() *mysql.Conn {
+	conn := mysql.GetTestConn()
+	conn.Capabilities |= mysql.CapabilityClientSSL
+	return conn
+}
+
+func newTestAuthServer(t *testing.T, config Config) *AuthServerJWT {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	asj, err := newAuthServerJWT(ctx, config)
+	require.NoError(t, err)
+	asj.now = func() time.Time { return testNow }
+	return asj
+}
+
+func TestAuthServerJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, rsaJWK("rsa", rsaKey), ecJWK("ec", ecKey))
+
+	asj := newTestAuthServer(t, Config{
+		JWKSFile:      jwksFile,
+		Issuer:        "https://idp.example.com",
+		Audience:      "vitess",
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "realm_access.roles",
+		Leeway:        time.Minute,
+	})
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":                "https://idp.example.com",
+			"aud":                []string{"other", "vitess"},
+			"sub":                "0b4e2f3c",
+			"preferred_username": "alice",
+			"realm_access":       map[string]any{"roles": []string{"dev", "oncall"}},
+			"exp":                testNow.Add(time.Hour).Unix(),
+			"nbf":                testNow.Add(-time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	testcases := []struct {
+		name  string
+		user  string
+		token string
+		err   string
+	}{{
+		name:  "RS256",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(nil)),
+	}, {
+		name:  "PS384",
+		user:  "alice",
+		token: signToken(t, "PS384", "rsa", rsaKey, claims(nil)),
+	}, {
+		name:  "ES256",
+		user:  "alice",
+		token: signToken(t, "ES256", "ec", ecKey, claims(nil)),
+	}, {
+		name:  "without kid",
+		user:  "alice",
+		token: signToken(t, "ES256", "", ecKey, claims(nil)),
+	}, {
+		name:  "expired within leeway",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"exp": testNow.Add(-30 * time.Second).Unix()})),
+	}, {
+		name:  "unknown key",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", otherKey, claims(nil)),
+		err:   "token signature verification failed",
+	}, {
+		name:  "wrong kid",
+		user:  "alice",
+		token: signToken(t, "RS256", "ec", rsaKey, claims(nil)),
+		err:   "token signature verification failed",
+	}, {
+		name:  "expired",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"exp": testNow.Add(-2 * time.Minute).Unix()})),
+		err:   "token is expired",
+	}, {
+		name:  "no expiration",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"exp": nil})),
+		err:   "token has no expiration time",
+	}, {
+		name:  "not valid yet",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"nbf": testNow.Add(2 * time.Minute).Unix()})),
+		err:   "token is not valid yet",
+	}, {
+		name:  "wrong issuer",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"iss": "https://evil.example.com"})),
+		err:   `unexpected token issuer "https://evil.example.com"`,
+	}, {
+		name:  "wrong audience",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"aud": "other"})),
+		err:   "token audience does not match",
+	}, {
+		name:  "username mismatch",
+		user:  "bob",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(nil)),
+		err:   "MySQL connection username 'bob' does not match token username 'alice'",
+	}, {
+		name:  "missing username",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"preferred_username": nil})),
+		err:   `token has no "preferred_username" claim`,
+	}, {
+		name:  "invalid groups",
+		user:  "alice",
+		token: signToken(t, "RS256", "rsa", rsaKey, claims(map[string]any{"realm_access": map[string]any{"roles": 1}})),
+		err:   `invalid "realm_access.roles" claim`,
+	}, {
+		name:  "malformed",
+		user:  "alice",
+		token: "not-a-token",
+		err:   "malformed token",
+	}, {
+		name:  "unsigned",
+		user:  "alice",
+		token: b64([]byte(`{"alg":"none"}`)) + "." + b64([]byte(`{"preferred_username":"alice"}`)) + ".",
+		err:   `unsupported token signing algorithm "none"`,
+	}}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			userData, err := asj.validate(tc.user, tc.token)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &querypb.VTGateCallerID{Username: "alice", Groups: []string{"dev", "oncall"}}, userData.Get())
+		})
+	}
+
+	t.Run("requires TLS", func(t *testing.T) {
+		token := signToken(t, "RS256", "rsa", rsaKey, claims(nil))
+
+		_, err := asj.UserEntryWithPassword(mysql.GetTestConn(), "alice", token, nil)
+		require.EqualError(t, err, "Access denied for user 'alice' (errno 1045) (sqlstate 28000)")
+
+		getter, err := asj.UserEntryWithPassword(tlsConn(), "alice", token, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", getter.Get().Username)
+
+		_, err = asj.UserEntryWithPassword(tlsConn(), "bob", token, nil)
+		require.EqualError(t, err, "Access denied for user 'bob' (errno 1045) (sqlstate 28000)")
+	})
+}
+
+func TestAuthServerJWTReload(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, rsaJWK("old", oldKey))
+
+	asj := newTestAuthServer(t, Config{
+		JWKSFile:      jwksFile,
+		UsernameClaim: "sub",
+	})
+
+	claims := map[string]any{"sub": "alice", "exp": testNow.Add(time.Hour).Unix()}
+	oldToken := signToken(t, "RS256", "old", oldKey, claims)
+	newToken := signToken(t, "ES384", "new", newKey, claims)
+
+	_, err = asj.validate("alice", oldToken)
+	require.NoError(t, err)
+	_, err = asj.validate("alice", newToken)
+	require.Error(t, err)
+
+	writeJWKS(t, jwksFile, ecJWK("new", newKey))
+	require.Eventually(t, func() bool {
+		_, err := asj.validate("alice", newToken)
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	_, err = asj.validate("alice", oldToken)
+	require.Error(t, err)
+
+	// An invalid key set keeps the previously loaded keys.
+	require.NoError(t, os.WriteFile(jwksFile, []byte(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "AA", "y": "AA"}]}`), 0o600))
+	require.Error(t, asj.reload())
+	_, err = asj.validate("alice", newToken)
+	require.NoError(t, err)
+}
+
+func TestAuthServerJWTStopWatching(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, rsaJWK("key", key))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	asj, err := newAuthServerJWT(ctx, Config{
+		JWKSFile:      jwksFile,
+		UsernameClaim: "sub",
+	})
+	require.NoError(t, err)
+
+	// The watcher is closed once the context is done.
+	require.NoError(t, asj.watcher.Add(filepath.Dir(jwksFile)))
+	cancel()
+	require.Eventually(t, func() bool {
+		return errors.Is(asj.watcher.Add(filepath.Dir(jwksFile)), fsnotify.ErrClosed)
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestParseJWKS(t *testing.T) {
+	_, err := parseJWKS([]byte(`{"keys": []}`))
+	require.EqualError(t, err, "JWKS does not contain any signature verification keys")
+
+	_, err = parseJWKS([]byte(`{"keys": [{"kty": "oct", "k": "c2VjcmV0"}, {"kty": "RSA", "use": "enc", "n": "AQAB", "e": "AQAB"}]}`))
+	require.EqualError(t, err, "JWKS does not contain any signature verification keys")
+
+	_, err = parseJWKS([]byte(`{"keys": [{"kty": "RSA", "kid": "a", "n": "AQAB"}]}`))
+	require.EqualError(t, err, `error parsing key 0 (kid "a") in JWKS: invalid exponent: missing value`)
+
+	_, err = parseJWKS([]byte(`{"keys": [{"kty": "EC", "kid": "b", "crv": "P-224", "x": "AA", "y": "AA"}]}`))
+	require.EqualError(t, err, `error parsing key 0 (kid "b") in JWKS: unsupported curve "P-224"`)
+
+	_, err = parseJWKS([]byte(`not json`))
+	require.ErrorContains(t, err, "error parsing JWKS")
+}
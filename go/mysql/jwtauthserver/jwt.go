@@ -0,0 +1,379 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jwtauthserver
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// jsonWebKey is a single entry of a JSON Web Key Set, as described in RFC 7517.
+// Only the fields needed to verify signatures are decoded.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA public key parameters.
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC public key parameters.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey is a public key from the key set that can be used to
+// verify token signatures.
+type verificationKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// parseJWKS parses a JSON Web Key Set and returns the keys usable for
+// signature verification. Keys of unknown types and keys marked for
+// encryption only are ignored.
+func parseJWKS(data []byte) ([]*verificationKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("error parsing JWKS: %v", err)
+	}
+
+	var keys []*verificationKey
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch jwk.Kty {
+		case "RSA":
+			key, err = parseRSAKey(&jwk)
+		case "EC":
+			key, err = parseECKey(&jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing key %d (kid %q) in JWKS: %v", i, jwk.Kid, err)
+		}
+		keys = append(keys, &verificationKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS does not contain any signature verification keys")
+	}
+	return keys, nil
+}
+
+func parseRSAKey(jwk *jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %v", err)
+	}
+	e, err := decodeBigInt(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %v", err)
+	}
+	if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func parseECKey(jwk *jsonWebKey) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+	x, err := decodeBigInt(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x coordinate: %v", err)
+	}
+	y, err := decodeBigInt(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y coordinate: %v", err)
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("missing value")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// signingAlgorithm describes how to verify a JWS signature for a given "alg".
+type signingAlgorithm struct {
+	hash crypto.Hash
+	// kty is the key type the algorithm requires.
+	kty string
+	// pss is set for the RSASSA-PSS variants.
+	pss bool
+}
+
+var signingAlgorithms = map[string]signingAlgorithm{
+	"RS256": {hash: crypto.SHA256, kty: "RSA"},
+	"RS384": {hash: crypto.SHA384, kty: "RSA"},
+	"RS512": {hash: crypto.SHA512, kty: "RSA"},
+	"PS256": {hash: crypto.SHA256, kty: "RSA", pss: true},
+	"PS384": {hash: crypto.SHA384, kty: "RSA", pss: true},
+	"PS512": {hash: crypto.SHA512, kty: "RSA", pss: true},
+	"ES256": {hash: crypto.SHA256, kty: "EC"},
+	"ES384": {hash: crypto.SHA384, kty: "EC"},
+	"ES512": {hash: crypto.SHA512, kty: "EC"},
+}
+
+func (sa signingAlgorithm) verify(key crypto.PublicKey, signed, signature []byte) bool {
+	h := sa.hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if sa.kty != "RSA" {
+			return false
+		}
+		if sa.pss {
+			return rsa.VerifyPSS(k, sa.hash, digest, signature, nil) == nil
+		}
+		return rsa.VerifyPKCS1v15(k, sa.hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		if sa.kty != "EC" {
+			return false
+		}
+		// JWS encodes ECDSA signatures as the fixed size concatenation of R and S.
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
+
+// claims holds the decoded payload of a token.
+type claims map[string]any
+
+// verifyToken checks the signature of a compact serialized JWS token against
+// the given keys and returns its claims. It does not validate the claims.
+func verifyToken(token string, keys []*verificationKey) (claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	sa, ok := signingAlgorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported token signing algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, k := range keys {
+		if header.Kid != "" && k.kid != header.Kid {
+			continue
+		}
+		if k.alg != "" && k.alg != header.Alg {
+			continue
+		}
+		if sa.verify(k.key, signed, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("token signature verification failed")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token payload: %v", err)
+	}
+	var c claims
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("malformed token payload: %v", err)
+	}
+	return c, nil
+}
+
+// validate checks the registered time, issuer and audience claims.
+// An empty issuer or audience disables the respective check.
+func (c claims) validate(now time.Time, leeway time.Duration, issuer, audience string) error {
+	exp, ok, err := c.time("exp")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("token has no expiration time")
+	}
+	if now.After(exp.Add(leeway)) {
+		return errors.New("token is expired")
+	}
+
+	nbf, ok, err := c.time("nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(leeway).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+
+	if issuer != "" {
+		if iss, _ := c["iss"].(string); iss != issuer {
+			return fmt.Errorf("unexpected token issuer %q", iss)
+		}
+	}
+	if audience != "" {
+		auds, err := c.strings("aud")
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, aud := range auds {
+			if aud == audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("token audience does not match")
+		}
+	}
+	return nil
+}
+
+// time returns a NumericDate claim.
+func (c claims) time(name string) (time.Time, bool, error) {
+	v, ok := c[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("invalid %q claim", name)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %q claim: %v", name, err)
+	}
+	return time.Unix(0, int64(f*float64(time.Second))), true, nil
+}
+
+// lookup returns the value of a claim. A name containing dots refers to a
+// claim nested inside JSON objects, e.g. "realm_access.roles".
+func (c claims) lookup(name string) (any, bool) {
+	var v any = map[string]any(c)
+	for _, part := range strings.Split(name, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// string returns the value of a string claim.
+func (c claims) string(name string) (string, error) {
+	v, ok := c.lookup(name)
+	if !ok {
+		return "", fmt.Errorf("token has no %q claim", name)
+	}
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return "", fmt.Errorf("invalid %q claim", name)
+	}
+	return s, nil
+}
+
+// strings returns the value of a claim that holds either a single string or
+// an array of strings. A missing claim is returned as an empty list.
+func (c claims) strings(name string) ([]string, error) {
+	v, ok := c.lookup(name)
+	if !ok {
+		return nil, nil
+	}
+	switch v := v.(type) {
+	case string:
+		return []string{v}, nil
+	case []any:
+		res := make([]string, 0, len(v))
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %q claim", name)
+			}
+			res = append(res, s)
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("invalid %q claim", name)
+}
@@ -55,7 +55,7 @@ const (
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) WriteComQuery(query string) error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(len(query) + 1)
 	data[pos] = ComQuery
@@ -71,6 +71,9 @@ func (c *Conn) WriteComQuery(query string) error {
 // Client -> Server.
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComInitDB(db string) error {
+	// This is a new command, need to reset the sequence.
+	c.resetSequence()
+
 	data, pos := c.startEphemeralPacketWithHeader(len(db) + 1)
 	data[pos] = ComInitDB
 	pos++
@@ -81,9 +84,46 @@ func (c *Conn) writeComInitDB(db string) error {
 	return nil
 }
 
+// writeComChangeUser authenticates as another user, with the
+// password scrambled for the current auth method.
+// Client -> Server.
+// Returns SQLError(CRServerGone) if it can't.
+func (c *Conn) writeComChangeUser(params *ConnParams, scrambledPassword []byte) error {
+	// This is a new command, need to reset the sequence.
+	c.resetSequence()
+
+	length := 1 + // ComChangeUser
+		len(params.Uname) + 1 + // 0-terminated user name
+		1 + len(scrambledPassword) + // length-prefixed auth response
+		len(params.DbName) + 1 + // 0-terminated db name
+		2 + // character set
+		len(c.authPluginName) + 1 // 0-terminated auth method
+
+	data, pos := c.startEphemeralPacketWithHeader(length)
+	pos = writeByte(data, pos, ComChangeUser)
+	pos = writeNullString(data, pos, params.Uname)
+	pos = writeByte(data, pos, uint8(len(scrambledPassword)))
+	pos += copy(data[pos:], scrambledPassword)
+	pos = writeNullString(data, pos, params.DbName)
+	pos = writeUint16(data, pos, uint16(params.Charset))
+	pos = writeNullString(data, pos, string(c.authPluginName))
+
+	// Sanity check.
+	if pos != len(data) {
+		return vterrors.Errorf(vtrpc.Code_INTERNAL, "error building ComChangeUser packet: got %v bytes expected %v", pos, len(data))
+	}
+	if err := c.writeEphemeralPacket(); err != nil {
+		return sqlerror.NewSQLError(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, err.Error())
+	}
+	return nil
+}
+
 // writeComSetOption changes the connection's capability of executing multi statements.
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComSetOption(operation uint16) error {
+	// This is a new command, need to reset the sequence.
+	c.resetSequence()
+
 	data, pos := c.startEphemeralPacketWithHeader(16 + 1)
 	data[pos] = ComSetOption
 	pos++
@@ -575,8 +615,97 @@ func (c *Conn) readComQueryResponse(packetOk *PacketOK) (int, error) {
 // Server side methods.
 //
 
-func (c *Conn) parseComQuery(data []byte) string {
-	return string(data[1:])
+// parseComQuery returns the query of a COM_QUERY packet. If the client
+// sends query attributes, it stores them in c.QueryAttributes.
+func (c *Conn) parseComQuery(data []byte) (string, error) {
+	payload := data[1:]
+	if c.Capabilities&CapabilityClientQueryAttributes == 0 {
+		return string(payload), nil
+	}
+
+	paramsCount, pos, ok := readLenEncInt(payload, 0)
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter count failed")
+	}
+	// The parameter set count is always 1.
+	_, pos, ok = readLenEncInt(payload, pos)
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter set count failed")
+	}
+	if paramsCount > 0 {
+		if paramsCount > uint64(len(payload)) {
+			return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "invalid parameter count")
+		}
+		var bitMap []byte
+		bitMap, pos, ok = readBytes(payload, pos, (int(paramsCount)+7)/8)
+		if !ok {
+			return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
+		}
+		// The new parameters bound flag is always 1.
+		var newParamsBoundFlag byte
+		newParamsBoundFlag, pos, ok = readByte(payload, pos)
+		if !ok || newParamsBoundFlag != 0x01 {
+			return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading new parameters bound flag failed")
+		}
+		attributes := make([]queryAttribute, paramsCount)
+		for i := range attributes {
+			if attributes[i], pos, ok = parseQueryAttributeType(payload, pos); !ok {
+				return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attribute type failed")
+			}
+		}
+		var err error
+		if pos, err = c.parseQueryAttributeValues(payload, pos, attributes, bitMap, 0); err != nil {
+			return "", err
+		}
+	}
+	return string(payload[pos:]), nil
+}
+
+// queryAttribute is the name and type of a query attribute, as sent by
+// the client before the values.
+type queryAttribute struct {
+	name string
+	typ  querypb.Type
+}
+
+func parseQueryAttributeType(payload []byte, pos int) (queryAttribute, int, bool) {
+	mysqlType, pos, ok := readByte(payload, pos)
+	if !ok {
+		return queryAttribute{}, 0, false
+	}
+	flags, pos, ok := readByte(payload, pos)
+	if !ok {
+		return queryAttribute{}, 0, false
+	}
+	name, pos, ok := readLenEncString(payload, pos)
+	if !ok {
+		return queryAttribute{}, 0, false
+	}
+	typ, err := sqltypes.MySQLToType(mysqlType, int64(flags))
+	if err != nil {
+		return queryAttribute{}, 0, false
+	}
+	return queryAttribute{name: name, typ: typ}, pos, true
+}
+
+// parseQueryAttributeValues reads the values of the query attributes, and
+// stores them in c.QueryAttributes. offset is the index of the first
+// attribute in the NULL-bitmap. Attributes with a NULL value are skipped.
+func (c *Conn) parseQueryAttributeValues(payload []byte, pos int, attributes []queryAttribute, bitMap []byte, offset int) (int, error) {
+	c.QueryAttributes = make(map[string]string, len(attributes))
+	for i, attr := range attributes {
+		if (bitMap[(offset+i)/8] & (1 << uint((offset+i)%8))) > 0 {
+			continue
+		}
+		var val sqltypes.Value
+		var ok bool
+		val, pos, ok = c.parseStmtArgs(payload, attr.typ, pos)
+		if !ok {
+			return 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute value failed: %v", attr.typ)
+		}
+		c.QueryAttributes[attr.name] = val.ToString()
+	}
+	return pos, nil
 }
 
 func (c *Conn) parseComSetOption(data []byte) (uint16, bool) {
@@ -618,8 +747,25 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "iteration count is not equal to 1")
 	}
 
-	if prepare.ParamsCount > 0 {
-		bitMap, pos, ok = readBytes(payload, pos, (int(prepare.ParamsCount)+7)/8)
+	// With query attributes, the client sends the number of parameters,
+	// which includes the attributes sent after the statement parameters.
+	queryAttributes := c.Capabilities&CapabilityClientQueryAttributes != 0
+	paramsCount := int(prepare.ParamsCount)
+	if queryAttributes && (prepare.ParamsCount > 0 || cursorType&ParameterCountAvailable != 0) {
+		var count uint64
+		count, pos, ok = readLenEncInt(payload, pos)
+		if !ok {
+			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter count failed")
+		}
+		if count < uint64(prepare.ParamsCount) || count > uint64(len(payload)) {
+			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "invalid parameter count")
+		}
+		paramsCount = int(count)
+	}
+	var attributes []queryAttribute
+
+	if paramsCount > 0 {
+		bitMap, pos, ok = readBytes(payload, pos, (paramsCount+7)/8)
 		if !ok {
 			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
 		}
@@ -639,6 +785,14 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
 			}
 
+			// statement parameters have no name.
+			if queryAttributes {
+				_, pos, ok = readLenEncString(payload, pos)
+				if !ok {
+					return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter name failed")
+				}
+			}
+
 			// convert MySQL type to internal type.
 			valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
 			if err != nil {
@@ -647,6 +801,17 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 
 			prepare.ParamsType[i] = int32(valType)
 		}
+		if paramsCount > int(prepare.ParamsCount) {
+			attributes = make([]queryAttribute, paramsCount-int(prepare.ParamsCount))
+			for i := range attributes {
+				if attributes[i], pos, ok = parseQueryAttributeType(payload, pos); !ok {
+					return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attribute type failed")
+				}
+			}
+		}
+	} else if paramsCount > int(prepare.ParamsCount) {
+		// The types of the query attributes are not kept between executions.
+		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "query attributes sent without their types")
 	}
 
 	for i := range len(prepare.ParamsType) {
@@ -670,6 +835,12 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 		prepare.BindVars[parameterID] = sqltypes.ValueBindVariable(val)
 	}
 
+	if len(attributes) > 0 {
+		if _, err := c.parseQueryAttributeValues(payload, pos, attributes, bitMap, int(prepare.ParamsCount)); err != nil {
+			return stmtID, 0, err
+		}
+	}
+
 	return stmtID, cursorType, nil
 }
 
@@ -956,6 +1127,50 @@ func (c *Conn) parseComInitDB(data []byte) string {
 	return string(data[1:])
 }
 
+// parseComChangeUser parses a COM_CHANGE_USER packet. The character set
+// and auth method are optional, an empty character set is returned as 0.
+// The connection attributes that may follow are ignored.
+func (c *Conn) parseComChangeUser(data []byte) (user string, authMethod AuthMethodDescription, authResponse []byte, schemaName string, characterSet collations.ID, err error) {
+	user, pos, ok := readNullString(data, 1)
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read username")
+	}
+
+	l, pos, ok := readByte(data, pos)
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth-response length")
+	}
+	authResponse, pos, ok = readBytesCopy(data, pos, int(l))
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth-response")
+	}
+
+	schemaName, pos, ok = readNullString(data, pos)
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read dbname")
+	}
+
+	authMethod = MysqlNativePassword
+	if pos < len(data) {
+		cs, p, ok := readUint16(data, pos)
+		if !ok {
+			return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read characterSet")
+		}
+		characterSet, pos = collations.ID(cs), p
+	}
+	if pos < len(data) {
+		authMethodStr, _, ok := readNullString(data, pos)
+		if !ok {
+			return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read authMethod")
+		}
+		// As in the handshake, an empty auth method means mysql_native_password.
+		if authMethodStr != "" {
+			authMethod = AuthMethodDescription(authMethodStr)
+		}
+	}
+	return user, authMethod, authResponse, schemaName, characterSet, nil
+}
+
 func (c *Conn) sendColumnCount(count uint64) error {
 	length := lenEncIntSize(count)
 	data, pos := c.startEphemeralPacketWithHeader(length)
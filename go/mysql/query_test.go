@@ -250,6 +250,82 @@ func TestComStmtExecute(t *testing.T) {
 
 }
 
+func TestComQueryWithQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	// This is simulated packet for `select 1` with the attributes
+	// svc = 'orders', retries = 3 and trace = NULL.
+	data := []byte{ComQuery, 0x03, 0x01, 0x04, 0x01,
+		0xfe, 0x00, 0x03, 's', 'v', 'c',
+		0x08, 0x00, 0x07, 'r', 'e', 't', 'r', 'i', 'e', 's',
+		0xfe, 0x00, 0x05, 't', 'r', 'a', 'c', 'e',
+		0x06, 'o', 'r', 'd', 'e', 'r', 's',
+		0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		's', 'e', 'l', 'e', 'c', 't', ' ', '1'}
+
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+	query, err := sConn.parseComQuery(data)
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Equal(t, map[string]string{"svc": "orders", "retries": "3"}, sConn.QueryAttributes)
+
+	// Without the capability, the packet only holds the query.
+	sConn.Capabilities &^= CapabilityClientQueryAttributes
+	sConn.QueryAttributes = nil
+	query, err = sConn.parseComQuery([]byte{ComQuery, 's', 'e', 'l', 'e', 'c', 't', ' ', '1'})
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Nil(t, sConn.QueryAttributes)
+
+	// A truncated packet is rejected.
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+	_, err = sConn.parseComQuery(data[:12])
+	require.ErrorContains(t, err, "reading query attribute type failed")
+}
+
+func TestComStmtExecuteWithQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	prepareDataMap := map[uint32]*PrepareData{
+		18: {
+			StatementID: 18,
+			ParamsCount: 1,
+			ParamsType:  make([]int32, 1),
+			BindVars:    map[string]*querypb.BindVariable{},
+		}}
+
+	// This is simulated packet for `select * from test_table where id = ?`
+	// with id = 5 and the attribute svc = 'orders'.
+	data := []byte{ComStmtExecute, 18, 0, 0, 0, ParameterCountAvailable, 1, 0, 0, 0,
+		0x02, 0x00, 0x01,
+		0x01, 0x00, 0x00,
+		0xfe, 0x00, 0x03, 's', 'v', 'c',
+		0x05,
+		0x06, 'o', 'r', 'd', 'e', 'r', 's'}
+
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+	stmtID, _, err := sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	require.EqualValues(t, 18, stmtID)
+	assert.Equal(t, sqltypes.Int64BindVariable(5), prepareDataMap[18].BindVars["v1"])
+	assert.Equal(t, map[string]string{"svc": "orders"}, sConn.QueryAttributes)
+
+	// Without new parameter types, the types of the attributes are unknown.
+	data[12] = 0x00
+	_, _, err = sConn.parseComStmtExecute(prepareDataMap, data[:13])
+	require.ErrorContains(t, err, "query attributes sent without their types")
+}
+
 func TestComStmtExecuteUpdStmt(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
@@ -113,6 +113,10 @@ type Handler interface {
 
 	// ComStmtExecute is called when a connection receives a statement
 	// execute query.
+	// If the client asked for a cursor, as set in prepare.CursorType,
+	// it is called in its own go routine, and each callback blocks until
+	// the client fetched the rows of the result. It is stopped before any
+	// other method is called for the Connection.
 	ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error
 
 	// ComRegisterReplica is called when a connection receives a ComRegisterReplica request
@@ -136,6 +140,21 @@ type Handler interface {
 	Env() *vtenv.Environment
 }
 
+// ChangeUserHandler can be implemented by a Handler to control how the
+// connection state changes on a COM_CHANGE_USER request. Handlers that don't
+// implement it get the connection reset with ComResetConnection, and reset
+// again for the previous user if the change fails.
+type ChangeUserHandler interface {
+	// ComChangeUser is called when a connection was authenticated as
+	// another user by a ComChangeUser request, before the new default
+	// schema is set. The connection must get a clean state, as for a new
+	// connection. The returned function is called when the change is done:
+	// if it failed because the new schema cannot be used, the connection
+	// stays with the previous user, and the state it had before
+	// ComChangeUser must be restored.
+	ComChangeUser(c *Conn) (done func(success bool))
+}
+
 // UnimplementedHandler implemnts all of the optional callbacks so as to satisy
 // the Handler interface. Intended to be embedded into your custom Handler
 // implementation without needing to define every callback and to help be forwards
@@ -207,6 +226,14 @@ type Listener struct {
 	// RequireSecureTransport configures the server to reject connections from insecure clients
 	RequireSecureTransport bool
 
+	// CompressionAlgorithms are the algorithms of the compressed protocol
+	// we will advertise. Connections are never compressed if it is empty.
+	CompressionAlgorithms []CompressionAlgorithm
+
+	// ZlibCompressionLevel is the compression level used for the responses
+	// sent with zlib. With zstd, the level asked for by the client is used.
+	ZlibCompressionLevel int
+
 	// PreHandleFunc is called for each incoming connection, immediately after
 	// accepting a new connection. By default it's no-op. Useful for custom
 	// connection inspection or TLS termination. The returned connection is
@@ -391,13 +418,15 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil, l.compressionCapabilities())
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
 		}
 		return
 	}
+	// Keep the salt, clients use it again for COM_CHANGE_USER.
+	c.salt = serverAuthPluginData
 
 	// Wait for the client response. This has to be a direct read,
 	// so we don't buffer the TLS negotiation packets.
@@ -450,6 +479,82 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		defer connCountByTLSVer.Add(versionNoTLS, -1)
 	}
 
+	userData, ok := l.authenticate(c, user, clientAuthMethod, clientAuthResponse, serverAuthPluginData)
+	if !ok {
+		return
+	}
+
+	c.User = user
+	c.UserData = userData
+
+	// The user can change with COM_CHANGE_USER, so the count is
+	// decremented for the user at the time the connection ends.
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+	}
+	defer func() {
+		if c.User != "" {
+			connCountPerUser.Add(c.User, -1)
+		}
+	}()
+
+	// Set initial db name.
+	if c.schemaName != "" {
+		err = l.handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
+			return nil
+		})
+		if err != nil {
+			c.writeErrorPacketFromError(err)
+			return
+		}
+	}
+
+	// Negotiation worked, send OK packet.
+	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
+		log.Errorf("Cannot write OK packet to %s: %v", c, err)
+		return
+	}
+
+	// Everything after the OK packet uses the compressed protocol,
+	// if it was negotiated.
+	if algorithm, level := l.negotiatedCompression(c); algorithm != CompressionNone {
+		if err := c.enableCompression(algorithm, level); err != nil {
+			log.Errorf("Cannot enable %s compression for %s: %v", algorithm, c, err)
+			return
+		}
+	}
+
+	// Record how long we took to establish the connection
+	timings.Record(connectTimingKey, acceptTime)
+
+	// Log a warning if it took too long to connect
+	connectTime := time.Since(acceptTime).Nanoseconds()
+	if threshold := l.SlowConnectWarnThreshold.Load(); threshold != 0 && connectTime > threshold {
+		connSlow.Add(1)
+		log.Warningf("Slow connection from %s: %v", c, connectTime)
+	}
+
+	// Tell our handler that we're finished handshake and are ready to
+	// process commands.
+	l.handler.ConnectionReady(c)
+
+	// Stop the handler of an open cursor before the connection is closed.
+	defer c.closeCursor()
+
+	for {
+		kontinue := c.handleNextCommand(l.handler)
+		// before going for next command check if the connection should be closed or not.
+		if !kontinue || c.IsMarkedForClose() {
+			return
+		}
+	}
+}
+
+// authenticate authenticates the user with the AuthServer, using the auth
+// response the client sent along with the user name, or switching to another
+// auth method if needed. If it fails, the error is sent to the client and
+// false is returned.
+func (l *Listener) authenticate(c *Conn, user string, clientAuthMethod AuthMethodDescription, clientAuthResponse []byte, serverAuthPluginData []byte) (Getter, bool) {
 	// See what auth method the AuthServer wants to use for that user.
 	negotiatedAuthMethod, err := negotiateAuthMethod(c, l.authServer, user, clientAuthMethod)
 
@@ -475,86 +580,45 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 
 		if negotiatedAuthMethod == nil {
 			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "No authentication methods available for authentication.")
-			return
+			return nil, false
 		}
 
 		if !l.AllowClearTextWithoutTLS.Load() && !c.TLSEnabled() && !negotiatedAuthMethod.AllowClearTextWithoutTLS() {
 			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "Cannot use clear text authentication over non-SSL connections.")
-			return
+			return nil, false
 		}
 
 		serverAuthPluginData, err = negotiatedAuthMethod.AuthPluginData()
 		if err != nil {
 			log.Errorf("Error generating auth switch packet for %s: %v", c, err)
-			return
+			return nil, false
 		}
 
 		if err := c.writeAuthSwitchRequest(string(negotiatedAuthMethod.Name()), serverAuthPluginData); err != nil {
 			log.Errorf("Error writing auth switch packet for %s: %v", c, err)
-			return
+			return nil, false
+		}
+		// Clients scramble with the latest salt for COM_CHANGE_USER.
+		if name := negotiatedAuthMethod.Name(); name == MysqlNativePassword || name == CachingSha2Password {
+			c.salt = serverAuthPluginData
 		}
 
 		clientAuthResponse, err = c.readEphemeralPacket()
 		if err != nil {
 			log.Errorf("Error reading auth switch response for %s: %v", c, err)
-			return
+			return nil, false
 		}
 		c.recycleReadPacket()
 	}
 
-	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, conn.RemoteAddr())
+	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, c.RemoteAddr())
 	if err != nil {
 		log.Warningf("Error authenticating user %s using: %s", user, negotiatedAuthMethod.Name())
 		c.writeErrorPacketFromError(err)
-		return
-	}
-
-	c.User = user
-	c.UserData = userData
-
-	if c.User != "" {
-		connCountPerUser.Add(c.User, 1)
-		defer connCountPerUser.Add(c.User, -1)
-	}
-
-	// Set initial db name.
-	if c.schemaName != "" {
-		err = l.handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
-			return nil
-		})
-		if err != nil {
-			c.writeErrorPacketFromError(err)
-			return
-		}
-	}
-
-	// Negotiation worked, send OK packet.
-	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
-		log.Errorf("Cannot write OK packet to %s: %v", c, err)
-		return
-	}
-
-	// Record how long we took to establish the connection
-	timings.Record(connectTimingKey, acceptTime)
-
-	// Log a warning if it took too long to connect
-	connectTime := time.Since(acceptTime).Nanoseconds()
-	if threshold := l.SlowConnectWarnThreshold.Load(); threshold != 0 && connectTime > threshold {
-		connSlow.Add(1)
-		log.Warningf("Slow connection from %s: %v", c, connectTime)
+		return nil, false
 	}
 
-	// Tell our handler that we're finished handshake and are ready to
-	// process commands.
-	l.handler.ConnectionReady(c)
-
-	for {
-		kontinue := c.handleNextCommand(l.handler)
-		// before going for next command check if the connection should be closed or not.
-		if !kontinue || c.IsMarkedForClose() {
-			return
-		}
-	}
+	return userData, true
 }
 
 // Close stops the listener, which prevents accept of any new connections. Existing connections won't be closed.
@@ -572,7 +636,7 @@ func (l *Listener) Shutdown() {
 
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool, compressionCapabilities uint32) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -585,10 +649,12 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 		CapabilityClientPluginAuth |
 		CapabilityClientPluginAuthLenencClientData |
 		CapabilityClientDeprecateEOF |
-		CapabilityClientConnAttr
+		CapabilityClientConnAttr |
+		CapabilityClientQueryAttributes
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	capabilities |= int(compressionCapabilities)
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -714,6 +780,11 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 		c.Capabilities |= CapabilityClientMultiStatements
 	}
 
+	// set connection capability for reading query attributes
+	if clientFlags&CapabilityClientQueryAttributes > 0 {
+		c.Capabilities |= CapabilityClientQueryAttributes
+	}
+
 	// Max packet size. Don't do anything with this now.
 	// See doc.go for more information.
 	_, pos, ok = readUint32(data, pos)
@@ -806,14 +877,63 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 
 	// Decode connection attributes send by the client
 	if clientFlags&CapabilityClientConnAttr != 0 {
-		if _, _, err := parseConnAttrs(data, pos); err != nil {
+		var err error
+		if _, pos, err = parseConnAttrs(data, pos); err != nil {
 			log.Warningf("Decode connection attributes send by the client: %v", err)
+			pos = len(data)
+		}
+	}
+
+	// Compressed protocol, only with an algorithm we advertised.
+	// zstd is preferred if the client asked for both.
+	c.Capabilities &^= CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm
+	compression := clientFlags & l.compressionCapabilities()
+	if compression&CapabilityClientZstdCompressionAlgorithm != 0 {
+		// The zstd compression level comes last.
+		var level byte
+		level, _, ok = readByte(data, pos)
+		if !ok {
+			return "", "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseClientHandshakePacket: can't read zstd compression level")
 		}
+		if _, err := CompressionLevel(CompressionZstd, int(level)); err != nil || level == 0 {
+			return "", "", nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "parseClientHandshakePacket: invalid zstd compression level %d", level)
+		}
+		c.Capabilities |= CapabilityClientZstdCompressionAlgorithm
+		c.zstdCompressionLevel = int(level)
+	} else if compression&CapabilityClientCompress != 0 {
+		c.Capabilities |= CapabilityClientCompress
 	}
 
 	return username, AuthMethodDescription(authMethod), authResponse, nil
 }
 
+// compressionCapabilities returns the capability flags of the compression
+// algorithms we advertise.
+func (l *Listener) compressionCapabilities() uint32 {
+	var capabilities uint32
+	for _, algorithm := range l.CompressionAlgorithms {
+		switch algorithm {
+		case CompressionZlib:
+			capabilities |= CapabilityClientCompress
+		case CompressionZstd:
+			capabilities |= CapabilityClientZstdCompressionAlgorithm
+		}
+	}
+	return capabilities
+}
+
+// negotiatedCompression returns the compression algorithm and level
+// negotiated with the client during the handshake.
+func (l *Listener) negotiatedCompression(c *Conn) (CompressionAlgorithm, int) {
+	switch {
+	case c.Capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		return CompressionZstd, c.zstdCompressionLevel
+	case c.Capabilities&CapabilityClientCompress != 0:
+		return CompressionZlib, l.ZlibCompressionLevel
+	}
+	return CompressionNone, 0
+}
+
 func parseConnAttrs(data []byte, pos int) (map[string]string, int, error) {
 	var attrLen uint64
 
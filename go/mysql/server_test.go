@@ -194,6 +194,8 @@ func (th *testHandler) ComQuery(c *Conn, query string, callback func(*sqltypes.R
 				},
 			},
 		})
+	case "use `unknown_db`":
+		return sqlerror.NewSQLError(sqlerror.ERBadDb, sqlerror.SSClientError, "Unknown database 'unknown_db'")
 	case "50ms delay":
 		callback(&sqltypes.Result{
 			Fields: []*querypb.Field{{
@@ -518,6 +520,91 @@ func TestConnCounts(t *testing.T) {
 	}, 1*time.Second, 10*time.Millisecond)
 }
 
+func TestChangeUser(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	th := &testHandler{}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["changeUser1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	authServer.entries["changeUser2"] = []*AuthServerStaticEntry{{
+		Password: "password2",
+		UserData: "userData2",
+	}}
+	defer authServer.close()
+
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	host, port := getHostPort(t, l.Addr())
+	params := &ConnParams{
+		Host:   host,
+		Port:   port,
+		Uname:  "changeUser1",
+		Pass:   "password1",
+		DbName: "db1",
+	}
+	go l.Accept()
+	defer cleanupListener(ctx, l, params)
+
+	c, err := Connect(ctx, params)
+	require.NoError(t, err)
+	defer c.Close()
+	checkCountsForUser(t, "changeUser1", 1)
+
+	err = c.ChangeUser(&ConnParams{
+		Uname:  "changeUser2",
+		Pass:   "password2",
+		DbName: "db2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "changeUser2", c.User)
+	checkCountsForUser(t, "changeUser1", 0)
+	checkCountsForUser(t, "changeUser2", 1)
+
+	result, err := c.ExecuteFetch("userData echo", 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, "changeUser2", result.Rows[0][0].ToString())
+	assert.Equal(t, "userData2", result.Rows[0][1].ToString())
+
+	result, err = c.ExecuteFetch("schema echo", 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, "db2", result.Rows[0][0].ToString())
+
+	// An unknown schema fails the change, and the connection stays
+	// authenticated as the previous user, on its previous schema.
+	err = c.ChangeUser(&ConnParams{
+		Uname:  "changeUser1",
+		Pass:   "password1",
+		DbName: "unknown_db",
+	})
+	require.EqualError(t, err, "Unknown database 'unknown_db' (errno 1049) (sqlstate 42000)")
+	checkCountsForUser(t, "changeUser1", 0)
+	checkCountsForUser(t, "changeUser2", 1)
+
+	result, err = c.ExecuteFetch("userData echo", 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, "changeUser2", result.Rows[0][0].ToString())
+	assert.Equal(t, "userData2", result.Rows[0][1].ToString())
+
+	result, err = c.ExecuteFetch("schema echo", 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, "db2", result.Rows[0][0].ToString())
+
+	// A failed authentication closes the connection.
+	err = c.ChangeUser(&ConnParams{
+		Uname: "changeUser1",
+		Pass:  "wrong",
+	})
+	require.EqualError(t, err, "Access denied for user 'changeUser1' (errno 1045) (sqlstate 28000)")
+	_, err = c.ExecuteFetch("select rows", 10, true)
+	require.Error(t, err)
+	assert.EventuallyWithT(t, func(t *assert.CollectT) {
+		checkCountsForUser(t, "changeUser2", 0)
+	}, 1*time.Second, 10*time.Millisecond)
+}
+
 func checkCountsForUser(t assert.TestingT, user string, expected int64) {
 	connCounts := connCountPerUser.Counts()
 
@@ -1226,6 +1313,7 @@ func TestErrorCodes(t *testing.T) {
 
 	client, err := Connect(ctx, params)
 	require.NoError(t, err)
+	defer client.Close()
 
 	// Test that the right mysql errno/sqlstate are returned for various
 	// internal vitess errors
@@ -1408,6 +1496,7 @@ func TestListenerShutdown(t *testing.T) {
 
 	conn, err := Connect(ctx, params)
 	require.NoError(t, err)
+	defer conn.Close()
 
 	err = conn.Ping()
 	require.NoError(t, err)
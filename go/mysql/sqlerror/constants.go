@@ -38,6 +38,11 @@ const (
 	ERNonAtomicCommit  = ErrorCode(301)
 	ERInAtomicRecovery = ErrorCode(302)
 
+	// ERTxInterruptedByFailover is returned when a transaction was rolled back because one of its
+	// shards failed over while it was open. It is sent with SSTxRollback, so the transaction can be
+	// retried, but unlike ERLockDeadlock it tells clients that no deadlock was involved.
+	ERTxInterruptedByFailover = ErrorCode(303)
+
 	// unknown
 	ERUnknownError = ErrorCode(1105)
 
@@ -121,6 +126,7 @@ const (
 	ERSPDoesNotExist                = ErrorCode(1305)
 	ERNoDefaultForField             = ErrorCode(1364)
 	ErSPNotVarArg                   = ErrorCode(1414)
+	ERStmtHasNoOpenCursor           = ErrorCode(1421)
 	ERRowIsReferenced2              = ErrorCode(1451)
 	ErNoReferencedRow2              = ErrorCode(1452)
 	ERInnodbIndexCorrupt            = ErrorCode(1817)
@@ -528,6 +534,9 @@ const (
 	// SSNoDB is ER_NO_DB_ERROR
 	SSNoDB = "3D000"
 
+	// SSTxRollback is a transaction rollback that is not caused by a deadlock or serialization failure
+	SSTxRollback = "40000"
+
 	// SSLockDeadlock is ER_LOCK_DEADLOCK
 	SSLockDeadlock = "40001"
 
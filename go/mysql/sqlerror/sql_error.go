@@ -212,6 +212,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.NonUniqTable:                        {num: ERNonUniqTable, state: SSClientError},
 	vterrors.NonUpdateableTable:                  {num: ERNonUpdateableTable, state: SSUnknownSQLState},
 	vterrors.QueryInterrupted:                    {num: ERQueryInterrupted, state: SSQueryInterrupted},
+	vterrors.TxInterruptedByFailover:             {num: ERTxInterruptedByFailover, state: SSTxRollback},
 	vterrors.SPDoesNotExist:                      {num: ERSPDoesNotExist, state: SSClientError},
 	vterrors.SyntaxError:                         {num: ERSyntaxError, state: SSClientError},
 	vterrors.UnsupportedPS:                       {num: ERUnsupportedPS, state: SSUnknownSQLState},
@@ -155,6 +155,11 @@ func TestNewSQLErrorFromError(t *testing.T) {
 			num: ERNoDb,
 			ss:  SSNoDB,
 		},
+		{
+			err: vterrors.VT10003("ks/-80", "transaction not found"),
+			num: ERTxInterruptedByFailover,
+			ss:  SSTxRollback,
+		},
 		{
 			err: fmt.Errorf("just some random text here"),
 			num: ERUnknownError,
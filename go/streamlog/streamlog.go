@@ -67,6 +67,7 @@ type QueryLogConfig struct {
 	Format               string
 	Mode                 string
 	RowThreshold         uint64
+	QueryAttributes      bool
 	sampleRate           float64
 }
 
@@ -89,6 +90,8 @@ func init() {
 	servenv.OnParseFor("vtcombo", registerStreamLogFlags)
 	servenv.OnParseFor("vttablet", registerStreamLogFlags)
 	servenv.OnParseFor("vtgate", registerStreamLogFlags)
+	servenv.OnParseFor("vtcombo", registerQueryAttributesFlags)
+	servenv.OnParseFor("vtgate", registerQueryAttributesFlags)
 }
 
 func registerStreamLogFlags(fs *pflag.FlagSet) {
@@ -111,6 +114,11 @@ func registerStreamLogFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&queryLogConfigInstance.Mode, "querylog-mode", queryLogConfigInstance.Mode, `Mode for logging queries. "error" will only log queries that return an error. Otherwise all queries will be logged.`)
 }
 
+func registerQueryAttributesFlags(fs *pflag.FlagSet) {
+	// QueryLogQueryAttributes adds the query attributes sent by the MySQL clients to the query log
+	fs.BoolVar(&queryLogConfigInstance.QueryAttributes, "querylog-query-attributes", queryLogConfigInstance.QueryAttributes, "Add the query attributes sent by the MySQL clients to the query log, as a QueryAttributes field that ends the text format.")
+}
+
 // StreamLogger is a non-blocking broadcaster of messages.
 // Subscribers can use channels or HTTP.
 type StreamLogger[T any] struct {
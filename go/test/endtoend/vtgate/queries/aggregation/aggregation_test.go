@@ -124,6 +124,44 @@ func TestGroupBy(t *testing.T) {
 	}
 }
 
+func TestRollupOnScatter(t *testing.T) {
+	utils.SkipIfBinaryIsBelowVersion(t, 22, "vtgate")
+	mcmp, closer := start(t)
+	defer closer()
+
+	mcmp.Exec("insert into aggr_test(id, val1, val2) values(1,'a',1), (2,'a',2), (3,'b',1), (4,'c',3), (5,'c',null), (6,null,2)")
+	mcmp.Exec("select val1, val2, count(*), sum(id) from aggr_test group by val1, val2 with rollup")
+	mcmp.Exec("select val1, val2, grouping(val1), grouping(val1, val2), count(*) from aggr_test group by val1, val2 with rollup")
+	mcmp.Exec("select val1, grouping(val1) + 1, max(val2) from aggr_test group by val1 with rollup")
+	mcmp.Exec("select val1, count(*) from aggr_test group by val1 with rollup order by val1")
+}
+
+func TestStatisticalBitwiseAndJSONAggregationsOnScatter(t *testing.T) {
+	utils.SkipIfBinaryIsBelowVersion(t, 22, "vtgate")
+	mcmp, closer := start(t)
+	defer closer()
+
+	mcmp.Exec("insert into aggr_test(id, val1, val2) values(1,'a',1), (2,'a',2), (3,'b',1), (4,'c',3), (5,'c',null), (6,null,2)")
+	mcmp.Exec("select round(std(val2), 6), round(stddev_samp(val2), 6), round(var_pop(val2), 6), round(var_samp(val2), 6) from aggr_test")
+	mcmp.Exec("select val1, round(variance(id), 6), round(stddev_samp(id), 6) from aggr_test group by val1")
+	mcmp.Exec("select bit_and(val2), bit_or(val2), bit_xor(val2) from aggr_test")
+	mcmp.Exec("select val1, bit_and(id), bit_or(id), bit_xor(id) from aggr_test group by val1")
+	mcmp.Exec("select json_length(json_arrayagg(val2)), json_length(json_objectagg(id, val1)) from aggr_test")
+	mcmp.Exec("select val1, json_objectagg(id, val2) from aggr_test group by val1")
+}
+
+func TestMultipleDistinctAggregationsOnScatter(t *testing.T) {
+	utils.SkipIfBinaryIsBelowVersion(t, 22, "vtgate")
+	mcmp, closer := start(t)
+	defer closer()
+
+	mcmp.Exec("insert into aggr_test(id, val1, val2) values(1,'a',1), (2,'A',1), (3,'b',2), (4,'c',2), (5,'c',null), (6,null,3)")
+	mcmp.Exec("select count(distinct val1), count(distinct val2) from aggr_test")
+	mcmp.Exec("select count(distinct val1, val2) from aggr_test")
+	mcmp.Exec("select count(distinct val1), sum(distinct val2), count(*) from aggr_test")
+	mcmp.Exec("select val2, count(distinct val1), count(distinct id) from aggr_test group by val2")
+}
+
 func TestEqualFilterOnScatter(t *testing.T) {
 	mcmp, closer := start(t)
 	defer closer()
@@ -623,6 +661,26 @@ func TestGroupConcatAggregation(t *testing.T) {
 	compareRow(t, mQr, vtQr, nil, []int{0})
 }
 
+// TestGroupConcatOrderByDistinct tests group_concat with ORDER BY, DISTINCT and SEPARATOR evaluated on the vtgate.
+func TestGroupConcatOrderByDistinct(t *testing.T) {
+	utils.SkipIfBinaryIsBelowVersion(t, 22, "vtgate")
+	mcmp, closer := start(t)
+	defer closer()
+	mcmp.Exec("insert into t1(t1_id, `name`, `value`, shardkey) values(1,'a1',null,100), (2,'b1','foo',20), (3,'c1','foo',10), (4,'a1','foo',100), (5,'d1','toto',200), (6,'c1',null,893), (10,'a1','titi',2380), (20,'b1','tete',12833), (9,'e1','yoyo',783493)")
+	mcmp.Exec("insert into t2(id, shardKey) values (1, 10), (2, 20)")
+
+	mcmp.Exec(`SELECT group_concat(name order by t1_id) FROM t1`)
+	mcmp.Exec(`SELECT group_concat(distinct name order by name desc separator ';') FROM t1`)
+	mcmp.Exec(`SELECT group_concat(name, value order by value, t1_id) FROM t1`)
+	mcmp.Exec(`SELECT name, group_concat(distinct value order by 1) FROM t1 group by name`)
+	mcmp.Exec(`SELECT count(distinct name), group_concat(distinct value separator '') FROM t1`)
+	mcmp.Exec(`SELECT t2.id, group_concat(t1.name order by t1.t1_id) FROM t1 join t2 on t1.shardKey = t2.shardKey group by t2.id`)
+
+	mcmp.Exec(`set group_concat_max_len = 8`)
+	mcmp.Exec(`SELECT group_concat(name order by t1_id) FROM t1`)
+	mcmp.Exec(`SELECT name, group_concat(value order by t1_id) FROM t1 group by name`)
+}
+
 func compareRow(t *testing.T, mRes *sqltypes.Result, vtRes *sqltypes.Result, grpCols []int, fCols []int) {
 	require.Equal(t, len(mRes.Rows), len(vtRes.Rows), "mysql and vitess result count does not match")
 	for _, row := range vtRes.Rows {
@@ -662,15 +720,15 @@ func TestDistinctAggregation(t *testing.T) {
 		expectedErr string
 		minVersion  int
 	}{{
-		query:       `SELECT COUNT(DISTINCT value), SUM(DISTINCT shardkey) FROM t1`,
-		expectedErr: "VT12001: unsupported: only one DISTINCT aggregation is allowed in a SELECT: sum(distinct shardkey) (errno 1235) (sqlstate 42000)",
+		minVersion: 22,
+		query:      `SELECT COUNT(DISTINCT value), SUM(DISTINCT shardkey) FROM t1`,
 	}, {
 		query: `SELECT a.t1_id, SUM(DISTINCT b.shardkey) FROM t1 a, t1 b group by a.t1_id`,
 	}, {
 		query: `SELECT a.value, SUM(DISTINCT b.shardkey) FROM t1 a, t1 b group by a.value`,
 	}, {
-		query:       `SELECT count(distinct a.value), SUM(DISTINCT b.t1_id) FROM t1 a, t1 b`,
-		expectedErr: "VT12001: unsupported: only one DISTINCT aggregation is allowed in a SELECT: sum(distinct b.t1_id) (errno 1235) (sqlstate 42000)",
+		minVersion: 22,
+		query:      `SELECT count(distinct a.value), SUM(DISTINCT b.t1_id) FROM t1 a, t1 b`,
 	}, {
 		query: `SELECT a.value, SUM(DISTINCT b.t1_id), min(DISTINCT a.t1_id) FROM t1 a, t1 b group by a.value`,
 	}, {
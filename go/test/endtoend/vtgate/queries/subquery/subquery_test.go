@@ -106,6 +106,20 @@ func TestSubQueryOnTopOfSubQuery(t *testing.T) {
 	mcmp.AssertMatches("select id1 from t1 where id1 not in (select id3 from t2) and id2 in (select id4 from t2) order by id1", `[[INT64(3)] [INT64(4)]]`)
 }
 
+func TestSubqueryInOuterJoinCondition(t *testing.T) {
+	mcmp, closer := start(t)
+	defer closer()
+
+	mcmp.Exec("insert into t1(id1, id2) values (1, 1), (2, 2), (3, 3)")
+	mcmp.Exec("insert into t2(id3, id4) values (1, 3), (2, 4), (3, 5)")
+
+	mcmp.AssertMatches("select t1.id1, t2.id3 from t1 left join t2 on t1.id1 = t2.id3 and t2.id4 in (select id2 from t1) order by t1.id1",
+		`[[INT64(1) INT64(1)] [INT64(2) NULL] [INT64(3) NULL]]`)
+	mcmp.AssertMatches("select t1.id1, t2.id3 from t1 left join t2 on t2.id3 in (select x.id1 from t1 as x where x.id2 = t1.id2) order by t1.id1",
+		`[[INT64(1) INT64(1)] [INT64(2) INT64(2)] [INT64(3) INT64(3)]]`)
+	mcmp.Exec("select t1.id1, t2.id3 from t1 left join t2 on t1.id2 = t2.id3 and exists (select 1 from t1 as x where x.id1 = t2.id4) order by t1.id1")
+}
+
 func TestSubqueryInINClause(t *testing.T) {
 	mcmp, closer := start(t)
 	defer closer()
@@ -19,7 +19,9 @@ limitations under the License.
 package azblobbackupstorage
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/url"
@@ -36,6 +38,7 @@ import (
 
 	"vitess.io/vitess/go/viperutil"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/servenv"
 )
@@ -204,6 +207,7 @@ type AZBlobBackupHandle struct {
 	name      string
 	readOnly  bool
 	waitGroup sync.WaitGroup
+	uploads   backupstorage.UploadCheckpoints
 	ctx       context.Context
 	cancel    context.CancelFunc
 	errors.PerFileErrorRecorder
@@ -236,26 +240,115 @@ func (bh *AZBlobBackupHandle) AddFile(ctx context.Context, filename string, file
 		return nil, err
 	}
 
-	blockBlobURL := containerURL.NewBlockBlobURL(obj)
+	// The files that fit in one part are uploaded at once, which limits the
+	// size of the parts.
+	partSize := int64(min(azBlobBufferSize.Get(), azblob.BlockBlobMaxUploadBlobBytes))
 
 	reader, writer := io.Pipe()
 	bh.waitGroup.Add(1)
 
-	go func() {
+	return backupstorage.NewUploadWriter(writer, func() error {
 		defer bh.waitGroup.Done()
-		_, err := azblob.UploadStreamToBlockBlob(bh.ctx, reader, blockBlobURL, azblob.UploadStreamToBlockBlobOptions{
-			BufferSize: azBlobBufferSize.Get(),
-			MaxBuffers: azBlobParallelism.Get(),
+		err := bh.uploads.Upload(bh.ctx, newAzBlobMultipartUploader(containerURL), obj, reader, backupstorage.UploadParams{
+			Logger:      logutil.NewConsoleLogger(),
+			PartSize:    partSize,
+			Parallelism: azBlobParallelism.Get(),
 		})
 		if err != nil {
 			reader.CloseWithError(err)
 			bh.RecordError(filename, err)
 		}
-	}()
+		return err
+	}), nil
+}
 
-	return writer, nil
+// azBlobBlocks is the part of the Azure Blob Storage API that
+// azBlobMultipartUploader uses, so that it can be tested without a
+// container.
+type azBlobBlocks interface {
+	// upload creates or replaces a block blob with data.
+	upload(ctx context.Context, blob string, data []byte) error
+	// stageBlock uploads a block of a blob, which is not part of the blob
+	// until it is committed.
+	stageBlock(ctx context.Context, blob, id string, data []byte) error
+	// commitBlockList creates or replaces a blob with the blocks of ids, in
+	// order. The blocks of the blob that are not listed are discarded.
+	commitBlockList(ctx context.Context, blob string, ids []string) error
+}
+
+// azBlobContainer implements azBlobBlocks for a container.
+type azBlobContainer struct {
+	containerURL *azblob.ContainerURL
+}
+
+func (c azBlobContainer) upload(ctx context.Context, blob string, data []byte) error {
+	_, err := c.containerURL.NewBlockBlobURL(blob).Upload(ctx, bytes.NewReader(data), azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.AccessTierNone, nil, azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
+	return err
+}
+
+func (c azBlobContainer) stageBlock(ctx context.Context, blob, id string, data []byte) error {
+	_, err := c.containerURL.NewBlockBlobURL(blob).StageBlock(ctx, id, bytes.NewReader(data), azblob.LeaseAccessConditions{}, nil, azblob.ClientProvidedKeyOptions{})
+	return err
 }
 
+func (c azBlobContainer) commitBlockList(ctx context.Context, blob string, ids []string) error {
+	_, err := c.containerURL.NewBlockBlobURL(blob).CommitBlockList(ctx, ids, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.AccessTierNone, nil, azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
+	return err
+}
+
+// azBlobMultipartUploader uploads the files of a backup as block blobs, whose
+// blocks are the parts of the files.
+type azBlobMultipartUploader struct {
+	blobs azBlobBlocks
+}
+
+func newAzBlobMultipartUploader(containerURL *azblob.ContainerURL) *azBlobMultipartUploader {
+	return &azBlobMultipartUploader{blobs: azBlobContainer{containerURL}}
+}
+
+// blockID returns the ID of the block of a part. All the IDs of the blocks
+// of a blob must have the same length.
+func blockID(number int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", number)))
+}
+
+// PutObject implements backupstorage.MultipartUploader.
+func (u *azBlobMultipartUploader) PutObject(ctx context.Context, object string, data []byte) error {
+	return u.blobs.upload(ctx, object, data)
+}
+
+// CreateMultipartUpload implements backupstorage.MultipartUploader. The
+// blocks are staged on the blob itself, so there is no upload ID.
+func (u *azBlobMultipartUploader) CreateMultipartUpload(ctx context.Context, object string) (string, error) {
+	return "", nil
+}
+
+// UploadPart implements backupstorage.MultipartUploader.
+func (u *azBlobMultipartUploader) UploadPart(ctx context.Context, object, uploadID string, number int, data []byte) (string, error) {
+	id := blockID(number)
+	if err := u.blobs.stageBlock(ctx, object, id, data); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// CompleteMultipartUpload implements backupstorage.MultipartUploader.
+func (u *azBlobMultipartUploader) CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []backupstorage.UploadedPart) error {
+	ids := make([]string, 0, len(parts))
+	for _, part := range parts {
+		ids = append(ids, part.ETag)
+	}
+	return u.blobs.commitBlockList(ctx, object, ids)
+}
+
+// AbortMultipartUpload implements backupstorage.MultipartUploader. The
+// service discards the blocks that are not committed after a week.
+func (u *azBlobMultipartUploader) AbortMultipartUpload(ctx context.Context, object, uploadID string) error {
+	return nil
+}
+
+var _ backupstorage.MultipartUploader = (*azBlobMultipartUploader)(nil)
+
 // EndBackup implements BackupHandle.
 func (bh *AZBlobBackupHandle) EndBackup(ctx context.Context) error {
 	if bh.readOnly {
@@ -270,8 +363,18 @@ func (bh *AZBlobBackupHandle) AbortBackup(ctx context.Context) error {
 	if bh.readOnly {
 		return fmt.Errorf("AbortBackup cannot be called on read-only backup")
 	}
-	// Cancel the context of any uploads.
+	// Cancel the context of any uploads, and wait for them to stop, so that
+	// none of their blobs is written once the backup is removed.
 	bh.cancel()
+	bh.waitGroup.Wait()
+
+	containerURL, err := bh.bs.containerURL()
+	if err != nil {
+		return err
+	}
+	if err := bh.uploads.Abort(ctx, newAzBlobMultipartUploader(containerURL)); err != nil {
+		log.Warningf("Cannot abort the uploads of backup %v/%v: %v", bh.dir, bh.name, err)
+	}
 
 	// Remove the backup
 	return bh.bs.RemoveBackup(ctx, bh.dir, bh.name)
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azblobbackupstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// fakeAzBlobBlocks is an in memory container.
+type fakeAzBlobBlocks struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	// staged are the blocks that are not committed yet, by blob and ID.
+	staged map[string]map[string][]byte
+	// stages are the IDs of the staged blocks, in order.
+	stages []string
+	// failStage is the ID of a block whose staging fails once.
+	failStage string
+}
+
+func newFakeAzBlobBlocks() *fakeAzBlobBlocks {
+	return &fakeAzBlobBlocks{
+		blobs:  make(map[string][]byte),
+		staged: make(map[string]map[string][]byte),
+	}
+}
+
+func (f *fakeAzBlobBlocks) upload(ctx context.Context, blob string, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.blobs[blob] = slices.Clone(data)
+	delete(f.staged, blob)
+	return nil
+}
+
+func (f *fakeAzBlobBlocks) stageBlock(ctx context.Context, blob, id string, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id == f.failStage {
+		f.failStage = ""
+		return errors.New("backend error")
+	}
+	if f.staged[blob] == nil {
+		f.staged[blob] = make(map[string][]byte)
+	}
+	f.staged[blob][id] = slices.Clone(data)
+	f.stages = append(f.stages, id)
+	return nil
+}
+
+func (f *fakeAzBlobBlocks) commitBlockList(ctx context.Context, blob string, ids []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var data []byte
+	for _, id := range ids {
+		block, ok := f.staged[blob][id]
+		if !ok {
+			return fmt.Errorf("block %v of %v is not staged", id, blob)
+		}
+		data = append(data, block...)
+	}
+	f.blobs[blob] = data
+	delete(f.staged, blob)
+	return nil
+}
+
+func testData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestBlockID(t *testing.T) {
+	// All the IDs of the blocks of a blob must have the same length.
+	first, last := blockID(1), blockID(50000)
+	assert.Len(t, last, len(first))
+	id, err := base64.StdEncoding.DecodeString(last)
+	require.NoError(t, err)
+	assert.Equal(t, "00050000", string(id))
+}
+
+func TestMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	params := backupstorage.UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 100, Parallelism: 4}
+	blobs := newFakeAzBlobBlocks()
+	u := &azBlobMultipartUploader{blobs: blobs}
+	var uploads backupstorage.UploadCheckpoints
+
+	data := testData(350)
+	require.NoError(t, uploads.Upload(ctx, u, "dir/backup/0", bytes.NewReader(data), params))
+	assert.Equal(t, map[string][]byte{"dir/backup/0": data}, blobs.blobs)
+	assert.ElementsMatch(t, []string{blockID(1), blockID(2), blockID(3), blockID(4)}, blobs.stages)
+	assert.Empty(t, blobs.staged)
+}
+
+func TestMultipartUploadSmallObject(t *testing.T) {
+	ctx := context.Background()
+	params := backupstorage.UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 100, Parallelism: 1}
+	blobs := newFakeAzBlobBlocks()
+	u := &azBlobMultipartUploader{blobs: blobs}
+	var uploads backupstorage.UploadCheckpoints
+
+	data := testData(50)
+	require.NoError(t, uploads.Upload(ctx, u, "dir/backup/0", bytes.NewReader(data), params))
+	assert.Equal(t, map[string][]byte{"dir/backup/0": data}, blobs.blobs)
+	assert.Empty(t, blobs.stages)
+}
+
+func TestMultipartUploadResume(t *testing.T) {
+	defer func(retries int) { backupstorage.UploadRetries = retries }(backupstorage.UploadRetries)
+	backupstorage.UploadRetries = 0
+
+	ctx := context.Background()
+	params := backupstorage.UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 100, Parallelism: 1}
+	blobs := newFakeAzBlobBlocks()
+	blobs.failStage = blockID(3)
+	u := &azBlobMultipartUploader{blobs: blobs}
+	var uploads backupstorage.UploadCheckpoints
+
+	data := testData(450)
+	require.Error(t, uploads.Upload(ctx, u, "dir/backup/0", bytes.NewReader(data), params))
+	assert.Empty(t, blobs.blobs)
+	assert.Equal(t, []string{blockID(1), blockID(2)}, blobs.stages)
+
+	// The second upload only stages the blocks that were not staged yet.
+	blobs.stages = nil
+	require.NoError(t, uploads.Upload(ctx, u, "dir/backup/0", bytes.NewReader(data), params))
+	assert.Equal(t, map[string][]byte{"dir/backup/0": data}, blobs.blobs)
+	assert.Equal(t, []string{blockID(3), blockID(4), blockID(5)}, blobs.stages)
+}
@@ -0,0 +1,667 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/ioutil"
+	"vitess.io/vitess/go/vt/logutil"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Chunked builtin backups split the files of full backups into
+// content-defined chunks. The chunks are stored once per shard, in a chunk
+// store next to the backups of the shard, and are shared by all the chunked
+// backups of the shard: a chunk that did not change since the previous backup
+// is not uploaded again. The MANIFEST lists the chunks of every file.
+//
+// Every chunk is stored as a backup of its own in the chunk store, so that it
+// can be removed with BackupStorage.RemoveBackup. It is named after the
+// SHA-256 of its content, followed by the compression engine if it is
+// compressed. The chunks are reference-counted by the MANIFESTs of the
+// backups of the shard, and RemoveBackup removes the chunks that are not
+// referenced anymore.
+//
+// A chunked backup only references its chunks once its MANIFEST is written,
+// and some backup storages do not list a backup until it has a file. So a
+// chunked backup first writes an in-progress marker, and the chunks are not
+// removed while a backup has the marker but no MANIFEST. The marker holds the
+// time it was written, so that the marker of a backup that never completed
+// stops keeping the chunks once it is older than
+// builtinBackupChunksInProgressTimeout.
+
+const (
+	// backupChunkFileName is the name of the file of a chunk, in its backup.
+	backupChunkFileName = "chunk"
+
+	// backupChunkDirSuffix is appended to the backup directory of a shard to
+	// get the directory of its chunk store.
+	backupChunkDirSuffix = ".chunks"
+
+	// backupChunkInProgressFileName is the name of the in-progress marker of
+	// a chunked backup. It is left in the backup once its MANIFEST is
+	// written.
+	backupChunkInProgressFileName = "CHUNKS_IN_PROGRESS"
+)
+
+var (
+	// builtinBackupChunked enables chunked builtin backups.
+	builtinBackupChunked bool
+
+	// builtinBackupChunkSize is the target size of the chunks.
+	builtinBackupChunkSize uint = 4 * 1024 * 1024
+
+	// builtinBackupChunksInProgressTimeout is the age after which the
+	// in-progress marker of a chunked backup without a MANIFEST is stale: its
+	// backup is assumed to have died, and the marker no longer keeps the
+	// unreferenced chunks from being removed.
+	builtinBackupChunksInProgressTimeout = 48 * time.Hour
+
+	// backupChunkGear is the table of the rolling hash that finds the chunk
+	// boundaries. It must never change, or the chunks of new backups would
+	// not match the chunks of the previous ones.
+	backupChunkGear = func() (gear [256]uint64) {
+		// splitmix64
+		seed := uint64(0x766974657373) // "vitess"
+		for i := range gear {
+			seed += 0x9e3779b97f4a7c15
+			z := seed
+			z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+			z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+			gear[i] = z ^ (z >> 31)
+		}
+		return gear
+	}()
+)
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupChunkFlags)
+	}
+}
+
+func registerBackupChunkFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&builtinBackupChunked, "builtinbackup-chunked", builtinBackupChunked, "split the files of full builtin backups into content-defined chunks, that are stored once per shard and shared by its chunked backups. Chunks that did not change since a previous backup are not uploaded again. Not supported with external compressors or encryption.")
+	fs.UintVar(&builtinBackupChunkSize, "builtinbackup-chunk-size", builtinBackupChunkSize, "the target size in bytes of the chunks of chunked builtin backups. Chunks are between a quarter and four times this size.")
+	fs.DurationVar(&builtinBackupChunksInProgressTimeout, "builtinbackup-chunks-in-progress-timeout", builtinBackupChunksInProgressTimeout, "the time after which a chunked builtin backup that has not written its MANIFEST is assumed to have failed, and no longer keeps the unreferenced chunks from being removed. Must be longer than the longest chunked backup.")
+}
+
+// BackupChunk is a chunk of a file of a chunked builtin backup.
+type BackupChunk struct {
+	// Key is the name of the chunk in the chunk store.
+	Key string
+	// Size is the uncompressed size of the chunk.
+	Size int64
+}
+
+// GetBackupChunkDir returns the directory of the chunk store of a shard.
+func GetBackupChunkDir(keyspace, shard string) string {
+	return GetBackupDir(keyspace, shard) + backupChunkDirSuffix
+}
+
+// backupChunker splits a stream into content-defined chunks: a chunk ends
+// where the rolling hash of its last bytes matches a mask, so that an insertion
+// or a deletion only changes the chunks around it.
+type backupChunker struct {
+	r        io.Reader
+	buf      []byte
+	n        int
+	eof      bool
+	min, max int
+	mask     uint64
+}
+
+func newBackupChunker(r io.Reader, size int) *backupChunker {
+	size = max(size, 64)
+	// The mask keeps the top bits of the hash, which depend on the most
+	// bytes.
+	maskBits := bits.Len(uint(size)) - 1
+	return &backupChunker{
+		r:    r,
+		buf:  make([]byte, 4*size),
+		min:  size / 4,
+		max:  4 * size,
+		mask: ^uint64(0) << (64 - maskBits),
+	}
+}
+
+// next returns the next chunk, or io.EOF at the end of the stream.
+func (c *backupChunker) next() ([]byte, error) {
+	if !c.eof && c.n < c.max {
+		n, err := io.ReadFull(c.r, c.buf[c.n:])
+		c.n += n
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			c.eof = true
+		case err != nil:
+			return nil, err
+		}
+	}
+	if c.n == 0 {
+		return nil, io.EOF
+	}
+
+	end := c.n
+	if end > c.min {
+		var h uint64
+		for i := c.min; i < c.n; i++ {
+			h = (h << 1) + backupChunkGear[c.buf[i]]
+			if h&c.mask == 0 {
+				end = i + 1
+				break
+			}
+		}
+	}
+
+	chunk := bytes.Clone(c.buf[:end])
+	c.n = copy(c.buf, c.buf[end:c.n])
+	return chunk, nil
+}
+
+// backupChunkStore reads and writes the chunks of the chunk store of a shard.
+type backupChunkStore struct {
+	bs     backupstorage.BackupStorage
+	dir    string
+	logger logutil.Logger
+	// compressionEngine compresses new chunks. They are not compressed if
+	// it is empty.
+	compressionEngine string
+
+	mu sync.Mutex
+	// chunks are the chunks that were in the store when it was opened.
+	chunks map[string]backupstorage.BackupHandle
+	// added are the chunks that were added, or are being added, since then.
+	added map[string]bool
+	// reused are the chunks that were in the store, and are referenced by
+	// the backup being taken.
+	reused map[string]bool
+}
+
+// openBackupChunkStore lists the chunks of the chunk store in the given
+// directory. The backup engine reports the stats of the chunks.
+func openBackupChunkStore(ctx context.Context, logger logutil.Logger, dir string, compressionEngine string) (*backupChunkStore, error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	bs = bs.WithParams(backupstorage.Params{
+		Logger: logger,
+		Stats:  stats.NoStats(),
+	})
+
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		bs.Close()
+		return nil, vterrors.Wrapf(err, "cannot list the chunks of %v", dir)
+	}
+	store := &backupChunkStore{
+		bs:                bs,
+		dir:               dir,
+		logger:            logger,
+		compressionEngine: compressionEngine,
+		chunks:            make(map[string]backupstorage.BackupHandle, len(bhs)),
+		added:             make(map[string]bool),
+		reused:            make(map[string]bool),
+	}
+	for _, bh := range bhs {
+		store.chunks[bh.Name()] = bh
+	}
+	logger.Infof("Found %v chunks in %v", len(store.chunks), dir)
+	return store, nil
+}
+
+func (store *backupChunkStore) close() error {
+	return store.bs.Close()
+}
+
+// backupChunkKey returns the key of a chunk, from its content and the engine
+// that compresses it.
+func backupChunkKey(data []byte, compressionEngine string) string {
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+	if compressionEngine != "" {
+		key += "." + compressionEngine
+	}
+	return key
+}
+
+// put stores a chunk, unless the store already has it. It returns the
+// reference to the chunk, and whether it was uploaded.
+func (store *backupChunkStore) put(ctx context.Context, data []byte) (BackupChunk, bool, error) {
+	chunk := BackupChunk{
+		Key:  backupChunkKey(data, store.compressionEngine),
+		Size: int64(len(data)),
+	}
+
+	store.mu.Lock()
+	if _, ok := store.chunks[chunk.Key]; ok {
+		store.reused[chunk.Key] = true
+		store.mu.Unlock()
+		return chunk, false, nil
+	}
+	if store.added[chunk.Key] {
+		store.mu.Unlock()
+		return chunk, false, nil
+	}
+	store.added[chunk.Key] = true
+	store.mu.Unlock()
+
+	if err := store.upload(ctx, chunk.Key, data); err != nil {
+		store.mu.Lock()
+		delete(store.added, chunk.Key)
+		store.mu.Unlock()
+		return chunk, false, err
+	}
+	return chunk, true, nil
+}
+
+func (store *backupChunkStore) upload(ctx context.Context, key string, data []byte) (finalErr error) {
+	bh, err := store.bs.StartBackup(ctx, store.dir, key)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot start chunk %v", key)
+	}
+	defer func() {
+		if finalErr != nil {
+			if err := bh.AbortBackup(ctx); err != nil {
+				store.logger.Errorf("failed to abort chunk %v: %v", key, err)
+			}
+		}
+	}()
+
+	dest, err := bh.AddFile(ctx, backupChunkFileName, int64(len(data)))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add chunk %v", key)
+	}
+	write := func() error {
+		if store.compressionEngine == "" {
+			_, err := dest.Write(data)
+			return err
+		}
+		compressor, err := newBuiltinCompressor(store.compressionEngine, dest, store.logger)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create compressor")
+		}
+		if _, err := compressor.Write(data); err != nil {
+			compressor.Close()
+			return err
+		}
+		return compressor.Close()
+	}
+	if err := write(); err != nil {
+		dest.Close()
+		return vterrors.Wrapf(err, "cannot write chunk %v", key)
+	}
+	if err := dest.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close chunk %v", key)
+	}
+
+	if err := bh.EndBackup(ctx); err != nil {
+		return vterrors.Wrapf(err, "cannot end chunk %v", key)
+	}
+	return bh.Error()
+}
+
+// get reads a chunk, and checks it against its key.
+func (store *backupChunkStore) get(ctx context.Context, chunk BackupChunk) (data []byte, finalErr error) {
+	store.mu.Lock()
+	bh, ok := store.chunks[chunk.Key]
+	store.mu.Unlock()
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "chunk %v is not in %v", chunk.Key, store.dir)
+	}
+
+	source, err := bh.ReadFile(ctx, backupChunkFileName)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot open chunk %v", chunk.Key)
+	}
+	defer source.Close()
+
+	var reader io.Reader = source
+	hash, compressionEngine, _ := strings.Cut(chunk.Key, ".")
+	if compressionEngine != "" {
+		if compressionEngine == PargzipCompressor {
+			compressionEngine = PgzipCompressor
+		}
+		decompressor, err := newBuiltinDecompressor(compressionEngine, source, store.logger)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "can't create decompressor")
+		}
+		defer func() {
+			if err := decompressor.Close(); err != nil {
+				finalErr = errors.Join(finalErr, vterrors.Wrapf(err, "failed to close decompressor of chunk %v", chunk.Key))
+			}
+		}()
+		reader = decompressor
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, chunk.Size))
+	if _, err := io.Copy(buf, reader); err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read chunk %v", chunk.Key)
+	}
+	data = buf.Bytes()
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != hash {
+		return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "hash mismatch for chunk %v", chunk.Key)
+	}
+	return data, nil
+}
+
+// verify checks that the chunks that the backup added or reuses are still in
+// the store, in case they were removed while the backup was taken.
+func (store *backupChunkStore) verify(ctx context.Context) error {
+	bhs, err := store.bs.ListBackups(ctx, store.dir)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot list the chunks of %v", store.dir)
+	}
+	present := make(map[string]bool, len(bhs))
+	for _, bh := range bhs {
+		present[bh.Name()] = true
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, keys := range []map[string]bool{store.added, store.reused} {
+		for key := range keys {
+			if !present[key] {
+				return vterrors.Errorf(vtrpcpb.Code_ABORTED, "chunk %v was removed from %v during the backup", key, store.dir)
+			}
+		}
+	}
+	return nil
+}
+
+// markBackupChunksInProgress writes the in-progress marker of a chunked
+// backup, which keeps its chunks from being removed before its MANIFEST
+// references them.
+func markBackupChunksInProgress(ctx context.Context, bh backupstorage.BackupHandle) error {
+	data := []byte(time.Now().UTC().Format(time.RFC3339))
+	dest, err := bh.AddFile(ctx, backupChunkInProgressFileName, int64(len(data)))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add %v", backupChunkInProgressFileName)
+	}
+	if _, err := dest.Write(data); err != nil {
+		dest.Close()
+		return vterrors.Wrapf(err, "cannot write %v", backupChunkInProgressFileName)
+	}
+	if err := dest.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close %v", backupChunkInProgressFileName)
+	}
+	return nil
+}
+
+// readBackupChunksInProgress returns the time the in-progress marker of a
+// chunked backup was written, and false if the backup has no marker. The time
+// is zero if the marker cannot be parsed.
+func readBackupChunksInProgress(ctx context.Context, bh backupstorage.BackupHandle) (time.Time, bool) {
+	marker, err := bh.ReadFile(ctx, backupChunkInProgressFileName)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer marker.Close()
+	data, err := io.ReadAll(marker)
+	if err != nil {
+		return time.Time{}, true
+	}
+	startedAt, _ := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	return startedAt, true
+}
+
+// backupFileChunks backs up a file as chunks of the chunk store.
+func (be *BuiltinBackupEngine) backupFileChunks(ctx context.Context, params BackupParams, fe *FileEntry) (finalErr error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	openSourceAt := time.Now()
+	source, err := fe.open(params.Cnf, true)
+	if err != nil {
+		return err
+	}
+	params.Stats.Scope(stats.Operation("Source:Open")).TimedIncrement(time.Since(openSourceAt))
+	defer source.Close()
+
+	fi, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
+	timedSource := ioutil.NewMeteredReadCloser(source, readStats.TimedIncrementBytes)
+
+	retryStr := retryToString(fe.RetryCount)
+	br := newBackupReader(fe.Name, fi.Size(), timedSource)
+	go br.ReportProgress(ctx, builtinBackupProgress, params.Logger, false /*restore*/, retryStr)
+	defer func() {
+		if err := br.Close(finalErr == nil); err != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(err, "failed to close the source reader"))
+		}
+	}()
+
+	params.Logger.Infof("Backing up file in chunks: %v %s", fe.Name, retryStr)
+	store := params.chunkStore
+	chunker := newBackupChunker(br, int(builtinBackupChunkSize))
+	fe.Chunks = nil
+	var uploaded, reused int
+	for {
+		data, err := chunker.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot read %v", fe.Name)
+		}
+
+		putAt := time.Now()
+		chunk, added, err := store.put(ctx, data)
+		if err != nil {
+			return err
+		}
+		if added {
+			uploaded++
+			params.Stats.Scope(stats.Operation("Chunk:Upload")).TimedIncrementBytes(len(data), time.Since(putAt))
+		} else {
+			reused++
+			params.Stats.Scope(stats.Operation("Chunk:Reuse")).TimedIncrementBytes(len(data), time.Since(putAt))
+		}
+		fe.Chunks = append(fe.Chunks, chunk)
+	}
+	params.Logger.Infof("Backed up file %v in %v chunks, %v of them were already stored", fe.Name, uploaded+reused, reused)
+
+	// The hash of a chunked file is the hash of its content.
+	fe.Hash = br.HashString()
+	return nil
+}
+
+// restoreFileChunks restores a file from its chunks.
+func (be *BuiltinBackupEngine) restoreFileChunks(ctx context.Context, params RestoreParams, fe *FileEntry) (finalErr error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	store := params.chunkStore
+	if store == nil {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "the chunk store of %v is not open", fe.Name)
+	}
+
+	openDestAt := time.Now()
+	dest, err := fe.open(params.Cnf, false)
+	if err != nil {
+		return vterrors.Wrap(err, "can't open destination file for writing")
+	}
+	params.Stats.Scope(stats.Operation("Destination:Open")).TimedIncrement(time.Since(openDestAt))
+	defer func() {
+		if cerr := dest.Close(); cerr != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(cerr, "failed to close destination file"))
+		}
+	}()
+
+	writeStats := params.Stats.Scope(stats.Operation("Destination:Write"))
+	timedDest := ioutil.NewMeteredWriter(dest, writeStats.TimedIncrementBytes)
+
+	var size int64
+	for _, chunk := range fe.Chunks {
+		size += chunk.Size
+	}
+	retryStr := retryToString(fe.RetryCount)
+	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, size, timedDest)
+	go bw.ReportProgress(ctx, builtinBackupProgress, params.Logger, true /*restore*/, retryStr)
+
+	copyChunks := func() error {
+		for _, chunk := range fe.Chunks {
+			getAt := time.Now()
+			data, err := store.get(ctx, chunk)
+			if err != nil {
+				return vterrors.Wrapf(err, "cannot restore %v", fe.Name)
+			}
+			params.Stats.Scope(stats.Operation("Chunk:Read")).TimedIncrementBytes(len(data), time.Since(getAt))
+			if _, err := bw.Write(data); err != nil {
+				return vterrors.Wrap(err, "failed to copy file contents")
+			}
+		}
+		return nil
+	}
+	err = copyChunks()
+	if cerr := bw.Close(err == nil); cerr != nil {
+		err = errors.Join(err, vterrors.Wrap(cerr, "failed to flush destination buffer"))
+	}
+	if err != nil {
+		return err
+	}
+
+	if hash := bw.HashString(); hash != fe.Hash {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "hash mismatch for %v, got %v expected %v", fe.Name, hash, fe.Hash)
+	}
+	return nil
+}
+
+// RemoveBackup removes a backup from the BackupStorage. If it is a chunked
+// builtin backup, the chunks that no other backup of the directory references
+// anymore are removed too.
+func RemoveBackup(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, dir, name string) error {
+	var chunkDir string
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return vterrors.Wrap(err, "ListBackups failed")
+	}
+	for _, bh := range bhs {
+		if bh.Name() != name {
+			continue
+		}
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err == nil {
+			chunkDir = bm.ChunkDirectory
+		}
+		break
+	}
+
+	if err := bs.RemoveBackup(ctx, dir, name); err != nil {
+		return err
+	}
+	if chunkDir == "" {
+		return nil
+	}
+	if err := removeUnreferencedBackupChunks(ctx, logger, bs, dir, chunkDir); err != nil {
+		return vterrors.Wrapf(err, "backup %v/%v was removed, but not its unreferenced chunks", dir, name)
+	}
+	return nil
+}
+
+// removeUnreferencedBackupChunks removes the chunks of the chunk store that
+// the backups of the directory do not reference. It does nothing if the
+// MANIFEST of a backup cannot be read, since the backup may be in progress,
+// and use chunks that it did not reference yet, unless the backup has an
+// in-progress marker that is stale.
+func removeUnreferencedBackupChunks(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, dir, chunkDir string) error {
+	// The chunks are listed before the backups: a chunk that is listed was
+	// uploaded after the in-progress marker of its backup was written, so
+	// the backup is either listed as in progress or references the chunk.
+	chunks, err := bs.ListBackups(ctx, chunkDir)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot list the chunks of %v", chunkDir)
+	}
+
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return vterrors.Wrap(err, "ListBackups failed")
+	}
+	refs := make(map[string]int)
+	for _, bh := range bhs {
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			if startedAt, ok := readBackupChunksInProgress(ctx, bh); ok {
+				// A marker that cannot be parsed is not known to be stale.
+				age := time.Since(startedAt)
+				if startedAt.IsZero() || age < builtinBackupChunksInProgressTimeout {
+					logger.Warningf("Not removing the unreferenced chunks of %v, chunked backup %v/%v is in progress", chunkDir, dir, bh.Name())
+					return nil
+				}
+				logger.Warningf("Ignoring chunked backup %v/%v, it started %v ago and has no MANIFEST: its chunks may be removed", dir, bh.Name(), age.Round(time.Second))
+				continue
+			}
+			logger.Warningf("Not removing the unreferenced chunks of %v, the MANIFEST of backup %v/%v cannot be read: %v", chunkDir, dir, bh.Name(), err)
+			return nil
+		}
+		if bm.ChunkDirectory != chunkDir {
+			continue
+		}
+		for _, fe := range bm.FileEntries {
+			for _, chunk := range fe.Chunks {
+				refs[chunk.Key]++
+			}
+		}
+	}
+
+	var removed int
+	for _, chunk := range chunks {
+		if refs[chunk.Name()] > 0 {
+			continue
+		}
+		if err := bs.RemoveBackup(ctx, chunkDir, chunk.Name()); err != nil {
+			return vterrors.Wrapf(err, "cannot remove chunk %v", chunk.Name())
+		}
+		removed++
+	}
+	logger.Infof("Removed %v unreferenced chunks of %v, %v chunks are left", removed, chunkDir, len(chunks)-removed)
+	return nil
+}
+
+// checkBackupChunkedSupport fails if the configured backup options cannot be
+// used with chunked backups.
+func checkBackupChunkedSupport() error {
+	if ExternalCompressorCmd != "" {
+		return fmt.Errorf("chunked builtin backups do not support external compressors")
+	}
+	if backupEncryptionKeyProvider != "" {
+		return fmt.Errorf("chunked builtin backups do not support encryption")
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func randomTestData(seed uint64, size int) []byte {
+	r := rand.New(rand.NewPCG(seed, seed))
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(r.Uint32())
+	}
+	return data
+}
+
+func chunkForTest(t *testing.T, data []byte, size int) [][]byte {
+	chunker := newBackupChunker(bytes.NewReader(data), size)
+	var chunks [][]byte
+	for {
+		chunk, err := chunker.next()
+		if err == io.EOF {
+			return chunks
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestBackupChunker(t *testing.T) {
+	const size = 16 * 1024
+	data := randomTestData(1, 1024*1024)
+
+	chunks := chunkForTest(t, data, size)
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+	for i, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), 4*size)
+		if i < len(chunks)-1 {
+			assert.Greater(t, len(chunk), size/4)
+		}
+	}
+	// The average size of the chunks is close to the target.
+	assert.InDelta(t, len(data)/size, len(chunks), float64(len(data)/size)/2)
+
+	// Inserting data only changes the chunks around it.
+	modified := slices.Concat(data[:len(data)/2], []byte("some new data"), data[len(data)/2:])
+	modifiedChunks := chunkForTest(t, modified, size)
+	var changed int
+	for _, chunk := range modifiedChunks {
+		if !slices.ContainsFunc(chunks, func(c []byte) bool { return bytes.Equal(c, chunk) }) {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 2)
+
+	assert.Empty(t, chunkForTest(t, nil, size))
+}
+
+func TestChunkedBuiltinBackup(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	defer func(implementation, root string, chunkSize uint) {
+		backupstorage.BackupStorageImplementation = implementation
+		filebackupstorage.FileBackupStorageRoot = root
+		builtinBackupChunkSize = chunkSize
+	}(backupstorage.BackupStorageImplementation, filebackupstorage.FileBackupStorageRoot, builtinBackupChunkSize)
+	backupstorage.BackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+	builtinBackupChunkSize = 16 * 1024
+
+	data := randomTestData(2, 512*1024)
+	require.NoError(t, os.MkdirAll(path.Join(root, "data", "db"), 0755))
+	require.NoError(t, os.WriteFile(path.Join(root, "data", "db", "t1.ibd"), data, 0644))
+	require.NoError(t, os.WriteFile(path.Join(root, "data", "db", "t2.ibd"), nil, 0644))
+
+	chunkDir := GetBackupChunkDir("ks", "0")
+	countChunks := func() int {
+		entries, err := os.ReadDir(path.Join(root, "backups", chunkDir))
+		require.NoError(t, err)
+		return len(entries)
+	}
+
+	be := &BuiltinBackupEngine{}
+	backup := func(name string) []FileEntry {
+		chunkStore, err := openBackupChunkStore(ctx, logutil.NewMemoryLogger(), chunkDir, PgzipCompressor)
+		require.NoError(t, err)
+		defer chunkStore.close()
+
+		require.NoError(t, os.MkdirAll(path.Join(root, "backups", "ks", "0", name), 0755))
+		bh := filebackupstorage.NewBackupHandle(nil, "ks/0", name, false)
+		require.NoError(t, markBackupChunksInProgress(ctx, bh))
+		fes := []FileEntry{{Base: backupData, Name: "db/t1.ibd"}, {Base: backupData, Name: "db/t2.ibd"}}
+		for i := range fes {
+			err := be.backupFile(ctx, BackupParams{
+				Cnf:        &Mycnf{DataDir: path.Join(root, "data")},
+				Logger:     logutil.NewMemoryLogger(),
+				Stats:      backupstats.NewFakeStats(),
+				chunkStore: chunkStore,
+			}, bh, &fes[i], []string{"0", "1"}[i])
+			require.NoError(t, err)
+		}
+		require.NoError(t, chunkStore.verify(ctx))
+
+		manifest, err := json.Marshal(builtinBackupManifest{
+			BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName},
+			FileEntries:    fes,
+			ChunkDirectory: chunkDir,
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path.Join(root, "backups", "ks", "0", name, backupManifestFileName), manifest, 0644))
+		return fes
+	}
+
+	restore := func(name string, fes []FileEntry) []byte {
+		chunkStore, err := openBackupChunkStore(ctx, logutil.NewMemoryLogger(), chunkDir, "")
+		require.NoError(t, err)
+		defer chunkStore.close()
+
+		bh := filebackupstorage.NewBackupHandle(nil, "ks/0", name, true)
+		for i := range fes {
+			err := be.restoreFile(ctx, RestoreParams{
+				Cnf:        &Mycnf{DataDir: path.Join(root, "restored", name)},
+				Logger:     logutil.NewMemoryLogger(),
+				Stats:      backupstats.NewFakeStats(),
+				chunkStore: chunkStore,
+			}, bh, &fes[i], builtinBackupManifest{ChunkDirectory: chunkDir}, []string{"0", "1"}[i])
+			require.NoError(t, err)
+		}
+		empty, err := os.ReadFile(path.Join(root, "restored", name, "db", "t2.ibd"))
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+		restored, err := os.ReadFile(path.Join(root, "restored", name, "db", "t1.ibd"))
+		require.NoError(t, err)
+		return restored
+	}
+
+	fes1 := backup("backup1")
+	assert.NotEmpty(t, fes1[0].Chunks)
+	assert.Empty(t, fes1[1].Chunks)
+	chunks := countChunks()
+	assert.Equal(t, len(fes1[0].Chunks), chunks)
+
+	// Only the chunks around the change are uploaded again.
+	modified := slices.Concat(data[:len(data)/2], []byte("some new data"), data[len(data)/2:])
+	require.NoError(t, os.WriteFile(path.Join(root, "data", "db", "t1.ibd"), modified, 0644))
+	fes2 := backup("backup2")
+	assert.LessOrEqual(t, countChunks(), chunks+2)
+
+	assert.Equal(t, data, restore("backup1", fes1))
+	assert.Equal(t, modified, restore("backup2", fes2))
+
+	// A corrupted chunk fails the restore.
+	i := slices.IndexFunc(fes1[0].Chunks, func(chunk BackupChunk) bool { return !slices.Contains(fes2[0].Chunks, chunk) })
+	require.NotEqual(t, -1, i)
+	corrupted := filebackupstorage.NewBackupHandle(nil, chunkDir, fes1[0].Chunks[i].Key, false)
+	w, err := corrupted.AddFile(ctx, backupChunkFileName, 0)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("not a chunk"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	chunkStore, err := openBackupChunkStore(ctx, logutil.NewMemoryLogger(), chunkDir, "")
+	require.NoError(t, err)
+	defer chunkStore.close()
+	err = be.restoreFile(ctx, RestoreParams{
+		Cnf:        &Mycnf{DataDir: path.Join(root, "restored", "corrupted")},
+		Logger:     logutil.NewMemoryLogger(),
+		Stats:      backupstats.NewFakeStats(),
+		chunkStore: chunkStore,
+	}, filebackupstorage.NewBackupHandle(nil, "ks/0", "backup1", true), &fes1[0], builtinBackupManifest{ChunkDirectory: chunkDir}, "0")
+	assert.Error(t, err)
+
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+	defer bs.Close()
+
+	// Another backup of the same data does not upload any chunk.
+	chunks = countChunks()
+	backup("backup3")
+	assert.Equal(t, chunks, countChunks())
+
+	// The unreferenced chunks are not removed while a backup is in progress.
+	require.NoError(t, os.MkdirAll(path.Join(root, "backups", "ks", "0", "backup4"), 0755))
+	require.NoError(t, markBackupChunksInProgress(ctx, filebackupstorage.NewBackupHandle(nil, "ks/0", "backup4", false)))
+	require.NoError(t, RemoveBackup(ctx, logutil.NewMemoryLogger(), bs, "ks/0", "backup1"))
+	assert.NoDirExists(t, path.Join(root, "backups", "ks", "0", "backup1"))
+	assert.Equal(t, chunks, countChunks())
+
+	// A backup fails if a chunk it added was removed before its MANIFEST was
+	// written.
+	chunkStore, err = openBackupChunkStore(ctx, logutil.NewMemoryLogger(), chunkDir, "")
+	require.NoError(t, err)
+	defer chunkStore.close()
+	chunk, added, err := chunkStore.put(ctx, []byte("a new chunk"))
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, chunkStore.verify(ctx))
+	require.NoError(t, bs.RemoveBackup(ctx, chunkDir, chunk.Key))
+	assert.ErrorContains(t, chunkStore.verify(ctx), "was removed")
+
+	// The in-progress marker of a backup that never wrote its MANIFEST is
+	// ignored once it is stale, and the chunks that backup2 references are
+	// kept.
+	staleAt := time.Now().Add(-builtinBackupChunksInProgressTimeout - time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, os.WriteFile(path.Join(root, "backups", "ks", "0", "backup4", backupChunkInProgressFileName), []byte(staleAt), 0644))
+	require.NoError(t, RemoveBackup(ctx, logutil.NewMemoryLogger(), bs, "ks/0", "backup3"))
+	assert.Equal(t, len(fes2[0].Chunks), countChunks())
+	assert.Equal(t, modified, restore("backup2", fes2))
+	require.NoError(t, os.RemoveAll(path.Join(root, "backups", "ks", "0", "backup4")))
+
+	require.NoError(t, RemoveBackup(ctx, logutil.NewMemoryLogger(), bs, "ks/0", "backup2"))
+	assert.Zero(t, countChunks())
+}
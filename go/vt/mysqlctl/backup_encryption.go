@@ -0,0 +1,323 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Builtin backups use envelope encryption: every file is encrypted with its
+// own random data key, and the data key is encrypted by a key provider, with
+// a key that never leaves the provider. The encrypted data key and the ID of
+// the provider's key are stored in the MANIFEST, next to the file entry.
+//
+// Files are encrypted between compression and storage with AES-256-GCM, in
+// chunks of backupEncryptionChunkSize bytes so that they can be streamed. The
+// nonce of a chunk is its index, with a flag on the last chunk, so that chunks
+// cannot be reordered, and a truncated file fails to decrypt.
+
+const (
+	// backupEncryptionChunkSize is the size of the plaintext chunks that are
+	// encrypted and authenticated separately.
+	backupEncryptionChunkSize = 64 * 1024
+
+	// backupDataKeySize is the size of the data keys, for AES-256.
+	backupDataKeySize = 32
+)
+
+var (
+	// backupEncryptionKeyProvider is the name of the key provider that
+	// encrypts the data keys of new backups. Backups are not encrypted if empty.
+	backupEncryptionKeyProvider string
+
+	backupKeyProvidersMu sync.Mutex
+	// backupKeyProviderFactories contains the registered key providers.
+	backupKeyProviderFactories = make(map[string]BackupKeyProviderFactory)
+	// backupKeyProviders caches the key providers that were created.
+	backupKeyProviders = make(map[string]BackupKeyProvider)
+)
+
+// BackupKeyProvider encrypts and decrypts the data keys of encrypted backups.
+type BackupKeyProvider interface {
+	// WrapKey encrypts the data key with the active key of the provider. It
+	// returns the ID of that key, which is needed to decrypt the data key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrappedKey []byte, err error)
+
+	// UnwrapKey decrypts a data key that was encrypted with the key of the
+	// given ID.
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// BackupKeyProviderFactory creates a key provider, configured by its flags.
+type BackupKeyProviderFactory func() (BackupKeyProvider, error)
+
+// RegisterBackupKeyProvider registers a key provider under the given name,
+// which can then be used with --backup-encryption-key-provider.
+func RegisterBackupKeyProvider(name string, factory BackupKeyProviderFactory) {
+	backupKeyProvidersMu.Lock()
+	defer backupKeyProvidersMu.Unlock()
+
+	if _, ok := backupKeyProviderFactories[name]; ok {
+		panic(fmt.Sprintf("backup key provider %s is already registered", name))
+	}
+	backupKeyProviderFactories[name] = factory
+}
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupEncryptionFlags)
+	}
+}
+
+func registerBackupEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&backupEncryptionKeyProvider, "backup-encryption-key-provider", backupEncryptionKeyProvider, fmt.Sprintf("key provider used to encrypt the files of builtin backups with AES-256-GCM. Backups are not encrypted when empty. Supported values: %v.", registeredBackupKeyProviders()))
+}
+
+func registeredBackupKeyProviders() []string {
+	backupKeyProvidersMu.Lock()
+	defer backupKeyProvidersMu.Unlock()
+
+	names := make([]string, 0, len(backupKeyProviderFactories))
+	for name := range backupKeyProviderFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getBackupKeyProvider returns the key provider of the given name, creating it
+// the first time it is used.
+func getBackupKeyProvider(name string) (BackupKeyProvider, error) {
+	backupKeyProvidersMu.Lock()
+	defer backupKeyProvidersMu.Unlock()
+
+	if provider, ok := backupKeyProviders[name]; ok {
+		return provider, nil
+	}
+	factory, ok := backupKeyProviderFactories[name]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown backup encryption key provider %q", name)
+	}
+	provider, err := factory()
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot create backup encryption key provider %q", name)
+	}
+	backupKeyProviders[name] = provider
+	return provider, nil
+}
+
+// newBackupDataKey generates a data key for a file of a backup, and encrypts
+// it with the key provider.
+func newBackupDataKey(ctx context.Context, provider BackupKeyProvider) (dataKey []byte, keyID string, wrappedKey []byte, err error) {
+	dataKey = make([]byte, backupDataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, "", nil, vterrors.Wrap(err, "cannot generate data key")
+	}
+	keyID, wrappedKey, err = provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, "", nil, vterrors.Wrap(err, "cannot encrypt data key")
+	}
+	return dataKey, keyID, wrappedKey, nil
+}
+
+// unwrapBackupDataKey decrypts the data key of a file of a backup, with the
+// key provider recorded in the backup's MANIFEST.
+func unwrapBackupDataKey(ctx context.Context, providerName string, fe *FileEntry) ([]byte, error) {
+	if providerName == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "file %v is encrypted but the MANIFEST has no encryption key provider", fe.Name)
+	}
+	provider, err := getBackupKeyProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := provider.UnwrapKey(ctx, fe.EncryptionKeyID, fe.DataKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot decrypt the data key of %v", fe.Name)
+	}
+	return dataKey, nil
+}
+
+// newBackupAEAD returns the AES-256-GCM cipher of the key.
+func newBackupAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != backupDataKeySize {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid key size %d, expected %d", len(key), backupDataKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// backupChunkNonce returns the nonce of a chunk of an encrypted file. Every
+// file has its own data key, so the nonces only need to be unique in a file.
+func backupChunkNonce(nonce []byte, chunk uint64, last bool) []byte {
+	binary.BigEndian.PutUint64(nonce, chunk)
+	nonce[len(nonce)-1] = 0
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// encryptingWriter encrypts what is written to it before writing it to the
+// underlying writer. Close must be called to write the last chunk.
+type encryptingWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	nonce  []byte
+	chunk  uint64
+	buf    []byte
+	sealed []byte
+	closed bool
+}
+
+func newEncryptingWriter(w io.Writer, key []byte) (*encryptingWriter, error) {
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		w:      w,
+		aead:   aead,
+		nonce:  make([]byte, aead.NonceSize()),
+		buf:    make([]byte, 0, backupEncryptionChunkSize),
+		sealed: make([]byte, 0, backupEncryptionChunkSize+aead.Overhead()),
+	}, nil
+}
+
+// Write is part of the io.Writer interface.
+func (ew *encryptingWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("write to closed encrypting writer")
+	}
+	var n int
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data comes, because the
+		// last chunk is sealed differently.
+		if len(ew.buf) == backupEncryptionChunkSize {
+			if err := ew.seal(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(ew.buf[len(ew.buf):cap(ew.buf)], p)
+		ew.buf = ew.buf[:len(ew.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// Close writes the last chunk. It does not close the underlying writer.
+func (ew *encryptingWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	return ew.seal(true)
+}
+
+func (ew *encryptingWriter) seal(last bool) error {
+	ew.sealed = ew.aead.Seal(ew.sealed[:0], backupChunkNonce(ew.nonce, ew.chunk, last), ew.buf, nil)
+	ew.chunk++
+	ew.buf = ew.buf[:0]
+	_, err := ew.w.Write(ew.sealed)
+	return err
+}
+
+// decryptingReader decrypts what it reads from the underlying reader, which
+// was written by an encryptingWriter.
+type decryptingReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	nonce  []byte
+	chunk  uint64
+	sealed []byte
+	plain  []byte
+	done   bool
+}
+
+func newDecryptingReader(r io.Reader, key []byte) (*decryptingReader, error) {
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReader{
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		nonce:  make([]byte, aead.NonceSize()),
+		sealed: make([]byte, backupEncryptionChunkSize+aead.Overhead()),
+	}, nil
+}
+
+// Read is part of the io.Reader interface.
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	for len(dr.plain) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.plain)
+	dr.plain = dr.plain[n:]
+	return n, nil
+}
+
+func (dr *decryptingReader) open() error {
+	n, err := io.ReadFull(dr.r, dr.sealed[:cap(dr.sealed)])
+	var last bool
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		last = true
+	case err != nil:
+		return err
+	default:
+		// A full chunk is the last one if nothing follows it.
+		if _, err := dr.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	plain, err := dr.aead.Open(dr.sealed[:0], backupChunkNonce(dr.nonce, dr.chunk, last), dr.sealed[:n], nil)
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "cannot decrypt chunk %d, the file is corrupted, truncated or was encrypted with another key", dr.chunk)
+	}
+	dr.chunk++
+	dr.plain = plain
+	dr.done = last
+	return nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func newTestBackupKey(t *testing.T) []byte {
+	key := make([]byte, backupDataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func encryptForTest(t *testing.T, key, data []byte) []byte {
+	var buf bytes.Buffer
+	ew, err := newEncryptingWriter(&buf, key)
+	require.NoError(t, err)
+	// Write in uneven pieces to cross the chunk boundaries.
+	for len(data) > 0 {
+		n := min(len(data), 1000)
+		_, err := ew.Write(data[:n])
+		require.NoError(t, err)
+		data = data[n:]
+	}
+	require.NoError(t, ew.Close())
+	return buf.Bytes()
+}
+
+func decryptForTest(key, data []byte) ([]byte, error) {
+	dr, err := newDecryptingReader(iotest.HalfReader(bytes.NewReader(data)), key)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(dr)
+}
+
+func TestBackupEncryption(t *testing.T) {
+	key := newTestBackupKey(t)
+	for _, size := range []int{0, 1, backupEncryptionChunkSize - 1, backupEncryptionChunkSize, backupEncryptionChunkSize + 1, 3*backupEncryptionChunkSize + 5} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := encryptForTest(t, key, data)
+		chunks := size/backupEncryptionChunkSize + 1
+		if size > 0 && size%backupEncryptionChunkSize == 0 {
+			chunks--
+		}
+		assert.Len(t, encrypted, size+chunks*16, "size %d", size)
+
+		decrypted, err := decryptForTest(key, encrypted)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, data, decrypted, "size %d", size)
+
+		_, err = decryptForTest(newTestBackupKey(t), encrypted)
+		assert.Equal(t, vtrpcpb.Code_DATA_LOSS, vterrors.Code(err), "size %d", size)
+
+		tampered := bytes.Clone(encrypted)
+		tampered[len(tampered)/2] ^= 1
+		_, err = decryptForTest(key, tampered)
+		assert.Error(t, err, "size %d", size)
+
+		// Truncated files fail, even at a chunk boundary.
+		_, err = decryptForTest(key, encrypted[:len(encrypted)-1])
+		assert.Error(t, err, "size %d", size)
+		if chunks > 1 {
+			_, err = decryptForTest(key, encrypted[:backupEncryptionChunkSize+16])
+			assert.Error(t, err, "size %d", size)
+		}
+	}
+}
+
+func writeTestBackupKeyFile(t *testing.T, keyFile string, activeKeyID string, keys map[string][]byte) {
+	content := backupKeyFile{ActiveKeyID: activeKeyID, Keys: make(map[string]string)}
+	for id, key := range keys {
+		content.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.Marshal(content)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, data, 0600))
+}
+
+func TestFileBackupKeyProvider(t *testing.T) {
+	ctx := context.Background()
+	keyFile := path.Join(t.TempDir(), "keys.json")
+	key1, key2 := newTestBackupKey(t), newTestBackupKey(t)
+	writeTestBackupKeyFile(t, keyFile, "k1", map[string][]byte{"k1": key1})
+
+	provider := &fileBackupKeyProvider{path: keyFile}
+	dataKey := newTestBackupKey(t)
+	keyID, wrapped, err := provider.WrapKey(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyID)
+	assert.NotContains(t, string(wrapped), string(dataKey))
+
+	// New data keys use the rotated key, and the old keys still decrypt.
+	writeTestBackupKeyFile(t, keyFile, "k2", map[string][]byte{"k1": key1, "k2": key2})
+	keyID2, wrapped2, err := provider.WrapKey(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", keyID2)
+
+	unwrapped, err := provider.UnwrapKey(ctx, "k1", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+	unwrapped, err = provider.UnwrapKey(ctx, "k2", wrapped2)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// The key ID is authenticated.
+	_, err = provider.UnwrapKey(ctx, "k2", wrapped)
+	assert.ErrorContains(t, err, `cannot decrypt data key with key "k2"`)
+
+	writeTestBackupKeyFile(t, keyFile, "k2", map[string][]byte{"k2": key2})
+	_, err = provider.UnwrapKey(ctx, "k1", wrapped)
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+
+	writeTestBackupKeyFile(t, keyFile, "k3", map[string][]byte{"k2": key2})
+	_, _, err = provider.WrapKey(ctx, dataKey)
+	assert.ErrorContains(t, err, `the active key "k3" is not in backup encryption key file`)
+}
+
+func TestBuiltinBackupEncryption(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	keyFile := path.Join(root, "keys.json")
+	writeTestBackupKeyFile(t, keyFile, "k1", map[string][]byte{"k1": newTestBackupKey(t)})
+	defer func(provider, file string) {
+		backupEncryptionKeyProvider, backupEncryptionKeyFile = provider, file
+		delete(backupKeyProviders, fileBackupKeyProviderName)
+	}(backupEncryptionKeyProvider, backupEncryptionKeyFile)
+	backupEncryptionKeyProvider, backupEncryptionKeyFile = fileBackupKeyProviderName, keyFile
+	delete(backupKeyProviders, fileBackupKeyProviderName)
+
+	defer func(root string) {
+		filebackupstorage.FileBackupStorageRoot = root
+	}(filebackupstorage.FileBackupStorageRoot)
+	filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+	require.NoError(t, os.MkdirAll(path.Join(root, "backups", "dir", "name"), 0755))
+
+	data := bytes.Repeat([]byte("some table data "), 10000)
+	require.NoError(t, os.MkdirAll(path.Join(root, "data", "db"), 0755))
+	require.NoError(t, os.WriteFile(path.Join(root, "data", "db", "t.ibd"), data, 0644))
+
+	be := &BuiltinBackupEngine{}
+	fe := &FileEntry{Base: backupData, Name: "db/t.ibd"}
+	bh := filebackupstorage.NewBackupHandle(nil, "dir", "name", false)
+	err := be.backupFile(ctx, BackupParams{
+		Cnf:    &Mycnf{DataDir: path.Join(root, "data")},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, bh, fe, "0")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", fe.EncryptionKeyID)
+	assert.NotEmpty(t, fe.DataKey)
+
+	stored, err := os.ReadFile(path.Join(root, "backups", "dir", "name", "0"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(stored), "some table data")
+
+	// Restores decrypt with the key provider of the MANIFEST, whatever the
+	// provider of new backups is.
+	backupEncryptionKeyProvider = ""
+	bm := builtinBackupManifest{
+		FileEntries:           []FileEntry{*fe},
+		CompressionEngine:     CompressionEngineName,
+		SkipCompress:          !backupStorageCompress,
+		EncryptionKeyProvider: fileBackupKeyProviderName,
+	}
+	bh = filebackupstorage.NewBackupHandle(nil, "dir", "name", true)
+	err = be.restoreFile(ctx, RestoreParams{
+		Cnf:    &Mycnf{DataDir: path.Join(root, "restored")},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, bh, fe, bm, "0")
+	require.NoError(t, err)
+	restored, err := os.ReadFile(path.Join(root, "restored", "db", "t.ibd"))
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+
+	bm.EncryptionKeyProvider = ""
+	err = be.restoreFile(ctx, RestoreParams{
+		Cnf:    &Mycnf{DataDir: path.Join(root, "restored")},
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, bh, fe, bm, "0")
+	assert.ErrorContains(t, err, "the MANIFEST has no encryption key provider")
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const fileBackupKeyProviderName = "file"
+
+// backupEncryptionKeyFile is the path of the key file of the file key provider.
+var backupEncryptionKeyFile string
+
+func init() {
+	RegisterBackupKeyProvider(fileBackupKeyProviderName, newFileBackupKeyProvider)
+
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vtctld", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerFileBackupKeyProviderFlags)
+	}
+}
+
+func registerFileBackupKeyProviderFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&backupEncryptionKeyFile, "backup-encryption-key-file", backupEncryptionKeyFile, `path of the JSON file with the keys of the "file" backup encryption key provider, of the form {"active_key_id": "<id>", "keys": {"<id>": "<base64 encoded 32 byte key>"}}. The file is read on every use, so that keys can be rotated without a restart.`)
+}
+
+// fileBackupKeyProvider is a key provider whose keys are stored in a local
+// file. New data keys are encrypted with the active key, and the older keys
+// must be kept in the file for as long as the backups that use them may have
+// to be restored.
+type fileBackupKeyProvider struct {
+	path string
+}
+
+// backupKeyFile is the content of the key file.
+type backupKeyFile struct {
+	ActiveKeyID string `json:"active_key_id"`
+	// Keys maps the ID of the keys to their base64 encoded value.
+	Keys map[string]string `json:"keys"`
+}
+
+func newFileBackupKeyProvider() (BackupKeyProvider, error) {
+	if backupEncryptionKeyFile == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--backup-encryption-key-file is required by the %q backup encryption key provider", fileBackupKeyProviderName)
+	}
+	p := &fileBackupKeyProvider{path: backupEncryptionKeyFile}
+	// Make sure the file can be used before taking any backup.
+	if _, err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *fileBackupKeyProvider) load() (*backupKeyFile, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot read backup encryption key file")
+	}
+	keys := &backupKeyFile{}
+	if err := json.Unmarshal(data, keys); err != nil {
+		return nil, vterrors.Wrapf(err, "cannot parse backup encryption key file %v", p.path)
+	}
+	if _, ok := keys.Keys[keys.ActiveKeyID]; !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the active key %q is not in backup encryption key file %v", keys.ActiveKeyID, p.path)
+	}
+	return keys, nil
+}
+
+func (p *fileBackupKeyProvider) key(keys *backupKeyFile, keyID string) ([]byte, error) {
+	encoded, ok := keys.Keys[keyID]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "key %q is not in backup encryption key file %v", keyID, p.path)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot decode key %q of backup encryption key file %v", keyID, p.path)
+	}
+	return key, nil
+}
+
+// WrapKey is part of the BackupKeyProvider interface.
+func (p *fileBackupKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	keys, err := p.load()
+	if err != nil {
+		return "", nil, err
+	}
+	key, err := p.key(keys, keys.ActiveKeyID)
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return "", nil, vterrors.Wrapf(err, "invalid key %q", keys.ActiveKeyID)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	// The wrapped key is the nonce followed by the sealed data key. The key
+	// ID is authenticated so that it cannot be swapped.
+	return keys.ActiveKeyID, aead.Seal(nonce, nonce, dataKey, []byte(keys.ActiveKeyID)), nil
+}
+
+// UnwrapKey is part of the BackupKeyProvider interface.
+func (p *fileBackupKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	keys, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	key, err := p.key(keys, keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid key %q", keyID)
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid wrapped data key")
+	}
+	nonce, sealed := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(keyID))
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot decrypt data key with key %q", keyID)
+	}
+	return dataKey, nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const backupRetentionWeek = 7 * 24 * time.Hour
+
+// BackupRetentionParams are the parameters of ApplyBackupRetentionPolicy.
+type BackupRetentionParams struct {
+	Logger logutil.Logger
+	// Keyspace and Shard are used to infer the directory where backups are stored
+	Keyspace string
+	Shard    string
+	// Policy is the retention policy of the keyspace.
+	Policy *topodatapb.BackupRetentionPolicy
+	// DryRun only returns the backups that would be removed.
+	DryRun bool
+	// Now is the time at which the policy is applied.
+	Now time.Time
+}
+
+// retentionBackup is a backup that the retention policy may remove.
+type retentionBackup struct {
+	handle   backupstorage.BackupHandle
+	manifest *BackupManifest
+	time     time.Time
+}
+
+// ValidateBackupRetentionPolicy checks that a retention policy can be applied.
+func ValidateBackupRetentionPolicy(policy *topodatapb.BackupRetentionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	window, _, err := protoutil.DurationFromProto(policy.PointInTimeRecoveryWindow)
+	if err != nil {
+		return vterrors.Wrap(err, "invalid point in time recovery window")
+	}
+	if window < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the point in time recovery window cannot be negative: %v", window)
+	}
+	return nil
+}
+
+// ApplyBackupRetentionPolicy removes the backups of a shard that are not kept
+// by the retention policy, and returns them. Backups without a readable
+// MANIFEST, such as backups in progress, are never removed.
+func ApplyBackupRetentionPolicy(ctx context.Context, params BackupRetentionParams) ([]backupstorage.BackupHandle, error) {
+	if params.Policy == nil {
+		return nil, nil
+	}
+	if err := ValidateBackupRetentionPolicy(params.Policy); err != nil {
+		return nil, err
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	backups := make([]*retentionBackup, 0, len(bhs))
+	for _, bh := range bhs {
+		manifest, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			params.Logger.Warningf("Keeping backup %v/%v, its MANIFEST cannot be read: %v", backupDir, bh.Name(), err)
+			continue
+		}
+		backupTime, err := ParseRFC3339(manifest.BackupTime)
+		if err != nil {
+			parsed, _, _ := ParseBackupName(backupDir, bh.Name())
+			if parsed == nil {
+				params.Logger.Warningf("Keeping backup %v/%v, its time is unknown", backupDir, bh.Name())
+				continue
+			}
+			backupTime = *parsed
+		}
+		backups = append(backups, &retentionBackup{handle: bh, manifest: manifest, time: backupTime})
+	}
+
+	removed := backupsToRemove(params.Policy, backups, params.Now)
+	handles := make([]backupstorage.BackupHandle, 0, len(removed))
+	for _, b := range removed {
+		if params.DryRun {
+			params.Logger.Infof("Dry run: would remove backup %v/%v", backupDir, b.handle.Name())
+		} else {
+			params.Logger.Infof("Removing backup %v/%v", backupDir, b.handle.Name())
+			if err := RemoveBackup(ctx, params.Logger, bs, backupDir, b.handle.Name()); err != nil {
+				return handles, vterrors.Wrapf(err, "cannot remove backup %v/%v", backupDir, b.handle.Name())
+			}
+		}
+		handles = append(handles, b.handle)
+	}
+	return handles, nil
+}
+
+// backupsToRemove returns the backups that the policy does not keep, oldest
+// first.
+func backupsToRemove(policy *topodatapb.BackupRetentionPolicy, backups []*retentionBackup, now time.Time) []*retentionBackup {
+	sort.SliceStable(backups, func(i, j int) bool { return backups[i].time.Before(backups[j].time) })
+
+	var fulls, incrementals []*retentionBackup
+	for _, b := range backups {
+		if b.manifest.Incremental {
+			incrementals = append(incrementals, b)
+		} else {
+			fulls = append(fulls, b)
+		}
+	}
+	if len(fulls) == 0 {
+		// The incremental backups cannot be restored anyway, and it is not
+		// the job of the policy to decide that.
+		return nil
+	}
+
+	keep := make(map[*retentionBackup]bool, len(backups))
+
+	// The most recent full backups.
+	keepFull := max(int(policy.KeepFull), 1)
+	for _, b := range fulls[max(len(fulls)-keepFull, 0):] {
+		keep[b] = true
+	}
+
+	// The most recent full backup of each of the last weeks.
+	for w := range int(policy.KeepWeekly) {
+		end := now.Add(-time.Duration(w) * backupRetentionWeek)
+		start := end.Add(-backupRetentionWeek)
+		for i := len(fulls) - 1; i >= 0; i-- {
+			if !fulls[i].time.Before(start) && fulls[i].time.Before(end) {
+				keep[fulls[i]] = true
+				break
+			}
+		}
+	}
+
+	// The backups needed to restore to any point in time of the window: the
+	// most recent full backup taken before the window, every full backup
+	// taken after it, and every incremental backup that goes beyond it.
+	if window, _, _ := protoutil.DurationFromProto(policy.PointInTimeRecoveryWindow); window > 0 {
+		since := now.Add(-window)
+		base := 0
+		for i, b := range fulls {
+			if !b.time.After(since) {
+				base = i
+			}
+		}
+		for _, b := range fulls[base:] {
+			keep[b] = true
+		}
+		for _, b := range incrementals {
+			if !fulls[base].manifest.Position.AtLeast(b.manifest.Position) {
+				keep[b] = true
+			}
+		}
+	}
+
+	// The backups that the kept incremental backups depend on: the most
+	// recent full backup that they can be applied on, and the incremental
+	// backups in between.
+	for _, inc := range incrementals {
+		if !keep[inc] {
+			continue
+		}
+		var base *retentionBackup
+		for _, b := range fulls {
+			if inc.manifest.FromPosition.AtLeast(b.manifest.Position) {
+				base = b
+			}
+		}
+		if base == nil {
+			// There is no way to tell which full backup the incremental
+			// backup depends on, so keep all the older ones.
+			for _, b := range fulls {
+				if b.time.Before(inc.time) {
+					keep[b] = true
+				}
+			}
+			continue
+		}
+		keep[base] = true
+		for _, b := range incrementals {
+			if !base.manifest.Position.AtLeast(b.manifest.Position) && inc.manifest.FromPosition.AtLeast(b.manifest.Position) {
+				keep[b] = true
+			}
+		}
+	}
+
+	var removed []*retentionBackup
+	for _, b := range backups {
+		if !keep[b] {
+			removed = append(removed, b)
+		}
+	}
+	return removed
+}
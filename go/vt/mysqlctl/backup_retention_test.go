@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const retentionTestUUID = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+
+func retentionTestPosition(t *testing.T, last int) replication.Position {
+	if last == 0 {
+		return replication.Position{}
+	}
+	pos, err := replication.DecodePosition(fmt.Sprintf("MySQL56/%s:1-%d", retentionTestUUID, last))
+	require.NoError(t, err)
+	return pos
+}
+
+// retentionTestBackup describes a backup for the retention tests: a full
+// backup at position 1-to, or an incremental backup from position 1-from to
+// position 1-to.
+type retentionTestBackup struct {
+	name     string
+	age      time.Duration
+	from, to int
+}
+
+func TestBackupsToRemove(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	backups := []retentionTestBackup{
+		{name: "full-60d", age: 60 * day, to: 10},
+		{name: "full-20d", age: 20 * day, to: 20},
+		{name: "full-13d", age: 13 * day, to: 30},
+		{name: "inc-12d", age: 12 * day, from: 30, to: 35},
+		{name: "full-10d", age: 10 * day, to: 40},
+		{name: "inc-9d", age: 9 * day, from: 40, to: 45},
+		{name: "inc-8d", age: 8 * day, from: 45, to: 50},
+		{name: "full-3d", age: 3 * day, to: 60},
+		// Taken from the position of inc-8d rather than full-3d.
+		{name: "inc-2d", age: 2 * day, from: 50, to: 65},
+		{name: "full-1d", age: 1 * day, to: 70},
+		{name: "inc-1h", age: time.Hour, from: 70, to: 75},
+	}
+
+	testcases := []struct {
+		name    string
+		policy  *topodatapb.BackupRetentionPolicy
+		removed []string
+	}{
+		{
+			name:    "the most recent full backup is always kept",
+			policy:  &topodatapb.BackupRetentionPolicy{},
+			removed: []string{"full-60d", "full-20d", "full-13d", "inc-12d", "full-10d", "inc-9d", "inc-8d", "full-3d", "inc-2d", "inc-1h"},
+		},
+		{
+			name:    "keep full",
+			policy:  &topodatapb.BackupRetentionPolicy{KeepFull: 3},
+			removed: []string{"full-60d", "full-20d", "full-13d", "inc-12d", "inc-9d", "inc-8d", "inc-2d", "inc-1h"},
+		},
+		{
+			name:    "keep weekly",
+			policy:  &topodatapb.BackupRetentionPolicy{KeepFull: 1, KeepWeekly: 3},
+			removed: []string{"full-60d", "full-13d", "inc-12d", "inc-9d", "inc-8d", "full-3d", "inc-2d", "inc-1h"},
+		},
+		{
+			name:    "point in time recovery window",
+			policy:  &topodatapb.BackupRetentionPolicy{KeepFull: 1, PointInTimeRecoveryWindow: protoutil.DurationToProto(5 * day)},
+			removed: []string{"full-60d", "full-20d", "full-13d", "inc-12d"},
+		},
+		{
+			name:    "point in time recovery window before all backups",
+			policy:  &topodatapb.BackupRetentionPolicy{KeepFull: 1, PointInTimeRecoveryWindow: protoutil.DurationToProto(100 * day)},
+			removed: nil,
+		},
+		{
+			name:    "a kept incremental backup keeps the backups it depends on",
+			policy:  &topodatapb.BackupRetentionPolicy{KeepFull: 1, PointInTimeRecoveryWindow: protoutil.DurationToProto(2*day + time.Hour)},
+			removed: []string{"full-60d", "full-20d", "full-13d", "inc-12d"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var rbs []*retentionBackup
+			// Out of order, to check that the backups are sorted.
+			for i := len(backups) - 1; i >= 0; i-- {
+				b := backups[i]
+				rbs = append(rbs, &retentionBackup{
+					handle: filebackupstorage.NewBackupHandle(nil, "ks/0", b.name, true),
+					manifest: &BackupManifest{
+						Incremental:  b.from != 0,
+						FromPosition: retentionTestPosition(t, b.from),
+						Position:     retentionTestPosition(t, b.to),
+					},
+					time: now.Add(-b.age),
+				})
+			}
+			var removed []string
+			for _, b := range backupsToRemove(tc.policy, rbs, now) {
+				removed = append(removed, b.handle.Name())
+			}
+			assert.Equal(t, tc.removed, removed)
+		})
+	}
+}
+
+func TestApplyBackupRetentionPolicy(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	defer func(implementation, root string) {
+		backupstorage.BackupStorageImplementation = implementation
+		filebackupstorage.FileBackupStorageRoot = root
+	}(backupstorage.BackupStorageImplementation, filebackupstorage.FileBackupStorageRoot)
+	backupstorage.BackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = root
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"2025-02-01.120000.zone1-0000000101", "2025-02-15.120000.zone1-0000000101", "2025-02-28.120000.zone1-0000000101"} {
+		dir := path.Join(root, "ks", "0", name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		backupTime, _, err := ParseBackupName("ks/0", name)
+		require.NoError(t, err)
+		manifest, err := json.Marshal(BackupManifest{
+			BackupMethod: builtinBackupEngineName,
+			Position:     retentionTestPosition(t, 10*(i+1)),
+			BackupTime:   FormatRFC3339(*backupTime),
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path.Join(dir, backupManifestFileName), manifest, 0644))
+	}
+	// A backup in progress, without a MANIFEST.
+	require.NoError(t, os.MkdirAll(path.Join(root, "ks", "0", "2025-01-01.120000.zone1-0000000101"), 0755))
+
+	apply := func(dryRun bool) []string {
+		removed, err := ApplyBackupRetentionPolicy(ctx, BackupRetentionParams{
+			Logger:   logutil.NewMemoryLogger(),
+			Keyspace: "ks",
+			Shard:    "0",
+			Policy:   &topodatapb.BackupRetentionPolicy{KeepFull: 2},
+			DryRun:   dryRun,
+			Now:      now,
+		})
+		require.NoError(t, err)
+		var names []string
+		for _, bh := range removed {
+			names = append(names, bh.Name())
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"2025-02-01.120000.zone1-0000000101"}, apply(true))
+	assert.DirExists(t, path.Join(root, "ks", "0", "2025-02-01.120000.zone1-0000000101"))
+
+	assert.Equal(t, []string{"2025-02-01.120000.zone1-0000000101"}, apply(false))
+	assert.NoDirExists(t, path.Join(root, "ks", "0", "2025-02-01.120000.zone1-0000000101"))
+	assert.DirExists(t, path.Join(root, "ks", "0", "2025-01-01.120000.zone1-0000000101"))
+
+	assert.Empty(t, apply(false))
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// ValidateBackupParams are the parameters of ValidateBackup.
+type ValidateBackupParams struct {
+	Logger logutil.Logger
+	// Keyspace and Shard are used to infer the directory where backups are stored
+	Keyspace string
+	Shard    string
+	// BackupName is the name of the backup to validate.
+	BackupName string
+	// Concurrency is the number of files that are restored in parallel.
+	Concurrency int
+	// ScratchDir is the directory where the files are restored. They are
+	// deleted as soon as they are checked. The default directory for
+	// temporary files is used if empty.
+	ScratchDir string
+	// Stats let's the validation report detailed timings.
+	Stats backupstats.Stats
+}
+
+// BackupValidation is the result of the validation of a backup.
+type BackupValidation struct {
+	// Engine is the backup engine that created the backup.
+	Engine string
+	// Files are the results of the files of the backup, in the order of the
+	// MANIFEST.
+	Files []FileValidation
+	// Valid is true if every file of the backup was restored and matched the
+	// hash of the MANIFEST.
+	Valid bool
+}
+
+// FileValidation is the result of the validation of a file of a backup.
+type FileValidation struct {
+	Name string
+	// Hash is the hash of the file recorded in the MANIFEST.
+	Hash string
+	// Bytes is the size of the restored file.
+	Bytes int64
+	// Err is the reason why the file could not be restored, or does not
+	// match its hash.
+	Err error
+}
+
+// ValidateBackup restores every file of a backup into a scratch directory, and
+// checks them against the hashes recorded in the MANIFEST. Only the backups of
+// the builtin engine record these hashes.
+//
+// An error is only returned if the backup could not be validated at all. The
+// files that failed to validate are reported in the result.
+func ValidateBackup(ctx context.Context, params ValidateBackupParams) (*BackupValidation, error) {
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+
+	startTs := time.Now()
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	// Scope bsStats to selected storage engine.
+	bsStats := params.Stats.Scope(
+		backupstats.Component(backupstats.BackupStorage),
+		backupstats.Implementation(
+			textutil.Title(backupstorage.BackupStorageImplementation),
+		),
+	)
+	bs = bs.WithParams(backupstorage.Params{
+		Logger: params.Logger,
+		Stats:  bsStats,
+	})
+
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	var bh backupstorage.BackupHandle
+	for _, h := range bhs {
+		if h.Name() == params.BackupName {
+			bh = h
+			break
+		}
+	}
+	if bh == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "backup %v not found in %v", params.BackupName, backupDir)
+	}
+
+	var bm builtinBackupManifest
+	if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+		return nil, err
+	}
+	if bm.BackupMethod != "" && bm.BackupMethod != builtinBackupEngineName {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "backup %v was created with the %q engine, only %q backups can be validated", params.BackupName, bm.BackupMethod, builtinBackupEngineName)
+	}
+
+	be := &BuiltinBackupEngine{}
+	result, err := be.validateFiles(ctx, params, bh, bm)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Stats.Scope(backupstats.Operation("Validate")).TimedIncrement(time.Since(startTs))
+	if !result.Valid {
+		params.Stats.Scope(backupstats.Operation("Validate:Invalid")).TimedIncrement(time.Since(startTs))
+	}
+	return result, nil
+}
+
+// validateFiles restores the files of the backup into a scratch directory, and
+// removes each file once it is checked.
+func (be *BuiltinBackupEngine) validateFiles(ctx context.Context, params ValidateBackupParams, bh backupstorage.BackupHandle, bm builtinBackupManifest) (*BackupValidation, error) {
+	scratchDir, err := os.MkdirTemp(params.ScratchDir, "validate-backup-*")
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot create scratch directory")
+	}
+	defer os.RemoveAll(scratchDir)
+
+	// The files are restored as if the scratch directory were a tablet.
+	cnf := &Mycnf{
+		DataDir:               path.Join(scratchDir, "data"),
+		InnodbDataHomeDir:     path.Join(scratchDir, "innodb", "data"),
+		InnodbLogGroupHomeDir: path.Join(scratchDir, "innodb", "logs"),
+		BinLogPath:            path.Join(scratchDir, "binlogs", "binlog"),
+	}
+	restoreParams := RestoreParams{
+		Cnf:         cnf,
+		Logger:      params.Logger,
+		Concurrency: max(params.Concurrency, 1),
+		Stats: params.Stats.Scope(
+			backupstats.Component(backupstats.BackupEngine),
+			backupstats.Implementation(textutil.Title(builtinBackupEngineName)),
+		),
+	}
+
+	if bm.ChunkDirectory != "" {
+		chunkStore, err := openBackupChunkStore(ctx, params.Logger, bm.ChunkDirectory, "")
+		if err != nil {
+			return nil, err
+		}
+		defer chunkStore.close()
+		restoreParams.chunkStore = chunkStore
+	}
+
+	if bm.CompressionEngine == PargzipCompressor {
+		params.Logger.Warningf(`engine "pargzip" doesn't support decompression, using "pgzip" instead`)
+		bm.CompressionEngine = PgzipCompressor
+	}
+
+	result := &BackupValidation{
+		Engine: builtinBackupEngineName,
+		Files:  make([]FileValidation, len(bm.FileEntries)),
+		Valid:  true,
+	}
+	var mu sync.Mutex
+	g := errgroup.Group{}
+	g.SetLimit(restoreParams.Concurrency)
+	for i := range bm.FileEntries {
+		if bm.FileEntries[i].Name == "" {
+			continue
+		}
+		fe := bm.FileEntries[i]
+		fe.ParentPath = ""
+		g.Go(func() error {
+			fv := FileValidation{Name: fe.Name, Hash: fe.Hash}
+			name := strconv.Itoa(i)
+			params.Logger.Infof("Validating file %v: %v", name, fe.Name)
+			fv.Err = be.restoreFile(ctx, restoreParams, bh, &fe, bm, name)
+
+			if fullPath, err := fe.fullPath(cnf); err == nil {
+				if fi, err := os.Stat(fullPath); err == nil {
+					fv.Bytes = fi.Size()
+				}
+				os.Remove(fullPath)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Files[i] = fv
+			if fv.Err != nil {
+				params.Logger.Errorf("File %v: %v is not valid: %v", name, fe.Name, fv.Err)
+				result.Valid = false
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, vterrors.Wrap(err, "backup validation was interrupted")
+	}
+	result.Files = slices.DeleteFunc(result.Files, func(fv FileValidation) bool { return fv.Name == "" })
+	return result, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func hasOperationScope(fs *backupstats.FakeStats, operation string) bool {
+	return slices.ContainsFunc(fs.ScopeCalls, func(scopes []backupstats.Scope) bool {
+		return slices.Contains(scopes, backupstats.Operation(operation))
+	})
+}
+
+func TestValidateBackup(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	defer func(implementation, root string) {
+		backupstorage.BackupStorageImplementation = implementation
+		filebackupstorage.FileBackupStorageRoot = root
+	}(backupstorage.BackupStorageImplementation, filebackupstorage.FileBackupStorageRoot)
+	backupstorage.BackupStorageImplementation = "file"
+	filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+	require.NoError(t, os.MkdirAll(path.Join(root, "backups", "ks", "0", "backup1"), 0755))
+
+	require.NoError(t, os.MkdirAll(path.Join(root, "data", "db"), 0755))
+	require.NoError(t, os.WriteFile(path.Join(root, "data", "db", "t1.ibd"), []byte("some table data"), 0644))
+	require.NoError(t, os.WriteFile(path.Join(root, "data", "db", "t2.ibd"), []byte("some other table data"), 0644))
+
+	be := &BuiltinBackupEngine{}
+	bh := filebackupstorage.NewBackupHandle(nil, "ks/0", "backup1", false)
+	fes := []FileEntry{{Base: backupData, Name: "db/t1.ibd"}, {Base: backupData, Name: "db/t2.ibd"}}
+	for i := range fes {
+		err := be.backupFile(ctx, BackupParams{
+			Cnf:    &Mycnf{DataDir: path.Join(root, "data")},
+			Logger: logutil.NewMemoryLogger(),
+			Stats:  backupstats.NewFakeStats(),
+		}, bh, &fes[i], []string{"0", "1"}[i])
+		require.NoError(t, err)
+	}
+	manifest, err := json.Marshal(builtinBackupManifest{
+		BackupManifest:    BackupManifest{BackupMethod: builtinBackupEngineName},
+		FileEntries:       fes,
+		CompressionEngine: CompressionEngineName,
+		SkipCompress:      !backupStorageCompress,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(root, "backups", "ks", "0", "backup1", backupManifestFileName), manifest, 0644))
+
+	validate := func(name string) (*BackupValidation, *backupstats.FakeStats, error) {
+		fs := backupstats.NewFakeStats()
+		result, err := ValidateBackup(ctx, ValidateBackupParams{
+			Logger:      logutil.NewMemoryLogger(),
+			Keyspace:    "ks",
+			Shard:       "0",
+			BackupName:  name,
+			Concurrency: 1,
+			ScratchDir:  root,
+			Stats:       fs,
+		})
+		return result, fs, err
+	}
+
+	result, fs, err := validate("backup1")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, builtinBackupEngineName, result.Engine)
+	require.Len(t, result.Files, 2)
+	assert.Equal(t, FileValidation{Name: "db/t1.ibd", Hash: fes[0].Hash, Bytes: 15}, result.Files[0])
+	assert.Equal(t, FileValidation{Name: "db/t2.ibd", Hash: fes[1].Hash, Bytes: 21}, result.Files[1])
+	assert.True(t, hasOperationScope(fs, "Validate"))
+	assert.False(t, hasOperationScope(fs, "Validate:Invalid"))
+
+	// The scratch directory is removed.
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), "validate-backup-")
+	}
+
+	// Corrupt the second file.
+	fes[1].Hash = "bad"
+	manifest, err = json.Marshal(builtinBackupManifest{
+		BackupManifest:    BackupManifest{BackupMethod: builtinBackupEngineName},
+		FileEntries:       fes,
+		CompressionEngine: CompressionEngineName,
+		SkipCompress:      !backupStorageCompress,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(root, "backups", "ks", "0", "backup1", backupManifestFileName), manifest, 0644))
+
+	result, fs, err = validate("backup1")
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Files, 2)
+	assert.NoError(t, result.Files[0].Err)
+	assert.ErrorContains(t, result.Files[1].Err, "hash mismatch for db/t2.ibd")
+	assert.True(t, hasOperationScope(fs, "Validate:Invalid"))
+
+	_, _, err = validate("backup2")
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+
+	manifest, err = json.Marshal(BackupManifest{BackupMethod: "xtrabackup"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(root, "backups", "ks", "0", "backup1", backupManifestFileName), manifest, 0644))
+	_, _, err = validate("backup1")
+	assert.Equal(t, vtrpcpb.Code_UNIMPLEMENTED, vterrors.Code(err))
+}
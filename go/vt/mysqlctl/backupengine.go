@@ -92,6 +92,9 @@ type BackupParams struct {
 	MysqlShutdownTimeout time.Duration
 	// BackupEngine allows us to override which backup engine should be used for a request
 	BackupEngine string
+
+	// chunkStore stores the chunks of the files of chunked builtin backups.
+	chunkStore *backupChunkStore
 }
 
 func (b *BackupParams) Copy() BackupParams {
@@ -150,6 +153,9 @@ type RestoreParams struct {
 	MysqlShutdownTimeout time.Duration
 	// AllowedBackupEngines if present will filter out any backups taken with engines not included in the list
 	AllowedBackupEngines []string
+
+	// chunkStore reads the chunks of the files of chunked builtin backups.
+	chunkStore *backupChunkStore
 }
 
 func (p *RestoreParams) Copy() RestoreParams {
@@ -64,15 +64,19 @@ var (
 
 	labels = []string{"component", "implementation", "operation"}
 
-	registerBackupStats  sync.Once
-	registerRestoreStats sync.Once
-
-	backupBytes       *stats.CountersWithMultiLabels
-	backupCount       *stats.CountersWithMultiLabels
-	backupDurationNs  *stats.CountersWithMultiLabels
-	restoreBytes      *stats.CountersWithMultiLabels
-	restoreCount      *stats.CountersWithMultiLabels
-	restoreDurationNs *stats.CountersWithMultiLabels
+	registerBackupStats   sync.Once
+	registerRestoreStats  sync.Once
+	registerValidateStats sync.Once
+
+	backupBytes        *stats.CountersWithMultiLabels
+	backupCount        *stats.CountersWithMultiLabels
+	backupDurationNs   *stats.CountersWithMultiLabels
+	restoreBytes       *stats.CountersWithMultiLabels
+	restoreCount       *stats.CountersWithMultiLabels
+	restoreDurationNs  *stats.CountersWithMultiLabels
+	validateBytes      *stats.CountersWithMultiLabels
+	validateCount      *stats.CountersWithMultiLabels
+	validateDurationNs *stats.CountersWithMultiLabels
 )
 
 // BackupStats creates a new Stats for backup operations.
@@ -137,6 +141,37 @@ func RestoreStats() Stats {
 	return newScopedStats(restoreBytes, restoreCount, restoreDurationNs, nil)
 }
 
+// ValidateStats creates a new Stats for backup validation operations.
+//
+// It registers the following metrics with the Vitess stats package.
+//
+//   - ValidateBytes: number of bytes processed by an an operation for given
+//     component and implementation.
+//   - ValidateCount: number of times an operation has happened for given
+//     component and implementation.
+//   - ValidateDurationNanoseconds: time spent on an operation for a given
+//     component and implementation.
+func ValidateStats() Stats {
+	registerValidateStats.Do(func() {
+		validateBytes = stats.NewCountersWithMultiLabels(
+			"ValidateBytes",
+			"How many backup validation bytes processed.",
+			labels,
+		)
+		validateCount = stats.NewCountersWithMultiLabels(
+			"ValidateCount",
+			"How many backup validation operations have happened.",
+			labels,
+		)
+		validateDurationNs = stats.NewCountersWithMultiLabels(
+			"ValidateDurationNanoseconds",
+			"How much time has been spent on backup validation operations (in nanoseconds).",
+			labels,
+		)
+	})
+	return newScopedStats(validateBytes, validateCount, validateDurationNs, nil)
+}
+
 // NoStats returns a no-op Stats suitable for tests and for backwards
 // compoatibility.
 func NoStats() Stats {
@@ -47,6 +47,23 @@ func TestRestoreStats(t *testing.T) {
 	require.NotNil(t, restoreDurationNs)
 }
 
+func TestValidateStats(t *testing.T) {
+	require.Nil(t, backupBytes)
+	require.Nil(t, restoreBytes)
+	require.Nil(t, validateBytes)
+	require.Nil(t, validateCount)
+	require.Nil(t, validateDurationNs)
+
+	ValidateStats()
+	defer resetStats()
+
+	require.Nil(t, backupBytes)
+	require.Nil(t, restoreBytes)
+	require.NotNil(t, validateBytes)
+	require.NotNil(t, validateCount)
+	require.NotNil(t, validateDurationNs)
+}
+
 func TestScope(t *testing.T) {
 	bytes := stats.NewCountersWithMultiLabels("TestScopeBytes", "", labels)
 	count := stats.NewCountersWithMultiLabels("TestScopeCount", "", labels)
@@ -179,4 +196,7 @@ func resetStats() {
 	restoreBytes = nil
 	restoreCount = nil
 	restoreDurationNs = nil
+	validateBytes = nil
+	validateCount = nil
+	validateDurationNs = nil
 }
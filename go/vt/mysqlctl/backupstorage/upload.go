@@ -0,0 +1,395 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	// UploadBandwidth is the maximum number of bytes per second that the
+	// process uploads to the backup storage, 0 for no limit.
+	// Exported for test purposes.
+	UploadBandwidth int64
+	// UploadRetries is the number of times a failed upload request is
+	// retried. Exported for test purposes.
+	UploadRetries = 5
+	// UploadRetryDelay is the delay before the first retry of a failed upload
+	// request. It doubles with each retry, up to maxUploadRetryDelay.
+	// Exported for test purposes.
+	UploadRetryDelay = time.Second
+
+	maxUploadRetryDelay = time.Minute
+)
+
+func registerUploadFlags(fs *pflag.FlagSet) {
+	fs.Int64Var(&UploadBandwidth, "backup-storage-upload-bandwidth", UploadBandwidth, "Maximum number of bytes per second uploaded to the backup storage, shared by all the files uploaded concurrently. 0 means no limit.")
+	fs.IntVar(&UploadRetries, "backup-storage-upload-retries", UploadRetries, "Number of times a failed request to upload a part of a file to the backup storage is retried before the file fails.")
+	fs.DurationVar(&UploadRetryDelay, "backup-storage-upload-retry-delay", UploadRetryDelay, "Delay before retrying a failed request to upload to the backup storage, doubled with each retry up to 1m.")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerUploadFlags)
+	servenv.OnParseFor("vttablet", registerUploadFlags)
+}
+
+// MultipartUploader is implemented by the object stores that can upload an
+// object in parts, each of which is retried on its own when it fails.
+type MultipartUploader interface {
+	// PutObject uploads a whole object in one request.
+	PutObject(ctx context.Context, object string, data []byte) error
+
+	// CreateMultipartUpload starts the upload of an object in parts, and
+	// returns the ID of the upload.
+	CreateMultipartUpload(ctx context.Context, object string) (string, error)
+
+	// UploadPart uploads a part of an object, and returns its ETag. Parts
+	// are numbered from 1. Uploading a part again replaces it.
+	UploadPart(ctx context.Context, object, uploadID string, number int, data []byte) (string, error)
+
+	// CompleteMultipartUpload assembles the parts, in order, into the
+	// object. The parts that are not listed are discarded.
+	CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []UploadedPart) error
+
+	// AbortMultipartUpload discards the parts of an upload.
+	AbortMultipartUpload(ctx context.Context, object, uploadID string) error
+}
+
+// UploadedPart is a part of an object that has been uploaded.
+type UploadedPart struct {
+	Number int
+	ETag   string
+
+	// size and checksum tell whether the part needs to be uploaded again
+	// when the upload of the object is resumed.
+	size     int
+	checksum [sha256.Size]byte
+}
+
+// UploadParams are the parameters of UploadCheckpoints.Upload.
+type UploadParams struct {
+	Logger logutil.Logger
+	// PartSize is the size of the parts of the object. Objects that are not
+	// larger are uploaded in one request.
+	PartSize int64
+	// Parallelism is the number of parts of the object that are uploaded
+	// concurrently. Each of them is buffered in memory.
+	Parallelism int
+}
+
+// uploadWriter is the writer of a file that is uploaded in the background.
+type uploadWriter struct {
+	io.WriteCloser
+	done chan struct{}
+	err  error
+}
+
+// NewUploadWriter runs upload in its own goroutine, and returns the writer of
+// the file it uploads: w is the writing end of the pipe that upload reads
+// from. Close closes w, then waits for upload to return, and returns its
+// error. The backup engines upload the files whose Close failed again, which
+// resumes their upload.
+func NewUploadWriter(w io.WriteCloser, upload func() error) io.WriteCloser {
+	uw := &uploadWriter{WriteCloser: w, done: make(chan struct{})}
+	go func() {
+		defer close(uw.done)
+		uw.err = upload()
+	}()
+	return uw
+}
+
+// Close is part of the io.WriteCloser interface.
+func (w *uploadWriter) Close() error {
+	err := w.WriteCloser.Close()
+	<-w.done
+	if w.err != nil {
+		return w.err
+	}
+	return err
+}
+
+// uploadCheckpoint records the parts of an object that have been uploaded.
+type uploadCheckpoint struct {
+	uploadID string
+	// parts is indexed by part number - 1. The parts that have not been
+	// uploaded have a zero Number.
+	parts []UploadedPart
+}
+
+// UploadCheckpoints uploads the files of a backup in parts, and keeps track
+// of the parts of the files whose upload failed. When a file is uploaded
+// again, as the backup engines do for the files that failed, the upload
+// resumes: only the parts that were not uploaded, or whose content changed,
+// are uploaded again.
+//
+// The zero value is ready to use. It is safe to upload different objects
+// from several goroutines.
+type UploadCheckpoints struct {
+	mu          sync.Mutex
+	checkpoints map[string]*uploadCheckpoint
+}
+
+func (c *UploadCheckpoints) take(object string) *uploadCheckpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cp := c.checkpoints[object]
+	delete(c.checkpoints, object)
+	return cp
+}
+
+func (c *UploadCheckpoints) put(object string, cp *uploadCheckpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checkpoints == nil {
+		c.checkpoints = make(map[string]*uploadCheckpoint)
+	}
+	c.checkpoints[object] = cp
+}
+
+// Upload reads r until EOF and uploads it to object. Each request is retried
+// with backoff, and waits for its share of the upload bandwidth.
+//
+// If the upload fails after some of the parts have been uploaded, they are
+// kept so that the next upload of the object resumes from them.
+func (c *UploadCheckpoints) Upload(ctx context.Context, store MultipartUploader, object string, r io.Reader, params UploadParams) error {
+	data := make([]byte, params.PartSize)
+	n, err := io.ReadFull(r, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	cp := c.take(object)
+
+	if err != nil {
+		// The object fits in one part.
+		if cp != nil {
+			if err := store.AbortMultipartUpload(ctx, object, cp.uploadID); err != nil {
+				params.Logger.Warningf("Cannot abort the previous upload of %v: %v", object, err)
+			}
+		}
+		return retryUpload(ctx, params.Logger, "upload of "+object, func() error {
+			if err := waitUploadBandwidth(ctx, n); err != nil {
+				return err
+			}
+			return store.PutObject(ctx, object, data[:n])
+		})
+	}
+
+	if cp == nil {
+		cp = &uploadCheckpoint{}
+		err := retryUpload(ctx, params.Logger, "start of the upload of "+object, func() error {
+			var err error
+			cp.uploadID, err = store.CreateMultipartUpload(ctx, object)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	count, err := uploadParts(ctx, store, object, cp, data, r, params)
+	if err != nil {
+		c.put(object, cp)
+		return err
+	}
+
+	parts := cp.parts[:count]
+	err = retryUpload(ctx, params.Logger, "completion of the upload of "+object, func() error {
+		return store.CompleteMultipartUpload(ctx, object, cp.uploadID, parts)
+	})
+	if err != nil {
+		// The upload may or may not have been completed, so it cannot be
+		// resumed.
+		if err := store.AbortMultipartUpload(ctx, object, cp.uploadID); err != nil {
+			params.Logger.Warningf("Cannot abort the upload of %v: %v", object, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// uploadParts uploads the parts of the object that are not in the checkpoint
+// yet, starting with first, and returns the number of parts of the object.
+func uploadParts(ctx context.Context, store MultipartUploader, object string, cp *uploadCheckpoint, first []byte, r io.Reader, params UploadParams) (int, error) {
+	eg, egctx := errgroup.WithContext(ctx)
+	eg.SetLimit(max(params.Parallelism, 1))
+
+	var (
+		mu      sync.Mutex
+		readErr error
+		resumed int
+		number  int
+	)
+	for data := first; data != nil && egctx.Err() == nil; {
+		number++
+		part := UploadedPart{Number: number, size: len(data), checksum: sha256.Sum256(data)}
+
+		mu.Lock()
+		if number <= len(cp.parts) {
+			if prev := cp.parts[number-1]; prev.Number != 0 && prev.size == part.size && prev.checksum == part.checksum {
+				mu.Unlock()
+				resumed++
+				data, readErr = readPart(r, params.PartSize, len(data))
+				continue
+			}
+			// The part is about to be replaced.
+			cp.parts[number-1] = UploadedPart{}
+		}
+		mu.Unlock()
+
+		partData := data
+		eg.Go(func() error {
+			// Another part may have failed while this one was waiting.
+			if err := egctx.Err(); err != nil {
+				return err
+			}
+			err := retryUpload(egctx, params.Logger, fmt.Sprintf("upload of part %d of %v", part.Number, object), func() error {
+				if err := waitUploadBandwidth(egctx, len(partData)); err != nil {
+					return err
+				}
+				var err error
+				part.ETag, err = store.UploadPart(egctx, object, cp.uploadID, part.Number, partData)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for len(cp.parts) < part.Number {
+				cp.parts = append(cp.parts, UploadedPart{})
+			}
+			cp.parts[part.Number-1] = part
+			return nil
+		})
+		data, readErr = readPart(r, params.PartSize, len(data))
+	}
+	err := eg.Wait()
+	if readErr != nil {
+		return 0, readErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if resumed > 0 {
+		params.Logger.Infof("Resumed the upload of %v: %d of its %d parts were already uploaded", object, resumed, number)
+	}
+	return number, nil
+}
+
+// readPart reads the next part of an object, after a part of size previous.
+// It returns nil at the end of the object.
+func readPart(r io.Reader, partSize int64, previous int) ([]byte, error) {
+	if int64(previous) < partSize {
+		// The previous part was the last one.
+		return nil, nil
+	}
+	data := make([]byte, partSize)
+	n, err := io.ReadFull(r, data)
+	switch err {
+	case nil:
+		return data, nil
+	case io.EOF:
+		return nil, nil
+	case io.ErrUnexpectedEOF:
+		return data[:n], nil
+	default:
+		return nil, err
+	}
+}
+
+// Abort discards the parts of the objects whose upload did not complete.
+func (c *UploadCheckpoints) Abort(ctx context.Context, store MultipartUploader) error {
+	c.mu.Lock()
+	checkpoints := c.checkpoints
+	c.checkpoints = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for object, cp := range checkpoints {
+		if err := store.AbortMultipartUpload(ctx, object, cp.uploadID); err != nil {
+			errs = append(errs, fmt.Errorf("cannot abort the upload of %v: %w", object, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retryUpload calls f until it succeeds, at most UploadRetries + 1 times,
+// waiting longer and longer between the calls.
+func retryUpload(ctx context.Context, logger logutil.Logger, what string, f func() error) error {
+	delay := UploadRetryDelay
+	for retry := 0; ; retry++ {
+		err := f()
+		if err == nil || retry >= UploadRetries || ctx.Err() != nil {
+			return err
+		}
+		logger.Warningf("The %v failed, retrying in %v: %v", what, delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxUploadRetryDelay)
+	}
+}
+
+// uploadLimiter limits the bandwidth of all the uploads of the process. It is
+// created again when UploadBandwidth changes.
+var uploadLimiter struct {
+	mu        sync.Mutex
+	bandwidth int64
+	limiter   *rate.Limiter
+}
+
+// waitUploadBandwidth waits until n bytes can be uploaded.
+func waitUploadBandwidth(ctx context.Context, n int) error {
+	uploadLimiter.mu.Lock()
+	if uploadLimiter.bandwidth != UploadBandwidth {
+		uploadLimiter.bandwidth = UploadBandwidth
+		uploadLimiter.limiter = nil
+		if UploadBandwidth > 0 {
+			uploadLimiter.limiter = rate.NewLimiter(rate.Limit(UploadBandwidth), int(UploadBandwidth))
+		}
+	}
+	limiter := uploadLimiter.limiter
+	uploadLimiter.mu.Unlock()
+
+	if limiter == nil {
+		return nil
+	}
+	// A request cannot wait for more than the burst at once.
+	for n > 0 {
+		burst := min(n, limiter.Burst())
+		if err := limiter.WaitN(ctx, burst); err != nil {
+			return err
+		}
+		n -= burst
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+)
+
+// fakeObjectStore is an in memory object store that fails the requests that
+// fail returns an error for.
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// uploads are the parts of the uploads in progress, by upload ID.
+	uploads map[string]map[int][]byte
+	nextID  int
+	// partUploads counts the successful UploadPart requests, by part number.
+	partUploads map[int]int
+
+	fail func(op string, number int) error
+}
+
+func newFakeObjectStore() *fakeObjectStore {
+	return &fakeObjectStore{
+		objects:     make(map[string][]byte),
+		uploads:     make(map[string]map[int][]byte),
+		partUploads: make(map[int]int),
+	}
+}
+
+func (s *fakeObjectStore) failure(op string, number int) error {
+	if s.fail == nil {
+		return nil
+	}
+	return s.fail(op, number)
+}
+
+func (s *fakeObjectStore) PutObject(ctx context.Context, object string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("PutObject", 0); err != nil {
+		return err
+	}
+	s.objects[object] = slices.Clone(data)
+	return nil
+}
+
+func (s *fakeObjectStore) CreateMultipartUpload(ctx context.Context, object string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("CreateMultipartUpload", 0); err != nil {
+		return "", err
+	}
+	s.nextID++
+	uploadID := fmt.Sprintf("%v-%d", object, s.nextID)
+	s.uploads[uploadID] = make(map[int][]byte)
+	return uploadID, nil
+}
+
+func (s *fakeObjectStore) UploadPart(ctx context.Context, object, uploadID string, number int, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("UploadPart", number); err != nil {
+		return "", err
+	}
+	parts, ok := s.uploads[uploadID]
+	if !ok {
+		return "", fmt.Errorf("no upload %v", uploadID)
+	}
+	parts[number] = slices.Clone(data)
+	s.partUploads[number]++
+	return fmt.Sprintf("etag-%d-%d", number, s.partUploads[number]), nil
+}
+
+func (s *fakeObjectStore) CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []UploadedPart) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("CompleteMultipartUpload", 0); err != nil {
+		return err
+	}
+	uploaded, ok := s.uploads[uploadID]
+	if !ok {
+		return fmt.Errorf("no upload %v", uploadID)
+	}
+	var data []byte
+	for i, part := range parts {
+		if part.Number != i+1 {
+			return fmt.Errorf("part %d is missing", i+1)
+		}
+		if part.ETag != fmt.Sprintf("etag-%d-%d", part.Number, s.partUploads[part.Number]) {
+			return fmt.Errorf("part %d has a wrong ETag: %v", part.Number, part.ETag)
+		}
+		data = append(data, uploaded[part.Number]...)
+	}
+	s.objects[object] = data
+	delete(s.uploads, uploadID)
+	return nil
+}
+
+func (s *fakeObjectStore) AbortMultipartUpload(ctx context.Context, object, uploadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uploads, uploadID)
+	return nil
+}
+
+func setUploadFlagsForTest(t *testing.T, bandwidth int64, retries int) {
+	t.Cleanup(func(bandwidth int64, retries int, delay time.Duration) func() {
+		return func() {
+			UploadBandwidth = bandwidth
+			UploadRetries = retries
+			UploadRetryDelay = delay
+		}
+	}(UploadBandwidth, UploadRetries, UploadRetryDelay))
+	UploadBandwidth = bandwidth
+	UploadRetries = retries
+	UploadRetryDelay = time.Millisecond
+}
+
+func randomUploadData(size int) []byte {
+	data := make([]byte, size)
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range data {
+		data[i] = byte(r.Uint32())
+	}
+	return data
+}
+
+func TestUpload(t *testing.T) {
+	setUploadFlagsForTest(t, 0, 0)
+	ctx := context.Background()
+	params := UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 1024, Parallelism: 3}
+
+	testcases := []struct {
+		name  string
+		size  int
+		parts int
+	}{
+		{name: "empty", size: 0},
+		{name: "smaller than a part", size: 100},
+		{name: "one part", size: 1024, parts: 1},
+		{name: "several parts", size: 10*1024 + 100, parts: 11},
+		{name: "several full parts", size: 8 * 1024, parts: 8},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newFakeObjectStore()
+			var uploads UploadCheckpoints
+			data := randomUploadData(tc.size)
+			require.NoError(t, uploads.Upload(ctx, store, "obj", bytes.NewReader(data), params))
+			assert.Equal(t, data, store.objects["obj"])
+			assert.Len(t, store.partUploads, tc.parts)
+			assert.Empty(t, store.uploads)
+			assert.Empty(t, uploads.checkpoints)
+		})
+	}
+}
+
+func TestUploadRetries(t *testing.T) {
+	setUploadFlagsForTest(t, 0, 2)
+	ctx := context.Background()
+	params := UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 1024, Parallelism: 2}
+	data := randomUploadData(5 * 1024)
+
+	// Each request fails twice before succeeding.
+	store := newFakeObjectStore()
+	failures := make(map[string]int)
+	store.fail = func(op string, number int) error {
+		key := fmt.Sprintf("%v-%d", op, number)
+		if failures[key] < 2 {
+			failures[key]++
+			return errors.New("network blip")
+		}
+		return nil
+	}
+	var uploads UploadCheckpoints
+	require.NoError(t, uploads.Upload(ctx, store, "obj", bytes.NewReader(data), params))
+	assert.Equal(t, data, store.objects["obj"])
+	require.NoError(t, uploads.Upload(ctx, store, "small", bytes.NewReader(data[:10]), params))
+	assert.Equal(t, data[:10], store.objects["small"])
+	assert.Equal(t, map[string]int{
+		"CreateMultipartUpload-0":   2,
+		"UploadPart-1":              2,
+		"UploadPart-2":              2,
+		"UploadPart-3":              2,
+		"UploadPart-4":              2,
+		"UploadPart-5":              2,
+		"CompleteMultipartUpload-0": 2,
+		"PutObject-0":               2,
+	}, failures)
+
+	// A part that keeps failing fails the upload.
+	store = newFakeObjectStore()
+	store.fail = func(op string, number int) error {
+		if number == 3 {
+			return errors.New("part 3 cannot be uploaded")
+		}
+		return nil
+	}
+	err := uploads.Upload(ctx, store, "obj", bytes.NewReader(data), params)
+	assert.ErrorContains(t, err, "part 3 cannot be uploaded")
+	assert.NotContains(t, store.objects, "obj")
+
+	// The upload stops when the context is canceled.
+	ctx, cancel := context.WithCancel(ctx)
+	store = newFakeObjectStore()
+	store.fail = func(op string, number int) error {
+		cancel()
+		return errors.New("canceled")
+	}
+	UploadRetryDelay = time.Hour
+	err = uploads.Upload(ctx, store, "obj2", bytes.NewReader(data), params)
+	assert.ErrorContains(t, err, "canceled")
+}
+
+func TestUploadResume(t *testing.T) {
+	setUploadFlagsForTest(t, 0, 0)
+	ctx := context.Background()
+	params := UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 1024, Parallelism: 1}
+	data := randomUploadData(10*1024 + 100)
+	store := newFakeObjectStore()
+	var uploads UploadCheckpoints
+
+	// The source fails in the middle of part 5.
+	err := uploads.Upload(ctx, store, "obj", io.MultiReader(bytes.NewReader(data[:4*1024+10]), iotest.ErrReader(errors.New("read failed"))), params)
+	assert.ErrorContains(t, err, "read failed")
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1, 4: 1}, store.partUploads)
+	assert.Len(t, store.uploads, 1)
+
+	// Part 8 cannot be uploaded.
+	store.fail = func(op string, number int) error {
+		if number == 8 {
+			return errors.New("part 8 cannot be uploaded")
+		}
+		return nil
+	}
+	err = uploads.Upload(ctx, store, "obj", bytes.NewReader(data), params)
+	assert.ErrorContains(t, err, "part 8 cannot be uploaded")
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1}, store.partUploads)
+
+	// The content of part 2 changed, the upload resumes from part 8.
+	store.fail = nil
+	modified := slices.Clone(data)
+	modified[1024] = ^modified[1024]
+	require.NoError(t, uploads.Upload(ctx, store, "obj", bytes.NewReader(modified), params))
+	assert.Equal(t, modified, store.objects["obj"])
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1, 8: 1, 9: 1, 10: 1, 11: 1}, store.partUploads)
+	assert.Empty(t, store.uploads)
+	assert.Empty(t, uploads.checkpoints)
+
+	// A resumed upload can also end up smaller than a part.
+	err = uploads.Upload(ctx, store, "obj2", io.MultiReader(bytes.NewReader(data[:2048]), iotest.ErrReader(errors.New("read failed"))), params)
+	assert.ErrorContains(t, err, "read failed")
+	require.NoError(t, uploads.Upload(ctx, store, "obj2", bytes.NewReader(data[:10]), params))
+	assert.Equal(t, data[:10], store.objects["obj2"])
+	assert.Empty(t, store.uploads)
+
+	// Abort discards the uploads that did not complete.
+	err = uploads.Upload(ctx, store, "obj3", io.MultiReader(bytes.NewReader(data[:2048]), iotest.ErrReader(errors.New("read failed"))), params)
+	assert.ErrorContains(t, err, "read failed")
+	assert.Len(t, store.uploads, 1)
+	require.NoError(t, uploads.Abort(ctx, store))
+	assert.Empty(t, store.uploads)
+	assert.Empty(t, uploads.checkpoints)
+}
+
+func TestUploadBandwidth(t *testing.T) {
+	const bandwidth = 64 * 1024
+	setUploadFlagsForTest(t, bandwidth, 0)
+	ctx := context.Background()
+	params := UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 4 * 1024, Parallelism: 2}
+	data := randomUploadData(32 * 1024)
+	stores := []*fakeObjectStore{newFakeObjectStore(), newFakeObjectStore(), newFakeObjectStore()}
+	var uploads UploadCheckpoints
+
+	// The bandwidth is shared by the concurrent uploads: the first second of
+	// bandwidth is available right away, and the rest of the data takes half
+	// a second.
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, store := range stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, uploads.Upload(ctx, store, "obj", bytes.NewReader(data), params))
+		}()
+	}
+	wg.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	for _, store := range stores {
+		assert.Equal(t, data, store.objects["obj"])
+	}
+}
+
+func TestUploadWriter(t *testing.T) {
+	setUploadFlagsForTest(t, 0, 0)
+	ctx := context.Background()
+	params := UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 1024, Parallelism: 2}
+	data := randomUploadData(3*1024 + 10)
+	store := newFakeObjectStore()
+	var uploads UploadCheckpoints
+
+	newWriter := func(object string) io.WriteCloser {
+		reader, writer := io.Pipe()
+		return NewUploadWriter(writer, func() error {
+			err := uploads.Upload(ctx, store, object, reader, params)
+			reader.CloseWithError(err)
+			return err
+		})
+	}
+
+	// Close returns once the object is complete.
+	w := newWriter("obj")
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, data, store.objects["obj"])
+
+	// The error of the last request of the upload is returned by Close.
+	store.fail = func(op string, number int) error {
+		if op == "CompleteMultipartUpload" {
+			return errors.New("cannot complete the upload")
+		}
+		return nil
+	}
+	w = newWriter("obj2")
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	assert.ErrorContains(t, w.Close(), "cannot complete the upload")
+	assert.NotContains(t, store.objects, "obj2")
+}
@@ -130,6 +130,16 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// EncryptionKeyProvider is the name of the key provider that encrypted
+	// the data keys of the files, if the backup is encrypted.
+	EncryptionKeyProvider string `json:",omitempty"`
+
+	// ChunkDirectory is the directory of the chunk store that holds the
+	// chunks of the files, if the backup is chunked. The compression fields
+	// do not apply to chunked files: the key of each chunk tells how it is
+	// compressed.
+	ChunkDirectory string `json:",omitempty"`
 }
 
 // FileEntry is one file to backup
@@ -152,6 +162,18 @@ type FileEntry struct {
 	// for writing files in a temporary directory
 	ParentPath string
 
+	// EncryptionKeyID is the ID of the key of the backup's key provider that
+	// encrypted DataKey. It is empty if the file is not encrypted.
+	EncryptionKeyID string `json:",omitempty"`
+
+	// DataKey is the key the file was encrypted with, itself encrypted by
+	// the backup's key provider.
+	DataKey []byte `json:",omitempty"`
+
+	// Chunks are the chunks of the file in the chunk store, in order, if the
+	// backup is chunked. Hash is then the hash of the content of the file.
+	Chunks []BackupChunk `json:",omitempty"`
+
 	// RetryCount specifies how many times we retried restoring/backing up this FileEntry.
 	// If we fail to restore/backup this FileEntry, we will retry up to maxRetriesPerFile times.
 	// Every time the builtin backup engine retries this file, we increment this field by 1.
@@ -223,6 +245,13 @@ func (be *BuiltinBackupEngine) ExecuteBackup(ctx context.Context, params BackupP
 	params.Logger.Infof("Executing Backup at %v for keyspace/shard %v/%v on tablet %v, concurrency: %v, compress: %v, incrementalFromPos: %v",
 		params.BackupTime, params.Keyspace, params.Shard, params.TabletAlias, params.Concurrency, backupStorageCompress, params.IncrementalFromPos)
 
+	if backupEncryptionKeyProvider != "" {
+		// Fail early if the files cannot be encrypted.
+		if _, err := getBackupKeyProvider(backupEncryptionKeyProvider); err != nil {
+			return BackupUnusable, err
+		}
+	}
+
 	if isIncrementalBackup(params) {
 		return be.executeIncrementalBackup(ctx, params, bh)
 	}
@@ -405,6 +434,26 @@ func (be *BuiltinBackupEngine) executeFullBackup(ctx context.Context, params Bac
 		return be.executeIncrementalBackup(ctx, params, bh)
 	}
 
+	// Open the chunk store before mysqld is shut down, if the backup is chunked.
+	if builtinBackupChunked {
+		if err := checkBackupChunkedSupport(); err != nil {
+			return BackupUnusable, err
+		}
+		var compressionEngine string
+		if backupStorageCompress {
+			compressionEngine = CompressionEngineName
+		}
+		chunkStore, err := openBackupChunkStore(ctx, params.Logger, GetBackupChunkDir(params.Keyspace, params.Shard), compressionEngine)
+		if err != nil {
+			return BackupUnusable, err
+		}
+		defer chunkStore.close()
+		params.chunkStore = chunkStore
+		if err := markBackupChunksInProgress(ctx, bh); err != nil {
+			return BackupUnusable, err
+		}
+	}
+
 	// Save initial state so we can restore.
 	replicaStartRequired := false
 	sourceIsPrimary := false
@@ -651,6 +700,13 @@ func (be *BuiltinBackupEngine) backupFiles(
 		}
 	}
 
+	// Make sure that the chunks the backup reuses were not removed meanwhile.
+	if params.chunkStore != nil {
+		if err := params.chunkStore.verify(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Backup the MANIFEST file and apply retry logic.
 	var manifestErr error
 	for currentRetry := 0; currentRetry <= maxRetriesPerFile; currentRetry++ {
@@ -834,6 +890,10 @@ func (bp *backupPipe) ReportProgress(ctx context.Context, period time.Duration,
 
 // backupFile backs up an individual file.
 func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+	if params.chunkStore != nil {
+		return be.backupFileChunks(ctx, params, fe)
+	}
+
 	// We need another context that does not live outside of this function.
 	// Reporting progress, compressing and writing are operations that will be
 	// over by the time we exit this function, they can use this cancelable context.
@@ -865,6 +925,19 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 		return err
 	}
 
+	// Generate the key the file is encrypted with, if necessary.
+	var dataKey []byte
+	if backupEncryptionKeyProvider != "" {
+		provider, err := getBackupKeyProvider(backupEncryptionKeyProvider)
+		if err != nil {
+			return err
+		}
+		dataKey, fe.EncryptionKeyID, fe.DataKey, err = newBackupDataKey(ctx, provider)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot create the encryption key of %v", fe.Name)
+		}
+	}
+
 	retryStr := retryToString(fe.RetryCount)
 	br := newBackupReader(fe.Name, fi.Size(), timedSource)
 	go br.ReportProgress(cancelableCtx, builtinBackupProgress, params.Logger, false /*restore*/, retryStr)
@@ -911,6 +984,26 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 			}
 
 		}()
+		// Create the encryption pipe, if necessary. It sits between the
+		// compressor and the destination, so it is closed after the compressor.
+		if dataKey != nil {
+			encryptor, err := newEncryptingWriter(writer, dataKey)
+			if err != nil {
+				return vterrors.Wrap(err, "can't create encryptor")
+			}
+
+			encryptStats := params.Stats.Scope(stats.Operation("Encryptor:Write"))
+			writer = ioutil.NewMeteredWriter(encryptor, encryptStats.TimedIncrementBytes)
+
+			defer func() {
+				if cerr := encryptor.Close(); cerr != nil {
+					cerr = vterrors.Wrapf(cerr, "failed to close encryptor %v", fe.Name)
+					params.Logger.Error(cerr)
+					createAndCopyErr = errors.Join(createAndCopyErr, cerr)
+				}
+			}()
+		}
+
 		// Create the gzip compression pipe, if necessary.
 		if backupStorageCompress {
 			var compressor io.WriteCloser
@@ -1023,10 +1116,14 @@ func (be *BuiltinBackupEngine) backupManifest(
 			},
 
 			// Builtin-specific fields
-			FileEntries:          fes,
-			SkipCompress:         !backupStorageCompress,
-			CompressionEngine:    CompressionEngineName,
-			ExternalDecompressor: ManifestExternalDecompressorCmd,
+			FileEntries:           fes,
+			SkipCompress:          !backupStorageCompress,
+			CompressionEngine:     CompressionEngineName,
+			ExternalDecompressor:  ManifestExternalDecompressorCmd,
+			EncryptionKeyProvider: backupEncryptionKeyProvider,
+		}
+		if params.chunkStore != nil {
+			bm.ChunkDirectory = params.chunkStore.dir
 		}
 		data, err := json.MarshalIndent(bm, "", "  ")
 		if err != nil {
@@ -1164,6 +1261,15 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}()
 	}
 
+	if bm.ChunkDirectory != "" {
+		chunkStore, err := openBackupChunkStore(ctx, params.Logger, bm.ChunkDirectory, "")
+		if err != nil {
+			return "", err
+		}
+		defer chunkStore.close()
+		params.chunkStore = chunkStore
+	}
+
 	if bm.Incremental {
 		createdDir, err = os.MkdirTemp(builtinIncrementalRestorePath, "restore-incremental-*")
 		if err != nil {
@@ -1181,11 +1287,14 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			}
 			oldFes := fes[fileNb]
 			newFEs[fileNb] = FileEntry{
-				Base:       oldFes.Base,
-				Name:       oldFes.Name,
-				ParentPath: oldFes.ParentPath,
-				Hash:       oldFes.Hash,
-				RetryCount: 1,
+				Base:            oldFes.Base,
+				Name:            oldFes.Name,
+				ParentPath:      oldFes.ParentPath,
+				Hash:            oldFes.Hash,
+				EncryptionKeyID: oldFes.EncryptionKeyID,
+				DataKey:         oldFes.DataKey,
+				Chunks:          oldFes.Chunks,
+				RetryCount:      1,
 			}
 			bh.ResetErrorForFile(file)
 		}
@@ -1241,6 +1350,10 @@ func (be *BuiltinBackupEngine) restoreFileEntries(ctx context.Context, fes []Fil
 
 // restoreFile restores an individual file.
 func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string) (finalErr error) {
+	if bm.ChunkDirectory != "" {
+		return be.restoreFileChunks(ctx, params, fe)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -1293,6 +1406,21 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 
 	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
 
+	// Create the decryptor if needed.
+	if fe.DataKey != nil {
+		dataKey, err := unwrapBackupDataKey(ctx, bm.EncryptionKeyProvider, fe)
+		if err != nil {
+			return err
+		}
+		decryptor, err := newDecryptingReader(reader, dataKey)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create decryptor")
+		}
+
+		decryptStats := params.Stats.Scope(stats.Operation("Decryptor:Read"))
+		reader = ioutil.NewMeteredReader(decryptor, decryptStats.TimedIncrementBytes)
+	}
+
 	// Create the uncompresser if needed.
 	if !bm.SkipCompress {
 		var decompressor io.ReadCloser
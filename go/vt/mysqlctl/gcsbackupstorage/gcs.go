@@ -35,10 +35,24 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl/errors"
 
 	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/servenv"
 )
 
+const (
+	// gcsDefaultPartSize is the size of the parts of the files whose size
+	// is small or unknown.
+	gcsDefaultPartSize = 16 * 1024 * 1024
+	// gcsMaxComposeSources is the maximum number of objects that an object
+	// can be composed from in one request.
+	gcsMaxComposeSources = 32
+	// gcsMaxComponents is the maximum number of components of a composite
+	// object.
+	gcsMaxComponents = 1024
+)
+
 var (
 	// bucket is where the backups will go.
 	bucket string
@@ -66,6 +80,12 @@ type GCSBackupHandle struct {
 	dir      string
 	name     string
 	readOnly bool
+	// waitGroup tracks the files being uploaded.
+	waitGroup sync.WaitGroup
+	uploads   backupstorage.UploadCheckpoints
+	// ctx is the context of the uploads, canceled by AbortBackup.
+	ctx    context.Context
+	cancel context.CancelFunc
 	errors.PerFileErrorRecorder
 }
 
@@ -85,7 +105,27 @@ func (bh *GCSBackupHandle) AddFile(ctx context.Context, filename string, filesiz
 		return nil, fmt.Errorf("AddFile cannot be called on read-only backup")
 	}
 	object := objName(bh.dir, bh.name, filename)
-	return bh.client.Bucket(bucket).Object(object).NewWriter(ctx), nil
+
+	reader, writer := io.Pipe()
+	bh.waitGroup.Add(1)
+
+	return backupstorage.NewUploadWriter(writer, func() error {
+		defer bh.waitGroup.Done()
+		err := bh.uploads.Upload(bh.ctx, bh.multipartUploader(), object, reader, backupstorage.UploadParams{
+			Logger:      logutil.NewConsoleLogger(),
+			PartSize:    gcsPartSize(filesize),
+			Parallelism: 1,
+		})
+		if err != nil {
+			reader.CloseWithError(err)
+			bh.RecordError(filename, err)
+		}
+		return err
+	}), nil
+}
+
+func (bh *GCSBackupHandle) multipartUploader() *gcsMultipartUploader {
+	return &gcsMultipartUploader{objects: gcsBucket{bh.client.Bucket(bucket)}}
 }
 
 // EndBackup implements BackupHandle.
@@ -93,7 +133,11 @@ func (bh *GCSBackupHandle) EndBackup(ctx context.Context) error {
 	if bh.readOnly {
 		return fmt.Errorf("EndBackup cannot be called on read-only backup")
 	}
-	return nil
+	// The parts of the files that failed are kept: the backup engines upload
+	// these files again, which resumes their uploads. AbortBackup deletes
+	// them if the backup fails.
+	bh.waitGroup.Wait()
+	return bh.Error()
 }
 
 // AbortBackup implements BackupHandle.
@@ -101,9 +145,149 @@ func (bh *GCSBackupHandle) AbortBackup(ctx context.Context) error {
 	if bh.readOnly {
 		return fmt.Errorf("AbortBackup cannot be called on read-only backup")
 	}
+	// Stop the uploads, so that none of their objects is written once the
+	// backup is removed.
+	bh.cancel()
+	bh.waitGroup.Wait()
+	if err := bh.uploads.Abort(ctx, bh.multipartUploader()); err != nil {
+		log.Warningf("Cannot delete the parts of backup %v/%v: %v", bh.dir, bh.name, err)
+	}
 	return bh.bs.RemoveBackup(ctx, bh.dir, bh.name)
 }
 
+// gcsPartSize returns the size of the parts of a file, so that the object
+// that is composed from them does not have too many components.
+func gcsPartSize(filesize int64) int64 {
+	// The file size is only an estimate, so leave some room.
+	return max(gcsDefaultPartSize, (filesize+gcsMaxComponents/2-1)/(gcsMaxComponents/2))
+}
+
+// gcsObjects is the part of the Google Cloud Storage API that
+// gcsMultipartUploader uses, so that it can be tested without a bucket.
+type gcsObjects interface {
+	// write creates or replaces an object, and returns its ETag.
+	write(ctx context.Context, object string, data []byte) (string, error)
+	// compose creates or replaces dst with the concatenation of srcs.
+	compose(ctx context.Context, dst string, srcs []string) error
+	// list returns the names of the objects that start with prefix.
+	list(ctx context.Context, prefix string) ([]string, error)
+	// delete removes an object, and returns storage.ErrObjectNotExist if
+	// there is no such object.
+	delete(ctx context.Context, object string) error
+}
+
+// gcsBucket implements gcsObjects for a bucket.
+type gcsBucket struct {
+	bucket *storage.BucketHandle
+}
+
+func (b gcsBucket) write(ctx context.Context, object string, data []byte) (string, error) {
+	w := b.bucket.Object(object).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return w.Attrs().Etag, nil
+}
+
+func (b gcsBucket) compose(ctx context.Context, dst string, srcs []string) error {
+	handles := make([]*storage.ObjectHandle, 0, len(srcs))
+	for _, src := range srcs {
+		handles = append(handles, b.bucket.Object(src))
+	}
+	_, err := b.bucket.Object(dst).ComposerFrom(handles...).Run(ctx)
+	return err
+}
+
+func (b gcsBucket) list(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	it := b.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		obj, err := it.Next()
+		if err == iterator.Done {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, obj.Name)
+	}
+}
+
+func (b gcsBucket) delete(ctx context.Context, object string) error {
+	return b.bucket.Object(object).Delete(ctx)
+}
+
+// gcsMultipartUploader uploads each part of a file as a temporary object, and
+// composes the file from them.
+type gcsMultipartUploader struct {
+	objects gcsObjects
+}
+
+func partObjName(uploadID string, number int) string {
+	return fmt.Sprintf("%s%05d", uploadID, number)
+}
+
+// PutObject implements backupstorage.MultipartUploader.
+func (u *gcsMultipartUploader) PutObject(ctx context.Context, object string, data []byte) error {
+	_, err := u.objects.write(ctx, object, data)
+	return err
+}
+
+// CreateMultipartUpload implements backupstorage.MultipartUploader. The ID
+// of the upload is the prefix of the names of the part objects, which are
+// in the directory of the backup so that they are removed along with it.
+func (u *gcsMultipartUploader) CreateMultipartUpload(ctx context.Context, object string) (string, error) {
+	return object + ".part-", nil
+}
+
+// UploadPart implements backupstorage.MultipartUploader.
+func (u *gcsMultipartUploader) UploadPart(ctx context.Context, object, uploadID string, number int, data []byte) (string, error) {
+	return u.objects.write(ctx, partObjName(uploadID, number), data)
+}
+
+// CompleteMultipartUpload implements backupstorage.MultipartUploader. A
+// compose request has a limited number of sources, so the object is composed
+// from the first parts, and then from itself and the next parts. It starts
+// over when it is retried, so that no part is added twice.
+func (u *gcsMultipartUploader) CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []backupstorage.UploadedPart) error {
+	for i := 0; i < len(parts); {
+		var srcs []string
+		if i > 0 {
+			srcs = append(srcs, object)
+		}
+		for ; i < len(parts) && len(srcs) < gcsMaxComposeSources; i++ {
+			srcs = append(srcs, partObjName(uploadID, parts[i].Number))
+		}
+		if err := u.objects.compose(ctx, object, srcs); err != nil {
+			return err
+		}
+	}
+	if err := u.AbortMultipartUpload(ctx, object, uploadID); err != nil {
+		log.Warningf("Cannot delete the parts of %v: %v", object, err)
+	}
+	return nil
+}
+
+// AbortMultipartUpload implements backupstorage.MultipartUploader.
+func (u *gcsMultipartUploader) AbortMultipartUpload(ctx context.Context, object, uploadID string) error {
+	names, err := u.objects.list(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := u.objects.delete(ctx, name); err != nil && err != storage.ErrObjectNotExist {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ backupstorage.MultipartUploader = (*gcsMultipartUploader)(nil)
+
 // ReadFile implements BackupHandle.
 func (bh *GCSBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
 	if !bh.readOnly {
@@ -185,12 +369,15 @@ func (bs *GCSBackupStorage) StartBackup(ctx context.Context, dir, name string) (
 		return nil, err
 	}
 
+	cancelableCtx, cancel := context.WithCancel(ctx)
 	return &GCSBackupHandle{
 		client:   c,
 		bs:       bs,
 		dir:      dir,
 		name:     name,
 		readOnly: false,
+		ctx:      cancelableCtx,
+		cancel:   cancel,
 	}, nil
 }
 
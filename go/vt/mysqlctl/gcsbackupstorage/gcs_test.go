@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcsbackupstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// fakeGCSObjects is an in memory bucket.
+type fakeGCSObjects struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// composes are the sources of the compose requests.
+	composes [][]string
+	// failCompose is the number of compose requests that fail before they
+	// succeed.
+	failCompose int
+}
+
+func newFakeGCSObjects() *fakeGCSObjects {
+	return &fakeGCSObjects{objects: make(map[string][]byte)}
+}
+
+func (f *fakeGCSObjects) write(ctx context.Context, object string, data []byte) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[object] = slices.Clone(data)
+	return fmt.Sprintf("etag-%s-%d", object, len(data)), nil
+}
+
+func (f *fakeGCSObjects) compose(ctx context.Context, dst string, srcs []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(srcs) > gcsMaxComposeSources {
+		return fmt.Errorf("too many sources: %d", len(srcs))
+	}
+	f.composes = append(f.composes, srcs)
+	if f.failCompose > 0 {
+		f.failCompose--
+		return errors.New("backend error")
+	}
+	var data []byte
+	for _, src := range srcs {
+		srcData, ok := f.objects[src]
+		if !ok {
+			return fmt.Errorf("%v: %w", src, storage.ErrObjectNotExist)
+		}
+		data = append(data, srcData...)
+	}
+	f.objects[dst] = data
+	return nil
+}
+
+func (f *fakeGCSObjects) list(ctx context.Context, prefix string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for name := range f.objects {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func (f *fakeGCSObjects) delete(ctx context.Context, object string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.objects[object]; !ok {
+		return storage.ErrObjectNotExist
+	}
+	delete(f.objects, object)
+	return nil
+}
+
+func testData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestMultipartUploadCompose(t *testing.T) {
+	defer func(retries int, delay time.Duration) {
+		backupstorage.UploadRetries = retries
+		backupstorage.UploadRetryDelay = delay
+	}(backupstorage.UploadRetries, backupstorage.UploadRetryDelay)
+	backupstorage.UploadRetries = 1
+	backupstorage.UploadRetryDelay = time.Millisecond
+
+	ctx := context.Background()
+	params := backupstorage.UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 100, Parallelism: 4}
+
+	testcases := []struct {
+		name        string
+		parts       int
+		failCompose int
+		// composes are the number of sources of each compose request.
+		composes []int
+	}{
+		{name: "one compose", parts: 20, composes: []int{20}},
+		{name: "as many parts as compose sources", parts: gcsMaxComposeSources, composes: []int{32}},
+		{name: "one more part than compose sources", parts: gcsMaxComposeSources + 1, composes: []int{32, 2}},
+		{name: "chained composes", parts: 70, composes: []int{32, 32, 8}},
+		{name: "retried compose starts over", parts: 40, failCompose: 1, composes: []int{32, 32, 9}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects := newFakeGCSObjects()
+			objects.failCompose = tc.failCompose
+			u := &gcsMultipartUploader{objects: objects}
+			var uploads backupstorage.UploadCheckpoints
+
+			data := testData(tc.parts*100 - 10)
+			require.NoError(t, uploads.Upload(ctx, u, "dir/backup/0", bytes.NewReader(data), params))
+			assert.Equal(t, data, objects.objects["dir/backup/0"])
+
+			var composes []int
+			for _, srcs := range objects.composes {
+				composes = append(composes, len(srcs))
+			}
+			assert.Equal(t, tc.composes, composes)
+			// After the first parts, the object is composed from itself and
+			// the next parts.
+			last := objects.composes[len(objects.composes)-1]
+			if len(tc.composes) > 1 {
+				assert.Equal(t, "dir/backup/0", last[0])
+			}
+			assert.Equal(t, partObjName("dir/backup/0.part-", tc.parts), last[len(last)-1])
+			if tc.failCompose > 0 {
+				// The retry starts over from the first parts.
+				assert.Equal(t, objects.composes[0], objects.composes[1])
+			}
+
+			// The parts are deleted once the object is composed.
+			assert.Equal(t, []string{"dir/backup/0"}, slices.Sorted(maps.Keys(objects.objects)))
+		})
+	}
+}
+
+func TestMultipartUploadSmallObject(t *testing.T) {
+	ctx := context.Background()
+	params := backupstorage.UploadParams{Logger: logutil.NewMemoryLogger(), PartSize: 100, Parallelism: 1}
+	objects := newFakeGCSObjects()
+	u := &gcsMultipartUploader{objects: objects}
+	var uploads backupstorage.UploadCheckpoints
+
+	data := testData(50)
+	require.NoError(t, uploads.Upload(ctx, u, "dir/backup/0", bytes.NewReader(data), params))
+	assert.Equal(t, map[string][]byte{"dir/backup/0": data}, objects.objects)
+	assert.Empty(t, objects.composes)
+}
+
+func TestMultipartUploadAbort(t *testing.T) {
+	ctx := context.Background()
+	objects := newFakeGCSObjects()
+	u := &gcsMultipartUploader{objects: objects}
+
+	uploadID, err := u.CreateMultipartUpload(ctx, "dir/backup/1")
+	require.NoError(t, err)
+	for number := 1; number <= 3; number++ {
+		_, err := u.UploadPart(ctx, "dir/backup/1", uploadID, number, testData(10))
+		require.NoError(t, err)
+	}
+	_, err = objects.write(ctx, "dir/backup/10", testData(10))
+	require.NoError(t, err)
+	require.Len(t, objects.objects, 4)
+
+	// Only the parts of the upload are deleted.
+	require.NoError(t, u.AbortMultipartUpload(ctx, "dir/backup/1", uploadID))
+	assert.Equal(t, []string{"dir/backup/10"}, slices.Sorted(maps.Keys(objects.objects)))
+}
@@ -24,6 +24,7 @@ limitations under the License.
 package s3backupstorage
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"crypto/tls"
@@ -157,6 +158,7 @@ type S3BackupHandle struct {
 	name      string
 	readOnly  bool
 	waitGroup sync.WaitGroup
+	uploads   backupstorage.UploadCheckpoints
 	errorsbackup.PerFileErrorRecorder
 }
 
@@ -184,48 +186,30 @@ func (bh *S3BackupHandle) AddFile(ctx context.Context, filename string, filesize
 	bh.bs.params.Logger.Infof("Using S3 upload part size: %s", humanize.IBytes(uint64(partSizeBytes)))
 
 	reader, writer := io.Pipe()
-	bh.handleAddFile(ctx, filename, partSizeBytes, reader, func(err error) {
+	return bh.handleAddFile(ctx, filename, partSizeBytes, reader, writer, func(err error) {
 		reader.CloseWithError(err)
-	})
-
-	return writer, nil
+	}), nil
 }
 
-func (bh *S3BackupHandle) handleAddFile(ctx context.Context, filename string, partSizeBytes int64, reader io.Reader, closer func(error)) {
+// handleAddFile uploads what is written to writer, which reader reads from,
+// and returns the writer of the file.
+func (bh *S3BackupHandle) handleAddFile(ctx context.Context, filename string, partSizeBytes int64, reader io.Reader, writer io.WriteCloser, closer func(error)) io.WriteCloser {
 	bh.waitGroup.Add(1)
 
-	go func() {
+	return backupstorage.NewUploadWriter(writer, func() error {
 		defer bh.waitGroup.Done()
-		uploader := manager.NewUploader(bh.client, func(u *manager.Uploader) {
-			u.PartSize = partSizeBytes
-		})
 		object := objName(bh.dir, bh.name, filename)
-		sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
-		_, err := uploader.Upload(ctx, &s3.PutObjectInput{
-			Bucket:               &bucket,
-			Key:                  &object,
-			Body:                 reader,
-			ServerSideEncryption: bh.bs.s3SSE.awsAlg,
-			SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
-			SSECustomerKey:       bh.bs.s3SSE.customerKey,
-			SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
-		}, func(u *manager.Uploader) {
-			u.ClientOptions = append(u.ClientOptions, func(o *s3.Options) {
-				o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
-					return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("CompleteAttemptMiddleware", func(ctx context.Context, input middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
-						start := time.Now()
-						output, metadata, err := next.HandleFinalize(ctx, input)
-						sendStats.TimedIncrement(time.Since(start))
-						return output, metadata, err
-					}), middleware.Before)
-				})
-			})
+		err := bh.uploads.Upload(ctx, bh.multipartUploader(), object, reader, backupstorage.UploadParams{
+			Logger:      bh.bs.params.Logger,
+			PartSize:    partSizeBytes,
+			Parallelism: manager.DefaultUploadConcurrency,
 		})
 		if err != nil {
 			closer(err)
 			bh.RecordError(filename, err)
 		}
-	}()
+		return err
+	})
 }
 
 // calculateUploadPartSize is a helper to calculate the part size, taking into consideration the minimum part size
@@ -259,6 +243,9 @@ func (bh *S3BackupHandle) EndBackup(ctx context.Context) error {
 	if bh.readOnly {
 		return fmt.Errorf("EndBackup cannot be called on read-only backup")
 	}
+	// The multipart uploads of the files that failed are kept: the backup
+	// engines upload these files again, which resumes them. AbortBackup
+	// aborts them if the backup fails.
 	bh.waitGroup.Wait()
 	return bh.Error()
 }
@@ -268,6 +255,10 @@ func (bh *S3BackupHandle) AbortBackup(ctx context.Context) error {
 	if bh.readOnly {
 		return fmt.Errorf("AbortBackup cannot be called on read-only backup")
 	}
+	bh.waitGroup.Wait()
+	if err := bh.uploads.Abort(ctx, bh.multipartUploader()); err != nil {
+		bh.bs.params.Logger.Warningf("Cannot abort the multipart uploads of backup %v/%v: %v", bh.dir, bh.name, err)
+	}
 	return bh.bs.RemoveBackup(ctx, bh.dir, bh.name)
 }
 
@@ -302,6 +293,113 @@ func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.Rea
 
 var _ backupstorage.BackupHandle = (*S3BackupHandle)(nil)
 
+// s3MultipartUploader uploads the files of a backup with the multipart
+// upload API of S3.
+type s3MultipartUploader struct {
+	bh        *S3BackupHandle
+	sendStats stats.Stats
+}
+
+func (bh *S3BackupHandle) multipartUploader() *s3MultipartUploader {
+	return &s3MultipartUploader{
+		bh:        bh,
+		sendStats: bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send")),
+	}
+}
+
+// timeRequests records the duration of each attempt of a request.
+func (u *s3MultipartUploader) timeRequests(o *s3.Options) {
+	o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("CompleteAttemptMiddleware", func(ctx context.Context, input middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			start := time.Now()
+			output, metadata, err := next.HandleFinalize(ctx, input)
+			u.sendStats.TimedIncrement(time.Since(start))
+			return output, metadata, err
+		}), middleware.Before)
+	})
+}
+
+// PutObject is part of the backupstorage.MultipartUploader interface.
+func (u *s3MultipartUploader) PutObject(ctx context.Context, object string, data []byte) error {
+	_, err := u.bh.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: u.bh.bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	}, u.timeRequests)
+	return err
+}
+
+// CreateMultipartUpload is part of the backupstorage.MultipartUploader interface.
+func (u *s3MultipartUploader) CreateMultipartUpload(ctx context.Context, object string) (string, error) {
+	out, err := u.bh.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		ServerSideEncryption: u.bh.bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	}, u.timeRequests)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+// UploadPart is part of the backupstorage.MultipartUploader interface.
+func (u *s3MultipartUploader) UploadPart(ctx context.Context, object, uploadID string, number int, data []byte) (string, error) {
+	out, err := u.bh.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		UploadId:             &uploadID,
+		PartNumber:           aws.Int32(int32(number)),
+		Body:                 bytes.NewReader(data),
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	}, u.timeRequests)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+// CompleteMultipartUpload is part of the backupstorage.MultipartUploader interface.
+func (u *s3MultipartUploader) CompleteMultipartUpload(ctx context.Context, object, uploadID string, parts []backupstorage.UploadedPart) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(int32(part.Number)),
+		})
+	}
+	_, err := u.bh.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		UploadId:             &uploadID,
+		MultipartUpload:      &types.CompletedMultipartUpload{Parts: completed},
+		SSECustomerAlgorithm: u.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       u.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    u.bh.bs.s3SSE.customerMd5,
+	}, u.timeRequests)
+	return err
+}
+
+// AbortMultipartUpload is part of the backupstorage.MultipartUploader interface.
+func (u *s3MultipartUploader) AbortMultipartUpload(ctx context.Context, object, uploadID string) error {
+	_, err := u.bh.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &bucket,
+		Key:      &object,
+		UploadId: &uploadID,
+	}, u.timeRequests)
+	return err
+}
+
+var _ backupstorage.MultipartUploader = (*s3MultipartUploader)(nil)
+
 type S3ServerSideEncryption struct {
 	awsAlg      types.ServerSideEncryption
 	customerAlg *string
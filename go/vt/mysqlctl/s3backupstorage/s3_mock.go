@@ -174,10 +174,9 @@ func FailFirstWrite(s3bh *S3BackupHandle, ctx context.Context, filename string,
 		r = &failReadPipeReader{PipeReader: reader}
 	}
 
-	s3bh.handleAddFile(ctx, filename, partSizeBytes, r, func(err error) {
+	return s3bh.handleAddFile(ctx, filename, partSizeBytes, r, writer, func(err error) {
 		reader.CloseWithError(err)
-	})
-	return writer, nil
+	}), nil
 }
 
 func FailAllWrites(s3bh *S3BackupHandle, ctx context.Context, filename string, filesize int64, _ bool) (io.WriteCloser, error) {
@@ -193,10 +192,9 @@ func FailAllWrites(s3bh *S3BackupHandle, ctx context.Context, filename string, f
 	reader, writer := io.Pipe()
 	r := &failReadPipeReader{PipeReader: reader}
 
-	s3bh.handleAddFile(ctx, filename, partSizeBytes, r, func(err error) {
+	return s3bh.handleAddFile(ctx, filename, partSizeBytes, r, writer, func(err error) {
 		r.PipeReader.CloseWithError(err)
-	})
-	return writer, nil
+	}), nil
 }
 
 type failRead struct{}
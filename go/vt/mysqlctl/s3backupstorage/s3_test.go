@@ -29,6 +29,9 @@ type s3FakeClient struct {
 	*s3.Client
 	err   error
 	delay time.Duration
+	// failures is the number of PutObject requests that fail before they
+	// succeed.
+	failures int
 }
 
 type fakeClientDo struct {
@@ -62,6 +65,10 @@ func (sfc *s3FakeClient) PutObject(ctx context.Context, in *s3.PutObjectInput, o
 	if sfc.err != nil {
 		return nil, sfc.err
 	}
+	if sfc.failures > 0 {
+		sfc.failures--
+		return nil, errors.New("connection reset by peer")
+	}
 
 	return &s3.PutObjectOutput{
 		ETag: aws.String("fake-etag"),
@@ -69,6 +76,9 @@ func (sfc *s3FakeClient) PutObject(ctx context.Context, in *s3.PutObjectInput, o
 }
 
 func TestAddFileError(t *testing.T) {
+	defer func(retries int) { backupstorage.UploadRetries = retries }(backupstorage.UploadRetries)
+	backupstorage.UploadRetries = 0
+
 	bh := &S3BackupHandle{
 		client: &s3FakeClient{err: errors.New("some error")},
 		bs: &S3BackupStorage{
@@ -90,9 +100,7 @@ func TestAddFileError(t *testing.T) {
 	require.NoErrorf(t, err, "TestAddFile() could not write to uploader, got %d bytes written, err %s", n, err)
 
 	err = wc.Close()
-	require.NoErrorf(t, err, "TestAddFile() could not close writer, got %s", err)
-
-	bh.waitGroup.Wait() // wait for the goroutine to finish, at which point it should have recorded an error
+	require.ErrorContains(t, err, "some error", "Close() expected to return the error of the upload")
 
 	require.True(t, bh.HasErrors(), "AddFile() expected bh to record async error but did not")
 }
@@ -144,6 +152,9 @@ func TestAddFileStats(t *testing.T) {
 }
 
 func TestAddFileErrorStats(t *testing.T) {
+	defer func(retries int) { backupstorage.UploadRetries = retries }(backupstorage.UploadRetries)
+	backupstorage.UploadRetries = 0
+
 	fakeStats := stats.NewFakeStats()
 
 	delay := 10 * time.Millisecond
@@ -175,11 +186,9 @@ func TestAddFileErrorStats(t *testing.T) {
 	require.NoErrorf(t, err, "TestAddFile() could not write to uploader, got %d bytes written, err %s", n, err)
 
 	err = wc.Close()
-	require.NoErrorf(t, err, "TestAddFile() could not close writer, got %s", err)
-
-	bh.waitGroup.Wait() // wait for the goroutine to finish, at which point it should have recorded an error
+	require.ErrorContains(t, err, "some error", "Close() expected to return the error of the upload")
 
-	require.True(t, bh.HasErrors(), "AddFile() expected bh not to record async errors but did")
+	require.True(t, bh.HasErrors(), "AddFile() expected bh to record async errors but did not")
 
 	require.Len(t, fakeStats.ScopeCalls, 1)
 	scopedStats := fakeStats.ScopeReturns[0]
@@ -190,6 +199,40 @@ func TestAddFileErrorStats(t *testing.T) {
 	require.Len(t, scopedStats.TimedIncrementBytesCalls, 0)
 }
 
+func TestAddFileRetry(t *testing.T) {
+	defer func(delay time.Duration) { backupstorage.UploadRetryDelay = delay }(backupstorage.UploadRetryDelay)
+	backupstorage.UploadRetryDelay = time.Millisecond
+
+	fakeStats := stats.NewFakeStats()
+	bh := &S3BackupHandle{
+		client: &s3FakeClient{failures: 2},
+		bs: &S3BackupStorage{
+			params: backupstorage.Params{
+				Logger: logutil.NewMemoryLogger(),
+				Stats:  fakeStats,
+			},
+			s3SSE: S3ServerSideEncryption{
+				customerAlg: new(string),
+				customerKey: new(string),
+				customerMd5: new(string),
+			},
+		},
+		readOnly: false,
+	}
+
+	wc, err := bh.AddFile(context.Background(), "somefile", 100000)
+	require.NoError(t, err)
+	_, err = wc.Write([]byte("here are some bytes"))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+
+	bh.waitGroup.Wait()
+
+	require.False(t, bh.HasErrors(), "AddFile() expected the failed requests to be retried")
+	require.Len(t, fakeStats.ScopeCalls, 1)
+	require.Len(t, fakeStats.ScopeReturns[0].TimedIncrementCalls, 3)
+}
+
 func TestNoSSE(t *testing.T) {
 	sseData := S3ServerSideEncryption{}
 	err := sseData.init()
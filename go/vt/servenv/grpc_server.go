@@ -146,6 +146,7 @@ func RegisterGRPCServerFlags() {
 		fs.StringVar(&gRPCServerCA, "grpc_server_ca", gRPCServerCA, "path to server CA in PEM format, which will be combine with server cert, return full certificate chain to clients")
 		fs.DurationVar(&gRPCKeepaliveTime, "grpc_server_keepalive_time", gRPCKeepaliveTime, "After a duration of this time, if the server doesn't see any activity, it pings the client to see if the transport is still alive.")
 		fs.DurationVar(&gRPCKeepaliveTimeout, "grpc_server_keepalive_timeout", gRPCKeepaliveTimeout, "After having pinged for keepalive check, the server waits for a duration of Timeout and if no activity is seen even after that the connection is closed.")
+		fs.BoolVar(&gRPCProxyProtocol, "grpc-proxy-protocol", gRPCProxyProtocol, "Enable HAProxy PROXY protocol (v1 and v2) on gRPC listener socket")
 	})
 }
 
@@ -300,7 +301,7 @@ func serveGRPC() {
 
 	// listen on the port
 	log.Infof("Listening for gRPC calls on port %v", gRPCPort)
-	listener, err := net.Listen("tcp", net.JoinHostPort(gRPCBindAddress, strconv.Itoa(gRPCPort)))
+	listener, err := listenTCP(net.JoinHostPort(gRPCBindAddress, strconv.Itoa(gRPCPort)), gRPCProxyProtocol)
 	if err != nil {
 		log.Exitf("Cannot listen on port %v for gRPC: %v", gRPCPort, err)
 	}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servenv
+
+import (
+	"net"
+
+	"github.com/pires/go-proxyproto"
+)
+
+// This file handles the PROXY protocol on the gRPC and HTTP listeners.
+// Behind an L4 load balancer, every connection comes from the address of the
+// load balancer. With the PROXY protocol, the load balancer sends the address
+// of the original client at the start of the connection, which then becomes
+// the remote address of the connection. It flows from there to the gRPC peer
+// and the HTTP request, and so into the call info and the query logs.
+var (
+	// gRPCProxyProtocol enables the PROXY protocol on the gRPC listener.
+	gRPCProxyProtocol bool
+
+	// httpProxyProtocol enables the PROXY protocol on the HTTP listener.
+	httpProxyProtocol bool
+)
+
+// ProxyProtocolListener wraps the listener so that its connections may start
+// with a PROXY protocol v1 or v2 header, in which case their remote address
+// is the address of the client sent in the header. Connections without a
+// header are accepted as they are.
+func ProxyProtocolListener(l net.Listener) net.Listener {
+	return &proxyproto.Listener{Listener: l}
+}
+
+// listenTCP listens on the TCP address, accepting the PROXY protocol if
+// proxyProtocol is set.
+func listenTCP(address string, proxyProtocol bool) (net.Listener, error) {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if proxyProtocol {
+		return ProxyProtocolListener(l), nil
+	}
+	return l, nil
+}
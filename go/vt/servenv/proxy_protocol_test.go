@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servenv
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyProtocolListener(t *testing.T) {
+	l, err := listenTCP("127.0.0.1:0", true)
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.RemoteAddr)
+	})}
+	go server.Serve(l)
+	defer server.Close()
+
+	get := func(version byte) string {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		if version > 0 {
+			header := proxyproto.HeaderProxyFromAddrs(version,
+				&net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 5678},
+				&net.TCPAddr{IP: net.ParseIP("10.4.5.6"), Port: 15000})
+			_, err = header.WriteTo(conn)
+			require.NoError(t, err)
+		}
+		req, err := http.NewRequest(http.MethodGet, "http://"+l.Addr().String()+"/", nil)
+		require.NoError(t, err)
+		require.NoError(t, req.Write(conn))
+		resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Equal(t, "10.1.2.3:5678", get(1))
+	assert.Equal(t, "10.1.2.3:5678", get(2))
+	// Connections without a header keep their own address.
+	host, _, err := net.SplitHostPort(get(0))
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+}
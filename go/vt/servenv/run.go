@@ -44,7 +44,7 @@ func Run(bindAddress string, port int) {
 	serveGRPC()
 	serveSocketFile()
 
-	l, err := net.Listen("tcp", net.JoinHostPort(bindAddress, strconv.Itoa(port)))
+	l, err := listenTCP(net.JoinHostPort(bindAddress, strconv.Itoa(port)), httpProxyProtocol)
 	if err != nil {
 		log.Exit(err)
 	}
@@ -233,6 +233,7 @@ func RegisterDefaultFlags() {
 	OnParse(func(fs *pflag.FlagSet) {
 		fs.IntVar(&port, "port", port, "port for the server")
 		fs.StringVar(&bindAddress, "bind-address", bindAddress, "Bind address for the server. If empty, the server will listen on all available unicast and anycast IP addresses of the local system.")
+		fs.BoolVar(&httpProxyProtocol, "http-proxy-protocol", httpProxyProtocol, "Enable HAProxy PROXY protocol (v1 and v2) on HTTP listener socket")
 	})
 }
 
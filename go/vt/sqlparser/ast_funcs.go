@@ -549,6 +549,18 @@ func (op ComparisonExprOperator) Inverse() ComparisonExprOperator {
 	panic("unreachable")
 }
 
+// Inverse returns the modifier to use when the comparison operator is inverted:
+// NOT (a > ANY (subquery)) is the same as a <= ALL (subquery), and vice versa.
+func (m ComparisonModifier) Inverse() ComparisonModifier {
+	switch m {
+	case Any:
+		return All
+	case All:
+		return Any
+	}
+	return m
+}
+
 // SwitchSides returns the reversed comparison operator if applicable, along with a boolean indicating success.
 // For symmetric operators like '=', '!=', and '<=>', it returns the same operator and true.
 // For directional comparison operators ('<', '>', '<=', '>='), it returns the opposite operator and true.
@@ -2138,6 +2150,16 @@ func (joinType JoinType) IsInner() bool {
 	}
 }
 
+// IsNatural returns whether the join type is a natural join or not.
+func (joinType JoinType) IsNatural() bool {
+	switch joinType {
+	case NaturalJoinType, NaturalLeftJoinType, NaturalRightJoinType:
+		return true
+	default:
+		return false
+	}
+}
+
 // ToString returns the type as a string
 func (ty LockType) ToString() string {
 	switch ty {
@@ -2376,6 +2398,85 @@ func ContainsAggregation(e SQLNode) bool {
 	return hasAggregates
 }
 
+// GetOverClause returns the OVER clause of a window function call. Aggregate functions
+// are only window functions when they have an OVER clause. For all other nodes, nil is returned.
+func GetOverClause(node SQLNode) *OverClause {
+	switch node := node.(type) {
+	case *ArgumentLessWindowExpr:
+		return node.OverClause
+	case *FirstOrLastValueExpr:
+		return node.OverClause
+	case *NtileExpr:
+		return node.OverClause
+	case *NTHValueExpr:
+		return node.OverClause
+	case *LagLeadExpr:
+		return node.OverClause
+	case *Count:
+		return node.OverClause
+	case *CountStar:
+		return node.OverClause
+	case *Avg:
+		return node.OverClause
+	case *Max:
+		return node.OverClause
+	case *Min:
+		return node.OverClause
+	case *Sum:
+		return node.OverClause
+	case *BitAnd:
+		return node.OverClause
+	case *BitOr:
+		return node.OverClause
+	case *BitXor:
+		return node.OverClause
+	case *Std:
+		return node.OverClause
+	case *StdDev:
+		return node.OverClause
+	case *StdPop:
+		return node.OverClause
+	case *StdSamp:
+		return node.OverClause
+	case *VarPop:
+		return node.OverClause
+	case *VarSamp:
+		return node.OverClause
+	case *Variance:
+		return node.OverClause
+	case *JSONArrayAgg:
+		return node.OverClause
+	case *JSONObjectAgg:
+		return node.OverClause
+	}
+	return nil
+}
+
+// IsWindowFunc returns true if the node is a window function call
+func IsWindowFunc(node SQLNode) bool {
+	return GetOverClause(node) != nil
+}
+
+// ContainsWindowFunc returns true if the expression contains a window function call
+func ContainsWindowFunc(e SQLNode) bool {
+	hasWindowFunc := false
+	_ = Walk(func(node SQLNode) (kontinue bool, err error) {
+		switch node.(type) {
+		case *Offset:
+			return false, nil
+		case *Subquery:
+			// window functions inside subqueries belong to the subquery
+			return false, nil
+		}
+		if IsWindowFunc(node) {
+			hasWindowFunc = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, e)
+	return hasWindowFunc
+}
+
 // setFuncArgs sets the arguments for the aggregation function, while checking that there is only one argument
 func setFuncArgs(aggr AggrFunc, exprs []Expr, name string) error {
 	if len(exprs) != 1 {
@@ -2776,6 +2877,13 @@ func (node *IntervalDateExpr) FnName() string {
 	}
 }
 
+// IsGroupingFunc returns true if the node is a call to the GROUPING() function,
+// used to tell super-aggregate rows produced by WITH ROLLUP apart from regular rows
+func IsGroupingFunc(node SQLNode) bool {
+	fnc, ok := node.(*FuncExpr)
+	return ok && fnc.Qualifier.IsEmpty() && fnc.Name.EqualString("grouping")
+}
+
 func IsDistinct(f AggrFunc) bool {
 	da, ok := f.(DistinctableAggr)
 	if !ok {
@@ -254,3 +254,33 @@ func TestKeyspaceToNonQualifiedTable(t *testing.T) {
 	AddKeyspace(stmt, "ks2")
 	require.Equal(t, "select col, col + (select 1 from ks2.t4) from ks.t join ks2.t2 join (select 1 from ks2.t3) as x where t.id = t2.id and x.id = t.id", String(stmt))
 }
+
+func TestContainsWindowFunc(t *testing.T) {
+	tcases := []struct {
+		expr     string
+		expected bool
+	}{{
+		expr:     "row_number() over ()",
+		expected: true,
+	}, {
+		expr:     "sum(col) over (partition by id order by col)",
+		expected: true,
+	}, {
+		expr:     "1 + lag(col, 2) over w",
+		expected: true,
+	}, {
+		expr:     "sum(col)",
+		expected: false,
+	}, {
+		expr:     "(select row_number() over () from t)",
+		expected: false,
+	}}
+	parser := NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.expr, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tcase.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expected, ContainsWindowFunc(expr))
+		})
+	}
+}
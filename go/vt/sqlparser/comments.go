@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -58,6 +59,13 @@ const (
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
+	// DirectiveResultCacheTTL caches the result of a SELECT in vtgate for the given duration, e.g. 5s.
+	DirectiveResultCacheTTL = "RESULT_CACHE_TTL"
+
+	// QueryAttributeWorkloadName is the query attribute a client can send instead of the DirectiveWorkloadName directive.
+	QueryAttributeWorkloadName = "workload_name"
+	// QueryAttributePriority is the query attribute a client can send instead of the DirectivePriority directive.
+	QueryAttributePriority = "priority"
 
 	// MaxPriorityValue specifies the maximum value allowed for the priority query directive. Valid priority values are
 	// between zero and MaxPriorityValue.
@@ -569,6 +577,7 @@ type QueryHints struct {
 	ForeignKeyChecks    *bool
 	Priority            string
 	Timeout             *int
+	ResultCacheTTL      time.Duration
 }
 
 func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
@@ -590,10 +599,27 @@ func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
 	qh.Workload = getWorkload(directives)
 	qh.ForeignKeyChecks = getForeignKeyChecksState(comment)
 	qh.Timeout = getQueryTimeout(directives)
+	qh.ResultCacheTTL = getResultCacheTTL(stmt, directives)
 
 	return qh, nil
 }
 
+// ApplyQueryAttributes sets the workload name and the priority from the query
+// attributes sent by the client, unless they are already set by directives.
+func (qh *QueryHints) ApplyQueryAttributes(attributes map[string]string) error {
+	if qh.Workload == "" {
+		qh.Workload = attributes[QueryAttributeWorkloadName]
+	}
+	if qh.Priority == "" {
+		priority, err := validatePriority(attributes[QueryAttributePriority])
+		if err != nil {
+			return err
+		}
+		qh.Priority = priority
+	}
+	return nil
+}
+
 // getConsolidator returns the consolidator option.
 func getConsolidator(stmt Statement, directives *CommentDirectives) querypb.ExecuteOptions_Consolidator {
 	if _, isSelect := stmt.(SelectStatement); !isSelect {
@@ -637,7 +663,15 @@ func getForeignKeyChecksState(cmt Commented) *bool {
 // getPriority gets the priority from the provided Statement, using DirectivePriority
 func getPriority(directives *CommentDirectives) (string, error) {
 	priority, ok := directives.GetString(DirectivePriority, "")
-	if !ok || priority == "" {
+	if !ok {
+		return "", nil
+	}
+	return validatePriority(priority)
+}
+
+// validatePriority checks that the priority is an integer between 0 and MaxPriorityValue.
+func validatePriority(priority string) (string, error) {
+	if priority == "" {
 		return "", nil
 	}
 
@@ -662,3 +696,21 @@ func getQueryTimeout(directives *CommentDirectives) *int {
 	}
 	return &timeout
 }
+
+// getResultCacheTTL gets the result cache TTL from the provided Statement, using DirectiveResultCacheTTL.
+// Only SELECT statements can be cached, and invalid or negative durations disable the cache.
+func getResultCacheTTL(stmt Statement, directives *CommentDirectives) time.Duration {
+	if _, isSelect := stmt.(SelectStatement); !isSelect {
+		return 0
+	}
+	ttlString, ok := directives.GetString(DirectiveResultCacheTTL, "")
+	if !ok || ttlString == "" {
+		return 0
+	}
+
+	ttl, err := time.ParseDuration(ttlString)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
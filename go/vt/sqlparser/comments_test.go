@@ -19,6 +19,7 @@ package sqlparser
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -482,6 +483,33 @@ func TestConsolidator(t *testing.T) {
 	}
 }
 
+func TestResultCacheTTL(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected time.Duration
+	}{
+		{"select * from users", 0},
+		{"select /*vt+ RESULT_CACHE_TTL=5s */ * from users", 5 * time.Second},
+		{"select /*vt+ RESULT_CACHE_TTL=1m30s */ * from users", 90 * time.Second},
+		{"select /*vt+ RESULT_CACHE_TTL=invalid */ * from users", 0},
+		{"select /*vt+ RESULT_CACHE_TTL=-5s */ * from users", 0},
+		{"select /*vt+ RESULT_CACHE_TTL=5s */ * from users union select * from admins", 5 * time.Second},
+		{"update /*vt+ RESULT_CACHE_TTL=5s */ users set name=1", 0},
+		{"delete /*vt+ RESULT_CACHE_TTL=5s */ from users", 0},
+	}
+
+	parser := NewTestParser()
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			qh, err := BuildQueryHints(stmt)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, qh.ResultCacheTTL)
+		})
+	}
+}
+
 func TestGetPriorityFromStatement(t *testing.T) {
 	testCases := []struct {
 		query            string
@@ -547,6 +575,55 @@ func TestGetPriorityFromStatement(t *testing.T) {
 	}
 }
 
+func TestApplyQueryAttributes(t *testing.T) {
+	testCases := []struct {
+		query            string
+		attributes       map[string]string
+		expectedWorkload string
+		expectedPriority string
+		expectedError    error
+	}{
+		{
+			query: "select * from a_table",
+		},
+		{
+			query:            "select * from a_table",
+			attributes:       map[string]string{QueryAttributeWorkloadName: "checkout", QueryAttributePriority: "20"},
+			expectedWorkload: "checkout",
+			expectedPriority: "20",
+		},
+		{
+			query:            "select /*vt+ WORKLOAD_NAME=reports PRIORITY=33 */ * from a_table",
+			attributes:       map[string]string{QueryAttributeWorkloadName: "checkout", QueryAttributePriority: "20"},
+			expectedWorkload: "reports",
+			expectedPriority: "33",
+		},
+		{
+			query:         "select * from a_table",
+			attributes:    map[string]string{QueryAttributePriority: "200"},
+			expectedError: ErrInvalidPriority,
+		},
+	}
+
+	parser := NewTestParser()
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			stmt, err := parser.Parse(testCase.query)
+			require.NoError(t, err)
+			qh, err := BuildQueryHints(stmt)
+			require.NoError(t, err)
+			err = qh.ApplyQueryAttributes(testCase.attributes)
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedWorkload, qh.Workload)
+			assert.Equal(t, testCase.expectedPriority, qh.Priority)
+		})
+	}
+}
+
 // TestGetMySQLSetVarValue tests the functionality of GetMySQLSetVarValue
 func TestGetMySQLSetVarValue(t *testing.T) {
 	tests := []struct {
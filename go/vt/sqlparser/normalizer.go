@@ -158,7 +158,7 @@ func newNormalizer(
 func (nz *normalizer) walkDown(node, _ SQLNode) bool {
 	switch node := node.(type) {
 	case *Begin, *Commit, *Rollback, *Savepoint, *SRollback, *Release, *OtherAdmin, *Analyze, *AssignmentExpr,
-		*PrepareStmt, *ExecuteStmt, *FramePoint, *ColName, TableName, *ConvertType:
+		*PrepareStmt, *ExecuteStmt, *FramePoint, *ColName, TableName, *ConvertType, *JtColumnDefinition:
 		// These statement don't need normalizing
 		return false
 	case *Set:
@@ -278,9 +278,15 @@ func (nz *normalizer) visitLiteral(cursor *Cursor, node *Literal) {
 		nz.convertLiteral(node, cursor)
 		return
 	}
-	switch cursor.Parent().(type) {
+	switch parent := cursor.Parent().(type) {
 	case *Order, *GroupBy:
 		return
+	case *JSONTableExpr:
+		if parent.Filter == node {
+			// JSON_TABLE paths have to be string literals
+			return
+		}
+		nz.convertLiteralDedup(node, cursor)
 	case *Limit:
 		nz.convertLiteral(node, cursor)
 	default:
@@ -615,6 +621,7 @@ func (nz *normalizer) rewriteNotExpr(cursor *Cursor, node *NotExpr) {
 		// Invert comparison operators.
 		if canChange, inverse := inverseOp(inner.Operator); canChange {
 			inner.Operator = inverse
+			inner.Modifier = inner.Modifier.Inverse()
 			cursor.Replace(inner)
 		}
 	case *NotExpr:
@@ -690,6 +697,8 @@ func (nz *normalizer) sysVarRewrite(cursor *Cursor, node *Variable) {
 		sysvars.TransactionMode.Name,
 		sysvars.ReadAfterWriteGTID.Name,
 		sysvars.ReadAfterWriteTimeOut.Name,
+		sysvars.ReadAfterWriteAuto.Name,
+		sysvars.ScatterConsistency.Name,
 		sysvars.SessionEnableSystemSettings.Name,
 		sysvars.SessionTrackGTIDs.Name,
 		sysvars.SessionUUID.Name,
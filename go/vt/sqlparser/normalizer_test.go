@@ -443,6 +443,13 @@ func TestNormalize(t *testing.T) {
 			"bv1": sqltypes.Int64BindVariable(1),
 			"bv2": sqltypes.Int64BindVariable(0),
 		},
+	}, {
+		// JSON_TABLE paths are not parameterized
+		in:      "select jt.a from json_table('[{\"a\": 1}]', '$[*]' columns(a int path '$.a' default '0' on empty)) as jt",
+		outstmt: "select jt.a from json_table(:bv1 /* VARCHAR */, '$[*]' columns(\n\ta int path '$.a' default '0' on empty \n\t)\n) as jt",
+		outbv: map[string]*querypb.BindVariable{
+			"bv1": sqltypes.StringBindVariable("[{\"a\": 1}]"),
+		},
 	}}
 	parser := NewTestParser()
 	for _, tc := range testcases {
@@ -700,6 +707,9 @@ func TestRewrites(in *testing.T) {
 		in:       "SELECT @@workload",
 		expected: "SELECT :__vtworkload as `@@workload`",
 		workload: true,
+	}, {
+		in:       "SELECT @@scatter_consistency",
+		expected: "SELECT :__vtscatter_consistency as `@@scatter_consistency`",
 	}, {
 		in:       "SELECT @@socket",
 		expected: "SELECT :__vtsocket as `@@socket`",
@@ -730,8 +740,8 @@ func TestRewrites(in *testing.T) {
 		expected:         "select * from user where col = :__vtmigration_context",
 		migrationContext: true,
 	}, {
-		in:       `select * from user where col = @@read_after_write_gtid OR col = @@read_after_write_timeout OR col = @@session_track_gtids`,
-		expected: "select * from user where col = :__vtread_after_write_gtid or col = :__vtread_after_write_timeout or col = :__vtsession_track_gtids",
+		in:       `select * from user where col = @@read_after_write_gtid OR col = @@read_after_write_timeout OR col = @@session_track_gtids OR col = @@read_after_write_auto`,
+		expected: "select * from user where col = :__vtread_after_write_gtid or col = :__vtread_after_write_timeout or col = :__vtsession_track_gtids or col = :__vtread_after_write_auto",
 		rawGTID:  true, rawTimeout: true, sessTrackGTID: true,
 	}, {
 		in:       "SELECT * FROM tbl WHERE id IN (SELECT 1 FROM dual)",
@@ -72,6 +72,7 @@ var (
 	TransactionReadOnly         = SystemVariable{Name: "transaction_read_only", IsBoolean: true, Default: off}
 	TxReadOnly                  = SystemVariable{Name: "tx_read_only", IsBoolean: true, Default: off}
 	Workload                    = SystemVariable{Name: "workload", IdentifierAsString: true}
+	ScatterConsistency          = SystemVariable{Name: "scatter_consistency", IdentifierAsString: true}
 	QueryTimeout                = SystemVariable{Name: "query_timeout"}
 
 	// Online DDL
@@ -86,6 +87,7 @@ var (
 	ReadAfterWriteGTID    = SystemVariable{Name: "read_after_write_gtid"}
 	ReadAfterWriteTimeOut = SystemVariable{Name: "read_after_write_timeout"}
 	SessionTrackGTIDs     = SystemVariable{Name: "session_track_gtids", IdentifierAsString: true}
+	ReadAfterWriteAuto    = SystemVariable{Name: "read_after_write_auto", IsBoolean: true, Default: off}
 
 	VitessAware = []SystemVariable{
 		Autocommit,
@@ -97,6 +99,7 @@ var (
 		TransactionMode,
 		DDLStrategy,
 		Workload,
+		ScatterConsistency,
 		Charset,
 		Names,
 		SessionUUID,
@@ -105,6 +108,7 @@ var (
 		ReadAfterWriteGTID,
 		ReadAfterWriteTimeOut,
 		SessionTrackGTIDs,
+		ReadAfterWriteAuto,
 		QueryTimeout,
 	}
 
@@ -51,6 +51,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/mysqlctl/mysqlctlproto"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
@@ -170,6 +171,56 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// ApplyBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.ApplyBackupRetentionPolicyRequest) (resp *vtctldatapb.ApplyBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("dry_run", req.DryRun)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if ki.BackupRetentionPolicy == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %v has no backup retention policy", req.Keyspace)
+		return nil, err
+	}
+
+	shards, err := s.ts.GetShardNames(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ApplyBackupRetentionPolicyResponse{}
+	now := time.Now()
+	for _, shard := range shards {
+		removed, err := mysqlctl.ApplyBackupRetentionPolicy(ctx, mysqlctl.BackupRetentionParams{
+			Logger:   logutil.NewConsoleLogger(),
+			Keyspace: req.Keyspace,
+			Shard:    shard,
+			Policy:   ki.BackupRetentionPolicy,
+			DryRun:   req.DryRun,
+			Now:      now,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bh := range removed {
+			bi := mysqlctlproto.BackupHandleToProto(bh)
+			bi.Keyspace = req.Keyspace
+			bi.Shard = shard
+			resp.RemovedBackups = append(resp.RemovedBackups, bi)
+		}
+	}
+
+	return resp, nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -3545,7 +3596,7 @@ func (s *VtctldServer) RemoveBackup(ctx context.Context, req *vtctldatapb.Remove
 	}
 	defer bs.Close()
 
-	if err = bs.RemoveBackup(ctx, bucket, req.Name); err != nil {
+	if err = mysqlctl.RemoveBackup(ctx, logutil.NewConsoleLogger(), bs, bucket, req.Name); err != nil {
 		return nil, err
 	}
 
@@ -3829,6 +3880,45 @@ func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunH
 	return &vtctldatapb.RunHealthCheckResponse{}, nil
 }
 
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetKeyspaceBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest) (resp *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("backup_retention_policy", req.BackupRetentionPolicy.String())
+
+	if err = mysqlctl.ValidateBackupRetentionPolicy(req.BackupRetentionPolicy); err != nil {
+		return nil, err
+	}
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceBackupRetentionPolicy")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	ki.BackupRetentionPolicy = req.BackupRetentionPolicy
+
+	err = s.ts.UpdateKeyspace(ctx, ki)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+		Keyspace: ki.Keyspace,
+	}, nil
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
@@ -4703,6 +4793,56 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 	return resp, err
 }
 
+// ValidateBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidateBackup(ctx context.Context, req *vtctldatapb.ValidateBackupRequest) (resp *vtctldatapb.ValidateBackupResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("backup_name", req.Name)
+	span.Annotate("concurrency", req.Concurrency)
+
+	concurrency := int(req.Concurrency)
+	if concurrency == 0 {
+		concurrency = 4
+	}
+
+	validation, err := mysqlctl.ValidateBackup(ctx, mysqlctl.ValidateBackupParams{
+		Logger:      logutil.NewConsoleLogger(),
+		Keyspace:    req.Keyspace,
+		Shard:       req.Shard,
+		BackupName:  req.Name,
+		Concurrency: concurrency,
+		Stats:       backupstats.ValidateStats(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ValidateBackupResponse{
+		Engine: validation.Engine,
+		Valid:  validation.Valid,
+		Files:  make([]*vtctldatapb.ValidateBackupResponse_File, 0, len(validation.Files)),
+	}
+	for _, fv := range validation.Files {
+		file := &vtctldatapb.ValidateBackupResponse_File{
+			Name:  fv.Name,
+			Hash:  fv.Hash,
+			Bytes: uint64(fv.Bytes),
+		}
+		if fv.Err != nil {
+			file.Error = fv.Err.Error()
+		}
+		resp.Files = append(resp.Files, file)
+	}
+	span.Annotate("valid", resp.Valid)
+
+	return resp, nil
+}
+
 // ValidateKeyspace is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.ValidateKeyspaceRequest) (resp *vtctldatapb.ValidateKeyspaceResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateKeyspace")
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
 	"slices"
@@ -236,6 +237,84 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestApplyBackupRetentionPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddKeyspaces(ctx, t, ts,
+		&vtctldatapb.Keyspace{
+			Name: "testkeyspace",
+			Keyspace: &topodatapb.Keyspace{
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepFull: 1},
+			},
+		},
+		&vtctldatapb.Keyspace{
+			Name:     "nopolicy",
+			Keyspace: &topodatapb.Keyspace{},
+		},
+	)
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "-"})
+
+	manifest := []byte(`{"BackupMethod": "builtin"}`)
+	testutil.BackupStorage.Backups = map[string][]string{
+		"testkeyspace/-": {"2025-02-01.120000.zone1-0000000101", "2025-02-02.120000.zone1-0000000101", "2025-02-03.120000.zone1-0000000101"},
+	}
+	testutil.BackupStorage.Files = map[string]map[string][]byte{
+		"testkeyspace/-/2025-02-01.120000.zone1-0000000101": {"MANIFEST": manifest},
+		"testkeyspace/-/2025-02-02.120000.zone1-0000000101": {"MANIFEST": manifest},
+		// A backup in progress, without a MANIFEST.
+		"testkeyspace/-/2025-02-03.120000.zone1-0000000101": {},
+	}
+	defer func() { testutil.BackupStorage.Files = nil }()
+
+	removedBackups := func(resp *vtctldatapb.ApplyBackupRetentionPolicyResponse) []string {
+		var names []string
+		for _, bi := range resp.RemovedBackups {
+			assert.Equal(t, "testkeyspace", bi.Keyspace)
+			assert.Equal(t, "-", bi.Shard)
+			names = append(names, bi.Name)
+		}
+		return names
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		resp, err := vtctld.ApplyBackupRetentionPolicy(ctx, &vtctldatapb.ApplyBackupRetentionPolicyRequest{
+			Keyspace: "testkeyspace",
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2025-02-01.120000.zone1-0000000101"}, removedBackups(resp))
+		assert.Len(t, testutil.BackupStorage.Backups["testkeyspace/-"], 3)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		resp, err := vtctld.ApplyBackupRetentionPolicy(ctx, &vtctldatapb.ApplyBackupRetentionPolicyRequest{
+			Keyspace: "testkeyspace",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2025-02-01.120000.zone1-0000000101"}, removedBackups(resp))
+		assert.Equal(t, []string{"2025-02-02.120000.zone1-0000000101", "2025-02-03.120000.zone1-0000000101"}, testutil.BackupStorage.Backups["testkeyspace/-"])
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		_, err := vtctld.ApplyBackupRetentionPolicy(ctx, &vtctldatapb.ApplyBackupRetentionPolicyRequest{
+			Keyspace: "nopolicy",
+		})
+		assert.ErrorContains(t, err, "keyspace nopolicy has no backup retention policy")
+	})
+
+	t.Run("keyspace not found", func(t *testing.T) {
+		_, err := vtctld.ApplyBackupRetentionPolicy(ctx, &vtctldatapb.ApplyBackupRetentionPolicyRequest{
+			Keyspace: "notfound",
+		})
+		assert.Error(t, err)
+	})
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -11341,6 +11420,111 @@ func TestRunHealthCheck(t *testing.T) {
 	}
 }
 
+func TestSetKeyspaceBackupRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keyspaces   []*vtctldatapb.Keyspace
+		req         *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest
+		expected    *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse
+		expectedErr string
+	}{
+		{
+			name: "ok",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+				{
+					Name:     "ks2",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+					KeepFull:                  2,
+					PointInTimeRecoveryWindow: protoutil.DurationToProto(24 * time.Hour),
+				},
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+						KeepFull:                  2,
+						PointInTimeRecoveryWindow: protoutil.DurationToProto(24 * time.Hour),
+					},
+				},
+			},
+		},
+		{
+			name: "clear policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name: "ks1",
+					Keyspace: &topodatapb.Keyspace{
+						BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepFull: 2},
+					},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{},
+			},
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+		{
+			name: "negative point in time recovery window",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{
+					PointInTimeRecoveryWindow: protoutil.DurationToProto(-time.Hour),
+				},
+			},
+			expectedErr: "the point in time recovery window cannot be negative: -1h0m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetKeyspaceBackupRetentionPolicy(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -13774,6 +13958,77 @@ func TestValidate(t *testing.T) {
 	}, resp)
 }
 
+func TestValidateBackup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	data := []byte("some table data")
+	manifest := func(hash string) []byte {
+		return []byte(fmt.Sprintf(`{"BackupMethod": "builtin", "SkipCompress": true, "FileEntries": [{"Base": "Data", "Name": "db/t.ibd", "Hash": %q}]}`, hash))
+	}
+	testutil.BackupStorage.Backups = map[string][]string{
+		"testkeyspace/-": {"backup1", "backup2", "backup3"},
+	}
+	testutil.BackupStorage.Files = map[string]map[string][]byte{
+		"testkeyspace/-/backup1": {"MANIFEST": manifest(fmt.Sprintf("%08x", crc32.ChecksumIEEE(data))), "0": data},
+		"testkeyspace/-/backup2": {"MANIFEST": manifest("bad"), "0": data},
+		"testkeyspace/-/backup3": {"MANIFEST": []byte(`{"BackupMethod": "xtrabackup"}`)},
+	}
+	defer func() { testutil.BackupStorage.Files = nil }()
+
+	t.Run("valid", func(t *testing.T) {
+		resp, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Name:     "backup1",
+		})
+		require.NoError(t, err)
+		utils.MustMatch(t, &vtctldatapb.ValidateBackupResponse{
+			Engine: "builtin",
+			Valid:  true,
+			Files: []*vtctldatapb.ValidateBackupResponse_File{{
+				Name:  "db/t.ibd",
+				Hash:  fmt.Sprintf("%08x", crc32.ChecksumIEEE(data)),
+				Bytes: uint64(len(data)),
+			}},
+		}, resp)
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		resp, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Name:     "backup2",
+		})
+		require.NoError(t, err)
+		assert.False(t, resp.Valid)
+		require.Len(t, resp.Files, 1)
+		assert.Contains(t, resp.Files[0].Error, "hash mismatch for db/t.ibd")
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		_, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Name:     "backup3",
+		})
+		assert.ErrorContains(t, err, `created with the "xtrabackup" engine`)
+	})
+
+	t.Run("no backup found", func(t *testing.T) {
+		_, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Name:     "notfound",
+		})
+		assert.ErrorContains(t, err, "backup notfound not found")
+	})
+}
+
 func TestValidateSchemaKeyspace(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -17,8 +17,11 @@ limitations under the License.
 package testutil
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
@@ -32,6 +35,9 @@ type backupStorage struct {
 	Backups map[string][]string
 	// ListBackupsError is returned from ListBackups when it is non-nil.
 	ListBackupsError error
+	// Files is a mapping of "<directory>/<name>" of a backup to the contents
+	// of its files, by file name.
+	Files map[string]map[string][]byte
 }
 
 // ListBackups is part of the backupstorage.BackupStorage interface.
@@ -45,7 +51,7 @@ func (bs *backupStorage) ListBackups(ctx context.Context, dir string) ([]backups
 	for k, v := range bs.Backups {
 		if k == dir {
 			for _, name := range v {
-				handles = append(handles, &backupHandle{directory: k, name: name})
+				handles = append(handles, &backupHandle{directory: k, name: name, files: bs.Files[k+"/"+name]})
 			}
 		}
 	}
@@ -81,17 +87,33 @@ func (bs *backupStorage) RemoveBackup(ctx context.Context, dir string, name stri
 // Close is part of the backupstorage.BackupStorage interface.
 func (bs *backupStorage) Close() error { return nil }
 
+// WithParams is part of the backupstorage.BackupStorage interface.
+func (bs *backupStorage) WithParams(backupstorage.Params) backupstorage.BackupStorage { return bs }
+
 // backupHandle implements a subset of the backupstorage.backupHandle interface.
 type backupHandle struct {
 	backupstorage.BackupHandle
 
 	directory string
 	name      string
+	files     map[string][]byte
 }
 
 func (bh *backupHandle) Directory() string { return bh.directory }
 func (bh *backupHandle) Name() string      { return bh.name }
 
+// ReadFile is part of the backupstorage.BackupHandle interface.
+func (bh *backupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	data, ok := bh.files[filename]
+	if !ok {
+		return nil, fmt.Errorf("no file %s in backup %s/%s: %w", filename, bh.directory, bh.name, os.ErrNotExist)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Error is part of the backupstorage.BackupHandle interface.
+func (bh *backupHandle) Error() error { return nil }
+
 // handlesByName implements the sort interface for backup handles by Name().
 type handlesByName []backupstorage.BackupHandle
 
@@ -106,6 +106,7 @@ var (
 
 	VT10001 = errorWithoutState("VT10001", vtrpcpb.Code_ABORTED, "foreign key constraints are not allowed", "Foreign key constraints are not allowed, see https://vitess.io/blog/2021-06-15-online-ddl-why-no-fk/.")
 	VT10002 = errorWithoutState("VT10002", vtrpcpb.Code_ABORTED, "atomic distributed transaction not allowed: %s", "The distributed transaction cannot be committed. A rollback decision is taken.")
+	VT10003 = errorWithState("VT10003", vtrpcpb.Code_ABORTED, TxInterruptedByFailover, "transaction was interrupted by a failover of shard %s and has been rolled back: %v", "The shard failed over while the transaction was open on it. The whole transaction has been rolled back and can be retried.")
 
 	VT12001 = errorWithoutState("VT12001", vtrpcpb.Code_UNIMPLEMENTED, "unsupported: %s", "This statement is unsupported by Vitess. Please rewrite your query to use supported syntax.")
 	VT12002 = errorWithoutState("VT12002", vtrpcpb.Code_UNIMPLEMENTED, "unsupported: cross-shard foreign keys", "Vitess does not support cross shard foreign keys.")
@@ -197,6 +198,7 @@ var (
 		VT09031,
 		VT10001,
 		VT10002,
+		VT10003,
 		VT12001,
 		VT12002,
 		VT13001,
@@ -86,6 +86,9 @@ const (
 	// cancelled
 	QueryInterrupted
 
+	// aborted
+	TxInterruptedByFailover
+
 	// unimplemented
 	NotSupportedYet
 	UnsupportedPS
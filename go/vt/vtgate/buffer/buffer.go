@@ -94,6 +94,13 @@ func CausedByFailover(err error) bool {
 	return isFailover
 }
 
+// CausedByReparent returns true if err was returned by a tablet of a shard
+// which is being reparented. Unlike CausedByFailover, it does not signal
+// resharding or MoveTables.
+func CausedByReparent(err error) bool {
+	return isErrorDueToReparenting(err)
+}
+
 // isErrorDueToReparenting is a stronger check than CausedByFailover, meant to return
 // if the failure is caused because of a reparent.
 func isErrorDueToReparenting(err error) bool {
@@ -207,6 +214,16 @@ func (b *Buffer) WaitForFailoverEnd(ctx context.Context, keyspace, shard string,
 	return sb.waitForFailoverEnd(ctx, keyspace, shard, kev, err)
 }
 
+// FailoverInProgress returns true if requests for keyspace/shard are currently
+// buffered because of a failover. Transactions which were open on the old
+// PRIMARY tablet cannot be buffered and will be lost.
+func (b *Buffer) FailoverInProgress(keyspace, shard string) bool {
+	b.mu.RLock()
+	sb, ok := b.buffers[topoproto.KeyspaceShardString(keyspace, shard)]
+	b.mu.RUnlock()
+	return ok && sb.failoverInProgress()
+}
+
 func (b *Buffer) HandleKeyspaceEvent(ksevent *discovery.KeyspaceEvent) {
 	log.Infof("Keyspace Event received for keyspace %v", ksevent.Keyspace)
 	for _, shard := range ksevent.Shards {
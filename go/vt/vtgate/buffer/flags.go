@@ -40,6 +40,8 @@ var (
 
 	bufferDrainConcurrency = 1
 	bufferKeyspaceShards   string
+
+	bufferInFlightTransactions bool
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -53,6 +55,7 @@ func registerFlags(fs *pflag.FlagSet) {
 
 	fs.IntVar(&bufferDrainConcurrency, "buffer_drain_concurrency", 1, "Maximum number of requests retried simultaneously. More concurrency will increase the load on the PRIMARY vttablet when draining the buffer.")
 	fs.StringVar(&bufferKeyspaceShards, "buffer_keyspace_shards", "", "If not empty, limit buffering to these entries (comma separated). Entry format: keyspace or keyspace/shard. Requires --enable_buffer=true.")
+	fs.BoolVar(&bufferInFlightTransactions, "buffer_in_flight_transactions", false, "Also handle open transactions during failovers: hold statements which would start a transaction on a failing over shard, and fail statements of transactions interrupted by the failover with a retryable error. Requires --enable_buffer=true.")
 }
 
 func init() {
@@ -81,6 +84,9 @@ func verifyFlags() error {
 	if bufferKeyspaceShards != "" && !bufferEnabled {
 		return fmt.Errorf("--buffer_keyspace_shards=%v also requires that --enable_buffer is set", bufferKeyspaceShards)
 	}
+	if bufferInFlightTransactions && !bufferEnabled {
+		return errors.New("--buffer_in_flight_transactions also requires that --enable_buffer is set")
+	}
 	if bufferEnabled && bufferEnabledDryRun && bufferKeyspaceShards == "" {
 		return errors.New("both the dry-run mode and actual buffering is enabled. To avoid ambiguity, keyspaces and shards for actual buffering must be explicitly listed in --buffer_keyspace_shards")
 	}
@@ -148,6 +154,10 @@ type Config struct {
 	// If empty (and *enabled==true), buffering is enabled for all shards.
 	Shards map[string]bool
 
+	// InFlightTransactions enables the handling of open transactions during
+	// failovers, see FailoverInProgress.
+	InFlightTransactions bool
+
 	// internal: used for testing
 	now func() time.Time
 }
@@ -219,6 +229,8 @@ func NewConfigFromFlags() *Config {
 		Keyspaces: keyspaces,
 		Shards:    shards,
 
+		InFlightTransactions: bufferInFlightTransactions,
+
 		now: time.Now,
 	}
 }
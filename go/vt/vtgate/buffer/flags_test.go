@@ -47,6 +47,13 @@ func TestVerifyFlags(t *testing.T) {
 
 	resetFlagsForTesting()
 
+	parse([]string{"--buffer_in_flight_transactions"})
+	if err := verifyFlags(); err == nil || !strings.Contains(err.Error(), "also requires that") {
+		t.Fatalf("Handling in-flight transactions requires --enable_buffer. err: %v", err)
+	}
+
+	resetFlagsForTesting()
+
 	parse([]string{
 		"--enable_buffer",
 		"--enable_buffer_dry_run",
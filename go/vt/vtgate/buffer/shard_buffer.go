@@ -235,6 +235,13 @@ func (sb *shardBuffer) waitForFailoverEnd(ctx context.Context, keyspace, shard s
 	return sb.wait(ctx, entry)
 }
 
+// failoverInProgress returns true if requests are currently buffered.
+func (sb *shardBuffer) failoverInProgress() bool {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.mode == bufferModeEnabled && sb.state == stateBuffering
+}
+
 // shouldBufferLocked returns true if the current request should be buffered
 // (based on the current state and whether the request detected a failover).
 func (sb *shardBuffer) shouldBufferLocked(failoverDetected bool) bool {
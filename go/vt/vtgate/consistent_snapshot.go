@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// With scatter_consistency set to SNAPSHOT, a SELECT that spans several shards
+// reads all of them from snapshots started at a single point in time, the same
+// way VReplication copies a table along with its GTID position:
+//  1. the tables the query reads are locked for writes on every shard that it
+//     reads, with flush tables ... with read lock on a reserved connection,
+//  2. a read only transaction with a consistent snapshot is started on each of
+//     these shards while all the locks are held,
+//  3. the locks are released, and the query runs inside the transactions.
+//
+// Writes to the tables are blocked on these shards between the first lock
+// and the last snapshot, which is the latency this mode trades for a view of
+// the shards where no shard is ahead of another. Queries that read a single
+// shard do not take any lock.
+
+// snapshotLockReleaseTimeout bounds how long releasing the locks may take
+// once the query context is done.
+const snapshotLockReleaseTimeout = 10 * time.Second
+
+// snapshotLockWaitTimeout bounds how long a shard waits for the lock of its
+// tables. A pending lock blocks the writes to the tables, so it must not
+// wait long for the transactions that use them.
+const snapshotLockWaitTimeout = 2 * time.Second
+
+// snapshotBeginQuery is executed on every shard to start its snapshot.
+const snapshotBeginQuery = "select 1 from dual"
+
+// snapshotLock is a reserved connection holding the read lock of a shard.
+type snapshotLock struct {
+	rs         *srvtopo.ResolvedShard
+	reservedID int64
+}
+
+// needsConsistentSnapshot returns true if the plan must read its shards from
+// coordinated snapshots.
+func (e *Executor) needsConsistentSnapshot(safeSession *econtext.SafeSession, plan *engine.Plan) bool {
+	return safeSession.GetScatterConsistency() == vtgatepb.ScatterConsistency_SNAPSHOT &&
+		plan.Type == sqlparser.StmtSelect &&
+		len(plan.TablesUsed) > 0 &&
+		!safeSession.InTransaction() &&
+		!safeSession.InReservedConn()
+}
+
+// insideConsistentSnapshot executes the plan inside read only transactions
+// whose snapshots were started at the same point on all the shards that it
+// reads.
+func (e *Executor) insideConsistentSnapshot(ctx context.Context, safeSession *econtext.SafeSession, plan *engine.Plan, vcursor *econtext.VCursorImpl, bindVars map[string]*querypb.BindVariable, execPlan func() error) error {
+	rss, err := engine.RouteShards(ctx, vcursor, plan.Instructions, bindVars)
+	if err != nil {
+		return err
+	}
+	if len(rss) < 2 {
+		// A single shard is always read from a single snapshot.
+		return execPlan()
+	}
+	if vcursor.TabletType() != topodatapb.TabletType_PRIMARY {
+		// Replicas of different shards apply the changes of their primaries
+		// at different times, so their snapshots cannot be coordinated.
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "scatter_consistency SNAPSHOT is only supported for reads from the %s", topoproto.TabletTypeLString(topodatapb.TabletType_PRIMARY))
+	}
+
+	tables := make(map[string][]string)
+	for _, qualified := range plan.TablesUsed {
+		keyspace, table, ok := strings.Cut(qualified, ".")
+		if !ok {
+			continue
+		}
+		tables[keyspace] = append(tables[keyspace], table)
+	}
+	lockQueries := make(map[*srvtopo.ResolvedShard]string)
+	for _, rs := range rss {
+		if keyspaceTables := tables[rs.Target.Keyspace]; len(keyspaceTables) > 0 {
+			lockQueries[rs] = snapshotLockQuery(keyspaceTables)
+		}
+	}
+
+	locks, err := e.lockShards(ctx, lockQueries)
+	defer func() {
+		e.releaseShardLocks(ctx, locks)
+	}()
+	if err != nil {
+		return err
+	}
+
+	// The snapshot transactions are read only, so they may span several
+	// shards even when the session only allows single shard transactions.
+	txMode := safeSession.TransactionMode
+	safeSession.TransactionMode = vtgatepb.TransactionMode_MULTI
+	defer func() {
+		safeSession.TransactionMode = txMode
+	}()
+	if err := e.txConn.Begin(ctx, safeSession, []sqlparser.TxAccessMode{sqlparser.WithConsistentSnapshot, sqlparser.ReadOnly}); err != nil {
+		return err
+	}
+	defer func() {
+		_ = e.txConn.Rollback(context.WithoutCancel(ctx), safeSession)
+	}()
+	queries := make([]*querypb.BoundQuery, len(rss))
+	for i := range rss {
+		queries[i] = &querypb.BoundQuery{Sql: snapshotBeginQuery}
+	}
+	if _, errs := e.scatterConn.ExecuteMultiShard(ctx, nil, rss, queries, safeSession, false, false, nullResultsObserver{}, false); len(errs) > 0 {
+		return vterrors.Wrap(vterrors.Aggregate(errs), "failed to start the snapshots")
+	}
+
+	// All the snapshots are started, so writes can resume.
+	e.releaseShardLocks(ctx, locks)
+	locks = nil
+
+	return execPlan()
+}
+
+// snapshotLockQuery returns the query that blocks the writes to the tables.
+func snapshotLockQuery(tables []string) string {
+	var buf strings.Builder
+	buf.WriteString("flush tables ")
+	for i, table := range slices.Compact(slices.Sorted(slices.Values(tables))) {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(sqlescape.EscapeID(table))
+	}
+	buf.WriteString(" with read lock")
+	return buf.String()
+}
+
+// lockShards blocks the writes to the tables on the shards that have a lock
+// query. It returns the locks that were acquired, even if some of the shards
+// failed.
+func (e *Executor) lockShards(ctx context.Context, lockQueries map[*srvtopo.ResolvedShard]string) ([]snapshotLock, error) {
+	var (
+		mu        sync.Mutex
+		locks     []snapshotLock
+		wg        sync.WaitGroup
+		allErrors concurrency.AllErrorRecorder
+	)
+	preQueries := []string{fmt.Sprintf("set @@session.lock_wait_timeout = %d", int(snapshotLockWaitTimeout.Seconds()))}
+	for rs, lockQuery := range lockQueries {
+		wg.Add(1)
+		go func(rs *srvtopo.ResolvedShard, lockQuery string) {
+			defer wg.Done()
+			state, _, err := rs.Gateway.ReserveExecute(ctx, rs.Target, preQueries, lockQuery, nil, 0, nil)
+			if state.ReservedID != 0 {
+				mu.Lock()
+				locks = append(locks, snapshotLock{rs: rs, reservedID: state.ReservedID})
+				mu.Unlock()
+			}
+			if err != nil {
+				allErrors.RecordError(vterrors.Wrapf(err, "failed to lock the tables of %s", topoproto.KeyspaceShardString(rs.Target.Keyspace, rs.Target.Shard)))
+			}
+		}(rs, lockQuery)
+	}
+	wg.Wait()
+	return locks, allErrors.AggrError(vterrors.Aggregate)
+}
+
+// releaseShardLocks releases the reserved connections holding the locks,
+// which unlocks the tables.
+func (e *Executor) releaseShardLocks(ctx context.Context, locks []snapshotLock) {
+	if len(locks) == 0 {
+		return
+	}
+	// The locks block writes, so they are released even if the query was canceled.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), snapshotLockReleaseTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, lock := range locks {
+		wg.Add(1)
+		go func(lock snapshotLock) {
+			defer wg.Done()
+			if err := lock.rs.Gateway.Release(ctx, lock.rs.Target, 0, lock.reservedID); err != nil {
+				log.Warningf("Failed to release the snapshot lock of %s: %v", topoproto.KeyspaceShardString(lock.rs.Target.Keyspace, lock.rs.Target.Shard), err)
+			}
+		}(lock)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestConsistentSnapshotScatter(t *testing.T) {
+	var shardConns []*sandboxconn.SandboxConn
+	var unshardedConn *sandboxconn.SandboxConn
+	executor, ctx := createExecutorEnvCallback(t, createExecutorConfig(), func(shard, ks string, tabletType topodatapb.TabletType, conn *sandboxconn.SandboxConn) {
+		if ks == KsTestSharded {
+			shardConns = append(shardConns, conn)
+		} else if ks == KsTestUnsharded && tabletType == topodatapb.TabletType_PRIMARY {
+			unshardedConn = conn
+		}
+	})
+	session := &vtgatepb.Session{
+		TargetString:       "@primary",
+		Autocommit:         true,
+		TransactionMode:    vtgatepb.TransactionMode_SINGLE,
+		ScatterConsistency: vtgatepb.ScatterConsistency_SNAPSHOT,
+	}
+
+	_, err := executorExec(ctx, executor, session, "select id from `user`", nil)
+	require.NoError(t, err)
+	for _, sbc := range shardConns {
+		require.Len(t, sbc.Queries, 4)
+		assert.Equal(t, "set @@session.lock_wait_timeout = 2", sbc.Queries[0].Sql)
+		assert.Equal(t, "flush tables `user` with read lock", sbc.Queries[1].Sql)
+		assert.Equal(t, snapshotBeginQuery, sbc.Queries[2].Sql)
+		assert.Equal(t, "select id from `user`", sbc.Queries[3].Sql)
+		assert.EqualValues(t, 1, sbc.ReserveCount.Load())
+		assert.EqualValues(t, 1, sbc.ReleaseCount.Load())
+		assert.EqualValues(t, 1, sbc.BeginCount.Load())
+		assert.EqualValues(t, 1, sbc.RollbackCount.Load())
+	}
+	assert.False(t, session.InTransaction)
+	assert.Empty(t, session.ShardSessions)
+	assert.Empty(t, session.GetOptions().GetTransactionAccessMode())
+	assert.Equal(t, vtgatepb.TransactionMode_SINGLE, session.TransactionMode)
+
+	// Reads of a single shard do not need coordinated snapshots.
+	_, err = executorExec(ctx, executor, session, "select id from music_user_map", nil)
+	require.NoError(t, err)
+	require.Len(t, unshardedConn.Queries, 1)
+	assert.EqualValues(t, 0, unshardedConn.ReserveCount.Load())
+	assert.EqualValues(t, 0, unshardedConn.BeginCount.Load())
+
+	// Point lookups read a single shard of a sharded keyspace.
+	for _, sbc := range shardConns {
+		sbc.Queries = nil
+	}
+	_, err = executorExec(ctx, executor, session, "select id from `user` where id = 1", nil)
+	require.NoError(t, err)
+	var queried int
+	for _, sbc := range shardConns {
+		queried += len(sbc.Queries)
+		assert.EqualValues(t, 1, sbc.ReserveCount.Load())
+		assert.EqualValues(t, 1, sbc.BeginCount.Load())
+	}
+	assert.Equal(t, 1, queried)
+
+	// Only the shards that the query reads are locked.
+	for _, sbc := range shardConns {
+		sbc.Queries = nil
+	}
+	_, err = executorExec(ctx, executor, session, "select id from `user` where id in (1, 3)", nil)
+	require.NoError(t, err)
+	var locked int
+	for _, sbc := range shardConns {
+		switch len(sbc.Queries) {
+		case 0:
+			assert.EqualValues(t, 1, sbc.ReserveCount.Load())
+		case 4:
+			locked++
+			assert.EqualValues(t, 2, sbc.ReserveCount.Load())
+			assert.EqualValues(t, 2, sbc.BeginCount.Load())
+		default:
+			assert.Fail(t, "unexpected queries", "%v", sbc.Queries)
+		}
+	}
+	assert.Equal(t, 2, locked)
+
+	// Reads of replicas cannot be coordinated.
+	session.TargetString = "@replica"
+	_, err = executorExec(ctx, executor, session, "select id from `user`", nil)
+	require.ErrorContains(t, err, "scatter_consistency SNAPSHOT is only supported for reads from the primary")
+}
+
+func TestConsistentSnapshotScatterLockFailure(t *testing.T) {
+	var shardConns []*sandboxconn.SandboxConn
+	executor, ctx := createExecutorEnvCallback(t, createExecutorConfig(), func(shard, ks string, tabletType topodatapb.TabletType, conn *sandboxconn.SandboxConn) {
+		if ks == KsTestSharded {
+			shardConns = append(shardConns, conn)
+		}
+	})
+	session := &vtgatepb.Session{
+		TargetString:       "@primary",
+		Autocommit:         true,
+		ScatterConsistency: vtgatepb.ScatterConsistency_SNAPSHOT,
+	}
+
+	// The first failure goes to the lock wait timeout, the second to the lock.
+	shardConns[1].MustFailCodes[vtrpcpb.Code_INVALID_ARGUMENT] = 2
+	_, err := executorExec(ctx, executor, session, "select id from `user`", nil)
+	require.ErrorContains(t, err, "failed to lock the tables of TestExecutor/20-40")
+	var released int64
+	for _, sbc := range shardConns {
+		released += sbc.ReleaseCount.Load()
+		assert.EqualValues(t, 0, sbc.BeginCount.Load())
+	}
+	// Every reserved connection is released, including the one whose lock failed.
+	assert.EqualValues(t, len(shardConns), released)
+	assert.False(t, session.InTransaction)
+}
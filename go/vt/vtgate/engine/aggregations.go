@@ -18,7 +18,10 @@ package engine
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/slice"
@@ -50,6 +53,24 @@ type AggregateParams struct {
 	// not what we use to aggregate at the engine primitive level.
 	OrigOpcode AggregateOpcode
 
+	// GroupingKeys are the offsets in the GroupByKeys of the arguments to a GROUPING() call
+	GroupingKeys []int
+
+	// DistinctCols are set when the input is not sorted by the distinct values.
+	// The values already seen are then tracked using hashing, which also allows for
+	// distinct aggregations with multiple arguments.
+	DistinctCols []CheckCol
+
+	// ConcatCols and OrderBy are used when a GROUP_CONCAT is evaluated on all the rows of a group.
+	// ConcatCols are the offsets of the arguments that are concatenated for every row,
+	// and OrderBy is the ordering of the values within the group.
+	ConcatCols []int
+	OrderBy    evalengine.Comparison
+
+	// StatisticsCols are the offsets of the count and the average of the values, when the input has the
+	// population variance of a group of values in Col for a VARIANCE or STDDEV. Without them, every row has a single value.
+	StatisticsCols []int
+
 	CollationEnv *collations.Environment
 }
 
@@ -79,6 +100,19 @@ func (ap *AggregateParams) String() string {
 	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
 		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
 	}
+	if len(ap.DistinctCols) > 0 {
+		// the aggregated column is the first of the distinct columns
+		keyCol = "HASH " + strings.Join(slice.Map(ap.DistinctCols, CheckCol.String), ", ")
+	} else if len(ap.ConcatCols) > 1 {
+		keyCol = strings.Join(slice.Map(ap.ConcatCols, strconv.Itoa), ", ")
+	} else if len(ap.StatisticsCols) > 0 {
+		keyCol = strings.Join(slice.Map(append([]int{ap.Col}, ap.StatisticsCols...), strconv.Itoa), ", ")
+	}
+	if len(ap.OrderBy) > 0 {
+		keyCol += " ORDER BY " + strings.Join(slice.Map(ap.OrderBy, func(obp evalengine.OrderByParams) string {
+			return obp.String()
+		}), ", ")
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -108,9 +142,22 @@ type aggregatorDistinct struct {
 	coll         collations.ID
 	collationEnv *collations.Environment
 	values       *evalengine.EnumSetValues
+
+	// seen is used instead of the last value when the input is not sorted by the distinct values
+	seen *probeTable
 }
 
 func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
+	if a.seen != nil {
+		for _, col := range a.seen.checkCols {
+			// like MySQL, we skip the rows where any of the distinct values is NULL
+			if row[col.Col].IsNull() {
+				return true, nil
+			}
+		}
+		found, err := a.seen.exists(row)
+		return found == nil, err
+	}
 	if a.column >= 0 {
 		last := a.last
 		next := row[a.column]
@@ -132,6 +179,9 @@ func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
 
 func (a *aggregatorDistinct) reset() {
 	a.last = sqltypes.NULL
+	if a.seen != nil {
+		clear(a.seen.seenRows)
+	}
 }
 
 type aggregatorCount struct {
@@ -255,27 +305,77 @@ func (a *aggregatorScalar) reset() {
 }
 
 type aggregatorGroupConcat struct {
-	from      int
 	type_     sqltypes.Type
 	separator []byte
+	maxLen    int
 
-	concat []byte
-	n      int
+	// cols are the columns concatenated for every row
+	cols     []int
+	distinct aggregatorDistinct
+	// order is set when the values have to be sorted before they are concatenated
+	order evalengine.Comparison
+	rows  []sqltypes.Row
+
+	concat    []byte
+	n         int
+	truncated bool
 }
 
-func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
-	if row[a.from].IsNull() {
+func (a *aggregatorGroupConcat) add(row []sqltypes.Value) (err error) {
+	// like MySQL, we skip the rows where any of the values is NULL
+	for _, col := range a.cols {
+		if row[col].IsNull() {
+			return nil
+		}
+	}
+	if ret, err := a.distinct.shouldReturn(row); ret {
+		return err
+	}
+	if a.order == nil {
+		a.append(row)
 		return nil
 	}
+
+	// the rows are sorted once the group is done. comparing every row with the previous one
+	// makes sure that values that can't be compared are reported here instead
+	defer evalengine.PanicHandler(&err)
+	if len(a.rows) > 0 {
+		a.order.Compare(a.rows[len(a.rows)-1], row)
+	}
+	a.rows = append(a.rows, row)
+	return nil
+}
+
+func (a *aggregatorGroupConcat) append(row []sqltypes.Value) {
+	if a.truncated {
+		return
+	}
 	if a.n > 0 {
 		a.concat = append(a.concat, a.separator...)
 	}
-	a.concat = append(a.concat, row[a.from].Raw()...)
+	for _, col := range a.cols {
+		a.concat = append(a.concat, row[col].Raw()...)
+	}
 	a.n++
-	return nil
+
+	if len(a.concat) > a.maxLen {
+		// the result is cut at group_concat_max_len, without splitting a multibyte character
+		end := a.maxLen
+		if sqltypes.IsText(a.type_) {
+			for end > 0 && !utf8.RuneStart(a.concat[end]) {
+				end--
+			}
+		}
+		a.concat = a.concat[:end]
+		a.truncated = true
+	}
 }
 
 func (a *aggregatorGroupConcat) finish() sqltypes.Value {
+	slices.SortStableFunc(a.rows, a.order.Compare)
+	for _, row := range a.rows {
+		a.append(row)
+	}
 	if a.n == 0 {
 		return sqltypes.NULL
 	}
@@ -285,6 +385,72 @@ func (a *aggregatorGroupConcat) finish() sqltypes.Value {
 func (a *aggregatorGroupConcat) reset() {
 	a.n = 0
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+	a.truncated = false
+	a.rows = nil
+	a.distinct.reset()
+}
+
+type aggregatorBitwise struct {
+	from    int
+	bitwise evalengine.Bitwise
+}
+
+func (a *aggregatorBitwise) add(row []sqltypes.Value) error {
+	return a.bitwise.Add(row[a.from])
+}
+
+func (a *aggregatorBitwise) finish() sqltypes.Value {
+	return a.bitwise.Result()
+}
+
+func (a *aggregatorBitwise) reset() {
+	a.bitwise.Reset()
+}
+
+// aggregatorJSON merges the JSON arrays or objects produced by JSON_ARRAYAGG or JSON_OBJECTAGG
+type aggregatorJSON struct {
+	from int
+	json evalengine.JSONAggregation
+}
+
+func (a *aggregatorJSON) add(row []sqltypes.Value) error {
+	return a.json.Add(row[a.from])
+}
+
+func (a *aggregatorJSON) finish() sqltypes.Value {
+	return a.json.Result()
+}
+
+func (a *aggregatorJSON) reset() {
+	a.json.Reset()
+}
+
+// aggregatorVariance merges the count, average and population variance of groups of values.
+// Without the count and average columns, every row is a group with a single value.
+type aggregatorVariance struct {
+	from      int
+	countCol  int
+	avgCol    int
+	variance  evalengine.Variance
+	singleVal bool
+}
+
+func (a *aggregatorVariance) add(row []sqltypes.Value) error {
+	if a.singleVal {
+		if row[a.from].IsNull() {
+			return nil
+		}
+		return a.variance.Add(sqltypes.NewInt64(1), row[a.from], sqltypes.NULL)
+	}
+	return a.variance.Add(row[a.countCol], row[a.avgCol], row[a.from])
+}
+
+func (a *aggregatorVariance) finish() sqltypes.Value {
+	return a.variance.Result()
+}
+
+func (a *aggregatorVariance) reset() {
+	a.variance.Reset()
 }
 
 type aggregatorGtid struct {
@@ -310,6 +476,20 @@ func (a *aggregatorGtid) reset() {
 	a.shards = a.shards[:0] // safe to reuse because only the serialized form of a.shards is returned
 }
 
+type aggregatorGrouping struct {
+	grouping evalengine.Grouping
+}
+
+func (a *aggregatorGrouping) add([]sqltypes.Value) error {
+	return nil
+}
+
+func (a *aggregatorGrouping) finish() sqltypes.Value {
+	return a.grouping.Result()
+}
+
+func (a *aggregatorGrouping) reset() {}
+
 type aggregationState []aggregator
 
 func (a aggregationState) add(row []sqltypes.Value) error {
@@ -335,6 +515,16 @@ func (a aggregationState) reset() {
 	}
 }
 
+// rollup marks the grouping keys from offset keep and on as rolled up,
+// for the aggregators that need to know this
+func (a aggregationState) rollup(keep int) {
+	for _, st := range a {
+		if g, ok := st.(*aggregatorGrouping); ok {
+			g.grouping.Rollup(keep)
+		}
+	}
+}
+
 func isComparable(typ sqltypes.Type) bool {
 	if typ == sqltypes.Null || sqltypes.IsNumber(typ) || sqltypes.IsBinary(typ) {
 		return true
@@ -354,9 +544,29 @@ func isComparable(typ sqltypes.Type) bool {
 	return false
 }
 
-func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
+// defaultGroupConcatMaxLen is the default value of group_concat_max_len in MySQL
+const defaultGroupConcatMaxLen = 1024
+
+// groupConcatMaxLen returns the maximum length of a GROUP_CONCAT result for the session
+func groupConcatMaxLen(vcursor VCursor) int {
+	maxLen := defaultGroupConcatMaxLen
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if !strings.EqualFold(k, "group_concat_max_len") {
+			return
+		}
+		if n, err := strconv.Atoi(strings.Trim(v, "'")); err == nil && n > 0 {
+			maxLen = n
+		}
+	})
+	return maxLen
+}
+
+func newAggregation(vcursor VCursor, fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
 	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
 
+	// the session is only consulted if there is a GROUP_CONCAT
+	maxLen := -1
+
 	agstate := make([]aggregator, len(fields))
 	for _, aggr := range aggregates {
 		sourceType := fields[aggr.Col].Type
@@ -365,6 +575,11 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 		var ag aggregator
 		var distinct = -1
 
+		var seen *probeTable
+		if len(aggr.DistinctCols) > 0 {
+			seen = newProbeTable(aggr.DistinctCols, aggr.CollationEnv)
+		}
+
 		if aggr.Opcode.IsDistinct() {
 			distinct = aggr.KeyCol
 			if aggr.WAssigned() && !isComparable(sourceType) {
@@ -390,6 +605,7 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 					coll:         aggr.Type.Collation(),
 					collationEnv: aggr.CollationEnv,
 					values:       aggr.Type.Values(),
+					seen:         seen,
 				},
 			}
 
@@ -410,6 +626,7 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 					coll:         aggr.Type.Collation(),
 					collationEnv: aggr.CollationEnv,
 					values:       aggr.Type.Values(),
+					seen:         seen,
 				},
 			}
 
@@ -429,20 +646,64 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 				},
 			}
 
+		case AggregateBitAnd:
+			ag = &aggregatorBitwise{from: aggr.Col, bitwise: evalengine.NewAggregationBitAnd()}
+
+		case AggregateBitOr:
+			ag = &aggregatorBitwise{from: aggr.Col, bitwise: evalengine.NewAggregationBitOr()}
+
+		case AggregateBitXor:
+			ag = &aggregatorBitwise{from: aggr.Col, bitwise: evalengine.NewAggregationBitXor()}
+
+		case AggregateJSONArrayAgg:
+			ag = &aggregatorJSON{from: aggr.Col, json: evalengine.NewAggregationJSONArray()}
+
+		case AggregateJSONObjectAgg:
+			ag = &aggregatorJSON{from: aggr.Col, json: evalengine.NewAggregationJSONObject()}
+
+		case AggregateStdDevPop, AggregateStdDevSamp, AggregateVarPop, AggregateVarSamp:
+			sample := aggr.Opcode == AggregateStdDevSamp || aggr.Opcode == AggregateVarSamp
+			stddev := aggr.Opcode == AggregateStdDevPop || aggr.Opcode == AggregateStdDevSamp
+			va := &aggregatorVariance{
+				from:      aggr.Col,
+				variance:  evalengine.NewAggregationVariance(sample, stddev),
+				singleVal: len(aggr.StatisticsCols) == 0,
+			}
+			if !va.singleVal {
+				va.countCol, va.avgCol = aggr.StatisticsCols[0], aggr.StatisticsCols[1]
+			}
+			ag = va
+
 		case AggregateGtid:
 			ag = &aggregatorGtid{from: aggr.Col}
 
 		case AggregateAnyValue:
 			ag = &aggregatorScalar{from: aggr.Col}
 
+		case AggregateGrouping:
+			ag = &aggregatorGrouping{grouping: evalengine.NewGrouping(aggr.GroupingKeys)}
+
 		case AggregateGroupConcat:
 			gcFunc := aggr.Func.(*sqlparser.GroupConcatExpr)
 			separator := []byte(gcFunc.Separator)
-			ag = &aggregatorGroupConcat{
-				from:      aggr.Col,
+			if maxLen < 0 {
+				maxLen = groupConcatMaxLen(vcursor)
+			}
+			gc := &aggregatorGroupConcat{
 				type_:     targetType,
 				separator: separator,
+				maxLen:    maxLen,
+				cols:      aggr.ConcatCols,
+				distinct:  aggregatorDistinct{column: -1, seen: seen},
+			}
+			if len(gc.cols) == 0 {
+				gc.cols = []int{aggr.Col}
+			}
+			if len(aggr.OrderBy) > 0 {
+				// the comparison can switch to the weight string columns, so every aggregation needs its own copy
+				gc.order = slices.Clone(aggr.OrderBy)
 			}
+			ag = gc
 
 		default:
 			panic("BUG: unexpected Aggregation opcode")
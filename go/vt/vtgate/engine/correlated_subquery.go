@@ -0,0 +1,239 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*CorrelatedSubquery)(nil)
+
+// CorrelatedSubquery filters the rows of the outer query using a subquery
+// that depends on values from the outer row. The subquery is executed once
+// for every distinct combination of correlation values in a batch of outer
+// rows, and its result is bound to SubqueryResult and HasValues before the
+// predicate is evaluated against the outer row.
+type CorrelatedSubquery struct {
+	Opcode PulloutOpcode
+
+	// SubqueryResult and HasValues are the bind variables the predicate uses to
+	// reference the subquery result.
+	SubqueryResult string
+	HasValues      string
+
+	// Vars defines the bind variables that are built from the
+	// outer row before executing the subquery.
+	Vars map[string]int
+
+	// Predicate is evaluated against every outer row. For PulloutAny and PulloutAll
+	// it is evaluated once per subquery row, and the results are combined.
+	Predicate    evalengine.Expr
+	ASTPredicate sqlparser.Expr
+
+	Outer    Primitive
+	Subquery Primitive
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (cs *CorrelatedSubquery) RouteType() string {
+	return cs.Opcode.String()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (cs *CorrelatedSubquery) GetKeyspaceName() string {
+	if cs.Outer.GetKeyspaceName() == cs.Subquery.GetKeyspaceName() {
+		return cs.Outer.GetKeyspaceName()
+	}
+	return cs.Outer.GetKeyspaceName() + "_" + cs.Subquery.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (cs *CorrelatedSubquery) GetTableName() string {
+	return cs.Outer.GetTableName() + "_" + cs.Subquery.GetTableName()
+}
+
+// TryExecute satisfies the Primitive interface.
+func (cs *CorrelatedSubquery) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	result, err := vcursor.ExecutePrimitive(ctx, cs.Outer, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	result.Rows, err = cs.filter(ctx, vcursor, bindVars, result.Rows)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TryStreamExecute satisfies the Primitive interface.
+func (cs *CorrelatedSubquery) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+
+	return vcursor.StreamExecutePrimitive(ctx, cs.Outer, bindVars, wantfields, func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		rows, err := cs.filter(ctx, vcursor, bindVars, result.Rows)
+		if err != nil {
+			return err
+		}
+		result.Rows = rows
+		return callback(result)
+	})
+}
+
+// GetFields implements the Primitive interface.
+func (cs *CorrelatedSubquery) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return cs.Outer.GetFields(ctx, vcursor, bindVars)
+}
+
+// Inputs returns the input primitives for this CorrelatedSubquery
+func (cs *CorrelatedSubquery) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{cs.Outer, cs.Subquery}, []map[string]any{{
+		inputName: "Outer",
+	}, {
+		inputName: "SubQuery",
+	}}
+}
+
+// NeedsTransaction implements the Primitive interface
+func (cs *CorrelatedSubquery) NeedsTransaction() bool {
+	return cs.Outer.NeedsTransaction() || cs.Subquery.NeedsTransaction()
+}
+
+// filter returns the outer rows that satisfy the predicate. Rows sharing the same
+// correlation values reuse the subquery result, so the subquery is executed once
+// per distinct set of values in the batch.
+func (cs *CorrelatedSubquery) filter(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	names := slices.Sorted(maps.Keys(cs.Vars))
+	cache := make(map[string][]sqltypes.Row)
+
+	subqueryVars := make(map[string]*querypb.BindVariable, len(cs.Vars))
+	predicateVars := make(map[string]*querypb.BindVariable, len(bindVars)+2)
+	maps.Copy(predicateVars, bindVars)
+	env := evalengine.NewExpressionEnv(ctx, predicateVars, vcursor)
+
+	var out []sqltypes.Row
+	for _, row := range rows {
+		key := cs.correlationKey(names, row)
+		sqRows, ok := cache[key]
+		if !ok {
+			for name, col := range cs.Vars {
+				subqueryVars[name] = sqltypes.ValueBindVariable(row[col])
+			}
+			result, err := vcursor.ExecutePrimitive(ctx, cs.Subquery, combineVars(bindVars, subqueryVars), false)
+			if err != nil {
+				return nil, err
+			}
+			sqRows = result.Rows
+			cache[key] = sqRows
+		}
+
+		env.Row = row
+		match, err := cs.evaluate(env, predicateVars, sqRows)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// correlationKey encodes the values of the correlation columns of a row
+func (cs *CorrelatedSubquery) correlationKey(names []string, row sqltypes.Row) string {
+	var sb strings.Builder
+	for _, name := range names {
+		v := row[cs.Vars[name]]
+		sb.WriteString(strconv.Itoa(int(v.Type())))
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(len(v.Raw())))
+		sb.WriteByte(':')
+		sb.Write(v.Raw())
+	}
+	return sb.String()
+}
+
+func (cs *CorrelatedSubquery) evaluate(env *evalengine.ExpressionEnv, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) (bool, error) {
+	switch cs.Opcode {
+	case PulloutAny, PulloutAll:
+		return cs.evaluateQuantified(env, bindVars, rows)
+	}
+
+	if err := setSubqueryVars(cs.Opcode, rows, cs.SubqueryResult, cs.HasValues, bindVars); err != nil {
+		return false, err
+	}
+	res, err := env.Evaluate(cs.Predicate)
+	if err != nil {
+		return false, err
+	}
+	return res.ToBoolean(), nil
+}
+
+// evaluateQuantified evaluates an ANY/ALL comparison by checking the predicate against
+// every value returned by the subquery. A NULL comparison never satisfies the filter,
+// so ANY needs one true comparison and ALL must not see anything but true ones.
+func (cs *CorrelatedSubquery) evaluateQuantified(env *evalengine.ExpressionEnv, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) (bool, error) {
+	isAny := cs.Opcode == PulloutAny
+	for _, row := range rows {
+		bindVars[cs.SubqueryResult] = sqltypes.ValueBindVariable(row[0])
+		res, err := env.Evaluate(cs.Predicate)
+		if err != nil {
+			return false, err
+		}
+		if res.ToBoolean() == isAny {
+			return isAny, nil
+		}
+	}
+	return !isAny, nil
+}
+
+func (cs *CorrelatedSubquery) description() PrimitiveDescription {
+	other := map[string]any{
+		"Predicate": sqlparser.String(cs.ASTPredicate),
+	}
+	if len(cs.Vars) > 0 {
+		other["JoinVars"] = orderedStringIntMap(cs.Vars)
+	}
+	var pulloutVars []string
+	if cs.HasValues != "" {
+		pulloutVars = append(pulloutVars, cs.HasValues)
+	}
+	if cs.SubqueryResult != "" {
+		pulloutVars = append(pulloutVars, cs.SubqueryResult)
+	}
+	if len(pulloutVars) > 0 {
+		other["PulloutVars"] = pulloutVars
+	}
+	return PrimitiveDescription{
+		OperatorType: "CorrelatedSubquery",
+		Variant:      cs.Opcode.String(),
+		Other:        other,
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func translateCorrelatedPredicate(t *testing.T, expr sqlparser.Expr, fields []*querypb.Field) evalengine.Expr {
+	t.Helper()
+	pred, err := evalengine.Translate(expr, &evalengine.Config{
+		Collation:     collations.MySQL8().LookupByName("utf8mb4_bin"),
+		ResolveColumn: evalengine.FieldResolver(fields).Column,
+		Environment:   vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+	return pred
+}
+
+func TestCorrelatedSubqueryValue(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(outerFields, "1|10", "2|20", "1|30"),
+		},
+	}
+	sqFields := sqltypes.MakeTestFields("x", "int64")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "15"),
+			sqltypes.MakeTestResult(sqFields, "25"),
+		},
+	}
+
+	// col > :sq
+	predicate := &sqlparser.ComparisonExpr{
+		Operator: sqlparser.GreaterThanOp,
+		Left:     sqlparser.NewColName("col"),
+		Right:    sqlparser.NewArgument("sq"),
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "sq",
+		Vars:           map[string]int{"id": 0},
+		Predicate:      translateCorrelatedPredicate(t, predicate, outerFields),
+		ASTPredicate:   predicate,
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	qr, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, `[[INT64(1) INT64(30)]]`, fmt.Sprintf("%v", qr.Rows))
+
+	// the third row has the same correlation value as the first one, so the subquery only runs twice
+	subquery.ExpectLog(t, []string{
+		`Execute id: type:INT64 value:"1" false`,
+		`Execute id: type:INT64 value:"2" false`,
+	})
+}
+
+func TestCorrelatedSubqueryValueBadRows(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	sqFields := sqltypes.MakeTestFields("x", "int64")
+	predicate := &sqlparser.ComparisonExpr{
+		Operator: sqlparser.EqualOp,
+		Left:     sqlparser.NewColName("col"),
+		Right:    sqlparser.NewArgument("sq"),
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "sq",
+		Vars:           map[string]int{"id": 0},
+		Predicate:      translateCorrelatedPredicate(t, predicate, outerFields),
+		ASTPredicate:   predicate,
+		Outer: &fakePrimitive{
+			results: []*sqltypes.Result{sqltypes.MakeTestResult(outerFields, "1|10")},
+		},
+		Subquery: &fakePrimitive{
+			results: []*sqltypes.Result{sqltypes.MakeTestResult(sqFields, "1", "2")},
+		},
+	}
+
+	_, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "subquery returned more than one row")
+}
+
+func TestCorrelatedSubqueryInNotIn(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	sqFields := sqltypes.MakeTestFields("x", "int64")
+
+	// :has_values and col in ::sq
+	in := sqlparser.AndExpressions(
+		sqlparser.NewArgument("has_values"),
+		&sqlparser.ComparisonExpr{
+			Operator: sqlparser.InOp,
+			Left:     sqlparser.NewColName("col"),
+			Right:    sqlparser.NewListArg("sq"),
+		})
+	// not :has_values or col not in ::sq
+	notIn := &sqlparser.OrExpr{
+		Left: sqlparser.NewNotExpr(sqlparser.NewArgument("has_values")),
+		Right: &sqlparser.ComparisonExpr{
+			Operator: sqlparser.NotInOp,
+			Left:     sqlparser.NewColName("col"),
+			Right:    sqlparser.NewListArg("sq"),
+		},
+	}
+
+	tcases := []struct {
+		opcode    PulloutOpcode
+		predicate sqlparser.Expr
+		expected  string
+	}{{
+		opcode:    PulloutIn,
+		predicate: in,
+		expected:  `[[INT64(1) INT64(10)]]`,
+	}, {
+		opcode:    PulloutNotIn,
+		predicate: notIn,
+		expected:  `[[INT64(1) INT64(30)] [INT64(2) INT64(20)]]`,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.opcode.String(), func(t *testing.T) {
+			subquery := &fakePrimitive{
+				results: []*sqltypes.Result{
+					sqltypes.MakeTestResult(sqFields, "10", "20"),
+					sqltypes.MakeTestResult(sqFields),
+				},
+			}
+			cs := &CorrelatedSubquery{
+				Opcode:         tc.opcode,
+				SubqueryResult: "sq",
+				HasValues:      "has_values",
+				Vars:           map[string]int{"id": 0},
+				Predicate:      translateCorrelatedPredicate(t, tc.predicate, outerFields),
+				ASTPredicate:   tc.predicate,
+				Outer: &fakePrimitive{
+					results: []*sqltypes.Result{sqltypes.MakeTestResult(outerFields, "1|10", "1|30", "2|20")},
+				},
+				Subquery: subquery,
+			}
+
+			qr, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fmt.Sprintf("%v", qr.Rows))
+			subquery.ExpectLog(t, []string{
+				`Execute id: type:INT64 value:"1" false`,
+				`Execute id: type:INT64 value:"2" false`,
+			})
+		})
+	}
+}
+
+func TestCorrelatedSubqueryAnyAll(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("col", "int64")
+	sqFields := sqltypes.MakeTestFields("x", "int64")
+
+	// col > :sq
+	predicate := &sqlparser.ComparisonExpr{
+		Operator: sqlparser.GreaterThanOp,
+		Left:     sqlparser.NewColName("col"),
+		Right:    sqlparser.NewArgument("sq"),
+	}
+
+	tcases := []struct {
+		name     string
+		opcode   PulloutOpcode
+		sqRows   []string
+		expected string
+	}{{
+		name:     "any",
+		opcode:   PulloutAny,
+		sqRows:   []string{"5", "15"},
+		expected: `[[INT64(10)] [INT64(20)]]`,
+	}, {
+		name:     "all",
+		opcode:   PulloutAll,
+		sqRows:   []string{"5", "15"},
+		expected: `[[INT64(20)]]`,
+	}, {
+		name:     "all with null",
+		opcode:   PulloutAll,
+		sqRows:   []string{"5", "null"},
+		expected: `[]`,
+	}, {
+		name:     "any without rows",
+		opcode:   PulloutAny,
+		expected: `[]`,
+	}, {
+		name:     "all without rows",
+		opcode:   PulloutAll,
+		expected: `[[INT64(1)] [INT64(10)] [INT64(20)] [NULL]]`,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			subquery := &fakePrimitive{
+				results: []*sqltypes.Result{sqltypes.MakeTestResult(sqFields, tc.sqRows...)},
+			}
+			cs := &CorrelatedSubquery{
+				Opcode:         tc.opcode,
+				SubqueryResult: "sq",
+				Predicate:      translateCorrelatedPredicate(t, predicate, outerFields),
+				ASTPredicate:   predicate,
+				Outer: &fakePrimitive{
+					results: []*sqltypes.Result{sqltypes.MakeTestResult(outerFields, "1", "10", "20", "null")},
+				},
+				Subquery: subquery,
+			}
+
+			qr, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fmt.Sprintf("%v", qr.Rows))
+			// without correlation values, the subquery only has to run once
+			subquery.ExpectLog(t, []string{`Execute  false`})
+		})
+	}
+}
+
+func TestCorrelatedSubqueryStream(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|int64")
+	sqFields := sqltypes.MakeTestFields("x", "int64")
+	predicate := sqlparser.NewNotExpr(sqlparser.NewArgument("has_values"))
+	cs := &CorrelatedSubquery{
+		Opcode:    PulloutExists,
+		HasValues: "has_values",
+		Vars:      map[string]int{"id": 0},
+		Predicate: translateCorrelatedPredicate(t, predicate, outerFields),
+		Outer: &fakePrimitive{
+			results:             sqltypes.MakeTestStreamingResults(outerFields, "1|10", "---", "2|20", "3|30"),
+			allResultsInOneCall: true,
+		},
+		Subquery: &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(sqFields, "1"),
+				sqltypes.MakeTestResult(sqFields),
+				sqltypes.MakeTestResult(sqFields, "1"),
+			},
+		},
+	}
+
+	qr, err := wrapStreamExecute(cs, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, `[[INT64(2) INT64(20)]]`, fmt.Sprintf("%v", qr.Rows))
+}
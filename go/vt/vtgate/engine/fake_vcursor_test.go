@@ -187,9 +187,7 @@ func (t *noopVCursor) HasSystemVariables() bool {
 	panic("implement me")
 }
 
-func (t *noopVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
-}
+func (t *noopVCursor) GetSystemVariables(func(k string, v string)) {}
 
 func (t *noopVCursor) GetWarnings() []*querypb.QueryWarning {
 	panic("implement me")
@@ -251,6 +249,10 @@ func (t *noopVCursor) SetSessionTrackGTIDs(b bool) {
 	panic("implement me")
 }
 
+func (t *noopVCursor) SetReadAfterWriteAuto(ctx context.Context, b bool) error {
+	panic("implement me")
+}
+
 func (t *noopVCursor) HasCreatedTempTable() {
 	panic("implement me")
 }
@@ -329,6 +331,10 @@ func (t *noopVCursor) SetWorkload(querypb.ExecuteOptions_Workload) {
 	panic("implement me")
 }
 
+func (t *noopVCursor) SetScatterConsistency(vtgatepb.ScatterConsistency) {
+	panic("implement me")
+}
+
 func (t *noopVCursor) SetWorkloadName(string) {
 	panic("implement me")
 }
@@ -507,8 +513,10 @@ func (f *loggingVCursor) HasSystemVariables() bool {
 	return len(f.systemVariables) > 0
 }
 
-func (f *loggingVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
+func (f *loggingVCursor) GetSystemVariables(fn func(k string, v string)) {
+	for k, v := range f.systemVariables {
+		fn(k, v)
+	}
 }
 
 func (f *loggingVCursor) SetFoundRows(u uint64) {
@@ -833,6 +841,10 @@ func (f *loggingVCursor) SetWorkload(querypb.ExecuteOptions_Workload) {
 	panic("implement me")
 }
 
+func (f *loggingVCursor) SetScatterConsistency(vtgatepb.ScatterConsistency) {
+	panic("implement me")
+}
+
 func (f *loggingVCursor) SetWorkloadName(string) {
 	panic("implement me")
 }
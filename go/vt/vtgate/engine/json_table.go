@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*JSONTable)(nil)
+
+// JSONTable is a primitive that expands a JSON document into rows on the vtgate,
+// the same way the JSON_TABLE table function does in MySQL.
+// It is used when the JSON_TABLE can't be sent down to the shards together with
+// the table producing the document, in which case the document arrives as a bind variable.
+type JSONTable struct {
+	// JSONTable does not take inputs
+	noInputs
+
+	// JSONTable does not need to work inside a tx
+	noTxNeeded
+
+	// Fields is the field info for the result.
+	Fields []*querypb.Field
+	// Cols are the offsets of the JSON_TABLE columns returned
+	Cols  []int
+	Table *evalengine.JSONTable
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (jt *JSONTable) RouteType() string {
+	return "JSONTable"
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (jt *JSONTable) GetKeyspaceName() string {
+	return ""
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (jt *JSONTable) GetTableName() string {
+	return ""
+}
+
+// TryExecute performs a non-streaming exec.
+func (jt *JSONTable) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	return jt.expand(ctx, vcursor, bindVars)
+}
+
+// TryStreamExecute performs a streaming exec.
+func (jt *JSONTable) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	r, err := jt.expand(ctx, vcursor, bindVars)
+	if err != nil {
+		return err
+	}
+	if err := callback(r.Metadata()); err != nil {
+		return err
+	}
+	return callback(&sqltypes.Result{Rows: r.Rows})
+}
+
+// GetFields fetches the field info.
+func (jt *JSONTable) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: jt.Fields}, nil
+}
+
+func (jt *JSONTable) expand(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	rows, err := jt.Table.Evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+	result := &sqltypes.Result{Fields: jt.Fields}
+	for _, row := range rows {
+		out := make(sqltypes.Row, 0, len(jt.Cols))
+		for _, col := range jt.Cols {
+			out = append(out, row[col])
+		}
+		result.Rows = append(result.Rows, out)
+	}
+	return result, nil
+}
+
+func (jt *JSONTable) description() PrimitiveDescription {
+	var columns []string
+	for _, col := range jt.Cols {
+		columns = append(columns, jt.Table.Columns[col].Name)
+	}
+	return PrimitiveDescription{
+		OperatorType: "JSONTable",
+		Other: map[string]any{
+			"Document": sqlparser.String(jt.Table.Doc),
+			"Columns":  columns,
+		},
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func newTestJSONTable(t *testing.T, columns string) *JSONTable {
+	stmt, err := sqlparser.NewTestParser().Parse("select * from json_table(:doc, '$[*]' columns(" + columns + ")) as jt")
+	require.NoError(t, err)
+	expr := stmt.(*sqlparser.Select).From[0].(*sqlparser.JSONTableExpr)
+
+	table, err := evalengine.TranslateJSONTable(expr, &evalengine.Config{
+		Collation:   collations.MySQL8().DefaultConnectionCharset(),
+		Environment: vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+
+	jt := &JSONTable{Table: table}
+	for idx, col := range table.Columns {
+		jt.Cols = append(jt.Cols, idx)
+		jt.Fields = append(jt.Fields, col.Type.ToField(col.Name))
+	}
+	return jt
+}
+
+func TestJSONTableExecute(t *testing.T) {
+	jt := newTestJSONTable(t, "idx for ordinality, a int path '$.a', b varchar(10) path '$.b' default 'err' on error, nested path '$.n[*]' columns(n int path '$')")
+
+	tcases := []struct {
+		doc    *querypb.BindVariable
+		expRes string
+	}{{
+		doc:    sqltypes.StringBindVariable(`[{"a": 1, "b": "x", "n": [1, 2]}, {"b": [1]}]`),
+		expRes: `[[UINT32(1) INT32(1) VARCHAR("x") INT32(1)] [UINT32(1) INT32(1) VARCHAR("x") INT32(2)] [UINT32(2) NULL VARCHAR("err") NULL]]`,
+	}, {
+		doc:    sqltypes.StringBindVariable(`[{"a": 42, "b": null}]`),
+		expRes: `[[UINT32(1) INT32(42) NULL NULL]]`,
+	}, {
+		doc:    sqltypes.NullBindVariable,
+		expRes: `[]`,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.expRes, func(t *testing.T) {
+			bv := map[string]*querypb.BindVariable{"doc": tc.doc}
+			qr, err := jt.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+			require.NoError(t, err)
+			require.Equal(t, jt.Fields, qr.Fields)
+			require.Equal(t, tc.expRes, fmt.Sprintf("%v", qr.Rows))
+
+			qr, err = wrapStreamExecute(jt, &noopVCursor{}, bv, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.expRes, fmt.Sprintf("%v", qr.Rows))
+		})
+	}
+}
+
+func TestJSONTableErrors(t *testing.T) {
+	jt := newTestJSONTable(t, "a int path '$.a' error on empty")
+	_, err := jt.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{
+		"doc": sqltypes.StringBindVariable(`[{"b": 1}]`),
+	}, true)
+	require.ErrorContains(t, err, "Missing value for JSON_TABLE column 'a'")
+
+	jt = newTestJSONTable(t, "a int path '$.a' error on error")
+	_, err = jt.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{
+		"doc": sqltypes.StringBindVariable(`[{"a": {"b": 1}}]`),
+	}, true)
+	require.ErrorContains(t, err, "Can't store an array or an object in the scalar column 'a' of JSON_TABLE")
+}
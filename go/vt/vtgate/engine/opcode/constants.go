@@ -36,6 +36,8 @@ const (
 	PulloutNotIn
 	PulloutExists
 	PulloutNotExists
+	PulloutAny
+	PulloutAll
 )
 
 var pulloutName = map[PulloutOpcode]string{
@@ -44,6 +46,8 @@ var pulloutName = map[PulloutOpcode]string{
 	PulloutNotIn:     "PulloutNotIn",
 	PulloutExists:    "PulloutExists",
 	PulloutNotExists: "PulloutNotExists",
+	PulloutAny:       "PulloutAny",
+	PulloutAll:       "PulloutAll",
 }
 
 func (code PulloutOpcode) String() string {
@@ -77,6 +81,16 @@ const (
 	AggregateCountStar
 	AggregateGroupConcat
 	AggregateAvg
+	AggregateGrouping
+	AggregateBitAnd
+	AggregateBitOr
+	AggregateBitXor
+	AggregateStdDevPop
+	AggregateStdDevSamp
+	AggregateVarPop
+	AggregateVarSamp
+	AggregateJSONArrayAgg
+	AggregateJSONObjectAgg
 	AggregateUDF  // This is an opcode used to represent UDFs
 	_NumOfOpCodes // This line must be last of the opcodes!
 )
@@ -89,6 +103,19 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"min":   AggregateMin,
 	"max":   AggregateMax,
 	"avg":   AggregateAvg,
+
+	"bit_and":        AggregateBitAnd,
+	"bit_or":         AggregateBitOr,
+	"bit_xor":        AggregateBitXor,
+	"std":            AggregateStdDevPop,
+	"stddev":         AggregateStdDevPop,
+	"stddev_pop":     AggregateStdDevPop,
+	"stddev_samp":    AggregateStdDevSamp,
+	"variance":       AggregateVarPop,
+	"var_pop":        AggregateVarPop,
+	"var_samp":       AggregateVarSamp,
+	"json_arrayagg":  AggregateJSONArrayAgg,
+	"json_objectagg": AggregateJSONObjectAgg,
 	// These functions don't exist in mysql, but are used
 	// to display the plan.
 	"count_distinct": AggregateCountDistinct,
@@ -97,6 +124,7 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"count_star":     AggregateCountStar,
 	"any_value":      AggregateAnyValue,
 	"group_concat":   AggregateGroupConcat,
+	"grouping":       AggregateGrouping,
 }
 
 var AggregateName = map[AggregateOpcode]string{
@@ -111,6 +139,16 @@ var AggregateName = map[AggregateOpcode]string{
 	AggregateGroupConcat:   "group_concat",
 	AggregateAnyValue:      "any_value",
 	AggregateAvg:           "avg",
+	AggregateGrouping:      "grouping",
+	AggregateBitAnd:        "bit_and",
+	AggregateBitOr:         "bit_or",
+	AggregateBitXor:        "bit_xor",
+	AggregateStdDevPop:     "stddev_pop",
+	AggregateStdDevSamp:    "stddev_samp",
+	AggregateVarPop:        "var_pop",
+	AggregateVarSamp:       "var_samp",
+	AggregateJSONArrayAgg:  "json_arrayagg",
+	AggregateJSONObjectAgg: "json_objectagg",
 }
 
 func (code AggregateOpcode) String() string {
@@ -150,8 +188,18 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 			return sqltypes.Decimal
 		}
 		return sqltypes.Float64
-	case AggregateCount, AggregateCountStar, AggregateCountDistinct:
+	case AggregateCount, AggregateCountStar, AggregateCountDistinct, AggregateGrouping:
 		return sqltypes.Int64
+	case AggregateBitAnd, AggregateBitOr, AggregateBitXor:
+		// binary strings are combined byte by byte, everything else as unsigned 64-bit integers
+		if sqltypes.IsBinary(typ) {
+			return sqltypes.VarBinary
+		}
+		return sqltypes.Uint64
+	case AggregateStdDevPop, AggregateStdDevSamp, AggregateVarPop, AggregateVarSamp:
+		return sqltypes.Float64
+	case AggregateJSONArrayAgg, AggregateJSONObjectAgg:
+		return sqltypes.TypeJSON
 	case AggregateGtid:
 		return sqltypes.VarChar
 	case AggregateUDF:
@@ -163,7 +211,7 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 
 func (code AggregateOpcode) Nullable() bool {
 	switch code {
-	case AggregateCount, AggregateCountStar:
+	case AggregateCount, AggregateCountStar, AggregateGrouping, AggregateBitAnd, AggregateBitOr, AggregateBitXor:
 		return false
 	default:
 		return true
@@ -200,3 +248,86 @@ func (code AggregateOpcode) IsDistinct() bool {
 		return false
 	}
 }
+
+// WindowOpcode is the opcode for window functions evaluated at the vtgate level.
+type WindowOpcode int
+
+// These constants list the possible window function opcodes.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowPercentRank
+	WindowCumeDist
+	WindowNtile
+	WindowLag
+	WindowLead
+	WindowFirstValue
+	WindowLastValue
+	WindowNthValue
+	// WindowAggregate is an aggregate function used as a window function.
+	// The aggregation to use is specified separately using an AggregateOpcode
+	WindowAggregate
+	_NumOfWindowOpCodes // This line must be last of the opcodes!
+)
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber:   "row_number",
+	WindowRank:        "rank",
+	WindowDenseRank:   "dense_rank",
+	WindowPercentRank: "percent_rank",
+	WindowCumeDist:    "cume_dist",
+	WindowNtile:       "ntile",
+	WindowLag:         "lag",
+	WindowLead:        "lead",
+	WindowFirstValue:  "first_value",
+	WindowLastValue:   "last_value",
+	WindowNthValue:    "nth_value",
+	WindowAggregate:   "aggregate",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// SQLType returns the type of the window function result. For window functions that
+// return a value from the input, the input type is returned. The type of WindowAggregate
+// depends on the aggregation being used, so the caller has to resolve it.
+func (code WindowOpcode) SQLType(typ querypb.Type) querypb.Type {
+	switch code {
+	case WindowUnassigned:
+		return sqltypes.Null
+	case WindowAggregate:
+		return typ
+	case WindowRowNumber, WindowRank, WindowDenseRank, WindowNtile:
+		return sqltypes.Uint64
+	case WindowPercentRank, WindowCumeDist:
+		return sqltypes.Float64
+	case WindowLag, WindowLead, WindowFirstValue, WindowLastValue, WindowNthValue:
+		return typ
+	default:
+		panic(code.String()) // we have a unit test checking we never reach here
+	}
+}
+
+// UsesFrame returns true if the window function is evaluated over the window frame,
+// and false if it is evaluated over the whole partition.
+func (code WindowOpcode) UsesFrame() bool {
+	switch code {
+	case WindowFirstValue, WindowLastValue, WindowNthValue, WindowAggregate:
+		return true
+	default:
+		return false
+	}
+}
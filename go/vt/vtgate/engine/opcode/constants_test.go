@@ -52,6 +52,14 @@ func TestType(t *testing.T) {
 		{AggregateCount, sqltypes.Int32, sqltypes.Int64},
 		{AggregateCountStar, sqltypes.Int64, sqltypes.Int64},
 		{AggregateGtid, sqltypes.VarChar, sqltypes.VarChar},
+		{AggregateGrouping, sqltypes.VarChar, sqltypes.Int64},
+		{AggregateBitAnd, sqltypes.Int32, sqltypes.Uint64},
+		{AggregateBitOr, sqltypes.VarChar, sqltypes.Uint64},
+		{AggregateBitXor, sqltypes.VarBinary, sqltypes.VarBinary},
+		{AggregateStdDevPop, sqltypes.Int64, sqltypes.Float64},
+		{AggregateVarSamp, sqltypes.Decimal, sqltypes.Float64},
+		{AggregateJSONArrayAgg, sqltypes.Int64, sqltypes.TypeJSON},
+		{AggregateJSONObjectAgg, sqltypes.VarChar, sqltypes.TypeJSON},
 	}
 
 	for _, tc := range tt {
@@ -83,6 +91,8 @@ func TestNeedsListArg(t *testing.T) {
 		{PulloutNotIn, true},
 		{PulloutExists, false},
 		{PulloutNotExists, false},
+		{PulloutAny, false},
+		{PulloutAll, false},
 	}
 
 	for _, tc := range tt {
@@ -103,6 +113,8 @@ func TestPulloutOpcode_MarshalJSON(t *testing.T) {
 		{PulloutNotIn, "\"PulloutNotIn\""},
 		{PulloutExists, "\"PulloutExists\""},
 		{PulloutNotExists, "\"PulloutNotExists\""},
+		{PulloutAny, "\"PulloutAny\""},
+		{PulloutAll, "\"PulloutAll\""},
 	}
 
 	for _, tc := range tt {
@@ -161,3 +173,30 @@ func TestIsDistinct(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckAllWindowOpCodes(t *testing.T) {
+	// This test is just checking that we never reach the panic when using Type() on valid opcodes
+	for i := WindowOpcode(0); i < _NumOfWindowOpCodes; i++ {
+		i.SQLType(sqltypes.Null)
+	}
+}
+
+func TestWindowType(t *testing.T) {
+	tt := []struct {
+		opcode WindowOpcode
+		typ    querypb.Type
+		out    querypb.Type
+	}{
+		{WindowRowNumber, sqltypes.VarChar, sqltypes.Uint64},
+		{WindowDenseRank, sqltypes.Null, sqltypes.Uint64},
+		{WindowCumeDist, sqltypes.Int64, sqltypes.Float64},
+		{WindowLag, sqltypes.VarChar, sqltypes.VarChar},
+		{WindowNthValue, sqltypes.Decimal, sqltypes.Decimal},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.opcode.String()+"_"+tc.typ.String(), func(t *testing.T) {
+			assert.Equal(t, tc.out, tc.opcode.SQLType(tc.typ))
+		})
+	}
+}
@@ -51,6 +51,11 @@ type OrderedAggregate struct {
 
 	// Input is the primitive that will feed into this Primitive.
 	Input Primitive
+
+	// WithRollup adds the super-aggregate rows of GROUP BY ... WITH ROLLUP.
+	// After each group, a row is produced for every prefix of the GroupByKeys that
+	// ended with it, with the rolled up grouping keys set to NULL.
+	WithRollup bool
 }
 
 // GroupByParams specify the grouping key to be used.
@@ -142,11 +147,14 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	if err != nil {
 		return nil, err
 	}
+	if oa.WithRollup {
+		return oa.executeRollup(vcursor, result)
+	}
 	if len(oa.Aggregates) == 0 {
 		return oa.executeGroupBy(result)
 	}
 
-	agg, fields, err := newAggregation(result.Fields, oa.Aggregates)
+	agg, fields, err := newAggregation(vcursor, result.Fields, oa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -182,6 +190,75 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	return out, nil
 }
 
+func (oa *OrderedAggregate) executeRollup(vcursor VCursor, result *sqltypes.Result) (*sqltypes.Result, error) {
+	r, fields, err := oa.newRollup(vcursor, result.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &sqltypes.Result{
+		Fields: fields,
+		Rows:   make([][]sqltypes.Value, 0, len(result.Rows)),
+	}
+	for _, row := range result.Rows {
+		rows, err := r.add(row)
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+	}
+	out.Rows = append(out.Rows, r.finish()...)
+	return out, nil
+}
+
+func (oa *OrderedAggregate) executeStreamRollup(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) error {
+	cb := func(qr *sqltypes.Result) error {
+		return callback(qr.Truncate(oa.TruncateColumnCount))
+	}
+
+	var r *rollup
+	visitor := func(qr *sqltypes.Result) error {
+		if r == nil && len(qr.Fields) != 0 {
+			var fields []*querypb.Field
+			var err error
+			r, fields, err = oa.newRollup(vcursor, qr.Fields)
+			if err != nil {
+				return err
+			}
+			if err = cb(&sqltypes.Result{Fields: fields}); err != nil {
+				return err
+			}
+		}
+
+		var out []sqltypes.Row
+		for _, row := range qr.Rows {
+			rows, err := r.add(row)
+			if err != nil {
+				return err
+			}
+			out = append(out, rows...)
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return cb(&sqltypes.Result{Rows: out})
+	}
+
+	/* we need the input fields types to correctly calculate the output types */
+	err := vcursor.StreamExecutePrimitive(ctx, oa.Input, bindVars, true, visitor)
+	if err != nil {
+		return err
+	}
+
+	if r == nil {
+		return nil
+	}
+	if rows := r.finish(); len(rows) > 0 {
+		return cb(&sqltypes.Result{Rows: rows})
+	}
+	return nil
+}
+
 func (oa *OrderedAggregate) executeStreamGroupBy(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) error {
 	cb := func(qr *sqltypes.Result) error {
 		return callback(qr.Truncate(oa.TruncateColumnCount))
@@ -235,6 +312,9 @@ func (oa *OrderedAggregate) executeStreamGroupBy(ctx context.Context, vcursor VC
 
 // TryStreamExecute is a Primitive function.
 func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	if oa.WithRollup {
+		return oa.executeStreamRollup(ctx, vcursor, bindVars, callback)
+	}
 	if len(oa.Aggregates) == 0 {
 		return oa.executeStreamGroupBy(ctx, vcursor, bindVars, callback)
 	}
@@ -251,7 +331,7 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		var err error
 
 		if agg == nil && len(qr.Fields) != 0 {
-			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates)
+			agg, fields, err = newAggregation(vcursor, qr.Fields, oa.Aggregates)
 			if err != nil {
 				return err
 			}
@@ -306,7 +386,7 @@ func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, oa.Aggregates)
+	_, fields, err := newAggregation(vcursor, qr.Fields, oa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -330,30 +410,120 @@ func (oa *OrderedAggregate) nextGroupBy(currentKey, nextRow []sqltypes.Value) (n
 		return nextRow, false, nil
 	}
 
-	for _, gb := range oa.GroupByKeys {
+	idx, err := oa.firstDifferentKey(currentKey, nextRow)
+	if err != nil {
+		return nil, false, err
+	}
+	if idx >= 0 {
+		return nextRow, true, nil
+	}
+	return currentKey, false, nil
+}
+
+// firstDifferentKey returns the offset of the first grouping key that differs between the two rows,
+// or -1 if the rows belong to the same group
+func (oa *OrderedAggregate) firstDifferentKey(currentKey, nextRow []sqltypes.Value) (int, error) {
+	for idx, gb := range oa.GroupByKeys {
 		v1 := currentKey[gb.KeyCol]
 		v2 := nextRow[gb.KeyCol]
 		if v1.TinyWeightCmp(v2) != 0 {
-			return nextRow, true, nil
+			return idx, nil
 		}
 
 		cmp, err := evalengine.NullsafeCompare(v1, v2, gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 		if err != nil {
 			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
 			if !isCollationErr || gb.WeightStringCol == -1 {
-				return nil, false, err
+				return -1, err
 			}
 			gb.KeyCol = gb.WeightStringCol
 			cmp, err = evalengine.NullsafeCompare(currentKey[gb.WeightStringCol], nextRow[gb.WeightStringCol], gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 			if err != nil {
-				return nil, false, err
+				return -1, err
 			}
 		}
 		if cmp != 0 {
-			return nextRow, true, nil
+			return idx, nil
 		}
 	}
-	return currentKey, false, nil
+	return -1, nil
+}
+
+// rollup produces the rows for GROUP BY ... WITH ROLLUP. It keeps one aggregation for each
+// level of grouping: levels[i] aggregates the rows sharing the first i grouping keys,
+// so the last level is the regular aggregation and the first one is the grand total.
+type rollup struct {
+	oa         *OrderedAggregate
+	levels     []aggregationState
+	currentKey []sqltypes.Value
+}
+
+func (oa *OrderedAggregate) newRollup(vcursor VCursor, fields []*querypb.Field) (*rollup, []*querypb.Field, error) {
+	r := &rollup{
+		oa:     oa,
+		levels: make([]aggregationState, len(oa.GroupByKeys)+1),
+	}
+	var outFields []*querypb.Field
+	for keep := range r.levels {
+		agg, aggFields, err := newAggregation(vcursor, fields, oa.Aggregates)
+		if err != nil {
+			return nil, nil, err
+		}
+		agg.rollup(keep)
+		r.levels[keep] = agg
+		outFields = aggFields
+	}
+	return r, outFields, nil
+}
+
+// add aggregates the row, and returns the rows for all the groups that ended before it
+func (r *rollup) add(row []sqltypes.Value) ([]sqltypes.Row, error) {
+	var out []sqltypes.Row
+	if r.currentKey != nil {
+		idx, err := r.oa.firstDifferentKey(r.currentKey, row)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= 0 {
+			// the groups that include the changed key are done
+			out = r.produce(idx + 1)
+			r.currentKey = row
+		}
+	} else {
+		r.currentKey = row
+	}
+
+	for _, level := range r.levels {
+		if err := level.add(row); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// finish returns the rows for all the groups that are still open, ending with the grand total
+func (r *rollup) finish() []sqltypes.Row {
+	if r.currentKey == nil {
+		return nil
+	}
+	return r.produce(0)
+}
+
+// produce returns the rows for the levels keeping at least the given number of grouping keys, and resets them
+func (r *rollup) produce(keep int) []sqltypes.Row {
+	var out []sqltypes.Row
+	for level := len(r.levels) - 1; level >= keep; level-- {
+		row := r.levels[level].finish()
+		for _, gb := range r.oa.GroupByKeys[level:] {
+			row[gb.KeyCol] = sqltypes.NULL
+			if gb.WeightStringCol >= 0 {
+				row[gb.WeightStringCol] = sqltypes.NULL
+			}
+		}
+		r.levels[level].reset()
+		out = append(out, row)
+	}
+	return out
 }
 func aggregateParamsToString(in any) string {
 	return in.(*AggregateParams).String()
@@ -373,6 +543,9 @@ func (oa *OrderedAggregate) description() PrimitiveDescription {
 	if oa.TruncateColumnCount > 0 {
 		other["ResultColumns"] = oa.TruncateColumnCount
 	}
+	if oa.WithRollup {
+		other["WithRollup"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType: "Aggregate",
 		Variant:      "Ordered",
@@ -875,6 +875,72 @@ func TestMultiDistinct(t *testing.T) {
 	utils.MustMatch(t, want, results)
 }
 
+func TestMultiDistinctHashed(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3|c4",
+		"int64|int64|varchar|int64",
+	)
+	// the input is only sorted by the grouping column, so the distinct values have to be hashed
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"10|2|a|1",
+			"10|2|A|2",
+			"10|2|b|1",
+			"10|null|a|3",
+			"10|1|null|2",
+			"20|1|a|1",
+			"20|1|a|1",
+			"30|null|null|null",
+		)},
+	}
+
+	hashCol := func(col int, typ sqltypes.Type, coll collations.ID) CheckCol {
+		return CheckCol{Col: col, Type: evalengine.NewType(typ, coll), CollationEnv: collations.MySQL8()}
+	}
+	countC2 := NewAggregateParam(AggregateCountDistinct, 1, "count(distinct c2)", collations.MySQL8())
+	countC2.DistinctCols = []CheckCol{hashCol(1, sqltypes.Int64, collations.CollationBinaryID)}
+	countC3C2 := NewAggregateParam(AggregateCountDistinct, 2, "count(distinct c3, c2)", collations.MySQL8())
+	countC3C2.DistinctCols = []CheckCol{
+		hashCol(2, sqltypes.VarChar, collations.CollationUtf8mb4ID),
+		hashCol(1, sqltypes.Int64, collations.CollationBinaryID),
+	}
+	sumC4 := NewAggregateParam(AggregateSumDistinct, 3, "sum(distinct c4)", collations.MySQL8())
+	sumC4.DistinctCols = []CheckCol{hashCol(3, sqltypes.Int64, collations.CollationBinaryID)}
+
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{countC2, countC3C2, sumC4},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       fp,
+	}
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"c1|count(distinct c2)|count(distinct c3, c2)|sum(distinct c4)",
+			"int64|int64|int64|decimal",
+		),
+		`10|2|2|6`,
+		`20|1|1|1`,
+		`30|0|0|null`,
+	)
+
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, qr)
+
+	fp.rewind()
+	results := &sqltypes.Result{}
+	err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			results.Fields = qr.Fields
+		}
+		results.Rows = append(results.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, want, results)
+}
+
 func TestOrderedAggregateCollate(t *testing.T) {
 	fields := sqltypes.MakeTestFields(
 		"col|count(*)",
@@ -1169,3 +1235,140 @@ func TestGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// TestGroupConcatOrderByDistinct tests group_concat with ORDER BY, DISTINCT, SEPARATOR and multiple columns evaluated on the vtgate.
+func TestGroupConcatOrderByDistinct(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c4|c3",
+		"int64|varchar|varchar|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"10|b|x|2",
+			"10|a|y|3",
+			"10|c|null|1",
+			"10|a|z|3",
+			"10|null|x|4",
+			"20|a|x|1",
+			"30|null|null|null",
+		)},
+	}
+
+	// group_concat(distinct c2 order by c2 desc separator ';')
+	distinctDesc := NewAggregateParam(AggregateGroupConcat, 1, "a", collations.MySQL8())
+	distinctDesc.Func = &sqlparser.GroupConcatExpr{Separator: ";"}
+	distinctDesc.DistinctCols = []CheckCol{{Col: 1, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID), CollationEnv: collations.MySQL8()}}
+	distinctDesc.OrderBy = evalengine.Comparison{{Col: 1, WeightStringCol: -1, Desc: true, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID), CollationEnv: collations.MySQL8()}}
+
+	// group_concat(c2, c4 order by c3)
+	multiCol := NewAggregateParam(AggregateGroupConcat, 2, "b", collations.MySQL8())
+	multiCol.Func = &sqlparser.GroupConcatExpr{Separator: ","}
+	multiCol.ConcatCols = []int{1, 2}
+	multiCol.OrderBy = evalengine.Comparison{{Col: 3, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collations.MySQL8()}}
+
+	oa := &OrderedAggregate{
+		Aggregates:          []*AggregateParams{distinctDesc, multiCol},
+		GroupByKeys:         []*GroupByParams{{KeyCol: 0}},
+		TruncateColumnCount: 3,
+		Input:               fp,
+	}
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"c1|a|b",
+			"int64|text|text",
+		),
+		`10|c;b;a|bx,ay,az`,
+		`20|a|ax`,
+		`30|null|null`,
+	)
+
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, qr)
+
+	fp.rewind()
+	results := &sqltypes.Result{}
+	err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			results.Fields = qr.Fields
+		}
+		results.Rows = append(results.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, want, results)
+}
+
+func TestOrderedAggregateRollup(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|b|grouping(a, b)|count(*)",
+		"varbinary|varbinary|int64|int64",
+	)
+	tcases := []struct {
+		name  string
+		input *sqltypes.Result
+		want  *sqltypes.Result
+	}{{
+		name: "two grouping keys",
+		input: sqltypes.MakeTestResult(fields,
+			"a|x|0|1",
+			"a|x|0|2",
+			"a|y|0|3",
+			"b|x|0|4",
+			"c|null|0|5",
+			"c|z|0|6",
+		),
+		want: sqltypes.MakeTestResult(fields,
+			"a|x|0|3",
+			"a|y|0|3",
+			"a|null|1|6",
+			"b|x|0|4",
+			"b|null|1|4",
+			"c|null|0|5",
+			"c|z|0|6",
+			"c|null|1|11",
+			"null|null|3|21",
+		),
+	}, {
+		name:  "empty input",
+		input: sqltypes.MakeTestResult(fields),
+		want:  sqltypes.MakeTestResult(fields),
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{tcase.input}}
+			grouping := NewAggregateParam(AggregateGrouping, 2, "", collations.MySQL8())
+			grouping.GroupingKeys = []int{0, 1}
+			count := NewAggregateParam(AggregateSum, 3, "", collations.MySQL8())
+			count.OrigOpcode = AggregateCountStar
+			oa := &OrderedAggregate{
+				Aggregates:  []*AggregateParams{grouping, count},
+				GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}, {KeyCol: 1, WeightStringCol: -1}},
+				Input:       fp,
+				WithRollup:  true,
+			}
+
+			qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+			require.NoError(t, err)
+			if len(qr.Rows) == 0 {
+				qr.Rows = nil
+			}
+			utils.MustMatch(t, tcase.want, qr)
+
+			fp.rewind()
+			results := &sqltypes.Result{}
+			err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+				if qr.Fields != nil {
+					results.Fields = qr.Fields
+				}
+				results.Rows = append(results.Rows, qr.Rows...)
+				return nil
+			})
+			require.NoError(t, err)
+			utils.MustMatch(t, tcase.want, results)
+		})
+	}
+}
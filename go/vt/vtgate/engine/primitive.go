@@ -178,6 +178,7 @@ type (
 		SetSQLSelectLimit(int64) error
 		SetTransactionMode(vtgatepb.TransactionMode)
 		SetWorkload(querypb.ExecuteOptions_Workload)
+		SetScatterConsistency(vtgatepb.ScatterConsistency)
 		SetPlannerVersion(querypb.ExecuteOptions_PlannerVersion)
 		SetConsolidator(querypb.ExecuteOptions_Consolidator)
 		SetWorkloadName(string)
@@ -202,6 +203,9 @@ type (
 		SetReadAfterWriteGTID(string)
 		SetReadAfterWriteTimeout(float64)
 		SetSessionTrackGTIDs(bool)
+		// SetReadAfterWriteAuto enables recording shard positions after writes and
+		// routing later replica reads to tablets that have caught up with them.
+		SetReadAfterWriteAuto(context.Context, bool) error
 
 		// HasCreatedTempTable will mark the session as having created temp tables
 		HasCreatedTempTable()
@@ -552,3 +552,47 @@ func (route *Route) executeWarmingReplicaRead(ctx context.Context, vcursor VCurs
 		log.Warning("Failed to execute warming replica read as pool is full")
 	}
 }
+
+// RouteShards returns the shards that the routes of the primitive read from,
+// resolved with the bind vars of the query. The shards of a route whose
+// values come from the rows of another primitive cannot be resolved before
+// the query runs, so all the shards of its keyspace are returned instead.
+// Reference tables and information schema queries are not included.
+func RouteShards(ctx context.Context, vcursor VCursor, primitive Primitive, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, error) {
+	var rss []*srvtopo.ResolvedShard
+	seen := make(map[string]bool)
+	var visit func(primitive Primitive) error
+	visit = func(primitive Primitive) error {
+		if route, ok := primitive.(*Route); ok {
+			switch route.Opcode {
+			case None, DBA, Reference:
+				return nil
+			}
+			routeRss, _, err := route.findRoute(ctx, vcursor, bindVars)
+			if err != nil {
+				routeRss, _, err = route.byDestination(ctx, vcursor, bindVars, key.DestinationAllShards{})
+				if err != nil {
+					return err
+				}
+			}
+			for _, rs := range routeRss {
+				shard := rs.Target.Keyspace + "/" + rs.Target.Shard
+				if !seen[shard] {
+					seen[shard] = true
+					rss = append(rss, rs)
+				}
+			}
+		}
+		inputs, _ := primitive.Inputs()
+		for _, input := range inputs {
+			if err := visit(input); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(primitive); err != nil {
+		return nil, err
+	}
+	return rss, nil
+}
@@ -32,6 +32,7 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -1751,3 +1752,40 @@ func TestSelectTupleMultiCol(t *testing.T) {
 		`StreamExecuteMulti select 1 from multicol_tbl where (colb, colx, cola) in ::vals user.-20: {vals: type:TUPLE values:{type:TUPLE value:"\x89\x02\x011\x950\x01a"} values:{type:TUPLE value:"\x89\x02\x014\x950\x01b"}} `,
 	})
 }
+
+func TestRouteShards(t *testing.T) {
+	vindex, _ := vindexes.CreateVindex("hash", "", nil)
+	ks := &vindexes.Keyspace{Name: "ks", Sharded: true}
+	left := NewRoute(EqualUnique, ks, "dummy_select", "dummy_select_field")
+	left.Vindex = vindex.(vindexes.SingleColumn)
+	left.Values = []evalengine.Expr{evalengine.NewLiteralInt(1)}
+	right := NewRoute(EqualUnique, ks, "dummy_select", "dummy_select_field")
+	right.Vindex = vindex.(vindexes.SingleColumn)
+	right.Values = []evalengine.Expr{evalengine.NewBindVar("t_id", evalengine.Type{})}
+	vc := &loggingVCursor{shards: []string{"-20", "20-"}}
+
+	shardsOf := func(rss []*srvtopo.ResolvedShard) []string {
+		var shards []string
+		for _, rs := range rss {
+			shards = append(shards, rs.Target.Shard)
+		}
+		return shards
+	}
+
+	rss, err := RouteShards(context.Background(), vc, left, map[string]*querypb.BindVariable{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-20"}, shardsOf(rss))
+
+	// The value of the right side comes from the rows of the left side, so
+	// it may read any shard.
+	join := &Join{
+		Opcode: InnerJoin,
+		Left:   left,
+		Right:  right,
+		Cols:   []int{-1, 1},
+		Vars:   map[string]int{"t_id": 0},
+	}
+	rss, err = RouteShards(context.Background(), vc, join, map[string]*querypb.BindVariable{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-20", "20-"}, shardsOf(rss))
+}
@@ -66,6 +66,22 @@ func TestEmptyRows(outer *testing.T) {
 		opcode:      AggregateMin,
 		expectedVal: "null",
 		expectedTyp: "int64",
+	}, {
+		opcode:      AggregateBitAnd,
+		expectedVal: "18446744073709551615",
+		expectedTyp: "uint64",
+	}, {
+		opcode:      AggregateBitXor,
+		expectedVal: "0",
+		expectedTyp: "uint64",
+	}, {
+		opcode:      AggregateJSONArrayAgg,
+		expectedVal: "null",
+		expectedTyp: "json",
+	}, {
+		opcode:      AggregateStdDevSamp,
+		expectedVal: "null",
+		expectedTyp: "float64",
 	}}
 
 	for _, test := range testCases {
@@ -418,3 +434,115 @@ func TestScalarGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// TestScalarGroupConcatMaxLen tests that group_concat is truncated using group_concat_max_len from the session.
+func TestScalarGroupConcatMaxLen(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"group_concat(c2)",
+		"varchar",
+	)
+
+	tcases := []struct {
+		name    string
+		sysVars map[string]string
+		want    string
+	}{{
+		name: "default length",
+		want: "abc,déf,ghi",
+	}, {
+		name:    "truncated",
+		sysVars: map[string]string{"group_concat_max_len": "6"},
+		want:    "abc,d",
+	}, {
+		name:    "truncated without splitting a character",
+		sysVars: map[string]string{"group_concat_max_len": "'7'"},
+		want:    "abc,dé",
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "abc", "déf", "ghi")}}
+			oa := &ScalarAggregate{
+				Aggregates: []*AggregateParams{{
+					Opcode: AggregateGroupConcat,
+					Col:    0,
+					Func:   &sqlparser.GroupConcatExpr{Separator: ","},
+				}},
+				Input: fp,
+			}
+			vc := &loggingVCursor{systemVariables: tcase.sysVars}
+			qr, err := oa.TryExecute(context.Background(), vc, nil, false)
+			require.NoError(t, err)
+			require.Len(t, qr.Rows, 1)
+			assert.Equal(t, tcase.want, qr.Rows[0][0].ToString())
+		})
+	}
+}
+
+func TestScalarMergeBitwiseAndJSON(t *testing.T) {
+	// each row is the partial aggregation produced by one shard
+	fields := sqltypes.MakeTestFields(
+		"bit_and(a)|bit_or(a)|bit_xor(a)|json_arrayagg(b)|json_objectagg(b, a)",
+		"uint64|uint64|uint64|json|json",
+	)
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields,
+		`7|7|7|[1, 2]|{"x": 1, "y": 2}`,
+		`18446744073709551615|0|0|null|null`,
+		`14|14|12|["z"]|{"z": 3}`,
+	)}}
+
+	oa := &ScalarAggregate{
+		Aggregates: []*AggregateParams{
+			NewAggregateParam(AggregateBitAnd, 0, "", collations.MySQL8()),
+			NewAggregateParam(AggregateBitOr, 1, "", collations.MySQL8()),
+			NewAggregateParam(AggregateBitXor, 2, "", collations.MySQL8()),
+			NewAggregateParam(AggregateJSONArrayAgg, 3, "", collations.MySQL8()),
+			NewAggregateParam(AggregateJSONObjectAgg, 4, "", collations.MySQL8()),
+		},
+		Input: fp,
+	}
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, `6|15|11|[1, 2, "z"]|{"x": 1, "y": 2, "z": 3}`), qr)
+}
+
+func TestScalarMergeVariance(t *testing.T) {
+	// each shard returns the population variance, count and average of 1000000001..1000000004;
+	// calculating the variance from the sum of squares would lose all precision for these values
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("var_samp(a)|var_pop(a)|count(a)|avg(a)", "float64|float64|int64|decimal"),
+		"0.25|0.25|2|1000000001.5000",
+		"null|null|0|null",
+		"0.25|0.25|2|1000000003.5000",
+	)}}
+
+	varSamp := NewAggregateParam(AggregateVarSamp, 0, "", collations.MySQL8())
+	varSamp.StatisticsCols = []int{2, 3}
+	varPop := NewAggregateParam(AggregateVarPop, 1, "", collations.MySQL8())
+	varPop.StatisticsCols = []int{2, 3}
+	oa := &ScalarAggregate{
+		Aggregates:          []*AggregateParams{varSamp, varPop},
+		TruncateColumnCount: 2,
+		Input:               fp,
+	}
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("var_samp(a)|var_pop(a)", "float64|float64"),
+		"1.6666666666666667|1.25",
+	), qr)
+	assert.Equal(t, "var_samp(0, 2, 3)", varSamp.String())
+
+	// without the count and average, every row is a single value
+	fp = &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("stddev_pop(a)", "int64"),
+		"1000000001", "1000000002", "null", "1000000003", "1000000004",
+	)}}
+	oa = &ScalarAggregate{
+		Aggregates: []*AggregateParams{NewAggregateParam(AggregateStdDevPop, 0, "", collations.MySQL8())},
+		Input:      fp,
+	}
+	qr, err = oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("stddev_pop(a)", "float64"), "1.118033988749895"), qr)
+}
@@ -471,6 +471,16 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid workload: %s", str)
 		}
 		vcursor.Session().SetWorkload(querypb.ExecuteOptions_Workload(out))
+	case sysvars.ScatterConsistency.Name:
+		str, err := svss.evalAsString(env, vcursor)
+		if err != nil {
+			return err
+		}
+		out, ok := vtgatepb.ScatterConsistency_value[strings.ToUpper(str)]
+		if !ok {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid scatter_consistency: %s", str)
+		}
+		vcursor.Session().SetScatterConsistency(vtgatepb.ScatterConsistency(out))
 	case sysvars.DDLStrategy.Name:
 		str, err := svss.evalAsString(env, vcursor)
 		if err != nil {
@@ -534,6 +544,8 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 		default:
 			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "variable 'session_track_gtids' can't be set to the value of '%s'", str)
 		}
+	case sysvars.ReadAfterWriteAuto.Name:
+		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetReadAfterWriteAuto)
 	default:
 		return vterrors.NewErrorf(vtrpcpb.Code_NOT_FOUND, vterrors.UnknownSystemVariable, "unknown system variable '%s'", svss.Name)
 	}
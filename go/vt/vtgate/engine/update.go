@@ -19,14 +19,19 @@ package engine
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
 
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -49,6 +54,25 @@ type Update struct {
 
 	// ChangedVindexValues contains values for updated Vindexes during an update statement.
 	ChangedVindexValues map[string]*VindexValues
+
+	// RowMigration is set when the statement changes the primary vindex columns.
+	// The updated rows are then moved to the shards their new values map to.
+	RowMigration *RowMigration
+}
+
+// RowMigration contains the queries needed to move rows between shards
+// when an update changes their primary vindex columns.
+// The Vindexes of the DML must hold all the column vindexes of the table, starting with the primary vindex.
+type RowMigration struct {
+	// Query selects the updated rows, with all the columns of the table but the generated ones,
+	// followed by the new values of the updated columns.
+	Query string
+
+	// UpdatedColumns are the names of the updated columns, in the order their new values follow the rows.
+	UpdatedColumns []string
+
+	// DeleteQuery removes the updated rows from the shards they currently live on.
+	DeleteQuery string
 }
 
 // TryExecute performs a non-streaming exec.
@@ -66,6 +90,9 @@ func (upd *Update) TryExecute(ctx context.Context, vcursor VCursor, bindVars map
 	case Unsharded:
 		return upd.execUnsharded(ctx, upd, vcursor, bindVars, rss)
 	case Equal, EqualUnique, IN, Scatter, ByDestination, SubShard, MultiEqual:
+		if upd.RowMigration != nil {
+			return upd.migrateRows(ctx, vcursor, rss, bvs)
+		}
 		return upd.execMultiDestination(ctx, upd, vcursor, bindVars, rss, upd.updateVindexEntries, bvs)
 	default:
 		// Unreachable.
@@ -194,6 +221,290 @@ func (upd *Update) updateVindexEntries(ctx context.Context, vcursor VCursor, bin
 	return nil
 }
 
+// migrateRows performs an update that changes the primary vindex columns.
+// Every updated row is read in full, along with its new values. The rows of a shard are
+// identified only by the WHERE clause of the update, so they are moved shard by shard:
+// when none of the rows of a shard map to another shard, they are updated in place;
+// otherwise they are all deleted from the shard, and inserted with their new values on the
+// shards their new keyspace ids map to. The entries of the lookup vindexes are moved along
+// with the rows.
+// None of the queries are allowed to autocommit, so the whole migration is part of the session
+// transaction. With transaction_mode=TWOPC it is committed atomically across the shards, with
+// transaction_mode=SINGLE moving a row to another shard fails like any other multi-shard write.
+func (upd *Update) migrateRows(ctx context.Context, vcursor VCursor, rss []*srvtopo.ResolvedShard, bvs []map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	if len(rss) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+	qr, errs := vcursor.ExecuteMultiShard(ctx, upd, rss, boundQueries(upd.RowMigration.Query, bvs), false /*rollbackOnError*/, false /*canAutocommit*/, false /*fetchLastInsertID*/)
+	if err := vterrors.Aggregate(errs); err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+
+	columns, oldRows, newRows, err := upd.RowMigration.splitRows(qr)
+	if err != nil {
+		return nil, err
+	}
+	oldKsids, err := upd.migrationKeyspaceIDs(ctx, vcursor, columns, oldRows)
+	if err != nil {
+		return nil, err
+	}
+	newKsids, err := upd.migrationKeyspaceIDs(ctx, vcursor, columns, newRows)
+	if err != nil {
+		return nil, err
+	}
+	oldShards, err := upd.keyspaceIDShards(ctx, vcursor, oldKsids)
+	if err != nil {
+		return nil, err
+	}
+	newShards, err := upd.keyspaceIDShards(ctx, vcursor, newKsids)
+	if err != nil {
+		return nil, err
+	}
+	rowShards := make(map[string]bool)
+	movedShards := make(map[string]bool)
+	for i := range oldRows {
+		rowShards[oldShards[i]] = true
+		if oldShards[i] != newShards[i] {
+			movedShards[oldShards[i]] = true
+		}
+	}
+	var movedRss, inPlaceRss []*srvtopo.ResolvedShard
+	var movedBvs, inPlaceBvs []map[string]*querypb.BindVariable
+	for i, rs := range rss {
+		switch {
+		case movedShards[rs.Target.Shard]:
+			movedRss = append(movedRss, rs)
+			movedBvs = append(movedBvs, bvs[i])
+		case rowShards[rs.Target.Shard]:
+			inPlaceRss = append(inPlaceRss, rs)
+			inPlaceBvs = append(inPlaceBvs, bvs[i])
+		}
+	}
+
+	for _, colVindex := range upd.Vindexes[1:] {
+		if !colVindex.Owned {
+			continue
+		}
+		for i, row := range oldRows {
+			if err := colVindex.Vindex.(vindexes.Lookup).Delete(ctx, vcursor, [][]sqltypes.Value{vindexColumnValues(colVindex, columns, row)}, oldKsids[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := &sqltypes.Result{}
+	if len(inPlaceRss) > 0 {
+		qr, errs := vcursor.ExecuteMultiShard(ctx, upd, inPlaceRss, boundQueries(upd.Query, inPlaceBvs), true /*rollbackOnError*/, false /*canAutocommit*/, false /*fetchLastInsertID*/)
+		if err := vterrors.Aggregate(errs); err != nil {
+			return nil, err
+		}
+		result.RowsAffected += qr.RowsAffected
+	}
+	if len(movedRss) > 0 {
+		_, errs = vcursor.ExecuteMultiShard(ctx, upd, movedRss, boundQueries(upd.RowMigration.DeleteQuery, movedBvs), true /*rollbackOnError*/, false /*canAutocommit*/, false /*fetchLastInsertID*/)
+		if err := vterrors.Aggregate(errs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := upd.createMigratedVindexEntries(ctx, vcursor, columns, newRows, newKsids); err != nil {
+		return nil, err
+	}
+
+	var movedRows []sqltypes.Row
+	var movedKsids [][]byte
+	for i, row := range newRows {
+		if movedShards[oldShards[i]] {
+			movedRows = append(movedRows, row)
+			movedKsids = append(movedKsids, newKsids[i])
+		}
+	}
+	if len(movedRows) > 0 {
+		if err := upd.insertMigratedRows(ctx, vcursor, columns, movedRows, movedKsids); err != nil {
+			return nil, err
+		}
+		result.RowsAffected += uint64(len(movedRows))
+	}
+	return result, nil
+}
+
+// keyspaceIDShards returns the names of the shards the keyspace ids map to.
+func (upd *Update) keyspaceIDShards(ctx context.Context, vcursor VCursor, ksids [][]byte) ([]string, error) {
+	indexes := make([]*querypb.Value, 0, len(ksids))
+	destinations := make([]key.Destination, 0, len(ksids))
+	for i, ksid := range ksids {
+		indexes = append(indexes, &querypb.Value{Value: strconv.AppendInt(nil, int64(i), 10)})
+		destinations = append(destinations, key.DestinationKeyspaceID(ksid))
+	}
+	rss, indexesPerRss, err := vcursor.ResolveDestinations(ctx, upd.Keyspace.Name, indexes, destinations)
+	if err != nil {
+		return nil, err
+	}
+	shards := make([]string, len(ksids))
+	for i, rs := range rss {
+		for _, indexValue := range indexesPerRss[i] {
+			rowNum, _ := strconv.Atoi(string(indexValue.Value))
+			shards[rowNum] = rs.Target.Shard
+		}
+	}
+	return shards, nil
+}
+
+// splitRows separates the result of the migration query into the rows as they are now,
+// and the rows as they will be after the update.
+func (rm *RowMigration) splitRows(qr *sqltypes.Result) (columns []sqlparser.IdentifierCI, oldRows, newRows []sqltypes.Row, err error) {
+	colCount := len(qr.Fields) - len(rm.UpdatedColumns)
+	for _, field := range qr.Fields[:colCount] {
+		columns = append(columns, sqlparser.NewIdentifierCI(field.Name))
+	}
+	offsets := make([]int, 0, len(rm.UpdatedColumns))
+	for _, updCol := range rm.UpdatedColumns {
+		offset := slices.IndexFunc(columns, func(col sqlparser.IdentifierCI) bool { return col.EqualString(updCol) })
+		if offset < 0 {
+			return nil, nil, nil, vterrors.VT13001(fmt.Sprintf("updated column %s not found in the rows to migrate", updCol))
+		}
+		offsets = append(offsets, offset)
+	}
+	for _, row := range qr.Rows {
+		newRow := slices.Clone(row[:colCount])
+		for i, offset := range offsets {
+			newRow[offset] = row[colCount+i]
+		}
+		oldRows = append(oldRows, row[:colCount])
+		newRows = append(newRows, newRow)
+	}
+	return columns, oldRows, newRows, nil
+}
+
+func (upd *Update) migrationKeyspaceIDs(ctx context.Context, vcursor VCursor, columns []sqlparser.IdentifierCI, rows []sqltypes.Row) ([][]byte, error) {
+	primary := upd.Vindexes[0]
+	ksids := make([][]byte, 0, len(rows))
+	for _, row := range rows {
+		vindexKey := vindexColumnValues(primary, columns, row)
+		ksid, err := resolveKeyspaceID(ctx, vcursor, primary.Vindex, vindexKey)
+		if err != nil {
+			return nil, err
+		}
+		if ksid == nil {
+			return nil, vterrors.VT09023(vindexKey)
+		}
+		ksids = append(ksids, ksid)
+	}
+	return ksids, nil
+}
+
+// createMigratedVindexEntries creates the owned lookup vindex entries for the migrated rows,
+// and verifies that the values of the other vindexes map to the new keyspace ids.
+func (upd *Update) createMigratedVindexEntries(ctx context.Context, vcursor VCursor, columns []sqlparser.IdentifierCI, rows []sqltypes.Row, ksids [][]byte) error {
+	for _, colVindex := range upd.Vindexes[1:] {
+		var rowsColValues [][]sqltypes.Value
+		var rowsKsids [][]byte
+		for i, row := range rows {
+			values := vindexColumnValues(colVindex, columns, row)
+			if !colVindex.Owned && allNullValues(values) {
+				// All columns for this Vindex are set to null, so we can skip verification
+				continue
+			}
+			rowsColValues = append(rowsColValues, values)
+			rowsKsids = append(rowsKsids, ksids[i])
+		}
+		if len(rowsColValues) == 0 {
+			continue
+		}
+
+		if colVindex.Owned {
+			if err := colVindex.Vindex.(vindexes.Lookup).Create(ctx, vcursor, rowsColValues, rowsKsids, false /* ignoreMode */); err != nil {
+				return err
+			}
+			continue
+		}
+		verified, err := vindexes.Verify(ctx, colVindex.Vindex, vcursor, rowsColValues, rowsKsids)
+		if err != nil {
+			return err
+		}
+		for i, v := range verified {
+			if !v {
+				return fmt.Errorf("values %v for column %v does not map to keyspace ids", rowsColValues[i], colVindex.Columns)
+			}
+		}
+	}
+	return nil
+}
+
+// insertMigratedRows inserts the migrated rows on the shards their new keyspace ids map to.
+func (upd *Update) insertMigratedRows(ctx context.Context, vcursor VCursor, columns []sqlparser.IdentifierCI, rows []sqltypes.Row, ksids [][]byte) error {
+	indexes := make([]*querypb.Value, 0, len(rows))
+	destinations := make([]key.Destination, 0, len(rows))
+	for i, ksid := range ksids {
+		indexes = append(indexes, &querypb.Value{Value: strconv.AppendInt(nil, int64(i), 10)})
+		destinations = append(destinations, key.DestinationKeyspaceID(ksid))
+	}
+	rss, indexesPerRss, err := vcursor.ResolveDestinations(ctx, upd.Keyspace.Name, indexes, destinations)
+	if err != nil {
+		return err
+	}
+
+	table := sqlparser.NewAliasedTableExpr(sqlparser.NewTableName(upd.TableNames[0]), "")
+	queries := make([]*querypb.BoundQuery, len(rss))
+	for i := range rss {
+		bindVars := make(map[string]*querypb.BindVariable)
+		var values sqlparser.Values
+		for _, indexValue := range indexesPerRss[i] {
+			rowNum, _ := strconv.Atoi(string(indexValue.Value))
+			tuple := make(sqlparser.ValTuple, 0, len(columns))
+			for colIdx, col := range columns {
+				name := InsertVarName(col, rowNum)
+				bindVars[name] = sqltypes.ValueBindVariable(rows[rowNum][colIdx])
+				tuple = append(tuple, sqlparser.NewArgument(name))
+			}
+			values = append(values, tuple)
+		}
+		ins := &sqlparser.Insert{
+			Action:  sqlparser.InsertAct,
+			Table:   table,
+			Columns: columns,
+			Rows:    values,
+		}
+		queries[i] = &querypb.BoundQuery{Sql: sqlparser.String(ins), BindVariables: bindVars}
+	}
+	_, errs := vcursor.ExecuteMultiShard(ctx, upd, rss, queries, true /*rollbackOnError*/, false /*canAutocommit*/, false /*fetchLastInsertID*/)
+	return vterrors.Aggregate(errs)
+}
+
+func boundQueries(query string, bvs []map[string]*querypb.BindVariable) []*querypb.BoundQuery {
+	queries := make([]*querypb.BoundQuery, len(bvs))
+	for i, bv := range bvs {
+		queries[i] = &querypb.BoundQuery{Sql: query, BindVariables: bv}
+	}
+	return queries
+}
+
+// vindexColumnValues returns the values of the vindex columns from the given row.
+func vindexColumnValues(colVindex *vindexes.ColumnVindex, columns []sqlparser.IdentifierCI, row sqltypes.Row) []sqltypes.Value {
+	values := make([]sqltypes.Value, 0, len(colVindex.Columns))
+	for _, vCol := range colVindex.Columns {
+		idx := slices.IndexFunc(columns, vCol.Equal)
+		if idx < 0 {
+			values = append(values, sqltypes.NULL)
+			continue
+		}
+		values = append(values, row[idx])
+	}
+	return values
+}
+
+func allNullValues(values []sqltypes.Value) bool {
+	for _, value := range values {
+		if !value.IsNull() {
+			return false
+		}
+	}
+	return true
+}
+
 func (upd *Update) description() PrimitiveDescription {
 	other := map[string]any{
 		"Query":                upd.Query,
@@ -217,6 +528,11 @@ func (upd *Update) description() PrimitiveDescription {
 	if upd.FetchLastInsertID {
 		other["FetchLastInsertID"] = upd.FetchLastInsertID
 	}
+	if upd.RowMigration != nil {
+		other["MigrationQuery"] = upd.RowMigration.Query
+		other["MigrationUpdatedColumns"] = upd.RowMigration.UpdatedColumns
+		other["MigrationDeleteQuery"] = upd.RowMigration.DeleteQuery
+	}
 
 	return PrimitiveDescription{
 		OperatorType:     "Update",
@@ -954,6 +954,93 @@ func TestUpdateInUnique(t *testing.T) {
 	})
 }
 
+func TestUpdateEqualMigrateRows(t *testing.T) {
+	ks := buildTestVSchema().Keyspaces["sharded"]
+	upd := &Update{
+		DML: &DML{
+			RoutingParameters: &RoutingParameters{
+				Opcode:   Equal,
+				Keyspace: ks.Keyspace,
+				Vindex:   ks.Vindexes["hash"],
+				Values:   []evalengine.Expr{evalengine.NewLiteralInt(1)},
+			},
+			Query:      "dummy_update",
+			TableNames: []string{ks.Tables["t1"].Name.String()},
+			Vindexes:   ks.Tables["t1"].ColumnVindexes,
+		},
+		RowMigration: &RowMigration{
+			Query:          "dummy_select",
+			UpdatedColumns: []string{"id", "c3"},
+			DeleteQuery:    "dummy_delete",
+		},
+	}
+
+	selectResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|c1|c2|c3|other|2|7",
+			"int64|int64|int64|int64|varchar|int64|int64",
+		),
+		"1|4|5|6|foo|2|7",
+	)
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.results = []*sqltypes.Result{selectResult, nil, nil, {RowsAffected: 1}}
+
+	qr, err := upd.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, qr.RowsAffected)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		// The full row is read along with the new values of the updated columns.
+		`ExecuteMultiShard sharded.-20: dummy_select {} false false`,
+		// The shards of the old and the new keyspace ids.
+		`ResolveDestinations sharded [value:"0"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`ResolveDestinations sharded [value:"0"] Destinations:DestinationKeyspaceID(06e7ea22ce92708f)`,
+		// The lookup entries pointing to the old keyspace id are removed.
+		`Execute delete from lkp2 where from1 = :from1 and from2 = :from2 and toc = :toc from1: type:INT64 value:"4" from2: type:INT64 value:"5" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`Execute delete from lkp1 where from = :from and toc = :toc from: type:INT64 value:"6" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		// The row stays on its shard, so it is updated in place.
+		`ExecuteMultiShard sharded.-20: dummy_update {} true false`,
+		// The lookup entries are created with the new values, pointing to the new keyspace id.
+		`Execute insert into lkp2(from1, from2, toc) values(:from1_0, :from2_0, :toc_0) from1_0: type:INT64 value:"4" from2_0: type:INT64 value:"5" toc_0: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" true`,
+		`Execute insert into lkp1(from, toc) values(:from_0, :toc_0) from_0: type:INT64 value:"7" toc_0: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" true`,
+	})
+
+	// The new keyspace id maps to another shard, so the row is moved there.
+	vc = newDMLTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"-20", "-20", "20-", "20-"}
+	vc.results = []*sqltypes.Result{selectResult}
+
+	qr, err = upd.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, qr.RowsAffected)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`ExecuteMultiShard sharded.-20: dummy_select {} false false`,
+		`ResolveDestinations sharded [value:"0"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`ResolveDestinations sharded [value:"0"] Destinations:DestinationKeyspaceID(06e7ea22ce92708f)`,
+		`Execute delete from lkp2 where from1 = :from1 and from2 = :from2 and toc = :toc from1: type:INT64 value:"4" from2: type:INT64 value:"5" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`Execute delete from lkp1 where from = :from and toc = :toc from: type:INT64 value:"6" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		// The row is deleted from its current shard.
+		`ExecuteMultiShard sharded.-20: dummy_delete {} true false`,
+		`Execute insert into lkp2(from1, from2, toc) values(:from1_0, :from2_0, :toc_0) from1_0: type:INT64 value:"4" from2_0: type:INT64 value:"5" toc_0: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" true`,
+		`Execute insert into lkp1(from, toc) values(:from_0, :toc_0) from_0: type:INT64 value:"7" toc_0: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" true`,
+		// The row is inserted with its new values on the shard of the new keyspace id.
+		`ResolveDestinations sharded [value:"0"] Destinations:DestinationKeyspaceID(06e7ea22ce92708f)`,
+		"ExecuteMultiShard sharded.20-: insert into t1(id, c1, c2, c3, other) values (:_id_0, :_c1_0, :_c2_0, :_c3_0, :_other_0) " +
+			`{_c1_0: type:INT64 value:"4" _c2_0: type:INT64 value:"5" _c3_0: type:INT64 value:"7" _id_0: type:INT64 value:"2" _other_0: type:VARCHAR value:"foo"} true false`,
+	})
+
+	// No rows matched, nothing to migrate.
+	vc = newDMLTestVCursor("-20", "20-")
+	qr, err = upd.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, qr.RowsAffected)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`ExecuteMultiShard sharded.-20: dummy_select {} false false`,
+	})
+}
+
 func buildTestVSchema() *vindexes.VSchema {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
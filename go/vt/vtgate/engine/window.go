@@ -0,0 +1,649 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions at the vtgate level.
+// The input must be sorted by the PARTITION BY columns, followed by the ORDER BY
+// columns of the window. This is usually achieved by merge-sorting the results of
+// a scatter route, or by sorting the input in memory.
+//
+// The results of the window functions are placed before the input columns in the
+// output rows, in the order the functions are listed in Functions.
+type Window struct {
+	// PartitionBy specifies the input columns that make up a partition.
+	// Rows that compare equal on all of these columns belong to the same partition.
+	PartitionBy evalengine.Comparison
+
+	// OrderBy specifies the ordering of the rows inside a partition.
+	// Rows that compare equal on all of these columns are peers.
+	OrderBy evalengine.Comparison
+
+	// Functions are the window functions evaluated over every partition.
+	Functions []*WindowFunc
+
+	// Input is the primitive that will feed into this Primitive.
+	Input Primitive
+}
+
+// WindowFunc specifies the parameters for a single window function.
+type WindowFunc struct {
+	Opcode opcode.WindowOpcode
+
+	// Aggregate is the aggregation used when Opcode is opcode.WindowAggregate
+	Aggregate opcode.AggregateOpcode
+
+	// Col is the input column holding the argument of the function.
+	// It is -1 for functions that do not take an argument.
+	Col  int
+	Type evalengine.Type
+
+	// N is the number of buckets for NTILE, the row to fetch for NTH_VALUE,
+	// and the offset used by LAG and LEAD.
+	N evalengine.Expr
+
+	// DefaultCol is the input column holding the default value of LAG and LEAD.
+	// It is -1 when no default was given, in which case NULL is used.
+	DefaultCol int
+
+	// Frame is the frame clause of the window. When nil, the default frame is used.
+	Frame *WindowFrame
+
+	Alias        string
+	CollationEnv *collations.Environment
+}
+
+// WindowFrame is the frame over which frame-aware window functions are evaluated.
+type WindowFrame struct {
+	Unit  sqlparser.FrameUnitType
+	Start WindowFramePoint
+	End   WindowFramePoint
+}
+
+// WindowFramePoint is the start or the end of a WindowFrame.
+type WindowFramePoint struct {
+	Type sqlparser.FramePointType
+	// Offset is the number of rows for N PRECEDING and N FOLLOWING points
+	Offset evalengine.Expr
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (w *Window) RouteType() string {
+	return w.Input.RouteType()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (w *Window) GetKeyspaceName() string {
+	return w.Input.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (w *Window) GetTableName() string {
+	return w.Input.GetTableName()
+}
+
+// TryExecute is a Primitive function.
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	result, err := vcursor.ExecutePrimitive(ctx, w.Input, bindVars, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ev, err := w.newEvaluator(ctx, vcursor, bindVars, result.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &sqltypes.Result{
+		Fields: ev.fields,
+		Rows:   make([]sqltypes.Row, 0, len(result.Rows)),
+	}
+
+	start := 0
+	for i := 1; i <= len(result.Rows); i++ {
+		if i < len(result.Rows) {
+			same, err := w.samePartition(result.Rows[start], result.Rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				if vcursor.ExceedsMaxMemoryRows(i + 1 - start) {
+					return nil, fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+				}
+				continue
+			}
+		}
+		rows, err := ev.evaluate(result.Rows[start:i])
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+		start = i
+	}
+	return out, nil
+}
+
+// TryStreamExecute is a Primitive function.
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	var ev *windowEvaluator
+	var partition []sqltypes.Row
+
+	flush := func() error {
+		if len(partition) == 0 {
+			return nil
+		}
+		rows, err := ev.evaluate(partition)
+		if err != nil {
+			return err
+		}
+		partition = nil
+		return callback(&sqltypes.Result{Rows: rows})
+	}
+
+	visitor := func(qr *sqltypes.Result) error {
+		var err error
+		if ev == nil && len(qr.Fields) != 0 {
+			ev, err = w.newEvaluator(ctx, vcursor, bindVars, qr.Fields)
+			if err != nil {
+				return err
+			}
+			if err := callback(&sqltypes.Result{Fields: ev.fields}); err != nil {
+				return err
+			}
+		}
+
+		for _, row := range qr.Rows {
+			if len(partition) > 0 {
+				same, err := w.samePartition(partition[0], row)
+				if err != nil {
+					return err
+				}
+				if !same {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+			partition = append(partition, row)
+		}
+		if vcursor.ExceedsMaxMemoryRows(len(partition)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	}
+
+	// we need the input fields to calculate the output types
+	if err := vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, true, visitor); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// GetFields is a Primitive function.
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: w.fields(qr.Fields)}, nil
+}
+
+// Inputs returns the Primitive input for this window
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+func (w *Window) samePartition(a, b sqltypes.Row) (same bool, err error) {
+	defer evalengine.PanicHandler(&err)
+	return w.PartitionBy.Compare(a, b) == 0, nil
+}
+
+func (w *Window) fields(input []*querypb.Field) []*querypb.Field {
+	fields := make([]*querypb.Field, 0, len(w.Functions)+len(input))
+	for _, fn := range w.Functions {
+		fields = append(fields, fn.field(input))
+	}
+	return append(fields, input...)
+}
+
+func (fn *WindowFunc) field(input []*querypb.Field) *querypb.Field {
+	inputType := sqltypes.Null
+	if fn.Col >= 0 {
+		inputType = input[fn.Col].Type
+	}
+
+	var typ querypb.Type
+	switch fn.Opcode {
+	case opcode.WindowAggregate:
+		typ = fn.Aggregate.SQLType(inputType)
+	default:
+		typ = fn.Opcode.SQLType(inputType)
+	}
+
+	if fn.Col >= 0 && typ == inputType {
+		// the value is copied from the input, so we keep the original field information
+		field := input[fn.Col].CloneVT()
+		field.Name = fn.Alias
+		return field
+	}
+
+	return &querypb.Field{
+		Name:    fn.Alias,
+		Type:    typ,
+		Charset: collations.CollationBinaryID,
+		Flags:   mysql.FlagsForColumn(typ, collations.CollationBinaryID),
+	}
+}
+
+func (w *Window) description() PrimitiveDescription {
+	other := map[string]any{
+		"Functions": GenericJoin(w.Functions, windowFuncToString),
+	}
+	if len(w.PartitionBy) > 0 {
+		other["PartitionBy"] = GenericJoin(w.PartitionBy, orderByParamsToString)
+	}
+	if len(w.OrderBy) > 0 {
+		other["OrderBy"] = GenericJoin(w.OrderBy, orderByParamsToString)
+	}
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other:        other,
+	}
+}
+
+func windowFuncToString(i any) string {
+	return i.(*WindowFunc).String()
+}
+
+// String returns a string. Used for plan descriptions
+func (fn *WindowFunc) String() string {
+	name := fn.Opcode.String()
+	if fn.Opcode == opcode.WindowAggregate {
+		name = fn.Aggregate.String()
+	}
+
+	var args []string
+	if fn.Col >= 0 {
+		args = append(args, fmt.Sprintf("%d", fn.Col))
+	}
+	if fn.N != nil {
+		args = append(args, sqlparser.String(fn.N))
+	}
+	if fn.DefaultCol >= 0 {
+		args = append(args, fmt.Sprintf("%d", fn.DefaultCol))
+	}
+
+	out := fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	if fn.Frame != nil && fn.Opcode.UsesFrame() {
+		out += " " + fn.Frame.String()
+	}
+	if fn.Alias != "" {
+		out += " AS " + fn.Alias
+	}
+	return out
+}
+
+// String returns a string. Used for plan descriptions
+func (f *WindowFrame) String() string {
+	unit := "ROWS"
+	if f.Unit == sqlparser.FrameRangeType {
+		unit = "RANGE"
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", unit, f.Start.String(), f.End.String())
+}
+
+// String returns a string. Used for plan descriptions
+func (p WindowFramePoint) String() string {
+	switch p.Type {
+	case sqlparser.CurrentRowType:
+		return "CURRENT ROW"
+	case sqlparser.UnboundedPrecedingType:
+		return "UNBOUNDED PRECEDING"
+	case sqlparser.UnboundedFollowingType:
+		return "UNBOUNDED FOLLOWING"
+	case sqlparser.ExprPrecedingType:
+		return sqlparser.String(p.Offset) + " PRECEDING"
+	case sqlparser.ExprFollowingType:
+		return sqlparser.String(p.Offset) + " FOLLOWING"
+	}
+	return "<unknown>"
+}
+
+// windowEvaluator holds the per-execution state needed to evaluate the
+// window functions of a Window primitive over a single partition at a time.
+type windowEvaluator struct {
+	w      *Window
+	fields []*querypb.Field
+	funcs  []*windowFuncState
+}
+
+type windowFuncState struct {
+	*WindowFunc
+
+	n           int64
+	start, end  int64
+	aggr        aggregator
+	incremental bool
+}
+
+func (w *Window) newEvaluator(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, fields []*querypb.Field) (*windowEvaluator, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	ev := &windowEvaluator{
+		w:      w,
+		fields: w.fields(fields),
+	}
+
+	for _, fn := range w.Functions {
+		st := &windowFuncState{WindowFunc: fn}
+		var err error
+
+		if fn.N != nil {
+			st.n, err = evalWindowCount(env, fn.N, vcursor.ConnCollation())
+			if err != nil {
+				return nil, err
+			}
+			switch fn.Opcode {
+			case opcode.WindowNtile, opcode.WindowNthValue:
+				if st.n == 0 {
+					return nil, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "Incorrect arguments to %s", fn.Opcode.String())
+				}
+			}
+		}
+
+		if fn.Frame != nil && fn.Opcode.UsesFrame() {
+			if st.start, err = evalFramePoint(env, fn.Frame.Unit, fn.Frame.Start, vcursor.ConnCollation()); err != nil {
+				return nil, err
+			}
+			if st.end, err = evalFramePoint(env, fn.Frame.Unit, fn.Frame.End, vcursor.ConnCollation()); err != nil {
+				return nil, err
+			}
+		}
+
+		if fn.Opcode == opcode.WindowAggregate {
+			st.aggr, err = newWindowAggregator(fn, fields)
+			if err != nil {
+				return nil, err
+			}
+			// when the frame always starts at the beginning of the partition, the frame only
+			// grows as we move forward, so we can keep adding rows to the same aggregation
+			st.incremental = fn.Frame == nil || fn.Frame.Start.Type == sqlparser.UnboundedPrecedingType
+		}
+
+		ev.funcs = append(ev.funcs, st)
+	}
+	return ev, nil
+}
+
+func evalWindowCount(env *evalengine.ExpressionEnv, expr evalengine.Expr, coll collations.ID) (int64, error) {
+	resolved, err := env.Evaluate(expr)
+	if err != nil {
+		return 0, err
+	}
+	value := resolved.Value(coll)
+	if !value.IsIntegral() {
+		return 0, sqltypes.ErrIncompatibleTypeCast
+	}
+	n, err := value.ToInt64()
+	if err != nil || n < 0 {
+		return 0, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "window function argument is out of range: %s", value.ToString())
+	}
+	return n, nil
+}
+
+// evalFramePoint resolves the offset of a frame point. The returned value is signed:
+// negative values point to rows before the current row, positive values to rows after it.
+func evalFramePoint(env *evalengine.ExpressionEnv, unit sqlparser.FrameUnitType, p WindowFramePoint, coll collations.ID) (int64, error) {
+	switch p.Type {
+	case sqlparser.ExprPrecedingType, sqlparser.ExprFollowingType:
+		if unit == sqlparser.FrameRangeType {
+			return 0, vterrors.VT12001("RANGE frame with an offset in a window function evaluated by vtgate")
+		}
+		n, err := evalWindowCount(env, p.Offset, coll)
+		if err != nil {
+			return 0, err
+		}
+		if p.Type == sqlparser.ExprPrecedingType {
+			return -n, nil
+		}
+		return n, nil
+	}
+	return 0, nil
+}
+
+func newWindowAggregator(fn *WindowFunc, fields []*querypb.Field) (aggregator, error) {
+	noDistinct := aggregatorDistinct{column: -1}
+	switch fn.Aggregate {
+	case opcode.AggregateCountStar:
+		return &aggregatorCountStar{}, nil
+	case opcode.AggregateCount:
+		return &aggregatorCount{from: fn.Col, distinct: noDistinct}, nil
+	case opcode.AggregateSum:
+		return &aggregatorSum{
+			from:     fn.Col,
+			sum:      evalengine.NewAggregationSum(fields[fn.Col].Type),
+			distinct: noDistinct,
+		}, nil
+	case opcode.AggregateMin, opcode.AggregateMax:
+		sourceType := fields[fn.Col].Type
+		minmax := aggregatorMinMax{
+			from:   fn.Col,
+			minmax: evalengine.NewAggregationMinMax(sourceType, fn.CollationEnv, fn.Type.Collation(), fn.Type.Values()),
+		}
+		if fn.Aggregate == opcode.AggregateMin {
+			return &aggregatorMin{minmax}, nil
+		}
+		return &aggregatorMax{minmax}, nil
+	}
+	return nil, vterrors.VT12001(fmt.Sprintf("aggregation '%s' used as a window function in vtgate", fn.Aggregate.String()))
+}
+
+// evaluate calculates the window functions over a single partition,
+// and returns the output rows for the partition
+func (ev *windowEvaluator) evaluate(partition []sqltypes.Row) (out []sqltypes.Row, err error) {
+	defer evalengine.PanicHandler(&err)
+
+	size := len(partition)
+	width := len(ev.funcs)
+	out = make([]sqltypes.Row, size)
+	for i, row := range partition {
+		out[i] = make(sqltypes.Row, width, width+len(row))
+		out[i] = append(out[i], row...)
+	}
+
+	// peerStart and peerEnd point to the first row and one past the last row of the peer group of each row
+	peerStart := make([]int, size)
+	peerEnd := make([]int, size)
+	groups := make([]int, size)
+	for i := 1; i < size; i++ {
+		if ev.w.OrderBy.Compare(partition[i-1], partition[i]) == 0 {
+			peerStart[i] = peerStart[i-1]
+			groups[i] = groups[i-1]
+		} else {
+			peerStart[i] = i
+			groups[i] = groups[i-1] + 1
+		}
+	}
+	for i := size - 1; i >= 0; i-- {
+		if i < size-1 && peerStart[i+1] == peerStart[i] {
+			peerEnd[i] = peerEnd[i+1]
+		} else {
+			peerEnd[i] = i + 1
+		}
+	}
+
+	for col, fn := range ev.funcs {
+		if fn.aggr != nil {
+			fn.aggr.reset()
+		}
+		added := 0
+		for i := range partition {
+			var value sqltypes.Value
+			switch fn.Opcode {
+			case opcode.WindowRowNumber:
+				value = sqltypes.NewUint64(uint64(i + 1))
+			case opcode.WindowRank:
+				value = sqltypes.NewUint64(uint64(peerStart[i] + 1))
+			case opcode.WindowDenseRank:
+				value = sqltypes.NewUint64(uint64(groups[i] + 1))
+			case opcode.WindowPercentRank:
+				rank := 0.0
+				if size > 1 {
+					rank = float64(peerStart[i]) / float64(size-1)
+				}
+				value = sqltypes.NewFloat64(rank)
+			case opcode.WindowCumeDist:
+				value = sqltypes.NewFloat64(float64(peerEnd[i]) / float64(size))
+			case opcode.WindowNtile:
+				value = sqltypes.NewUint64(ntile(i, size, int(fn.n)))
+			case opcode.WindowLag, opcode.WindowLead:
+				offset := int(fn.n)
+				if fn.N == nil {
+					offset = 1
+				}
+				if fn.Opcode == opcode.WindowLag {
+					offset = -offset
+				}
+				switch {
+				case i+offset >= 0 && i+offset < size:
+					value = partition[i+offset][fn.Col]
+				case fn.DefaultCol >= 0:
+					value = partition[i][fn.DefaultCol]
+				default:
+					value = sqltypes.NULL
+				}
+			default:
+				start, end := fn.frame(i, size, peerStart, peerEnd, len(ev.w.OrderBy) > 0)
+				value, added, err = fn.evalFrame(partition, start, end, added)
+				if err != nil {
+					return nil, err
+				}
+			}
+			out[i][col] = value
+		}
+	}
+	return out, nil
+}
+
+// frame returns the first row and one past the last row of the window frame of row i
+func (fn *windowFuncState) frame(i, size int, peerStart, peerEnd []int, ordered bool) (int, int) {
+	if fn.Frame == nil {
+		if ordered {
+			// the default frame is RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+			return 0, peerEnd[i]
+		}
+		return 0, size
+	}
+
+	var start, end int
+	switch fn.Frame.Start.Type {
+	case sqlparser.UnboundedPrecedingType:
+		start = 0
+	case sqlparser.UnboundedFollowingType:
+		start = size
+	case sqlparser.CurrentRowType:
+		start = i
+		if fn.Frame.Unit == sqlparser.FrameRangeType {
+			start = peerStart[i]
+		}
+	default:
+		start = i + int(fn.start)
+	}
+
+	switch fn.Frame.End.Type {
+	case sqlparser.UnboundedPrecedingType:
+		end = 0
+	case sqlparser.UnboundedFollowingType:
+		end = size
+	case sqlparser.CurrentRowType:
+		end = i + 1
+		if fn.Frame.Unit == sqlparser.FrameRangeType {
+			end = peerEnd[i]
+		}
+	default:
+		end = i + int(fn.end) + 1
+	}
+
+	return max(0, min(start, size)), max(0, min(end, size))
+}
+
+// evalFrame calculates the value of a frame-aware window function over the rows in [start, end).
+// For incremental aggregations, `added` tracks how many rows have already been added to the aggregation.
+func (fn *windowFuncState) evalFrame(partition []sqltypes.Row, start, end, added int) (sqltypes.Value, int, error) {
+	switch fn.Opcode {
+	case opcode.WindowFirstValue:
+		if start >= end {
+			return sqltypes.NULL, added, nil
+		}
+		return partition[start][fn.Col], added, nil
+	case opcode.WindowLastValue:
+		if start >= end {
+			return sqltypes.NULL, added, nil
+		}
+		return partition[end-1][fn.Col], added, nil
+	case opcode.WindowNthValue:
+		nth := start + int(fn.n) - 1
+		if nth >= end {
+			return sqltypes.NULL, added, nil
+		}
+		return partition[nth][fn.Col], added, nil
+	case opcode.WindowAggregate:
+		if !fn.incremental {
+			fn.aggr.reset()
+			added = start
+		}
+		for ; added < end; added++ {
+			if err := fn.aggr.add(partition[added]); err != nil {
+				return sqltypes.NULL, added, err
+			}
+		}
+		return fn.aggr.finish(), added, nil
+	}
+	return sqltypes.NULL, added, vterrors.VT13001(fmt.Sprintf("unexpected window function %s", fn.Opcode.String()))
+}
+
+// ntile returns the bucket for row i, using the same distribution as MySQL:
+// the first size%buckets buckets get one extra row each
+func ntile(i, size, buckets int) uint64 {
+	perBucket := size / buckets
+	extra := size % buckets
+	if i < extra*(perBucket+1) {
+		return uint64(i/(perBucket+1) + 1)
+	}
+	return uint64((i-extra*(perBucket+1))/perBucket + extra + 1)
+}
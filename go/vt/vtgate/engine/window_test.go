@@ -0,0 +1,264 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func newTestWindow(input Primitive, funcs ...*WindowFunc) *Window {
+	return &Window{
+		PartitionBy: evalengine.Comparison{{Col: 0, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.VarBinary, collations.CollationBinaryID)}},
+		OrderBy:     evalengine.Comparison{{Col: 1, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)}},
+		Functions:   funcs,
+		Input:       input,
+	}
+}
+
+func TestWindowRanking(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"p|o",
+		"varbinary|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1",
+			"a|2",
+			"a|2",
+			"a|3",
+			"b|1",
+			"b|5",
+		)},
+	}
+
+	w := newTestWindow(fp,
+		&WindowFunc{Opcode: opcode.WindowRowNumber, Col: -1, DefaultCol: -1, Alias: "rn"},
+		&WindowFunc{Opcode: opcode.WindowRank, Col: -1, DefaultCol: -1, Alias: "r"},
+		&WindowFunc{Opcode: opcode.WindowDenseRank, Col: -1, DefaultCol: -1, Alias: "dr"},
+		&WindowFunc{Opcode: opcode.WindowNtile, Col: -1, DefaultCol: -1, N: evalengine.NewLiteralInt(3), Alias: "nt"},
+	)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"rn|r|dr|nt|p|o",
+			"uint64|uint64|uint64|uint64|varbinary|int64",
+		),
+		"1|1|1|1|a|1",
+		"2|2|2|1|a|2",
+		"3|2|2|2|a|2",
+		"4|4|3|3|a|3",
+		"1|1|1|1|b|1",
+		"2|2|2|2|b|5",
+	)
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want.Rows, result.Rows)
+	assert.Equal(t, "rn", result.Fields[0].Name)
+	assert.Equal(t, sqltypes.Uint64, result.Fields[0].Type)
+	assert.Equal(t, fields, result.Fields[4:])
+
+	fp.rewind()
+	var rows []sqltypes.Row
+	err = w.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		rows = append(rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, want.Rows, rows)
+}
+
+func TestWindowLagLead(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"p|o|def",
+		"varbinary|int64|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1|0",
+			"a|2|0",
+			"a|3|0",
+			"b|4|-1",
+		)},
+	}
+
+	w := newTestWindow(fp,
+		&WindowFunc{Opcode: opcode.WindowLag, Col: 1, DefaultCol: -1, Alias: "lag"},
+		&WindowFunc{Opcode: opcode.WindowLead, Col: 1, DefaultCol: 2, N: evalengine.NewLiteralInt(2), Alias: "lead"},
+	)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"lag|lead|p|o|def",
+			"int64|int64|varbinary|int64|int64",
+		),
+		"null|3|a|1|0",
+		"1|0|a|2|0",
+		"2|0|a|3|0",
+		"null|-1|b|4|-1",
+	)
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want.Rows, result.Rows)
+}
+
+func TestWindowFrames(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"p|o",
+		"varbinary|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1",
+			"a|2",
+			"a|2",
+			"a|4",
+		)},
+	}
+
+	slidingFrame := &WindowFrame{
+		Unit:  sqlparser.FrameRowsType,
+		Start: WindowFramePoint{Type: sqlparser.ExprPrecedingType, Offset: evalengine.NewLiteralInt(1)},
+		End:   WindowFramePoint{Type: sqlparser.ExprFollowingType, Offset: evalengine.NewLiteralInt(1)},
+	}
+	w := newTestWindow(fp,
+		// default frame: RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+		&WindowFunc{Opcode: opcode.WindowAggregate, Aggregate: opcode.AggregateCountStar, Col: -1, DefaultCol: -1, Alias: "cnt"},
+		&WindowFunc{Opcode: opcode.WindowAggregate, Aggregate: opcode.AggregateMax, Col: 1, DefaultCol: -1, Frame: slidingFrame, Alias: "mx", Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)},
+		&WindowFunc{Opcode: opcode.WindowFirstValue, Col: 1, DefaultCol: -1, Frame: slidingFrame, Alias: "fv"},
+		&WindowFunc{Opcode: opcode.WindowLastValue, Col: 1, DefaultCol: -1, Alias: "lv"},
+	)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"cnt|mx|fv|lv|p|o",
+			"int64|int64|int64|int64|varbinary|int64",
+		),
+		"1|2|1|1|a|1",
+		"3|2|1|2|a|2",
+		"3|4|2|2|a|2",
+		"4|4|2|4|a|4",
+	)
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want.Rows, result.Rows)
+}
+
+func TestWindowNtileZero(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("p|o", "varbinary|int64"),
+			"a|1",
+		)},
+	}
+
+	w := newTestWindow(fp,
+		&WindowFunc{Opcode: opcode.WindowNtile, Col: -1, DefaultCol: -1, N: evalengine.NewLiteralInt(0), Alias: "nt"},
+	)
+
+	_, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.EqualError(t, err, "Incorrect arguments to ntile")
+}
+
+func TestWindowMaxMemoryRows(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	saveIgnore := testIgnoreMaxMemoryRows
+	testMaxMemoryRows = 3
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testIgnoreMaxMemoryRows = saveIgnore
+	}()
+
+	fields := sqltypes.MakeTestFields(
+		"p|o",
+		"varbinary|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1",
+			"a|2",
+			"b|1",
+			"b|2",
+			"b|3",
+			"b|4",
+		)},
+	}
+	w := newTestWindow(fp, &WindowFunc{Opcode: opcode.WindowRowNumber, Col: -1, DefaultCol: -1, Alias: "rn"})
+
+	testCases := []struct {
+		ignoreMaxMemoryRows bool
+		err                 string
+	}{
+		{true, ""},
+		{false, "in-memory row count exceeded allowed limit of 3"},
+	}
+	for _, test := range testCases {
+		testIgnoreMaxMemoryRows = test.ignoreMaxMemoryRows
+
+		fp.rewind()
+		_, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+		if test.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, test.err)
+		}
+
+		fp.rewind()
+		err = w.TryStreamExecute(context.Background(), &noopVCursor{}, nil, false, func(qr *sqltypes.Result) error {
+			return nil
+		})
+		if test.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, test.err)
+		}
+	}
+}
+
+func TestWindowDescription(t *testing.T) {
+	w := newTestWindow(&fakePrimitive{},
+		&WindowFunc{Opcode: opcode.WindowRowNumber, Col: -1, DefaultCol: -1, Alias: "rn"},
+		&WindowFunc{Opcode: opcode.WindowAggregate, Aggregate: opcode.AggregateSum, Col: 1, DefaultCol: -1, Alias: "s", Frame: &WindowFrame{
+			Unit:  sqlparser.FrameRowsType,
+			Start: WindowFramePoint{Type: sqlparser.UnboundedPrecedingType},
+			End:   WindowFramePoint{Type: sqlparser.CurrentRowType},
+		}},
+	)
+
+	desc := w.description()
+	assert.Equal(t, "Window", desc.OperatorType)
+	assert.Equal(t, "row_number() AS rn, sum(1) ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW AS s", desc.Other["Functions"])
+	assert.Equal(t, "0 ASC", desc.Other["PartitionBy"])
+	assert.Equal(t, "1 ASC", desc.Other["OrderBy"])
+}
@@ -17,13 +17,17 @@ limitations under the License.
 package evalengine
 
 import (
+	"math"
 	"strconv"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/decimal"
 	"vitess.io/vitess/go/mysql/fastparse"
 	"vitess.io/vitess/go/mysql/format"
+	"vitess.io/vitess/go/mysql/json"
 	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 )
 
 // Sum implements a SUM() aggregation
@@ -41,6 +45,37 @@ type MinMax interface {
 	Reset()
 }
 
+// Bitwise implements a BIT_AND(), BIT_OR() or BIT_XOR() aggregation
+type Bitwise interface {
+	Add(value sqltypes.Value) error
+	Result() sqltypes.Value
+	Reset()
+}
+
+// JSONAggregation merges the partial results of JSON_ARRAYAGG() or JSON_OBJECTAGG() aggregations
+type JSONAggregation interface {
+	Add(value sqltypes.Value) error
+	Result() sqltypes.Value
+	Reset()
+}
+
+// Variance implements a VARIANCE() or STDDEV() aggregation. The values are added in groups,
+// each one with the number of values, their average and their population variance,
+// so the partial results of several shards can be merged without losing precision.
+type Variance interface {
+	Add(count, avg, varPop sqltypes.Value) error
+	Result() sqltypes.Value
+	Reset()
+}
+
+// Grouping implements the GROUPING() function for the rows produced by GROUP BY ... WITH ROLLUP
+type Grouping interface {
+	// Rollup sets the number of grouping keys that are kept in the current row;
+	// all keys from this offset on have been rolled up into a super-aggregate row
+	Rollup(keep int)
+	Result() sqltypes.Value
+}
+
 // aggregationSumCount implements a sum of count values.
 // This is a Vitess-specific optimization that allows our planner to push down
 // some expensive cross-shard operations by summing counts from different result sets.
@@ -499,3 +534,264 @@ func NewAggregationMinMax(typ sqltypes.Type, collationEnv *collations.Environmen
 		return &aggregationMinMax{collation: collation, collationEnv: collationEnv, values: values}
 	}
 }
+
+// aggregationBitwise implements BIT_AND, BIT_OR and BIT_XOR aggregations.
+// Like in MySQL, binary strings are combined byte by byte and must all be of the same length,
+// while all other values are converted to unsigned 64-bit integers.
+// Since the operations are associative, the partial results from different shards
+// can be aggregated using the same operation.
+type aggregationBitwise struct {
+	op     opBitBinary
+	empty  uint64
+	n      uint64
+	binary []byte
+}
+
+func (a *aggregationBitwise) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	if sqltypes.IsBinary(value.Type()) {
+		if a.binary == nil {
+			a.binary = append([]byte{}, value.Raw()...)
+			return nil
+		}
+		if len(a.binary) != len(value.Raw()) {
+			return errBitwiseOperandsLength
+		}
+		a.binary = a.op.binary(a.binary, value.Raw())
+		return nil
+	}
+
+	e, err := valueToEval(value, collationBinary, nil)
+	if err != nil {
+		return err
+	}
+	a.n = a.op.numeric(a.n, uint64(evalToInt64(e).i))
+	return nil
+}
+
+func (a *aggregationBitwise) Result() sqltypes.Value {
+	if a.binary != nil {
+		return sqltypes.MakeTrusted(sqltypes.VarBinary, a.binary)
+	}
+	return sqltypes.NewUint64(a.n)
+}
+
+func (a *aggregationBitwise) Reset() {
+	a.n = a.empty
+	a.binary = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+}
+
+// NewAggregationBitAnd returns a BIT_AND() aggregation. Without any values, the result has all bits set.
+func NewAggregationBitAnd() Bitwise {
+	return &aggregationBitwise{op: opBitAnd{}, empty: math.MaxUint64, n: math.MaxUint64}
+}
+
+// NewAggregationBitOr returns a BIT_OR() aggregation
+func NewAggregationBitOr() Bitwise {
+	return &aggregationBitwise{op: opBitOr{}}
+}
+
+// NewAggregationBitXor returns a BIT_XOR() aggregation
+func NewAggregationBitXor() Bitwise {
+	return &aggregationBitwise{op: opBitXor{}}
+}
+
+// aggregationJSONArray concatenates JSON arrays, as produced by JSON_ARRAYAGG.
+// The result is NULL if no arrays have been aggregated.
+type aggregationJSONArray struct {
+	values []*json.Value
+	init   bool
+}
+
+func (a *aggregationJSONArray) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	doc, err := json.NewFromSQL(value)
+	if err != nil {
+		return err
+	}
+	a.init = true
+	if values, ok := doc.Array(); ok {
+		a.values = append(a.values, values...)
+	} else {
+		a.values = append(a.values, doc)
+	}
+	return nil
+}
+
+func (a *aggregationJSONArray) Result() sqltypes.Value {
+	if !a.init {
+		return sqltypes.NULL
+	}
+	return evalToSQLValue(json.NewArray(a.values))
+}
+
+func (a *aggregationJSONArray) Reset() {
+	a.values = nil
+	a.init = false
+}
+
+// aggregationJSONObject merges JSON objects, as produced by JSON_OBJECTAGG.
+// When a key is found more than once, the last value wins, like in MySQL.
+// The result is NULL if no objects have been aggregated.
+type aggregationJSONObject struct {
+	obj  json.Object
+	init bool
+}
+
+func (a *aggregationJSONObject) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	doc, err := json.NewFromSQL(value)
+	if err != nil {
+		return err
+	}
+	obj, ok := doc.Object()
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "expected a JSON object to aggregate, got: %s", doc.String())
+	}
+	a.init = true
+	obj.Visit(func(key string, v *json.Value) {
+		a.obj.Set(key, v, json.Set)
+	})
+	return nil
+}
+
+func (a *aggregationJSONObject) Result() sqltypes.Value {
+	if !a.init {
+		return sqltypes.NULL
+	}
+	return evalToSQLValue(json.NewObject(a.obj))
+}
+
+func (a *aggregationJSONObject) Reset() {
+	a.obj = json.Object{}
+	a.init = false
+}
+
+// NewAggregationJSONArray returns an aggregation that merges the results of JSON_ARRAYAGG()
+func NewAggregationJSONArray() JSONAggregation {
+	return &aggregationJSONArray{}
+}
+
+// NewAggregationJSONObject returns an aggregation that merges the results of JSON_OBJECTAGG()
+func NewAggregationJSONObject() JSONAggregation {
+	return &aggregationJSONObject{}
+}
+
+// aggregationVariance merges groups of values using the parallel algorithm by Chan et al.:
+// the mean and the sum of squared differences from the mean (M2) of two groups are combined as
+//
+//	delta = mean_b - mean_a
+//	mean  = mean_a + delta * n_b / n
+//	M2    = M2_a + M2_b + delta * delta * n_a * n_b / n
+//
+// Unlike calculating the variance from the sum of squares, this does not subtract two large
+// values from each other, which loses most of the precision when the values are large
+// and close together.
+type aggregationVariance struct {
+	sample bool
+	stddev bool
+
+	n    float64
+	mean float64
+	m2   float64
+}
+
+func (a *aggregationVariance) Add(count, avg, varPop sqltypes.Value) error {
+	if count.IsNull() || avg.IsNull() {
+		return nil
+	}
+	n, err := a.toFloat(count)
+	if err != nil || n <= 0 {
+		return err
+	}
+	mean, err := a.toFloat(avg)
+	if err != nil {
+		return err
+	}
+	var m2 float64
+	if !varPop.IsNull() {
+		v, err := a.toFloat(varPop)
+		if err != nil {
+			return err
+		}
+		m2 = v * n
+	}
+
+	total := a.n + n
+	delta := mean - a.mean
+	a.mean += delta * n / total
+	a.m2 += m2 + delta*delta*a.n*n/total
+	a.n = total
+	return nil
+}
+
+// toFloat converts the value to a DOUBLE like MySQL does when calculating a variance
+func (a *aggregationVariance) toFloat(value sqltypes.Value) (float64, error) {
+	e, err := valueToEval(value, collationBinary, nil)
+	if err != nil {
+		return 0, err
+	}
+	f, _ := evalToFloat(e)
+	return f.f, nil
+}
+
+func (a *aggregationVariance) Result() sqltypes.Value {
+	if a.n == 0 || (a.sample && a.n == 1) {
+		return sqltypes.NULL
+	}
+	variance := a.m2 / a.n
+	if a.sample {
+		variance = a.m2 / (a.n - 1)
+	}
+	if a.stddev {
+		return sqltypes.NewFloat64(math.Sqrt(variance))
+	}
+	return sqltypes.NewFloat64(variance)
+}
+
+func (a *aggregationVariance) Reset() {
+	a.n = 0
+	a.mean = 0
+	a.m2 = 0
+}
+
+// NewAggregationVariance returns a VAR_POP(), or a VAR_SAMP() when sample is set.
+// When stddev is set, the square root of the variance is returned instead.
+func NewAggregationVariance(sample, stddev bool) Variance {
+	return &aggregationVariance{sample: sample, stddev: stddev}
+}
+
+// groupingBitmask returns a bitmask with one bit for each argument to the GROUPING() call,
+// with the rightmost argument in the lowest bit. A bit is set when the argument
+// has been rolled up in the current row.
+type groupingBitmask struct {
+	keys []int
+	keep int
+}
+
+func (g *groupingBitmask) Rollup(keep int) {
+	g.keep = keep
+}
+
+func (g *groupingBitmask) Result() sqltypes.Value {
+	var mask int64
+	for _, key := range g.keys {
+		mask <<= 1
+		if key >= g.keep {
+			mask |= 1
+		}
+	}
+	return sqltypes.NewInt64(mask)
+}
+
+// NewGrouping returns a GROUPING() implementation. The keys are the offsets of the
+// function arguments in the GROUP BY clause.
+func NewGrouping(keys []int) Grouping {
+	return &groupingBitmask{keys: keys, keep: math.MaxInt}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package evalengine
 
 import (
+	"math"
 	"strconv"
 	"testing"
 
@@ -170,3 +171,160 @@ func TestMinMax(t *testing.T) {
 		})
 	}
 }
+
+func TestGrouping(t *testing.T) {
+	tcases := []struct {
+		keys []int
+		keep int
+		want sqltypes.Value
+	}{
+		{keys: []int{0}, keep: 1, want: NewInt64(0)},
+		{keys: []int{0}, keep: 0, want: NewInt64(1)},
+		{keys: []int{0, 1}, keep: 2, want: NewInt64(0)},
+		{keys: []int{0, 1}, keep: 1, want: NewInt64(1)},
+		{keys: []int{0, 1}, keep: 0, want: NewInt64(3)},
+		{keys: []int{1, 0}, keep: 1, want: NewInt64(2)},
+		{keys: []int{2, 0, 1}, keep: 1, want: NewInt64(5)},
+	}
+	for i, tcase := range tcases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			grouping := NewGrouping(tcase.keys)
+			utils.MustMatch(t, NewInt64(0), grouping.Result())
+
+			grouping.Rollup(tcase.keep)
+			utils.MustMatch(t, tcase.want, grouping.Result())
+		})
+	}
+}
+
+func TestBitwiseAggregation(t *testing.T) {
+	tcases := []struct {
+		values        []sqltypes.Value
+		and, or, xor  sqltypes.Value
+		expectedError string
+	}{
+		{
+			values: nil,
+			and:    sqltypes.NewUint64(18446744073709551615),
+			or:     sqltypes.NewUint64(0),
+			xor:    sqltypes.NewUint64(0),
+		},
+		{
+			values: []sqltypes.Value{NULL, NewInt64(6), NewInt64(3), NULL},
+			and:    sqltypes.NewUint64(2),
+			or:     sqltypes.NewUint64(7),
+			xor:    sqltypes.NewUint64(5),
+		},
+		{
+			values: []sqltypes.Value{NewInt64(-1), sqltypes.NewUint64(12), sqltypes.NewVarChar("4")},
+			and:    sqltypes.NewUint64(4),
+			or:     sqltypes.NewUint64(18446744073709551615),
+			xor:    sqltypes.NewUint64(18446744073709551607),
+		},
+		{
+			values: []sqltypes.Value{sqltypes.NewVarBinary("\x0f\xf0"), sqltypes.NewVarBinary("\x3c\x3c")},
+			and:    sqltypes.NewVarBinary("\x0c\x30"),
+			or:     sqltypes.NewVarBinary("\x3f\xfc"),
+			xor:    sqltypes.NewVarBinary("\x33\xcc"),
+		},
+		{
+			values:        []sqltypes.Value{sqltypes.NewVarBinary("\x0f\xf0"), sqltypes.NewVarBinary("\x3c")},
+			expectedError: "Binary operands of bitwise operators must be of equal length",
+		},
+	}
+	for i, tcase := range tcases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			for _, agg := range []struct {
+				bitwise     Bitwise
+				want, empty sqltypes.Value
+			}{
+				{NewAggregationBitAnd(), tcase.and, tcases[0].and},
+				{NewAggregationBitOr(), tcase.or, tcases[0].or},
+				{NewAggregationBitXor(), tcase.xor, tcases[0].xor},
+			} {
+				var err error
+				for _, v := range tcase.values {
+					if err = agg.bitwise.Add(v); err != nil {
+						break
+					}
+				}
+				if tcase.expectedError != "" {
+					require.ErrorContains(t, err, tcase.expectedError)
+					continue
+				}
+				require.NoError(t, err)
+				utils.MustMatch(t, agg.want, agg.bitwise.Result())
+
+				agg.bitwise.Reset()
+				utils.MustMatch(t, agg.empty, agg.bitwise.Result())
+			}
+		})
+	}
+}
+
+func TestJSONAggregation(t *testing.T) {
+	arrays := NewAggregationJSONArray()
+	require.True(t, arrays.Result().IsNull())
+	require.NoError(t, arrays.Add(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`[1, "a"]`))))
+	require.NoError(t, arrays.Add(NULL))
+	require.NoError(t, arrays.Add(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`[null, {"b": 2}]`))))
+	require.Equal(t, `[1, "a", null, {"b": 2}]`, arrays.Result().ToString())
+	arrays.Reset()
+	require.True(t, arrays.Result().IsNull())
+
+	objects := NewAggregationJSONObject()
+	require.True(t, objects.Result().IsNull())
+	require.NoError(t, objects.Add(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"b": 1, "a": [1]}`))))
+	require.NoError(t, objects.Add(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"c": null, "b": 2}`))))
+	require.Equal(t, `{"a": [1], "b": 2, "c": null}`, objects.Result().ToString())
+	require.ErrorContains(t, objects.Add(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`[1]`))), "expected a JSON object")
+	objects.Reset()
+	require.True(t, objects.Result().IsNull())
+}
+
+func TestVarianceAggregation(t *testing.T) {
+	// the values are 1000000001, 1000000002, 1000000003 and 1000000004, split over two shards and an empty one
+	groups := [][3]sqltypes.Value{
+		{NewInt64(2), sqltypes.NewDecimal("1000000001.5000"), sqltypes.NewFloat64(0.25)},
+		{NewInt64(0), NULL, NULL},
+		{NewInt64(2), sqltypes.NewDecimal("1000000003.5000"), sqltypes.NewFloat64(0.25)},
+	}
+	for _, tcase := range []struct {
+		sample, stddev bool
+		want           float64
+	}{
+		{want: 1.25},
+		{sample: true, want: 5.0 / 3},
+		{stddev: true, want: math.Sqrt(1.25)},
+		{sample: true, stddev: true, want: math.Sqrt(5.0 / 3)},
+	} {
+		variance := NewAggregationVariance(tcase.sample, tcase.stddev)
+		require.True(t, variance.Result().IsNull())
+		for _, group := range groups {
+			require.NoError(t, variance.Add(group[0], group[1], group[2]))
+		}
+		result, err := variance.Result().ToFloat64()
+		require.NoError(t, err)
+		require.InDelta(t, tcase.want, result, 1e-9)
+
+		variance.Reset()
+		for _, v := range []int64{1000000001, 1000000002, 1000000003, 1000000004} {
+			// a single value is a group without any variance
+			require.NoError(t, variance.Add(NewInt64(1), NewInt64(v), NULL))
+		}
+		result, err = variance.Result().ToFloat64()
+		require.NoError(t, err)
+		require.InDelta(t, tcase.want, result, 1e-9)
+	}
+
+	samp := NewAggregationVariance(true, false)
+	require.NoError(t, samp.Add(NewInt64(1), NewInt64(5), NULL))
+	require.True(t, samp.Result().IsNull())
+
+	// identical values never produce a negative variance, which would make the standard deviation NULL
+	stddev := NewAggregationVariance(false, true)
+	for range 5 {
+		require.NoError(t, stddev.Add(NewInt64(1), sqltypes.NewFloat64(0.7), NULL))
+	}
+	utils.MustMatch(t, sqltypes.NewFloat64(0), stddev.Result())
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+type (
+	// JSONTable expands a JSON document into rows, the same way the JSON_TABLE
+	// table function does in MySQL.
+	JSONTable struct {
+		// Doc is the expression producing the JSON document
+		Doc Expr
+		// Columns contains all the columns produced, in the order they appear in the output rows
+		Columns []*JSONTableColumn
+
+		root *jsonTableScope
+	}
+
+	// JSONTableColumn is a column produced by a JSON_TABLE
+	JSONTableColumn struct {
+		Name string
+		Type Type
+
+		// Ordinality is set for FOR ORDINALITY columns
+		Ordinality bool
+		// Exists is set for EXISTS PATH columns
+		Exists bool
+		Path   *json.Path
+
+		// OnEmpty and OnError are the responses used when the path does not match or
+		// when the value can't be stored in the column. A nil response means NULL.
+		OnEmpty, OnError *JSONTableResponse
+
+		offset int
+	}
+
+	// JSONTableResponse is the ON EMPTY or ON ERROR behaviour of a JSON_TABLE column
+	JSONTableResponse struct {
+		Error   bool
+		Default sqltypes.Value
+	}
+
+	// jsonTableScope is either the top level of the JSON_TABLE or one of its NESTED PATH clauses
+	jsonTableScope struct {
+		path    *json.Path
+		columns []*JSONTableColumn
+		nested  []*jsonTableScope
+		// first and last are the offsets of all columns belonging to this scope and the scopes nested under it
+		first, last int
+	}
+)
+
+// TranslateJSONTable translates a JSON_TABLE table function so it can be evaluated.
+func TranslateJSONTable(node *sqlparser.JSONTableExpr, cfg *Config) (*JSONTable, error) {
+	doc, err := Translate(node.Expr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	jt := &JSONTable{Doc: doc}
+	jt.root, err = jt.translateScope(node.Filter, node.Columns, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return jt, nil
+}
+
+func (jt *JSONTable) translateScope(path sqlparser.Expr, defs []*sqlparser.JtColumnDefinition, cfg *Config) (*jsonTableScope, error) {
+	jp, err := translateJSONTablePath(path)
+	if err != nil {
+		return nil, err
+	}
+	scope := &jsonTableScope{path: jp, first: len(jt.Columns)}
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			col := &JSONTableColumn{
+				Name:       def.JtOrdinal.Name.String(),
+				Type:       NewType(sqltypes.Uint32, collations.CollationBinaryID),
+				Ordinality: true,
+			}
+			jt.addColumn(scope, col)
+		case def.JtPath != nil:
+			col, err := translateJSONTableColumn(def.JtPath, cfg)
+			if err != nil {
+				return nil, err
+			}
+			jt.addColumn(scope, col)
+		case def.JtNestedPath != nil:
+			nested, err := jt.translateScope(def.JtNestedPath.Path, def.JtNestedPath.Columns, cfg)
+			if err != nil {
+				return nil, err
+			}
+			scope.nested = append(scope.nested, nested)
+		}
+	}
+	scope.last = len(jt.Columns)
+	return scope, nil
+}
+
+func (jt *JSONTable) addColumn(scope *jsonTableScope, col *JSONTableColumn) {
+	col.offset = len(jt.Columns)
+	jt.Columns = append(jt.Columns, col)
+	scope.columns = append(scope.columns, col)
+}
+
+func translateJSONTableColumn(def *sqlparser.JtPathColDef, cfg *Config) (*JSONTableColumn, error) {
+	path, err := translateJSONTablePath(def.Path)
+	if err != nil {
+		return nil, err
+	}
+	typ := def.Type.SQLType()
+	collation := collations.CollationForType(typ, cfg.Collation)
+	col := &JSONTableColumn{
+		Name:   def.Name.String(),
+		Type:   NewType(typ, collation),
+		Exists: def.JtColExists,
+		Path:   path,
+	}
+	col.OnEmpty, err = translateJSONTableResponse(def.EmptyOnResponse)
+	if err != nil {
+		return nil, err
+	}
+	col.OnError, err = translateJSONTableResponse(def.ErrorOnResponse)
+	if err != nil {
+		return nil, err
+	}
+	return col, nil
+}
+
+func translateJSONTableResponse(res *sqlparser.JtOnResponse) (*JSONTableResponse, error) {
+	if res == nil {
+		return nil, nil
+	}
+	switch res.ResponseType {
+	case sqlparser.ErrorJSONType:
+		return &JSONTableResponse{Error: true}, nil
+	case sqlparser.DefaultJSONType:
+		lit, ok := res.Expr.(*sqlparser.Literal)
+		if !ok {
+			return nil, vterrors.VT12001("non-literal DEFAULT value in JSON_TABLE")
+		}
+		return &JSONTableResponse{Default: sqltypes.NewVarChar(lit.Val)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func translateJSONTablePath(expr sqlparser.Expr) (*json.Path, error) {
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.StrVal {
+		return nil, vterrors.VT12001("non-literal path in JSON_TABLE")
+	}
+	var p json.PathParser
+	return p.ParseBytes([]byte(lit.Val))
+}
+
+// Evaluate evaluates the JSON document and expands it into rows
+func (jt *JSONTable) Evaluate(env *ExpressionEnv) ([]sqltypes.Row, error) {
+	res, err := env.Evaluate(jt.Doc)
+	if err != nil {
+		return nil, err
+	}
+	if res.v == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON("JSON_TABLE", res.v)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sqltypes.Row
+	row := make(sqltypes.Row, len(jt.Columns))
+	_, err = jt.root.expand(env, doc, row, func(row sqltypes.Row) {
+		rows = append(rows, append(sqltypes.Row(nil), row...))
+	})
+	return rows, err
+}
+
+// expand produces the rows for all matches of the scope's path in the given context,
+// and returns true if at least one row was produced.
+func (scope *jsonTableScope) expand(env *ExpressionEnv, ctx *json.Value, row sqltypes.Row, emit func(sqltypes.Row)) (bool, error) {
+	var matches []*json.Value
+	scope.path.Match(ctx, true, func(value *json.Value) {
+		matches = append(matches, value)
+	})
+
+	for idx, match := range matches {
+		for _, col := range scope.columns {
+			val, err := col.value(env, match, idx+1)
+			if err != nil {
+				return false, err
+			}
+			row[col.offset] = val
+		}
+
+		// sibling NESTED PATH clauses produce rows one after another,
+		// with the columns of the other siblings set to NULL
+		produced := false
+		for _, nested := range scope.nested {
+			scope.clearNested(row)
+			ok, err := nested.expand(env, match, row, emit)
+			if err != nil {
+				return false, err
+			}
+			produced = produced || ok
+		}
+		if !produced {
+			scope.clearNested(row)
+			emit(row)
+		}
+	}
+	return len(matches) > 0, nil
+}
+
+func (scope *jsonTableScope) clearNested(row sqltypes.Row) {
+	for _, nested := range scope.nested {
+		for i := nested.first; i < nested.last; i++ {
+			row[i] = sqltypes.NULL
+		}
+	}
+}
+
+func (col *JSONTableColumn) value(env *ExpressionEnv, ctx *json.Value, ordinal int) (sqltypes.Value, error) {
+	if col.Ordinality {
+		return sqltypes.NewUint32(uint32(ordinal)), nil
+	}
+
+	var matches []*json.Value
+	col.Path.Match(ctx, true, func(value *json.Value) {
+		matches = append(matches, value)
+	})
+
+	if col.Exists {
+		exists := sqltypes.NewInt64(0)
+		if len(matches) > 0 {
+			exists = sqltypes.NewInt64(1)
+		}
+		return col.coerce(env, exists)
+	}
+
+	switch {
+	case len(matches) == 0:
+		return col.respond(env, col.OnEmpty, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Missing value for JSON_TABLE column '%s'", col.Name))
+	case len(matches) > 1:
+		return col.respond(env, col.OnError, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Can't store an array or an object in the scalar column '%s' of JSON_TABLE", col.Name))
+	}
+
+	match := matches[0]
+	if col.Type.Type() == sqltypes.TypeJSON {
+		return evalToSQLValue(match), nil
+	}
+
+	var text []byte
+	switch match.Type() {
+	case json.TypeNull:
+		return sqltypes.NULL, nil
+	case json.TypeObject, json.TypeArray:
+		return col.respond(env, col.OnError, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Can't store an array or an object in the scalar column '%s' of JSON_TABLE", col.Name))
+	case json.TypeString:
+		text, _ = match.StringBytes()
+	case json.TypeBoolean:
+		b, _ := match.Bool()
+		if sqltypes.IsNumber(col.Type.Type()) {
+			text = []byte("0")
+			if b {
+				text = []byte("1")
+			}
+		} else {
+			text = match.ToRawBytes()
+		}
+	default:
+		text = match.ToRawBytes()
+	}
+
+	val, err := col.coerce(env, sqltypes.MakeTrusted(sqltypes.VarChar, text))
+	if err != nil {
+		return col.respond(env, col.OnError, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid JSON value for column '%s' of JSON_TABLE", col.Name))
+	}
+	return val, nil
+}
+
+func (col *JSONTableColumn) respond(env *ExpressionEnv, res *JSONTableResponse, err error) (sqltypes.Value, error) {
+	switch {
+	case res == nil:
+		return sqltypes.NULL, nil
+	case res.Error:
+		return sqltypes.Value{}, err
+	default:
+		return col.coerce(env, res.Default)
+	}
+}
+
+func (col *JSONTableColumn) coerce(env *ExpressionEnv, val sqltypes.Value) (sqltypes.Value, error) {
+	cast, err := valueToEvalCast(val, col.Type.Type(), col.Type.collation, col.Type.values, env.sqlmode)
+	if err != nil {
+		return sqltypes.Value{}, err
+	}
+	return evalToSQLValueWithType(cast, col.Type), nil
+}
@@ -323,6 +323,59 @@ func (cached *IsExpr) CachedSize(alloc bool) int64 {
 	size += cached.UnaryExpr.CachedSize(false)
 	return size
 }
+func (cached *JSONTable) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Doc vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Doc.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Columns []*vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(8))
+		for _, elem := range cached.Columns {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field root *vitess.io/vitess/go/vt/vtgate/evalengine.jsonTableScope
+	size += cached.root.CachedSize(true)
+	return size
+}
+func (cached *JSONTableColumn) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field Name string
+	size += hack.RuntimeAllocSize(int64(len(cached.Name)))
+	// field Type vitess.io/vitess/go/vt/vtgate/evalengine.Type
+	size += cached.Type.CachedSize(false)
+	// field OnEmpty *vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableResponse
+	size += cached.OnEmpty.CachedSize(true)
+	// field OnError *vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableResponse
+	size += cached.OnError.CachedSize(true)
+	return size
+}
+func (cached *JSONTableResponse) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(40)
+	}
+	// field Default vitess.io/vitess/go/sqltypes.Value
+	size += cached.Default.CachedSize(false)
+	return size
+}
 func (cached *LikeExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -2161,6 +2214,27 @@ func (cached *evalYear) CachedSize(alloc bool) int64 {
 func (cached *frame) CachedSize(alloc bool) int64 {
 	return int64(0)
 }
+func (cached *jsonTableScope) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(72)
+	}
+	// field columns []*vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.columns)) * int64(8))
+	}
+	// field nested []*vitess.io/vitess/go/vt/vtgate/evalengine.jsonTableScope
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.nested)) * int64(8))
+		for _, elem := range cached.nested {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
 func (cached *typedExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
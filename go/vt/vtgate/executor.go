@@ -142,6 +142,10 @@ type (
 
 		warmingReadsChannel chan bool
 
+		// resultCache caches the results of the SELECTs that opt in with
+		// the RESULT_CACHE_TTL directive. It is nil when disabled.
+		resultCache *resultCache
+
 		vConfig   econtext.VCursorConfig
 		ddlConfig dynamicconfig.DDL
 	}
@@ -237,6 +241,7 @@ func (e *Executor) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars, streamlog.GetQueryLogConfig())
+	logStats.QueryAttributes = safeSession.GetOptions().GetQueryAttributes()
 	stmtType, result, err := e.execute(ctx, mysqlCtx, safeSession, sql, bindVars, logStats)
 	logStats.Error = err
 	if result == nil {
@@ -300,6 +305,7 @@ func (e *Executor) StreamExecute(
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars, streamlog.GetQueryLogConfig())
+	logStats.QueryAttributes = safeSession.GetOptions().GetQueryAttributes()
 	srr := &streaminResultReceiver{callback: callback}
 	var err error
 
@@ -499,6 +505,8 @@ func (e *Executor) addNeededBindVars(vcursor *econtext.VCursorImpl, bindVarNeeds
 				v = options.GetWorkload().String()
 			})
 			bindVars[key] = sqltypes.StringBindVariable(v)
+		case sysvars.ScatterConsistency.Name:
+			bindVars[key] = sqltypes.StringBindVariable(session.ScatterConsistency.String())
 		case sysvars.DDLStrategy.Name:
 			bindVars[key] = sqltypes.StringBindVariable(session.DDLStrategy)
 		case sysvars.MigrationContext.Name:
@@ -527,6 +535,12 @@ func (e *Executor) addNeededBindVars(vcursor *econtext.VCursorImpl, bindVarNeeds
 				}
 			})
 			bindVars[key] = sqltypes.StringBindVariable(v)
+		case sysvars.ReadAfterWriteAuto.Name:
+			var v bool
+			ifReadAfterWriteExist(session, func(raw *vtgatepb.ReadAfterWrite) {
+				v = raw.ReadAfterWriteAuto
+			})
+			bindVars[key] = sqltypes.BoolBindVariable(v)
 		case sysvars.Version.Name:
 			bindVars[key] = sqltypes.StringBindVariable(servenv.AppVersion.MySQLVersion())
 		case sysvars.VersionComment.Name:
@@ -591,7 +605,10 @@ func (e *Executor) handleBegin(ctx context.Context, safeSession *econtext.SafeSe
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
 
 	begin := stmt.(*sqlparser.Begin)
+	// A transaction in progress is committed before the new one begins.
+	tables := safeSession.TakeResultCacheTables()
 	err := e.txConn.Begin(ctx, safeSession, begin.TxAccessModes)
+	e.invalidateResultCache(tables)
 	logStats.ExecuteTime = time.Since(execStart)
 
 	e.updateQueryCounts("Begin", "", "", 0)
@@ -605,13 +622,16 @@ func (e *Executor) handleCommit(ctx context.Context, safeSession *econtext.SafeS
 	logStats.ShardQueries = uint64(len(safeSession.ShardSessions))
 	e.updateQueryCounts("Commit", "", "", int64(logStats.ShardQueries))
 
-	err := e.txConn.Commit(ctx, safeSession)
+	err := e.Commit(ctx, safeSession)
 	logStats.CommitTime = time.Since(execStart)
 	return &sqltypes.Result{}, err
 }
 
 // Commit commits the existing transactions
 func (e *Executor) Commit(ctx context.Context, safeSession *econtext.SafeSession) error {
+	// The cached results of the written tables are invalidated even if the
+	// commit fails, as it may have succeeded on some shards.
+	defer e.invalidateResultCache(safeSession.TakeResultCacheTables())
 	return e.txConn.Commit(ctx, safeSession)
 }
 
@@ -1102,12 +1122,16 @@ func (e *Executor) getPlan(
 	if err != nil {
 		return nil, err
 	}
+	if err := qh.ApplyQueryAttributes(vcursor.SafeSession.GetOptions().GetQueryAttributes()); err != nil {
+		return nil, err
+	}
 	vcursor.SetIgnoreMaxMemoryRows(qh.IgnoreMaxMemoryRows)
 	vcursor.SetConsolidator(qh.Consolidator)
 	vcursor.SetWorkloadName(qh.Workload)
 	vcursor.UpdateForeignKeyChecksState(qh.ForeignKeyChecks)
 	vcursor.SetPriority(qh.Priority)
 	vcursor.SetExecQueryTimeout(qh.Timeout)
+	vcursor.SetResultCacheTTL(qh.ResultCacheTTL)
 
 	setVarComment, err := prepareSetVarComment(vcursor, stmt)
 	if err != nil {
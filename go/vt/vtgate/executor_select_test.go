@@ -783,6 +783,7 @@ func TestSelectSystemVariables(t *testing.T) {
 			ReadAfterWriteGtid:    "a fine gtid",
 			ReadAfterWriteTimeout: 13,
 			SessionTrackGtids:     true,
+			ReadAfterWriteAuto:    true,
 		},
 	}
 	logChan := executor.queryLogger.Subscribe("Test")
@@ -790,7 +791,7 @@ func TestSelectSystemVariables(t *testing.T) {
 
 	sql := "select @@autocommit, @@client_found_rows, @@skip_query_plan_cache, @@enable_system_settings, " +
 		"@@sql_select_limit, @@transaction_mode, @@workload, @@read_after_write_gtid, " +
-		"@@read_after_write_timeout, @@session_track_gtids, @@ddl_strategy, @@migration_context, @@socket, @@query_timeout"
+		"@@read_after_write_timeout, @@session_track_gtids, @@read_after_write_auto, @@ddl_strategy, @@migration_context, @@socket, @@query_timeout"
 
 	result, err := executorExec(ctx, executor, session, sql, map[string]*querypb.BindVariable{})
 	wantResult := &sqltypes.Result{
@@ -805,6 +806,7 @@ func TestSelectSystemVariables(t *testing.T) {
 			{Name: "@@read_after_write_gtid", Type: sqltypes.VarChar, Charset: uint32(collations.MySQL8().DefaultConnectionCharset())},
 			{Name: "@@read_after_write_timeout", Type: sqltypes.Float64, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_NUM_FLAG)},
 			{Name: "@@session_track_gtids", Type: sqltypes.VarChar, Charset: uint32(collations.MySQL8().DefaultConnectionCharset())},
+			{Name: "@@read_after_write_auto", Type: sqltypes.Int64, Charset: collations.CollationBinaryID, Flags: uint32(querypb.MySqlFlag_NUM_FLAG)},
 			{Name: "@@ddl_strategy", Type: sqltypes.VarChar, Charset: uint32(collations.MySQL8().DefaultConnectionCharset())},
 			{Name: "@@migration_context", Type: sqltypes.VarChar, Charset: uint32(collations.MySQL8().DefaultConnectionCharset())},
 			{Name: "@@socket", Type: sqltypes.VarChar, Charset: uint32(collations.MySQL8().DefaultConnectionCharset())},
@@ -823,6 +825,7 @@ func TestSelectSystemVariables(t *testing.T) {
 			sqltypes.NewVarChar("a fine gtid"),
 			sqltypes.NewFloat64(13),
 			sqltypes.NewVarChar("own_gtid"),
+			sqltypes.NewInt64(1),
 			sqltypes.NewVarChar(""),
 			sqltypes.NewVarChar(""),
 			sqltypes.NewVarChar(""),
@@ -153,6 +153,15 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set workload = 1",
 		err: "incorrect argument type to variable 'workload': INT64",
+	}, {
+		in:  "set scatter_consistency = 'snapshot'",
+		out: &vtgatepb.Session{Autocommit: true, ScatterConsistency: vtgatepb.ScatterConsistency_SNAPSHOT},
+	}, {
+		in:  "set scatter_consistency = eventual",
+		out: &vtgatepb.Session{Autocommit: true},
+	}, {
+		in:  "set scatter_consistency = 'aa'",
+		err: "invalid scatter_consistency: aa",
 	}, {
 		in:  "set tx_isolation = 'read-committed'",
 		out: &vtgatepb.Session{Autocommit: true},
@@ -264,6 +273,15 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set @@query_timeout = 50, query_timeout = 75",
 		out: &vtgatepb.Session{Autocommit: true, QueryTimeout: 75},
+	}, {
+		in:  "set @@read_after_write_auto = on",
+		out: &vtgatepb.Session{Autocommit: true, ReadAfterWrite: &vtgatepb.ReadAfterWrite{ReadAfterWriteAuto: true}},
+	}, {
+		in:  "set @@read_after_write_auto = off",
+		out: &vtgatepb.Session{Autocommit: true, ReadAfterWrite: &vtgatepb.ReadAfterWrite{}},
+	}, {
+		in:  "set @@read_after_write_auto = 'auto'",
+		err: "variable 'read_after_write_auto' can't be set to the value: 'auto' is not a boolean",
 	}}
 	for i, tcase := range testcases {
 		t.Run(fmt.Sprintf("%d-%s", i, tcase.in), func(t *testing.T) {
@@ -1813,6 +1813,42 @@ func TestGetPlanPriority(t *testing.T) {
 
 }
 
+func TestGetPlanQueryAttributes(t *testing.T) {
+	testCases := []struct {
+		name             string
+		sql              string
+		expectedPriority string
+		expectedWorkload string
+	}{
+		{name: "from query attributes", sql: "select * from music_user_map", expectedPriority: "20", expectedWorkload: "checkout"},
+		{name: "directives take precedence", sql: "select /*vt+ PRIORITY=33 WORKLOAD_NAME=reports */ * from music_user_map", expectedPriority: "33", expectedWorkload: "reports"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r, _, _, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
+
+			session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@unknown", Options: &querypb.ExecuteOptions{
+				QueryAttributes: map[string]string{
+					sqlparser.QueryAttributePriority:     "20",
+					sqlparser.QueryAttributeWorkloadName: "checkout",
+				},
+			}})
+			logStats := logstats.NewLogStats(ctx, "Test", "", "", nil, streamlog.NewQueryLogConfigForTest())
+			vCursor, err := econtext.NewVCursorImpl(session, makeComments(""), r, nil, r.vm, r.VSchema(), r.resolver.resolver, nil, nullResultsObserver{}, econtext.VCursorConfig{})
+			require.NoError(t, err)
+
+			stmt, err := sqlparser.NewTestParser().Parse(testCase.sql)
+			require.NoError(t, err)
+
+			_, err = r.getPlan(context.Background(), vCursor, testCase.sql, stmt, makeComments(""), map[string]*querypb.BindVariable{}, nil, true, logStats)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedPriority, vCursor.SafeSession.Options.Priority)
+			assert.Equal(t, testCase.expectedWorkload, vCursor.SafeSession.Options.WorkloadName)
+		})
+	}
+}
+
 func TestPassthroughDDL(t *testing.T) {
 	executor, sbc1, sbc2, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
 	session := &vtgatepb.Session{
@@ -18,6 +18,7 @@ package executorcontext
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -34,6 +35,7 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/sysvars"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 )
@@ -199,6 +201,7 @@ func (session *SafeSession) resetCommonLocked() {
 	session.Session.InTransaction = false
 	session.commitOrder = vtgatepb.CommitOrder_NORMAL
 	session.Savepoints = nil
+	session.ResultCacheTables = nil
 	if session.Options != nil {
 		session.Options.TransactionAccessMode = nil
 	}
@@ -705,6 +708,28 @@ func (session *SafeSession) StoreSavepoint(sql string) {
 	session.Savepoints = append(session.Savepoints, sql)
 }
 
+// StoreResultCacheTables records the tables written by the open transaction,
+// whose cached results must be invalidated when it commits.
+func (session *SafeSession) StoreResultCacheTables(tables []string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	for _, table := range tables {
+		if !slices.Contains(session.ResultCacheTables, table) {
+			session.ResultCacheTables = append(session.ResultCacheTables, table)
+		}
+	}
+}
+
+// TakeResultCacheTables returns the tables recorded by StoreResultCacheTables,
+// and forgets them.
+func (session *SafeSession) TakeResultCacheTables() []string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	tables := session.ResultCacheTables
+	session.ResultCacheTables = nil
+	return tables
+}
+
 // InReservedConn returns true if the session needs to execute on a dedicated connection
 func (session *SafeSession) InReservedConn() bool {
 	session.mu.Lock()
@@ -899,6 +924,55 @@ func (session *SafeSession) SetSessionTrackGtids(enable bool) {
 	session.ReadAfterWrite.SessionTrackGtids = enable
 }
 
+// SetReadAfterWriteAuto set the ReadAfterWriteAuto setting. Disabling it
+// forgets the shard positions recorded so far.
+func (session *SafeSession) SetReadAfterWriteAuto(enable bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.ReadAfterWrite == nil {
+		session.ReadAfterWrite = &vtgatepb.ReadAfterWrite{}
+	}
+	session.ReadAfterWrite.ReadAfterWriteAuto = enable
+	if !enable {
+		session.ReadAfterWrite.ShardPositions = nil
+	}
+}
+
+// ReadAfterWriteAuto returns true if shard positions are recorded after writes.
+func (session *SafeSession) ReadAfterWriteAuto() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.ReadAfterWrite.GetReadAfterWriteAuto()
+}
+
+// GetReadAfterWriteTimeout returns the ReadAfterWriteTimeout setting.
+func (session *SafeSession) GetReadAfterWriteTimeout() float64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.ReadAfterWrite.GetReadAfterWriteTimeout()
+}
+
+// SetShardPosition records the position that later reads of the shard must observe.
+func (session *SafeSession) SetShardPosition(keyspace, shard, position string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if !session.ReadAfterWrite.GetReadAfterWriteAuto() {
+		return
+	}
+	if session.ReadAfterWrite.ShardPositions == nil {
+		session.ReadAfterWrite.ShardPositions = make(map[string]string)
+	}
+	session.ReadAfterWrite.ShardPositions[topoproto.KeyspaceShardString(keyspace, shard)] = position
+}
+
+// GetShardPosition returns the position recorded for the shard, if any.
+func (session *SafeSession) GetShardPosition(keyspace, shard string) (string, bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	position, ok := session.ReadAfterWrite.GetShardPositions()[topoproto.KeyspaceShardString(keyspace, shard)]
+	return position, ok
+}
+
 func removeShard(tabletAlias *topodatapb.TabletAlias, sessions []*vtgatepb.Session_ShardSession) ([]*vtgatepb.Session_ShardSession, error) {
 	idx := -1
 	for i, session := range sessions {
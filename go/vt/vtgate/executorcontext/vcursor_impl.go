@@ -150,6 +150,7 @@ type (
 		vm                  VSchemaOperator
 		semTable            *semantics.SemTable
 		queryTimeout        time.Duration
+		resultCacheTTL      time.Duration
 
 		warnings []*querypb.QueryWarning // any warnings that are accumulated during the planning phase are stored here
 
@@ -1103,6 +1104,11 @@ func (vc *VCursorImpl) SetWorkload(workload querypb.ExecuteOptions_Workload) {
 	vc.SafeSession.GetOrCreateOptions().Workload = workload
 }
 
+// SetScatterConsistency implements the SessionActions interface
+func (vc *VCursorImpl) SetScatterConsistency(consistency vtgatepb.ScatterConsistency) {
+	vc.SafeSession.ScatterConsistency = consistency
+}
+
 // SetPlannerVersion implements the SessionActions interface
 func (vc *VCursorImpl) SetPlannerVersion(v plancontext.PlannerVersion) {
 	vc.SafeSession.GetOrCreateOptions().PlannerVersion = v
@@ -1116,6 +1122,16 @@ func (vc *VCursorImpl) SetPriority(priority string) {
 	}
 }
 
+// SetResultCacheTTL sets how long the result of the query may be cached by vtgate.
+func (vc *VCursorImpl) SetResultCacheTTL(ttl time.Duration) {
+	vc.resultCacheTTL = ttl
+}
+
+// ResultCacheTTL returns how long the result of the query may be cached by vtgate.
+func (vc *VCursorImpl) ResultCacheTTL() time.Duration {
+	return vc.resultCacheTTL
+}
+
 func (vc *VCursorImpl) SetExecQueryTimeout(timeout *int) {
 	// Determine the effective timeout: use passed timeout if non-nil, otherwise use session's query timeout if available
 	var execTimeout *int
@@ -1223,6 +1239,12 @@ func (vc *VCursorImpl) SetSessionTrackGTIDs(enable bool) {
 	vc.SafeSession.SetSessionTrackGtids(enable)
 }
 
+// SetReadAfterWriteAuto implements the SessionActions interface
+func (vc *VCursorImpl) SetReadAfterWriteAuto(_ context.Context, enable bool) error {
+	vc.SafeSession.SetReadAfterWriteAuto(enable)
+	return nil
+}
+
 // HasCreatedTempTable implements the SessionActions interface
 func (vc *VCursorImpl) HasCreatedTempTable() {
 	vc.SafeSession.GetOrCreateOptions().HasCreatedTempTables = true
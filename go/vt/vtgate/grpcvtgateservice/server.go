@@ -130,6 +130,16 @@ func withCallerIDContext(ctx context.Context, effectiveCallerID *vtrpcpb.CallerI
 	if immediate == "" {
 		immediate = unsecureClient
 	}
+
+	// Like the MySQL connector, the effective caller's component is the
+	// address of the client, which is the original client address when the
+	// gRPC listener accepts the PROXY protocol.
+	if effectiveCallerID != nil && effectiveCallerID.Component == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			effectiveCallerID = effectiveCallerID.CloneVT()
+			effectiveCallerID.Component = p.Addr.String()
+		}
+	}
 	return callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		effectiveCallerID,
 		&querypb.VTGateCallerID{Username: immediate, Groups: securityGroups})
@@ -57,6 +57,7 @@ type LogStats struct {
 	MirrorSourceExecuteTime time.Duration
 	MirrorTargetExecuteTime time.Duration
 	MirrorTargetError       error
+	QueryAttributes         map[string]string // QueryAttributes are the query attributes sent by the MySQL client
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -196,6 +197,10 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.Duration(stats.MirrorTargetExecuteTime)
 	log.Key("MirrorTargetError")
 	log.String(stats.MirrorTargetErrorStr())
+	if stats.Config.QueryAttributes {
+		log.Key("QueryAttributes")
+		log.StringMap(stats.QueryAttributes)
+	}
 
 	return log.Flush(w)
 }
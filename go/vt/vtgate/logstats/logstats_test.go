@@ -165,6 +165,35 @@ func TestLogStatsFilter(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestLogStatsQueryAttributes(t *testing.T) {
+	logStats := NewLogStats(context.Background(), "test", "sql1", "", nil, streamlog.NewQueryLogConfigForTest())
+	logStats.StartTime = time.Date(2017, time.January, 1, 1, 2, 3, 0, time.UTC)
+	logStats.EndTime = time.Date(2017, time.January, 1, 1, 2, 4, 1234, time.UTC)
+	logStats.QueryAttributes = map[string]string{"workload_name": "checkout", "priority": "20"}
+
+	// The query attributes are only logged when enabled.
+	got := testFormat(t, logStats, nil)
+	assert.True(t, strings.HasSuffix(got, "\t0.000000\t0.000000\t\"\"\n"), got)
+	assert.NotContains(t, got, "checkout")
+
+	logStats.Config.QueryAttributes = true
+	got = testFormat(t, logStats, nil)
+	assert.True(t, strings.HasSuffix(got, "\t\"\"\t{\"priority\":\"20\",\"workload_name\":\"checkout\"}\n"), got)
+
+	logStats.QueryAttributes = nil
+	got = testFormat(t, logStats, nil)
+	assert.True(t, strings.HasSuffix(got, "\t\"\"\t{}\n"), got)
+
+	logStats.QueryAttributes = map[string]string{"workload_name": "checkout", "priority": "20"}
+	logStats.Config.Format = streamlog.QueryLogFormatJSON
+	got = testFormat(t, logStats, nil)
+	assert.Contains(t, got, "\"QueryAttributes\": {\"priority\":\"20\",\"workload_name\":\"checkout\"}")
+
+	logStats.Config.QueryAttributes = false
+	got = testFormat(t, logStats, nil)
+	assert.NotContains(t, got, "QueryAttributes")
+}
+
 func TestLogStatsRowThreshold(t *testing.T) {
 	logStats := NewLogStats(context.Background(), "test", "sql1 /* LOG_THIS_QUERY */", "",
 		map[string]*querypb.BindVariable{"intVal": sqltypes.Int64BindVariable(1)}, streamlog.NewQueryLogConfigForTest())
@@ -168,7 +168,12 @@ func (e *Executor) newExecute(
 		}
 
 		// 5: Execute the plan.
-		if plan.Instructions.NeedsTransaction() {
+		if e.needsConsistentSnapshot(safeSession, plan) {
+			err = e.insideConsistentSnapshot(ctx, safeSession, plan, vcursor, bindVars,
+				func() error {
+					return execPlan(ctx, plan, vcursor, bindVars, execStart)
+				})
+		} else if plan.Instructions.NeedsTransaction() {
 			err = e.insideTransaction(ctx, safeSession, logStats,
 				func() error {
 					return execPlan(ctx, plan, vcursor, bindVars, execStart)
@@ -311,7 +316,7 @@ func (e *Executor) insideTransaction(ctx context.Context, safeSession *econtext.
 
 	if mustCommit {
 		commitStart := time.Now()
-		if err := e.txConn.Commit(ctx, safeSession); err != nil {
+		if err := e.Commit(ctx, safeSession); err != nil {
 			return err
 		}
 		logStats.CommitTime = time.Since(commitStart)
@@ -329,12 +334,37 @@ func (e *Executor) executePlan(
 	execStart time.Time,
 ) (*sqltypes.Result, error) {
 
+	resultCacheKey, cacheResult := e.resultCacheKey(ctx, safeSession, plan, vcursor, bindVars)
+	var resultCacheGeneration uint64
+	if cacheResult {
+		if qr, ok := e.resultCache.Get(resultCacheKey); ok {
+			e.setLogStats(logStats, plan, vcursor, execStart, nil, qr)
+			return qr, nil
+		}
+		resultCacheGeneration = e.resultCache.Generation()
+	}
+
 	// 4: Execute!
 	qr, err := vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
 
 	// 5: Log and add statistics
 	e.setLogStats(logStats, plan, vcursor, execStart, err, qr)
 
+	if e.resultCache != nil {
+		switch {
+		case cacheResult && err == nil:
+			e.resultCache.Set(resultCacheKey, qr, plan.TablesUsed, vcursor.ResultCacheTTL(), resultCacheGeneration)
+		case plan.Type != sqlparser.StmtSelect && safeSession.InTransaction():
+			// Until the transaction commits, other sessions can still read,
+			// and cache, the previous data.
+			safeSession.StoreResultCacheTables(plan.TablesUsed)
+		case plan.Type != sqlparser.StmtSelect:
+			// Writes made through this vtgate are invalidated right away,
+			// even when they partially failed.
+			e.resultCache.InvalidateTables(plan.TablesUsed...)
+		}
+	}
+
 	// Check if there was partial DML execution. If so, rollback the effect of the partially executed query.
 	if err != nil {
 		return nil, e.rollbackExecIfNeeded(ctx, safeSession, bindVars, logStats, err)
@@ -18,12 +18,14 @@ package planbuilder
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/sysvars"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -45,6 +47,8 @@ func transformToPrimitive(ctx *plancontext.PlanningContext, op operators.Operato
 		return transformUnionPlan(ctx, op)
 	case *operators.Vindex:
 		return transformVindexPlan(ctx, op)
+	case *operators.JSONTable:
+		return transformJSONTable(ctx, op)
 	case *operators.SubQuery:
 		return transformSubQuery(ctx, op)
 	case *operators.Filter:
@@ -61,6 +65,8 @@ func transformToPrimitive(ctx *plancontext.PlanningContext, op operators.Operato
 		return transformAggregator(ctx, op)
 	case *operators.Distinct:
 		return transformDistinct(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
 	case *operators.FkCascade:
 		return transformFkCascade(ctx, op)
 	case *operators.FkVerify:
@@ -257,6 +263,20 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 		return nil, err
 	}
 
+	if op.FilterWithOffsets != nil {
+		// the filter is evaluated on the vtgate, running the subquery for the values of the outer rows
+		return &engine.CorrelatedSubquery{
+			Opcode:         op.FilterType,
+			SubqueryResult: op.SubqueryValueName,
+			HasValues:      op.HasValuesName,
+			Vars:           op.Vars,
+			Predicate:      op.FilterWithOffsets,
+			ASTPredicate:   op.FilterPredicate,
+			Outer:          outer,
+			Subquery:       inner,
+		}, nil
+	}
+
 	cols, err := op.GetJoinColumns(ctx, op.Outer)
 	if err != nil {
 		return nil, err
@@ -310,9 +330,6 @@ func transformFkVerify(ctx *plancontext.PlanningContext, fkv *operators.FkVerify
 }
 
 func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggregator) (engine.Primitive, error) {
-	if op.WithRollup {
-		return nil, vterrors.VT12001("GROUP BY WITH ROLLUP not supported for sharded queries")
-	}
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
 		return nil, err
@@ -329,6 +346,11 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 			message := fmt.Sprintf("Aggregate UDF '%s' must be pushed down to MySQL", sqlparser.String(aggr.Original.Expr))
 			return nil, vterrors.VT12001(message)
 		}
+		if op.WithRollup && len(aggr.DistinctCols) == 0 && (aggr.Distinct || aggr.OpCode.IsDistinct() || aggr.OriginalOpCode.IsDistinct()) {
+			// the distinct values are only sorted inside each group, so we can't count them over the super-aggregate rows.
+			// this is not a problem when the distinct values are tracked using hashing
+			return nil, vterrors.VT12001(fmt.Sprintf("DISTINCT aggregation with ROLLUP in scatter query: '%s'", sqlparser.String(aggr.Original)))
+		}
 
 		aggrParam := engine.NewAggregateParam(aggr.OpCode, aggr.ColOffset, aggr.Alias, ctx.VSchema.Environment().CollationEnv())
 		aggrParam.Func = aggr.Func
@@ -339,6 +361,16 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		aggrParam.DistinctCols = aggr.DistinctCols
+		aggrParam.ConcatCols = aggr.ConcatCols
+		aggrParam.OrderBy = aggr.OrderBy
+		aggrParam.StatisticsCols = aggr.StatisticsCols
+		if aggr.OpCode == opcode.AggregateGrouping {
+			aggrParam.GroupingKeys, err = groupingKeys(ctx, op, aggr.Original.Expr.(*sqlparser.FuncExpr))
+			if err != nil {
+				return nil, err
+			}
+		}
 		aggregates = append(aggregates, aggrParam)
 	}
 
@@ -366,9 +398,28 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		GroupByKeys:         groupByKeys,
 		TruncateColumnCount: op.ResultColumns,
 		Input:               src,
+		WithRollup:          op.WithRollup,
 	}, nil
 }
 
+// groupingKeys returns the offsets in the grouping of the arguments to a GROUPING() call
+func groupingKeys(ctx *plancontext.PlanningContext, op *operators.Aggregator, fnc *sqlparser.FuncExpr) ([]int, error) {
+	if !op.WithRollup {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "The GROUPING function can only be used with GROUP BY WITH ROLLUP")
+	}
+	keys := make([]int, 0, len(fnc.Exprs))
+	for idx, arg := range fnc.Exprs {
+		key := slices.IndexFunc(op.Grouping, func(gb operators.GroupBy) bool {
+			return ctx.SemTable.EqualsExprWithDeps(gb.Inner, arg)
+		})
+		if key < 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Argument #%d of GROUPING function is not in GROUP BY", idx+1)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct) (engine.Primitive, error) {
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -411,6 +462,152 @@ func createMemorySort(ctx *plancontext.PlanningContext, src engine.Primitive, or
 	return prim, nil
 }
 
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (engine.Primitive, error) {
+	src, err := transformToPrimitive(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	prim := &engine.Window{Input: src}
+	for idx, expr := range op.PartitionBy {
+		typ, _ := ctx.TypeForExpr(expr)
+		prim.PartitionBy = append(prim.PartitionBy, evalengine.OrderByParams{
+			Col:             op.PartitionOffsets[idx],
+			WeightStringCol: op.PartitionWOffsets[idx],
+			Type:            typ,
+			CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	for idx, order := range op.OrderBy {
+		typ, _ := ctx.TypeForExpr(order.SimplifiedExpr)
+		prim.OrderBy = append(prim.OrderBy, evalengine.OrderByParams{
+			Col:             op.OrderOffsets[idx],
+			WeightStringCol: op.OrderWOffsets[idx],
+			Desc:            order.Inner.Direction == sqlparser.DescOrder,
+			Type:            typ,
+			CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+
+	for _, fn := range op.Functions {
+		windowFunc, err := createWindowFunc(ctx, fn)
+		if err != nil {
+			return nil, err
+		}
+		prim.Functions = append(prim.Functions, windowFunc)
+	}
+	return prim, nil
+}
+
+func createWindowFunc(ctx *plancontext.PlanningContext, fn *operators.WindowFunc) (*engine.WindowFunc, error) {
+	cfg := &evalengine.Config{
+		Collation:   ctx.VSchema.ConnCollation(),
+		Environment: ctx.VSchema.Environment(),
+	}
+	typ, _ := ctx.TypeForExpr(fn.Original)
+	windowFunc := &engine.WindowFunc{
+		Col:          fn.ArgOffset,
+		DefaultCol:   fn.DefaultOffset,
+		Type:         typ,
+		Alias:        sqlparser.String(fn.Original),
+		CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+	}
+
+	var n sqlparser.Expr
+	switch node := fn.Original.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch node.Type {
+		case sqlparser.RowNumberExprType:
+			windowFunc.Opcode = opcode.WindowRowNumber
+		case sqlparser.RankExprType:
+			windowFunc.Opcode = opcode.WindowRank
+		case sqlparser.DenseRankExprType:
+			windowFunc.Opcode = opcode.WindowDenseRank
+		case sqlparser.PercentRankExprType:
+			windowFunc.Opcode = opcode.WindowPercentRank
+		case sqlparser.CumeDistExprType:
+			windowFunc.Opcode = opcode.WindowCumeDist
+		}
+	case *sqlparser.NtileExpr:
+		windowFunc.Opcode = opcode.WindowNtile
+		n = node.N
+	case *sqlparser.LagLeadExpr:
+		windowFunc.Opcode = opcode.WindowLag
+		if node.Type == sqlparser.LeadExprType {
+			windowFunc.Opcode = opcode.WindowLead
+		}
+		n = node.N
+	case *sqlparser.FirstOrLastValueExpr:
+		windowFunc.Opcode = opcode.WindowFirstValue
+		if node.Type == sqlparser.LastValueExprType {
+			windowFunc.Opcode = opcode.WindowLastValue
+		}
+	case *sqlparser.NTHValueExpr:
+		windowFunc.Opcode = opcode.WindowNthValue
+		n = node.N
+	case *sqlparser.CountStar:
+		windowFunc.Opcode = opcode.WindowAggregate
+		windowFunc.Aggregate = opcode.AggregateCountStar
+	case *sqlparser.Count:
+		if node.Distinct || len(node.Args) != 1 {
+			return nil, vterrors.VT12001(fmt.Sprintf("window function '%s' on a sharded keyspace", sqlparser.String(node)))
+		}
+		windowFunc.Opcode = opcode.WindowAggregate
+		windowFunc.Aggregate = opcode.AggregateCount
+	case *sqlparser.Sum, *sqlparser.Min, *sqlparser.Max:
+		windowFunc.Opcode = opcode.WindowAggregate
+		windowFunc.Aggregate = opcode.SupportedAggregates[node.(sqlparser.AggrFunc).AggrName()]
+		if node.(sqlparser.DistinctableAggr).IsDistinct() {
+			return nil, vterrors.VT12001(fmt.Sprintf("window function '%s' on a sharded keyspace", sqlparser.String(node)))
+		}
+	default:
+		return nil, vterrors.VT12001(fmt.Sprintf("window function '%s' on a sharded keyspace", sqlparser.String(node)))
+	}
+
+	if n != nil {
+		expr, err := evalengine.Translate(n, cfg)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "unexpected expression in window function")
+		}
+		windowFunc.N = expr
+	}
+
+	frame := sqlparser.GetOverClause(fn.Original).WindowSpec.FrameClause
+	if frame == nil {
+		return windowFunc, nil
+	}
+	start, err := createWindowFramePoint(frame.Start, cfg)
+	if err != nil {
+		return nil, err
+	}
+	end := engine.WindowFramePoint{Type: sqlparser.CurrentRowType}
+	if frame.End != nil {
+		end, err = createWindowFramePoint(frame.End, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	windowFunc.Frame = &engine.WindowFrame{
+		Unit:  frame.Unit,
+		Start: start,
+		End:   end,
+	}
+	return windowFunc, nil
+}
+
+func createWindowFramePoint(point *sqlparser.FramePoint, cfg *evalengine.Config) (engine.WindowFramePoint, error) {
+	wfp := engine.WindowFramePoint{Type: point.Type}
+	if point.Expr == nil {
+		return wfp, nil
+	}
+	offset, err := evalengine.Translate(point.Expr, cfg)
+	if err != nil {
+		return wfp, vterrors.Wrap(err, "unexpected expression in window frame")
+	}
+	wfp.Offset = offset
+	return wfp, nil
+}
+
 func transformProjection(ctx *plancontext.PlanningContext, op *operators.Projection) (engine.Primitive, error) {
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -702,7 +899,11 @@ func autoIncGenerate(gen *operators.Generate) *engine.Generate {
 
 func generateInsertShardedQuery(ins *sqlparser.Insert) (prefix string, mids sqlparser.Values, suffix sqlparser.OnDup) {
 	mids, isValues := ins.Rows.(sqlparser.Values)
-	prefixFormat := "insert %v%sinto %v%v "
+	action := sqlparser.InsertStr
+	if ins.Action == sqlparser.ReplaceAct {
+		action = sqlparser.ReplaceStr
+	}
+	prefixFormat := action + " %v%sinto %v%v "
 	if isValues {
 		// the mid values are filled differently
 		// with select uses sqlparser.String for sqlparser.Values
@@ -754,18 +955,96 @@ func buildUpdatePrimitive(
 			return nil, vterrors.VT12001("Vindex update should have ORDER BY clause when using LIMIT")
 		}
 	}
+	var rowMigration *engine.RowMigration
+	if upd.MigrateRows {
+		if stmt.Limit != nil && len(stmt.OrderBy) == 0 {
+			return nil, vterrors.VT12001("Vindex update should have ORDER BY clause when using LIMIT")
+		}
+		rowMigration = buildRowMigration(stmt, upd.Target.VTable)
+		vindexes = upd.Target.VTable.ColumnVindexes
+	}
 	if upd.VerifyAll {
 		stmt.SetComments(stmt.GetParsedComments().SetMySQLSetVarValue(sysvars.ForeignKeyChecks, "OFF"))
 	}
 	_ = updateSelectedVindexPredicate(rb.Routing)
 	edml := createDMLPrimitive(ctx, rb, hints, upd.Target.VTable, generateQuery(stmt), vindexes, vQuery)
+	if rowMigration != nil && stmt.Limit != nil && !edml.Opcode.IsSingleShard() {
+		// the limit would apply to every shard the rows are moved from
+		return nil, vterrors.VT12001("LIMIT on an UPDATE of primary vindex columns across shards")
+	}
 
 	return &engine.Update{
 		DML:                 edml,
 		ChangedVindexValues: upd.ChangedVindexValues,
+		RowMigration:        rowMigration,
 	}, nil
 }
 
+// buildRowMigration creates the queries used to move the updated rows to the shards
+// of their new primary vindex values: a select of the full rows along with their new
+// column values, and a delete of the rows from the shards they live on now.
+// The rows are selected with the authoritative column list of the table rather than `*`,
+// which leaves the invisible columns out; the generated columns are left out as they
+// cannot be inserted, MySQL computes them again on the new shards.
+// Like MySQL, the assignments are evaluated from left to right, so the columns that
+// were assigned before are replaced by their new values in the following ones.
+func buildRowMigration(stmt *sqlparser.Update, vTbl *vindexes.BaseTable) *engine.RowMigration {
+	tblExpr := stmt.TableExprs[0].(*sqlparser.AliasedTableExpr)
+	tblName, err := tblExpr.TableName()
+	if err != nil {
+		panic(err)
+	}
+	qualifier := tblName
+	if !tblExpr.As.IsEmpty() {
+		qualifier = sqlparser.NewTableName(tblExpr.As.String())
+	}
+	sel := &sqlparser.Select{
+		From:    stmt.GetFrom(),
+		Where:   stmt.Where,
+		OrderBy: stmt.OrderBy,
+		Limit:   stmt.Limit,
+		Lock:    sqlparser.ForUpdateLock,
+	}
+	for _, col := range vTbl.Columns {
+		if col.Generated {
+			continue
+		}
+		sel.AddSelectExpr(&sqlparser.AliasedExpr{Expr: sqlparser.NewColNameWithQualifier(col.Name.String(), qualifier)})
+	}
+	var updatedColumns []string
+	assigned := make(map[string]sqlparser.Expr)
+	for _, updExpr := range stmt.Exprs {
+		expr := sqlparser.CopyOnRewrite(updExpr.Expr, func(node, _ sqlparser.SQLNode) bool {
+			// the columns of subqueries belong to their own tables
+			_, isSubquery := node.(*sqlparser.Subquery)
+			return !isSubquery
+		}, func(cursor *sqlparser.CopyOnWriteCursor) {
+			col, ok := cursor.Node().(*sqlparser.ColName)
+			if !ok || !(col.Qualifier.IsEmpty() || col.Qualifier.Name == tblName.Name || col.Qualifier.Name.String() == tblExpr.As.String()) {
+				return
+			}
+			if newValue, ok := assigned[col.Name.Lowered()]; ok {
+				cursor.Replace(sqlparser.Clone(newValue))
+			}
+		}, nil).(sqlparser.Expr)
+		assigned[updExpr.Name.Name.Lowered()] = expr
+		sel.AddSelectExpr(&sqlparser.AliasedExpr{Expr: expr})
+		updatedColumns = append(updatedColumns, updExpr.Name.Name.String())
+	}
+	del := &sqlparser.Delete{
+		Comments:   stmt.Comments,
+		TableExprs: stmt.GetFrom(),
+		Where:      stmt.Where,
+		OrderBy:    stmt.OrderBy,
+		Limit:      stmt.Limit,
+	}
+	return &engine.RowMigration{
+		Query:          generateQuery(sel),
+		UpdatedColumns: updatedColumns,
+		DeleteQuery:    generateQuery(del),
+	}
+}
+
 func buildDeletePrimitive(ctx *plancontext.PlanningContext, rb *operators.Route, dmlOp operators.Operator, stmt *sqlparser.Delete, hints *queryHints) (engine.Primitive, error) {
 	del := dmlOp.(*operators.Delete)
 
@@ -1005,6 +1284,30 @@ func transformVindexPlan(ctx *plancontext.PlanningContext, op *operators.Vindex)
 	return prim, nil
 }
 
+func transformJSONTable(ctx *plancontext.PlanningContext, op *operators.JSONTable) (engine.Primitive, error) {
+	table, err := evalengine.TranslateJSONTable(op.TableExpr(), &evalengine.Config{
+		Collation:   ctx.SemTable.Collation,
+		ResolveType: ctx.TypeForExpr,
+		Environment: ctx.VSchema.Environment(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prim := &engine.JSONTable{Table: table}
+	for _, col := range op.Columns {
+		idx := slices.IndexFunc(table.Columns, func(c *evalengine.JSONTableColumn) bool {
+			return strings.EqualFold(c.Name, col.Name.String())
+		})
+		if idx < 0 {
+			return nil, vterrors.VT03019(sqlparser.String(col))
+		}
+		prim.Cols = append(prim.Cols, idx)
+		prim.Fields = append(prim.Fields, table.Columns[idx].Type.ToField(col.Name.String()))
+	}
+	return prim, nil
+}
+
 func transformRecurseCTE(ctx *plancontext.PlanningContext, op *operators.RecurseCTE) (engine.Primitive, error) {
 	seed, err := transformToPrimitive(ctx, op.Seed())
 	if err != nil {
@@ -289,7 +289,13 @@ func (qb *queryBuilder) joinWith(other *queryBuilder, onCondition sqlparser.Expr
 		}
 	}
 
-	qb.mergeWhereClauses(stmt, otherStmt)
+	if joinType.IsInner() {
+		qb.mergeWhereClauses(stmt, otherStmt)
+	} else if otherPredicate := otherStmt.GetWherePredicate(); otherPredicate != nil {
+		// predicates on the inner side of an outer join filter the rows before they are joined,
+		// so they have to stay in the ON condition
+		onCondition = qb.ctx.SemTable.AndExpressions(onCondition, otherPredicate)
+	}
 
 	var newFromClause []sqlparser.TableExpr
 	switch joinType {
@@ -333,6 +339,11 @@ func buildJoin(stmt FromStatement, otherStmt FromStatement, onCondition sqlparse
 		rhs = &sqlparser.ParenTableExpr{Exprs: otherFromClause}
 	}
 
+	if onCondition == nil && joinType == sqlparser.LeftJoinType {
+		// outer joins need an ON condition, even when there is nothing to compare
+		onCondition = sqlparser.BoolVal(true)
+	}
+
 	return &sqlparser.JoinTableExpr{
 		LeftExpr:  lhs,
 		RightExpr: rhs,
@@ -349,6 +360,10 @@ func (qb *queryBuilder) sortTables() {
 		if !isSel {
 			return true, nil
 		}
+		if slices.ContainsFunc(sel.From, dependsOnPrecedingTables) {
+			// lateral derived tables and JSON_TABLE have to stay after the tables they use
+			return true, nil
+		}
 		ts := &tableSorter{
 			sel: sel,
 			tbl: qb.ctx.SemTable,
@@ -359,6 +374,22 @@ func (qb *queryBuilder) sortTables() {
 
 }
 
+func dependsOnPrecedingTables(expr sqlparser.TableExpr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.JSONTableExpr:
+		return true
+	case *sqlparser.AliasedTableExpr:
+		dt, ok := expr.Expr.(*sqlparser.DerivedTable)
+		return ok && dt.Lateral
+	case *sqlparser.JoinTableExpr:
+		return dependsOnPrecedingTables(expr.LeftExpr) || dependsOnPrecedingTables(expr.RightExpr)
+	case *sqlparser.ParenTableExpr:
+		return slices.ContainsFunc(expr.Exprs, dependsOnPrecedingTables)
+	default:
+		return false
+	}
+}
+
 type tableSorter struct {
 	sel *sqlparser.Select
 	tbl *semantics.SemTable
@@ -470,6 +501,8 @@ func buildQuery(op Operator, qb *queryBuilder) {
 		buildDML(op, qb)
 	case *RecurseCTE:
 		buildRecursiveCTE(op, qb)
+	case *JSONTable:
+		buildJSONTable(op, qb)
 	default:
 		panic(vterrors.VT13001(fmt.Sprintf("unknown operator to convert to SQL: %T", op)))
 	}
@@ -579,6 +612,17 @@ func buildTable(op *Table, qb *queryBuilder) {
 	}
 }
 
+func buildJSONTable(op *JSONTable, qb *queryBuilder) {
+	if qb.stmt == nil {
+		qb.stmt = &sqlparser.Select{}
+	}
+	stmt := qb.stmt.(FromStatement)
+	stmt.SetFrom(append(stmt.GetFrom(), op.TableExpr()))
+	for _, name := range op.Columns {
+		qb.addProjection(&sqlparser.AliasedExpr{Expr: name})
+	}
+}
+
 func buildProjection(op *Projection, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 
@@ -703,8 +747,14 @@ func buildDerivedSelect(op *Horizon, qb *queryBuilder, sel *sqlparser.Select) {
 	sel.Having = mergeHaving(sel.Having, opQuery.Having)
 	sel.SelectExprs = opQuery.SelectExprs
 	sel.Distinct = opQuery.Distinct
+	if op.Lateral {
+		// the tables the lateral derived table depends on are in the same query,
+		// so we can use the columns directly instead of the arguments
+		sel = restoreLateralReferences(sel, op.LateralVars).(*sqlparser.Select)
+	}
 	qb.addTableExpr(op.Alias, op.Alias, TableID(op), &sqlparser.DerivedTable{
-		Select: sel,
+		Lateral: op.Lateral,
+		Select:  sel,
 	}, nil, op.ColumnAliases)
 	for _, col := range op.Columns {
 		qb.addProjection(&sqlparser.AliasedExpr{Expr: col})
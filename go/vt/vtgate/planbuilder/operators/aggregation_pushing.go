@@ -27,20 +27,14 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 )
 
-func errDistinctAggrWithMultiExpr(f sqlparser.AggrFunc) {
-	if f == nil {
-		panic(vterrors.VT12001("distinct aggregation function with multiple expressions"))
-	}
-	panic(vterrors.VT12001(fmt.Sprintf("distinct aggregation function with multiple expressions '%s'", sqlparser.String(f))))
-}
-
 func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator) (output Operator, applyResult *ApplyResult) {
 	if aggregator.Pushed {
 		return aggregator, NoRewrite
 	}
 
-	// this rewrite is always valid, and we should do it whenever possible
-	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || overlappingUniqueVindex(ctx, aggregator.Grouping)) {
+	// this rewrite is always valid, and we should do it whenever possible.
+	// a rollup also aggregates over fewer grouping columns, so it can only be pushed to a single shard
+	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || (!aggregator.WithRollup && overlappingUniqueVindex(ctx, aggregator.Grouping))) {
 		return Swap(aggregator, route, "push down aggregation under route - remove original")
 	}
 
@@ -51,8 +45,15 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 
 	// if we have not yet been able to push this aggregation down,
 	// we need to turn AVG into SUM/COUNT to support this over a sharded keyspace
-	if needAvgBreaking(aggregator.Aggregations) {
-		return splitAvgAggregations(ctx, aggregator)
+	if needAggrBreaking(aggregator.Aggregations) {
+		return splitAggregations(ctx, aggregator)
+	}
+
+	if slices.ContainsFunc(aggregator.Aggregations, Aggr.needsAllRows) {
+		// the aggregator can't be split, so it stays on the vtgate and aggregates all the rows from its source.
+		// the input is not sorted by any distinct expression, so the distinct values are tracked using hashing
+		aggregator.HashDistinct = true
+		return aggregator, NoRewrite
 	}
 
 	switch src := aggregator.Source.(type) {
@@ -109,6 +110,7 @@ func pushAggregationThroughSubquery(
 			pushedAggr.addColumnWithoutPushing(ctx, aeWrap(colName), true)
 		}
 	}
+	pushStatisticsAggregations(ctx, rootAggr, pushedAggr)
 
 	src.Outer = pushedAggr
 
@@ -169,11 +171,19 @@ func pushAggregationThroughRoute(
 
 // pushAggregations splits aggregations between the original aggregator and the one we are pushing down
 func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator, aggrBelowRoute *Aggregator) {
-	canPushDistinctAggr, distinctExprs := checkIfWeCanPush(ctx, aggregator)
+	canPushDistinctAggr, distinctExpr := checkIfWeCanPush(ctx, aggregator)
 
-	distinctAggrGroupByAdded := false
+	var distinctGroupBy []sqlparser.Expr
 
 	for i, aggr := range aggregator.Aggregations {
+		if aggr.OpCode == opcode.AggregateGrouping {
+			// the shards don't produce any super-aggregate rows, so we only need a placeholder column
+			pushed := NewAggr(opcode.AggregateAnyValue, nil, aeWrap(aggr.getPushColumn()), "")
+			pushed.ColOffset = aggr.ColOffset
+			aggrBelowRoute.Columns[aggr.ColOffset] = pushed.Original
+			aggrBelowRoute.Aggregations = append(aggrBelowRoute.Aggregations, pushed)
+			continue
+		}
 		if !aggr.Distinct || canPushDistinctAggr {
 			aggrBelowRoute.Aggregations = append(aggrBelowRoute.Aggregations, aggr)
 			aggregateTheAggregate(aggregator, i)
@@ -181,35 +191,93 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 			continue
 		}
 
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(aggr.Func)
-		}
-
 		// We handle a distinct aggregation by turning it into a group by and
-		// doing the aggregating on the vtgate level instead
-		aeDistinctExpr := aeWrap(distinctExprs[0])
-		aggrBelowRoute.Columns[aggr.ColOffset] = aeDistinctExpr
+		// doing the aggregating on the vtgate level instead.
+		// Any other arguments of the aggregation are added to the group by during offset planning
+		arg := aggr.Func.GetArg()
+		aggrBelowRoute.Columns[aggr.ColOffset] = aeWrap(arg)
 
-		// We handle a distinct aggregation by turning it into a group by and
-		// doing the aggregating on the vtgate level instead
 		// Adding to group by can be done only once even though there are multiple distinct aggregation with same expression.
-		if !distinctAggrGroupByAdded {
-			groupBy := NewGroupBy(distinctExprs[0])
-			groupBy.ColOffset = aggr.ColOffset
-			aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
-			distinctAggrGroupByAdded = true
+		if slices.ContainsFunc(distinctGroupBy, func(expr sqlparser.Expr) bool { return ctx.SemTable.EqualsExpr(expr, arg) }) {
+			continue
 		}
+		groupBy := NewGroupBy(arg)
+		groupBy.ColOffset = aggr.ColOffset
+		aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
+		distinctGroupBy = append(distinctGroupBy, arg)
 	}
 
 	if !canPushDistinctAggr {
-		aggregator.DistinctExpr = distinctExprs[0]
+		aggregator.DistinctExpr = distinctExpr
+		aggregator.HashDistinct = distinctExpr == nil
 	}
+
+	pushStatisticsAggregations(ctx, aggregator, aggrBelowRoute)
 }
 
-func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, []sqlparser.Expr) {
+// pushStatisticsAggregations changes the VARIANCE and STDDEV aggregations that are pushed down, so the aggregator below
+// returns the population variance of every group together with the count and the average of its values.
+// The aggregator above merges these, which keeps the precision that the sum of squares would lose for large values.
+func pushStatisticsAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator, aggrBelow *Aggregator) {
+	for i, aggr := range aggregator.Aggregations {
+		if !isStatisticsAggr(aggr.OpCode) {
+			continue
+		}
+		below := slices.IndexFunc(aggrBelow.Aggregations, func(pushed Aggr) bool {
+			return pushed.ColOffset == aggr.ColOffset
+		})
+		if below < 0 {
+			panic(vterrors.VT13001("no aggregation pointing to this column was found"))
+		}
+
+		arg := aggrBelow.Aggregations[below].Func.GetArg()
+		varPop := &sqlparser.VarPop{Arg: arg}
+		pushed := NewAggr(opcode.AggregateVarPop, varPop, aeWrap(varPop), "")
+		pushed.ColOffset = aggr.ColOffset
+		aggrBelow.Columns[aggr.ColOffset] = pushed.Original
+		aggrBelow.Aggregations[below] = pushed
+
+		var cols []int
+		for _, expr := range []sqlparser.Expr{&sqlparser.Count{Args: []sqlparser.Expr{arg}}, &sqlparser.Avg{Arg: arg}} {
+			// several aggregations on the same values can share the count and average
+			offset := slices.IndexFunc(aggrBelow.Columns, func(col *sqlparser.AliasedExpr) bool {
+				return ctx.SemTable.EqualsExpr(col.Expr, expr)
+			})
+			if offset >= 0 {
+				cols = append(cols, offset)
+				continue
+			}
+			offset = aggrBelow.addColumnWithoutPushing(ctx, aeWrap(expr), false)
+			if offset == len(aggregator.Columns) {
+				// these columns are only used to merge the variances, so they are not part of the output
+				if aggregator.ResultColumns == 0 && aggregator.Truncate {
+					aggregator.ResultColumns = len(aggregator.Columns)
+				}
+				aggregator.Columns = append(aggregator.Columns, aeWrap(sqlparser.Clone(expr)))
+			}
+			cols = append(cols, offset)
+		}
+		aggregator.Aggregations[i].StatisticsCols = cols
+	}
+}
+
+func isStatisticsAggr(code opcode.AggregateOpcode) bool {
+	switch code {
+	case opcode.AggregateStdDevPop, opcode.AggregateStdDevSamp, opcode.AggregateVarPop, opcode.AggregateVarSamp:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkIfWeCanPush checks if the distinct aggregations can be pushed down, which is the case when all of them
+// use a column with a unique vindex. When they can't, and all distinct aggregations use the same single
+// expression, this expression is returned so the input of the aggregation can be sorted by it.
+// Otherwise, the distinct values have to be tracked using hashing.
+func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, sqlparser.Expr) {
 	canPush := true
+	sameExprs := true
 	var distinctExprs []sqlparser.Expr
-	var differentExpr *sqlparser.AliasedExpr
 
 	for _, aggr := range aggregator.Aggregations {
 		if !aggr.Distinct {
@@ -217,32 +285,24 @@ func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		}
 
 		args := aggr.Func.GetArgs()
-		hasUniqVindex := false
-		for _, arg := range args {
-			if exprHasUniqueVindex(ctx, arg) {
-				hasUniqVindex = true
-				break
-			}
-		}
+		hasUniqVindex := slices.ContainsFunc(args, func(arg sqlparser.Expr) bool {
+			return exprHasUniqueVindex(ctx, arg)
+		})
 		if !hasUniqVindex {
 			canPush = false
 		}
-		if len(distinctExprs) == 0 {
+		if distinctExprs == nil {
 			distinctExprs = args
+			continue
 		}
-		for idx, expr := range distinctExprs {
-			if !ctx.SemTable.EqualsExpr(expr, args[idx]) {
-				differentExpr = aggr.Original
-				break
-			}
-		}
+		sameExprs = sameExprs && slices.EqualFunc(distinctExprs, args, ctx.SemTable.EqualsExpr)
 	}
 
-	if !canPush && differentExpr != nil {
-		panic(vterrors.VT12001(fmt.Sprintf("only one DISTINCT aggregation is allowed in a SELECT: %s", sqlparser.String(differentExpr))))
+	if canPush || !sameExprs || len(distinctExprs) != 1 {
+		return canPush, nil
 	}
 
-	return canPush, distinctExprs
+	return false, distinctExprs[0]
 }
 
 func pushAggregationThroughFilter(
@@ -262,6 +322,7 @@ withNextColumn:
 		}
 		pushedAggr.addColumnWithoutPushing(ctx, aeWrap(col), true)
 	}
+	pushStatisticsAggregations(ctx, aggregator, pushedAggr)
 
 	// Set the source of the filter to the new aggregator placed below the route.
 	filter.Source = pushedAggr
@@ -373,6 +434,8 @@ func pushAggregationThroughApplyJoin(ctx *plancontext.PlanningContext, rootAggr
 	if err != nil {
 		// if we get this error, we just abort the splitting and fall back on simpler ways of solving the same query
 		if errors.Is(err, errAbortAggrPushing) {
+			// unless the rows from the join are sorted by the distinct expression, the distinct values are tracked using hashing
+			rootAggr.HashDistinct = rootAggr.DistinctExpr == nil
 			return nil, nil
 		}
 		panic(err)
@@ -408,6 +471,8 @@ func pushAggregationThroughHashJoin(ctx *plancontext.PlanningContext, rootAggr *
 	if err != nil {
 		// if we get this error, we just abort the splitting and fall back on simpler ways of solving the same query
 		if errors.Is(err, errAbortAggrPushing) {
+			// unless the rows from the join are sorted by the distinct expression, the distinct values are tracked using hashing
+			rootAggr.HashDistinct = rootAggr.DistinctExpr == nil
 			return nil, nil
 		}
 		panic(err)
@@ -542,15 +607,14 @@ func splitAggrColumnsToLeftAndRight(
 		outerJoin:   leftJoin,
 	}
 
-	canPushDistinctAggr, distinctExprs := checkIfWeCanPush(ctx, aggregator)
+	canPushDistinctAggr, distinctExpr := checkIfWeCanPush(ctx, aggregator)
 
 	// Distinctable aggregation cannot be pushed down in the join.
-	// We keep node of the distinct aggregation expression to be used later for ordering.
+	// We keep node of the distinct aggregation expression to be used later for ordering,
+	// or track the distinct values using hashing when there is no single such expression.
 	if !canPushDistinctAggr {
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(nil)
-		}
-		aggregator.DistinctExpr = distinctExprs[0]
+		aggregator.DistinctExpr = distinctExpr
+		aggregator.HashDistinct = distinctExpr == nil
 		return nil, errAbortAggrPushing
 	}
 
@@ -587,70 +651,78 @@ func initColReUse(size int) []int {
 
 func extractExpr(expr *sqlparser.AliasedExpr) sqlparser.Expr { return expr.Expr }
 
-func needAvgBreaking(aggrs []Aggr) bool {
+func needAggrBreaking(aggrs []Aggr) bool {
 	for _, aggr := range aggrs {
-		if aggr.OpCode == opcode.AggregateAvg {
+		if needsBreaking(aggr.OpCode) {
 			return true
 		}
 	}
 	return false
 }
 
-// splitAvgAggregations takes an aggregator that has AVG aggregations in it and splits
+func needsBreaking(code opcode.AggregateOpcode) bool {
+	switch code {
+	case opcode.AggregateAvg:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitAggregations takes an aggregator that has AVG aggregations in it and splits
 // these into sum/count expressions that can be spread out to shards
-func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (Operator, *ApplyResult) {
+func splitAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (Operator, *ApplyResult) {
 	proj := newAliasedProjection(aggr)
 
 	var columns []*sqlparser.AliasedExpr
 	var aggregations []Aggr
 
+	addAggregation := func(code opcode.AggregateOpcode, f sqlparser.AggrFunc) {
+		for _, col := range append(aggr.Columns, columns...) {
+			if ctx.SemTable.EqualsExpr(col.Expr, f) {
+				// several aggregations can share the same sum/count
+				return
+			}
+		}
+		ae := aeWrap(f)
+		newAggr := NewAggr(code, f, ae, sqlparser.String(f))
+		newAggr.ColOffset = len(aggr.Columns) + len(columns)
+		aggregations = append(aggregations, newAggr)
+		columns = append(columns, ae)
+	}
+
 	for offset, col := range aggr.Columns {
-		avg, ok := col.Expr.(*sqlparser.Avg)
-		if !ok {
+		aggrOffset := slices.IndexFunc(aggr.Aggregations, func(aggregation Aggr) bool {
+			return aggregation.ColOffset == offset
+		})
+		if aggrOffset < 0 || !needsBreaking(aggr.Aggregations[aggrOffset].OpCode) {
 			proj.addColumnWithoutPushing(ctx, col, false /* addToGroupBy */)
 			continue
 		}
 
-		if avg.Distinct {
+		if avg, ok := col.Expr.(*sqlparser.Avg); ok && avg.Distinct {
 			panic(vterrors.VT12001("AVG(distinct <>)"))
 		}
 
-		// We have an AVG that we need to split
-		sumExpr := &sqlparser.Sum{Arg: avg.Arg}
-		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}}
+		// We have an aggregation that we need to split. We'll change it to SUM, and then add a COUNT as well
+		arg := aggr.Aggregations[aggrOffset].Func.GetArg()
+		sumExpr := &sqlparser.Sum{Arg: arg}
+		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{arg}}
+		addAggregation(opcode.AggregateCount, countExpr)
+
 		calcExpr := &sqlparser.BinaryExpr{
 			Operator: sqlparser.DivOp,
 			Left:     sumExpr,
 			Right:    countExpr,
 		}
 
-		outputColumn := aeWrap(col.Expr)
-		outputColumn.As = sqlparser.NewIdentifierCI(col.ColumnName())
 		proj.addUnexploredExpr(sqlparser.Clone(col), calcExpr)
 		col.Expr = sumExpr
-		found := false
-		for aggrOffset, aggregation := range aggr.Aggregations {
-			if offset == aggregation.ColOffset {
-				// We have found the AVG column. We'll change it to SUM, and then we add a COUNT as well
-				aggr.Aggregations[aggrOffset].OpCode = opcode.AggregateSum
-
-				countExprAlias := aeWrap(countExpr)
-				countAggr := NewAggr(opcode.AggregateCount, countExpr, countExprAlias, sqlparser.String(countExpr))
-				countAggr.ColOffset = len(aggr.Columns) + len(columns)
-				aggregations = append(aggregations, countAggr)
-				columns = append(columns, countExprAlias)
-				found = true
-				break // no need to search the remaining aggregations
-			}
-		}
-		if !found {
-			// if we get here, it's because we didn't find the aggregation. Something is wrong
-			panic(vterrors.VT13001("no aggregation pointing to this column was found"))
-		}
+		aggr.Aggregations[aggrOffset].OpCode = opcode.AggregateSum
 	}
 
 	aggr.Columns = append(aggr.Columns, columns...)
 	aggr.Aggregations = append(aggr.Aggregations, aggregations...)
 
-	return proj, Rewrote("split avg aggregation")
+	return proj, Rewrote("split aggregation into sum/count")
 }
@@ -130,15 +130,21 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 		return nil
 	case opcode.AggregateCount, opcode.AggregateSum:
 		return ab.handleAggrWithCountStarMultiplier(ctx, aggr)
-	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue:
+	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue,
+		opcode.AggregateBitAnd, opcode.AggregateBitOr:
 		return ab.handlePushThroughAggregation(ctx, aggr)
+	case opcode.AggregateBitXor, opcode.AggregateJSONArrayAgg, opcode.AggregateJSONObjectAgg,
+		opcode.AggregateStdDevPop, opcode.AggregateStdDevSamp, opcode.AggregateVarPop, opcode.AggregateVarSamp:
+		// the result of these depends on how many times each value is seen,
+		// so they can't be split between the sides of the join
+		return errAbortAggrPushing
+	case opcode.AggregateGrouping:
+		// the value is calculated by the rollup above the join, so we only need a placeholder column
+		ab.proj.addUnexploredExpr(aggr.Original, aggr.getPushColumn())
+		return nil
 	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 {
-			panic(vterrors.VT12001("cannot evaluate group concat with distinct or order by"))
-		}
 		// this needs special handling, currently aborting the push of function
-		// and later will try pushing the column instead.
+		// and later will try pushing the columns instead.
 		// TODO: this should be handled better by pushing the function down.
 		return errAbortAggrPushing
 	case opcode.AggregateUnassigned:
@@ -19,12 +19,15 @@ package operators
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
@@ -41,11 +44,15 @@ type (
 		Grouping     []GroupBy
 		Aggregations []Aggr
 
-		// We support a single distinct aggregation per aggregator. It is stored here.
+		// When all distinct aggregations use the same expression, it is stored here.
 		// When planning the ordering that the OrderedAggregate will require,
 		// this needs to be the last ORDER BY expression
 		DistinctExpr sqlparser.Expr
 
+		// HashDistinct is set when the distinct aggregations use different or multiple expressions.
+		// The input can't be sorted by all of them, so the distinct values are tracked using hashing instead
+		HashDistinct bool
+
 		// Pushed will be set to true once this aggregation has been pushed deeper in the tree
 		Pushed        bool
 		offsetPlanned bool
@@ -93,7 +100,9 @@ func (a *Aggregator) addColumnWithoutPushing(ctx *plancontext.PlanningContext, e
 		case sqlparser.AggrFunc:
 			aggr = createAggrFromAggrFunc(e, expr)
 		case *sqlparser.FuncExpr:
-			if ctx.IsAggr(e) {
+			if sqlparser.IsGroupingFunc(e) {
+				aggr = NewAggr(opcode.AggregateGrouping, nil, expr, expr.As.String())
+			} else if ctx.IsAggr(e) {
 				aggr = NewAggr(opcode.AggregateUDF, nil, expr, expr.As.String())
 			} else {
 				aggr = NewAggr(opcode.AggregateAnyValue, nil, expr, expr.As.String())
@@ -397,9 +406,39 @@ func (a *Aggregator) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		}
 		a.Aggregations[idx].WSOffset = offset
 	}
+
+	a.planHashDistinctOffsets(ctx)
 	return nil
 }
 
+// planHashDistinctOffsets adds the arguments of the distinct aggregations that are not pushed down
+// when the distinct values are tracked using hashing, so the vtgate can check all of them
+func (a *Aggregator) planHashDistinctOffsets(ctx *plancontext.PlanningContext) {
+	if !a.HashDistinct {
+		return
+	}
+	for idx, aggr := range a.Aggregations {
+		if !aggr.Distinct || aggr.PushedDown {
+			continue
+		}
+		var cols []engine.CheckCol
+		for _, arg := range aggr.Func.GetArgs() {
+			typ, _ := ctx.TypeForExpr(arg)
+			col := engine.CheckCol{
+				Col:          a.internalAddColumn(ctx, aeWrap(arg), true),
+				Type:         typ,
+				CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+			}
+			if ctx.NeedsWeightString(arg) {
+				wsCol := a.internalAddColumn(ctx, aeWrap(weightStringFor(arg)), true)
+				col.WsCol = &wsCol
+			}
+			cols = append(cols, col)
+		}
+		a.Aggregations[idx].DistinctCols = cols
+	}
+}
+
 func (aggr Aggr) setPushColumn(exprs []sqlparser.Expr) {
 	if aggr.Func == nil {
 		if len(exprs) > 1 {
@@ -415,19 +454,35 @@ func (aggr Aggr) setPushColumn(exprs []sqlparser.Expr) {
 	}
 }
 
+// needsAllRows returns true for aggregations that can't be computed from partial aggregations,
+// and have to be evaluated on the vtgate using all the rows of a group
+func (aggr Aggr) needsAllRows() bool {
+	f, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+	return ok && (f.Distinct || len(f.OrderBy) > 0)
+}
+
 func (aggr Aggr) getPushColumn() sqlparser.Expr {
 	switch aggr.OpCode {
 	case opcode.AggregateAnyValue:
 		return aggr.Original.Expr
 	case opcode.AggregateCountStar:
 		return sqlparser.NewIntLiteral("1")
+	case opcode.AggregateGrouping:
+		// below the rollup there are no super-aggregate rows, so GROUPING() is always 0
+		return sqlparser.NewIntLiteral("0")
 	case opcode.AggregateGroupConcat:
-		if len(aggr.Func.GetArgs()) > 1 {
-			panic(vterrors.VT12001("group_concat with more than 1 column"))
-		}
+		// the other arguments are added when planning the offsets of the GROUP_CONCAT
 		return aggr.Func.GetArg()
+	case opcode.AggregateJSONArrayAgg:
+		// the vtgate merges arrays, so every value is wrapped in an array of its own
+		return &sqlparser.JSONArrayExpr{Params: aggr.Func.GetArgs()}
+	case opcode.AggregateJSONObjectAgg:
+		// the vtgate merges objects, so every key/value pair is wrapped in an object of its own
+		args := aggr.Func.GetArgs()
+		return &sqlparser.JSONObjectExpr{Params: []*sqlparser.JSONObjectParam{{Key: args[0], Value: args[1]}}}
 	default:
-		if len(aggr.Func.GetArgs()) > 1 {
+		// the other arguments of a distinct aggregation are added when planning the offsets of the hashed distinct values
+		if len(aggr.Func.GetArgs()) > 1 && !aggr.Distinct {
 			panic(vterrors.VT03001(sqlparser.String(aggr.Func)))
 		}
 		return aggr.Func.GetArg()
@@ -440,6 +495,8 @@ func (aggr Aggr) getPushColumnExprs() []sqlparser.Expr {
 		return []sqlparser.Expr{aggr.Original.Expr}
 	case opcode.AggregateCountStar:
 		return []sqlparser.Expr{sqlparser.NewIntLiteral("1")}
+	case opcode.AggregateGrouping:
+		return []sqlparser.Expr{sqlparser.NewIntLiteral("0")}
 	case opcode.AggregateUDF:
 		// AggregateUDFs can't be evaluated on the vtgate. So either we are able to push everything down, or we will have to fail the query.
 		return nil
@@ -465,6 +522,54 @@ func (a *Aggregator) planOffsetsNotPushed(ctx *plancontext.PlanningContext) {
 	}
 
 	a.pushRemainingGroupingColumnsAndWeightStrings(ctx)
+	a.planHashDistinctOffsets(ctx)
+	a.planGroupConcatOffsets(ctx)
+}
+
+// planGroupConcatOffsets adds the arguments and the ordering of the GROUP_CONCAT aggregations that are
+// evaluated on the vtgate, since only the first argument is added as the aggregation column
+func (a *Aggregator) planGroupConcatOffsets(ctx *plancontext.PlanningContext) {
+	for idx, aggr := range a.Aggregations {
+		f, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+		if !ok || aggr.PushedDown {
+			continue
+		}
+		var cols []int
+		for _, arg := range f.Exprs {
+			cols = append(cols, a.internalAddColumn(ctx, aeWrap(arg), true))
+		}
+		var orderBy evalengine.Comparison
+		for _, order := range f.OrderBy {
+			expr := groupConcatOrderExpr(f, order.Expr)
+			typ, _ := ctx.TypeForExpr(expr)
+			param := evalengine.OrderByParams{
+				Col:             a.internalAddColumn(ctx, aeWrap(expr), true),
+				WeightStringCol: -1,
+				Desc:            order.Direction == sqlparser.DescOrder,
+				Type:            typ,
+				CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+			}
+			if ctx.NeedsWeightString(expr) {
+				param.WeightStringCol = a.internalAddColumn(ctx, aeWrap(weightStringFor(expr)), true)
+			}
+			orderBy = append(orderBy, param)
+		}
+		a.Aggregations[idx].ConcatCols = cols
+		a.Aggregations[idx].OrderBy = orderBy
+	}
+}
+
+// groupConcatOrderExpr returns the expression to sort by. Like in MySQL, a position refers to the arguments of the GROUP_CONCAT
+func groupConcatOrderExpr(f *sqlparser.GroupConcatExpr, expr sqlparser.Expr) sqlparser.Expr {
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.IntVal {
+		return expr
+	}
+	pos, err := strconv.Atoi(lit.Val)
+	if err != nil || pos < 1 || pos > len(f.Exprs) {
+		panic(vterrors.VT03014(lit.Val, "order clause"))
+	}
+	return f.Exprs[pos-1]
 }
 
 func (a *Aggregator) addIfAggregationColumn(ctx *plancontext.PlanningContext, colIdx int) int {
@@ -579,6 +684,8 @@ func (a *Aggregator) SplitAggregatorBelowOperators(ctx *plancontext.PlanningCont
 	newOp.Pushed = false
 	newOp.Original = false
 	newOp.DT = nil
+	// the super-aggregate rows are only produced by the original aggregator
+	newOp.WithRollup = false
 
 	// We need to make sure that the columns are cloned so that the original operator is not affected
 	// by the changes we make to the new operator
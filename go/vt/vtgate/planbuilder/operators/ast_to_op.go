@@ -244,6 +244,8 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 		return getOperatorFromJoinTableExpr(ctx, tableExpr)
 	case *sqlparser.ParenTableExpr:
 		return crossJoin(ctx, tableExpr.Exprs)
+	case *sqlparser.JSONTableExpr:
+		return newJSONTable(ctx, tableExpr)
 	default:
 		panic(vterrors.VT13001(fmt.Sprintf("unable to use: %T table type", tableExpr)))
 	}
@@ -320,13 +322,23 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 			tbl.Select.SetOrderBy(nil)
 		}
 
-		inner := translateQueryToOp(ctx, tbl.Select)
-		if horizon, ok := inner.(*Horizon); ok {
+		stmt := tbl.Select
+		var lateralVars []BindVarExpr
+		if tbl.Lateral {
+			stmt, lateralVars = rewriteLateralReferences(ctx, stmt)
+		}
+
+		inner := translateQueryToOp(ctx, stmt)
+		horizon, ok := inner.(*Horizon)
+		if ok {
 			horizon.TableId = &tableID
 			horizon.Alias = tableExpr.As.String()
 			horizon.ColumnAliases = tableExpr.Columns
-			qp := CreateQPFromSelectStatement(ctx, tbl.Select)
+			qp := CreateQPFromSelectStatement(ctx, stmt)
 			horizon.QP = qp
+			horizon.LateralVars = lateralVars
+		} else if len(lateralVars) > 0 {
+			panic(vterrors.VT12001("lateral derived table with UNION"))
 		}
 
 		return inner
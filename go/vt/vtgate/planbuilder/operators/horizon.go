@@ -52,6 +52,13 @@ type Horizon struct {
 	ColumnsOffset []int
 
 	Truncate bool
+
+	// LateralVars are the values a lateral derived table uses from the tables preceding it.
+	// Inside the derived table, these have been replaced with arguments.
+	LateralVars []BindVarExpr
+	// Lateral is set when the lateral derived table has been merged with the tables it depends on,
+	// and has to be sent down as a LATERAL derived table
+	Lateral bool
 }
 
 func newHorizon(src Operator, query sqlparser.TableStatement) *Horizon {
@@ -68,6 +75,7 @@ func (h *Horizon) Clone(inputs []Operator) Operator {
 	klone.ColumnAliases = sqlparser.Clone(h.ColumnAliases)
 	klone.Columns = slices.Clone(h.Columns)
 	klone.ColumnsOffset = slices.Clone(h.ColumnsOffset)
+	klone.LateralVars = slices.Clone(h.LateralVars)
 	klone.QP = h.QP
 	return &klone
 }
@@ -96,7 +104,7 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 	}
 
 	newExpr := ctx.RewriteDerivedTableExpression(expr, tableInfo)
-	if ctx.ContainsAggr(newExpr) {
+	if ctx.ContainsAggr(newExpr) || (len(h.LateralVars) > 0 && !ctx.SemTable.RecursiveDeps(newExpr).IsSolvedBy(TableID(h.Source))) {
 		return newFilter(h, expr)
 	}
 	h.Source = h.Source.AddPredicate(ctx, newExpr)
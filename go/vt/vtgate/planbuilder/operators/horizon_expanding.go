@@ -86,6 +86,11 @@ func expandSelectHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, sel
 		}
 	}
 
+	windowFuncs := horizonWindowFuncs(ctx, qp)
+	if len(windowFuncs) > 0 && qp.HasAggr {
+		panic(vterrors.VT12001("window functions with aggregation on a sharded keyspace"))
+	}
+
 	op := createProjectionFromSelect(ctx, horizon)
 	if qp.HasAggr {
 		extracted = append(extracted, "Aggregation")
@@ -93,6 +98,11 @@ func expandSelectHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, sel
 		extracted = append(extracted, "Projection")
 	}
 
+	if !canPushWindowFuncs(ctx, horizon.src(), windowFuncs) {
+		op = planWindowFuncs(ctx, qp, op)
+		extracted = append(extracted, "Window")
+	}
+
 	if qp.NeedsDistinct() {
 		op = newDistinct(op, qp, true)
 		extracted = append(extracted, "Distinct")
@@ -113,37 +113,57 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 
 	vTbl, routing := buildVindexTableForDML(ctx, tableInfo, qt, ins, "insert")
 
-	deleteBeforeInsert := false
+	var delStmt *sqlparser.Delete
 	if ins.Action == sqlparser.ReplaceAct &&
 		(ctx.SemTable.ForeignKeysPresent() || vTbl.Keyspace.Sharded) &&
 		(len(vTbl.PrimaryKey) > 0 || len(vTbl.UniqueKeys) > 0) {
 		// this needs a delete before insert as there can be row clash which needs to be deleted first.
-		ins.Action = sqlparser.InsertAct
-		deleteBeforeInsert = true
+		// The delete is created before the insert operator, since that rewrites the row values to bind variables.
+		delStmt = createDeleteForReplace(ins, vTbl)
+		if delStmt != nil {
+			ins.Action = sqlparser.InsertAct
+		}
 	}
 
 	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
 
-	if !deleteBeforeInsert {
+	if delStmt == nil {
+		if ins.Action == sqlparser.ReplaceAct && vTbl.Keyspace.Sharded {
+			if len(vTbl.Owned) > 0 {
+				// without knowing which rows are replaced, we can't clean up the lookup vindexes for them
+				panic(vterrors.VT12001("REPLACE INTO with owned lookup vindexes on a table without primary key information"))
+			}
+			// the row it replaces may be on another shard than the new row, where the REPLACE would not see it
+			panic(vterrors.VT12001("REPLACE INTO on a sharded table without primary key information"))
+		}
 		return insOp
 	}
 
+	delOp := createOpFromStmt(ctx, delStmt, false, "")
+	return &Sequential{Sources: []Operator{delOp, insOp}}
+}
+
+// createDeleteForReplace creates the DELETE statement that removes the rows a REPLACE would clash with.
+// It returns nil when there is no key we can compare the new rows with.
+func createDeleteForReplace(ins *sqlparser.Insert, vTbl *vindexes.BaseTable) *sqlparser.Delete {
 	rows, isRows := ins.Rows.(sqlparser.Values)
 	if !isRows {
 		panic(vterrors.VT12001("REPLACE INTO using select statement"))
 	}
+	rows = sqlparser.Clone(rows)
 
 	pkCompExpr := pkCompExpression(vTbl, ins, rows)
 	uniqKeyCompExprs := uniqKeyCompExpressions(vTbl, ins, rows)
 	whereExpr := getWhereCondExpr(append(uniqKeyCompExprs, pkCompExpr))
+	if whereExpr == nil {
+		return nil
+	}
 
-	delStmt := &sqlparser.Delete{
+	return &sqlparser.Delete{
 		Comments:   ins.Comments,
 		TableExprs: sqlparser.TableExprs{sqlparser.Clone(ins.Table)},
 		Where:      sqlparser.NewWhere(sqlparser.WhereClause, whereExpr),
 	}
-	delOp := createOpFromStmt(ctx, delStmt, false, "")
-	return &Sequential{Sources: []Operator{delOp, insOp}}
 }
 
 func checkAndCreateInsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.BaseTable, routing Routing) Operator {
@@ -205,6 +225,9 @@ func pkCompExpression(vTbl *vindexes.BaseTable, ins *sqlparser.Insert, rows sqlp
 		return nil
 	}
 	pIndexes, pColTuple := findPKIndexes(vTbl, ins)
+	if len(pIndexes) == 0 {
+		return nil
+	}
 
 	var pValTuple sqlparser.ValTuple
 	for _, row := range rows {
@@ -250,6 +273,10 @@ func findDefault(vTbl *vindexes.BaseTable, pCol sqlparser.IdentifierCI) sqlparse
 			return column.Default
 		}
 	}
+	if !vTbl.ColumnListAuthoritative {
+		// we don't know about all the columns of this table, so we don't know the default either
+		return nil
+	}
 	panic(vterrors.VT03014(pCol.String(), vTbl.Name.String()))
 }
 
@@ -17,6 +17,8 @@ limitations under the License.
 package operators
 
 import (
+	"slices"
+
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -32,6 +34,10 @@ type Join struct {
 	// NormalJoinType, StraightJoinType and LeftJoinType.
 	JoinType sqlparser.JoinType
 
+	// LHSVars are the values from the LHS used by the subquery predicates
+	// of an outer join that have been pushed down to the RHS
+	LHSVars []BindVarExpr
+
 	noColumns
 }
 
@@ -42,6 +48,7 @@ func (j *Join) Clone(inputs []Operator) Operator {
 	clone := *j
 	clone.LHS = inputs[0]
 	clone.RHS = inputs[1]
+	clone.LHSVars = slices.Clone(j.LHSVars)
 	return &clone
 }
 
@@ -101,18 +108,69 @@ func createLeftOuterJoin(ctx *plancontext.PlanningContext, join *sqlparser.JoinT
 	// mark the RHS as outer tables so we know which columns are nullable
 	ctx.OuterTables = ctx.OuterTables.Merge(TableID(rhs))
 
-	// for outer joins we have to be careful with the predicates we use
-	var op Operator
-	subq, _ := getSubQuery(join.Condition.On)
-	if subq != nil {
-		panic(vterrors.VT12001("subquery in outer join predicate"))
+	// for outer joins we have to be careful with the predicates we use.
+	// predicates using subqueries can't be evaluated after the join, so they are
+	// pushed down to the RHS with the columns from the LHS replaced by arguments
+	var predicates, rhsPredicates []sqlparser.Expr
+	lhsID := TableID(lhs)
+	for _, pred := range sqlparser.SplitAndExpression(nil, join.Condition.On) {
+		if subq, _ := getSubQuery(pred); subq == nil {
+			predicates = append(predicates, pred)
+			continue
+		}
+		pred, vars := rewriteLHSReferences(ctx, pred, lhsID)
+		for _, bve := range vars {
+			if !slices.ContainsFunc(joinOp.LHSVars, func(other BindVarExpr) bool { return other.Name == bve.Name }) {
+				joinOp.LHSVars = append(joinOp.LHSVars, bve)
+			}
+		}
+		rhsPredicates = append(rhsPredicates, pred)
+	}
+	if len(rhsPredicates) > 0 {
+		joinOp.RHS = addJoinPredicates(ctx, ctx.SemTable.AndExpressions(rhsPredicates...), rhs)
 	}
-	predicate := join.Condition.On
+
+	predicate := ctx.SemTable.AndExpressions(predicates...)
 	sqlparser.RemoveKeyspaceInCol(predicate)
 	joinOp.Predicate = predicate
-	op = joinOp
 
-	return op
+	return joinOp
+}
+
+// rewriteLHSReferences replaces the columns coming from the LHS of an outer join with arguments,
+// so the expression can be evaluated on the RHS of the join. The returned BindVarExprs
+// are the values that have to be supplied from the LHS.
+func rewriteLHSReferences(ctx *plancontext.PlanningContext, expr sqlparser.Expr, lhsID semantics.TableSet) (sqlparser.Expr, []BindVarExpr) {
+	var vars []BindVarExpr
+	rewritten := sqlparser.CopyOnRewrite(expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		col, ok := cursor.Node().(*sqlparser.ColName)
+		if !ok {
+			return
+		}
+		deps := ctx.SemTable.RecursiveDeps(col)
+		if deps.IsEmpty() || !deps.IsSolvedBy(lhsID) {
+			return
+		}
+
+		bvName := ctx.GetReservedArgumentFor(col)
+		if !slices.ContainsFunc(vars, func(bve BindVarExpr) bool { return bve.Name == bvName }) {
+			vars = append(vars, BindVarExpr{Name: bvName, Expr: col})
+		}
+		typeForExpr, _ := ctx.TypeForExpr(col)
+		arg := sqlparser.NewTypedArgument(bvName, typeForExpr.Type())
+		arg.Scale = typeForExpr.Scale()
+		arg.Size = typeForExpr.Size()
+		ctx.SemTable.CopyExprInfo(col, arg)
+		cursor.Replace(arg)
+	}, func(from, to sqlparser.SQLNode) {
+		// the rewritten expressions no longer depend on the LHS
+		ctx.SemTable.CopySemanticInfo(from, to)
+		if e, ok := to.(sqlparser.Expr); ok && semantics.ValidAsMapKey(e) {
+			ctx.SemTable.Recursive[e] = ctx.SemTable.RecursiveDeps(e).Remove(lhsID)
+			ctx.SemTable.Direct[e] = ctx.SemTable.DirectDeps(e).Remove(lhsID)
+		}
+	})
+	return rewritten.(sqlparser.Expr), vars
 }
 
 func createInnerJoin(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr, lhs, rhs Operator) Operator {
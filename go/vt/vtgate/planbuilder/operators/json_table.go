@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"slices"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// JSONTable represents a JSON_TABLE table function.
+// When the document comes from a table in the same route, the table function is pushed down
+// together with that table. Otherwise, it is evaluated on the vtgate, and any columns the
+// document needs from other tables are sent in as arguments by an ApplyJoin.
+type JSONTable struct {
+	TableID semantics.TableSet
+	Expr    *sqlparser.JSONTableExpr
+
+	// Doc is the JSON document expanded by the table function.
+	// When evaluated on the vtgate, columns from the LHS of the join are replaced with arguments.
+	Doc sqlparser.Expr
+
+	Columns []*sqlparser.ColName
+
+	nullaryOperator
+}
+
+func newJSONTable(ctx *plancontext.PlanningContext, expr *sqlparser.JSONTableExpr) *JSONTable {
+	return &JSONTable{
+		TableID: ctx.SemTable.TableSetForJSONTable(expr),
+		Expr:    expr,
+		Doc:     expr.Expr,
+	}
+}
+
+// Clone implements the Operator interface
+func (jt *JSONTable) Clone([]Operator) Operator {
+	klone := *jt
+	klone.Columns = slices.Clone(jt.Columns)
+	return &klone
+}
+
+func (jt *JSONTable) introducesTableID() semantics.TableSet {
+	return jt.TableID
+}
+
+func (jt *JSONTable) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(jt, expr)
+}
+
+func (jt *JSONTable) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, ae *sqlparser.AliasedExpr) int {
+	if gb {
+		panic(vterrors.VT13001("tried to add group by to a table"))
+	}
+	if reuse {
+		offset := jt.FindCol(ctx, ae.Expr, true)
+		if offset > -1 {
+			return offset
+		}
+	}
+
+	return addColumn(ctx, jt, ae.Expr)
+}
+
+func (*JSONTable) AddWSColumn(*plancontext.PlanningContext, int, bool) int {
+	panic(vterrors.VT13001("did not expect this method to be called"))
+}
+
+func (jt *JSONTable) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range jt.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (jt *JSONTable) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return slice.Map(jt.Columns, colNameToExpr)
+}
+
+func (jt *JSONTable) GetSelectExprs(ctx *plancontext.PlanningContext) []sqlparser.SelectExpr {
+	return transformColumnsToSelectExprs(ctx, jt)
+}
+
+func (jt *JSONTable) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (jt *JSONTable) GetColNames() []*sqlparser.ColName {
+	return jt.Columns
+}
+
+func (jt *JSONTable) AddCol(col *sqlparser.ColName) {
+	jt.Columns = append(jt.Columns, col)
+}
+
+// TablesUsed implements the Operator interface.
+// A table function does not read any table, so nothing is added.
+func (jt *JSONTable) TablesUsed(in []string) []string {
+	return in
+}
+
+func (jt *JSONTable) ShortDescription() string {
+	return "JSON_TABLE(" + sqlparser.String(jt.Doc) + ") AS " + jt.Expr.Alias.String()
+}
+
+// TableExpr returns the JSON_TABLE expression with the document used by this operator
+func (jt *JSONTable) TableExpr() *sqlparser.JSONTableExpr {
+	if jt.Doc == jt.Expr.Expr {
+		return jt.Expr
+	}
+	expr := *jt.Expr
+	expr.Expr = jt.Doc
+	return &expr
+}
+
+// findJSONTable returns the JSON_TABLE operator, possibly under filters
+func findJSONTable(op Operator) *JSONTable {
+	switch op := op.(type) {
+	case *JSONTable:
+		return op
+	case *Filter:
+		return findJSONTable(op.Source)
+	default:
+		return nil
+	}
+}
+
+// planJSONTableJoin plans a join where one side is a JSON_TABLE. If the other side is a route
+// that can produce the document, the table function is pushed into that route.
+// Otherwise, the table function is evaluated on the vtgate on the RHS of an ApplyJoin.
+func planJSONTableJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
+	lhs, rhs := op.LHS, op.RHS
+	jt := findJSONTable(rhs)
+	if jt == nil {
+		jt = findJSONTable(lhs)
+		if jt == nil {
+			return nil, nil
+		}
+		// the document of a JSON_TABLE on the left side can't depend on anything on the right side,
+		// but for outer joins, every shard would produce the rows without matches
+		if route, ok := rhs.(*Route); ok && (op.JoinType.IsInner() || route.Routing.OpCode().IsSingleShard()) {
+			route.Source = NewApplyJoin(ctx, lhs, route.Source, op.Predicate, op.JoinType)
+			return route, Rewrote("push JSON_TABLE into route")
+		}
+		return nil, nil
+	}
+
+	docDeps := ctx.SemTable.RecursiveDeps(jt.Doc)
+	if route, ok := lhs.(*Route); ok && docDeps.IsSolvedBy(TableID(route)) {
+		route.Source = NewApplyJoin(ctx, route.Source, rhs, op.Predicate, op.JoinType)
+		return route, Rewrote("push JSON_TABLE into route")
+	}
+
+	if !docDeps.IsSolvedBy(TableID(lhs)) {
+		panic(vterrors.VT12001("JSON_TABLE document depending on tables not preceding it"))
+	}
+
+	join := NewApplyJoin(ctx, Clone(lhs), Clone(rhs), nil, op.JoinType)
+	jt = findJSONTable(join.RHS)
+	if !docDeps.IsEmpty() {
+		col := breakExpressionInLHSandRHS(ctx, jt.Doc, TableID(lhs))
+		jt.Doc = col.RHSExpr
+		join.ExtraLHSVars = append(join.ExtraLHSVars, col.LHSExprs...)
+	}
+	for _, pred := range sqlparser.SplitAndExpression(nil, op.Predicate) {
+		join.AddJoinPredicate(ctx, pred)
+	}
+	return join, Rewrote("evaluate JSON_TABLE on the vtgate")
+}
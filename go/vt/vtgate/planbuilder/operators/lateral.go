@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"slices"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// rewriteLateralReferences replaces the columns a lateral derived table uses from the tables preceding it
+// with arguments, so the derived table can be planned on its own. The returned BindVarExprs
+// are the values that have to be supplied from the left hand side of the join.
+func rewriteLateralReferences(ctx *plancontext.PlanningContext, sel sqlparser.TableStatement) (sqlparser.TableStatement, []BindVarExpr) {
+	var inner semantics.TableSet
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			inner = inner.Merge(ctx.SemTable.TableSetFor(node))
+		case *sqlparser.JSONTableExpr:
+			inner = inner.Merge(ctx.SemTable.TableSetForJSONTable(node))
+		}
+		return true, nil
+	}, sel)
+
+	var vars []BindVarExpr
+	rewritten := sqlparser.CopyOnRewrite(sel, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		col, ok := cursor.Node().(*sqlparser.ColName)
+		if !ok {
+			return
+		}
+		deps := ctx.SemTable.RecursiveDeps(col)
+		if deps.IsEmpty() || deps.IsOverlapping(inner) {
+			return
+		}
+
+		bvName := ctx.GetReservedArgumentFor(col)
+		if !slices.ContainsFunc(vars, func(bve BindVarExpr) bool { return bve.Name == bvName }) {
+			vars = append(vars, BindVarExpr{Name: bvName, Expr: col})
+		}
+		typeForExpr, _ := ctx.TypeForExpr(col)
+		arg := sqlparser.NewTypedArgument(bvName, typeForExpr.Type())
+		arg.Scale = typeForExpr.Scale()
+		arg.Size = typeForExpr.Size()
+		ctx.SemTable.CopyExprInfo(col, arg)
+		cursor.Replace(arg)
+	}, func(from, to sqlparser.SQLNode) {
+		// the dependencies of the rewritten expressions change, so we only keep the statement information
+		if _, isStmt := from.(sqlparser.Statement); isStmt {
+			ctx.SemTable.CopySemanticInfo(from, to)
+		}
+	})
+	return rewritten.(sqlparser.TableStatement), vars
+}
+
+// restoreLateralReferences undoes what rewriteLateralReferences did, and is used when the
+// lateral derived table ends up in the same query as the tables it depends on
+func restoreLateralReferences(stmt sqlparser.SQLNode, vars []BindVarExpr) sqlparser.SQLNode {
+	return sqlparser.CopyOnRewrite(stmt, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		arg, ok := cursor.Node().(*sqlparser.Argument)
+		if !ok {
+			return
+		}
+		idx := slices.IndexFunc(vars, func(bve BindVarExpr) bool { return bve.Name == arg.Name })
+		if idx >= 0 {
+			cursor.Replace(sqlparser.Clone(vars[idx].Expr))
+		}
+	}, nil)
+}
+
+// lateralVarsFor returns the values the lateral derived tables in this operator need from outside of it
+func lateralVarsFor(ctx *plancontext.PlanningContext, op Operator) (vars []BindVarExpr) {
+	solved := TableID(op)
+	_ = Visit(op, func(current Operator) error {
+		horizon, ok := current.(*Horizon)
+		if !ok {
+			return nil
+		}
+		for _, bve := range horizon.LateralVars {
+			if !ctx.SemTable.RecursiveDeps(bve.Expr).IsSolvedBy(solved) {
+				vars = append(vars, bve)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// planLateralJoin plans a join where the right hand side is a lateral derived table using the left hand side.
+// The sides can't be switched, and the inputs can only be merged if the derived table is
+// guaranteed to find the rows it needs on the same shard as the row from the left hand side.
+func planLateralJoin(ctx *plancontext.PlanningContext, op *Join, vars []BindVarExpr) (Operator, *ApplyResult) {
+	lhsID := TableID(op.LHS)
+	for _, bve := range vars {
+		if !ctx.SemTable.RecursiveDeps(bve.Expr).IsSolvedBy(lhsID) {
+			panic(vterrors.VT12001("lateral derived table referencing tables outside of the FROM clause"))
+		}
+	}
+
+	joinPredicates := sqlparser.SplitAndExpression(nil, op.Predicate)
+	if merged := mergeLateral(ctx, op, vars, joinPredicates); merged != nil {
+		_ = Visit(merged, func(current Operator) error {
+			switch current := current.(type) {
+			case *Horizon:
+				if len(current.LateralVars) > 0 {
+					current.Lateral = true
+				}
+			case *Filter:
+				// predicates pushed down from an outer join ON condition can use the LHS columns again
+				for i, pred := range current.Predicates {
+					current.Predicates[i] = restoreLateralReferences(pred, vars).(sqlparser.Expr)
+				}
+			}
+			return nil
+		})
+		return merged, Rewrote("merge lateral derived table into route")
+	}
+
+	join := NewApplyJoin(ctx, Clone(op.LHS), Clone(op.RHS), nil, op.JoinType)
+	join.ExtraLHSVars = append(join.ExtraLHSVars, vars...)
+	for _, pred := range joinPredicates {
+		join.AddJoinPredicate(ctx, pred)
+	}
+	return join, Rewrote("lateral join to applyJoin")
+}
+
+func mergeLateral(ctx *plancontext.PlanningContext, op *Join, vars []BindVarExpr, joinPredicates []sqlparser.Expr) *Route {
+	lhsRoute, rhsRoute := operatorsToRoutes(op.LHS, op.RHS)
+	if lhsRoute == nil || rhsRoute == nil {
+		return nil
+	}
+
+	jm := newJoinMerge(joinPredicates, op.JoinType)
+	rhsRouting, ok := rhsRoute.Routing.(*ShardedRouting)
+	if !ok || !routingUsesArguments(rhsRouting, vars) {
+		return jm.mergeJoinInputs(ctx, lhsRoute, rhsRoute, nil)
+	}
+
+	// the derived table is routed using values from the left hand side.
+	// we can only merge if these values lead to the shard the row from the left hand side lives on
+	if _, lhsSharded := lhsRoute.Routing.(*ShardedRouting); !lhsSharded || lhsRoute.Routing.Keyspace() != rhsRouting.Keyspace() {
+		return nil
+	}
+	for _, pred := range rhsRouting.Selected.Predicates {
+		cmp, ok := pred.(*sqlparser.ComparisonExpr)
+		if !ok || cmp.Operator != sqlparser.EqualOp {
+			continue
+		}
+		col, arg := cmp.Left, cmp.Right
+		if _, isArg := col.(*sqlparser.Argument); isArg {
+			col, arg = arg, col
+		}
+		argument, ok := arg.(*sqlparser.Argument)
+		if !ok {
+			continue
+		}
+		idx := slices.IndexFunc(vars, func(bve BindVarExpr) bool { return bve.Name == argument.Name })
+		if idx < 0 {
+			continue
+		}
+		if canMergeOnFilter(ctx, lhsRoute, rhsRoute, sqlparser.NewComparisonExpr(sqlparser.EqualOp, vars[idx].Expr, col, nil)) {
+			return jm.merge(ctx, lhsRoute, rhsRoute, lhsRoute.Routing)
+		}
+	}
+	return nil
+}
+
+func routingUsesArguments(routing *ShardedRouting, vars []BindVarExpr) (uses bool) {
+	for _, expr := range routing.VindexExpressions() {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if arg, ok := node.(*sqlparser.Argument); ok {
+				uses = uses || slices.ContainsFunc(vars, func(bve BindVarExpr) bool { return bve.Name == arg.Name })
+			}
+			return !uses, nil
+		}, expr)
+	}
+	return
+}
@@ -66,9 +66,9 @@ func mustFetchFromInput(ctx *plancontext.PlanningContext, e sqlparser.SQLNode) b
 	case *sqlparser.ColName, sqlparser.AggrFunc:
 		return true
 	case *sqlparser.FuncExpr:
-		return fun.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
+		return ctx.IsAggr(fun)
 	default:
-		return false
+		return sqlparser.IsWindowFunc(e)
 	}
 }
 
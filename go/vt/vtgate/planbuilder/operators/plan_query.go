@@ -181,6 +181,10 @@ func tryTruncateColumnsAt(op Operator, truncateAt int) bool {
 	case *Limit:
 		return tryTruncateColumnsAt(op.Source, truncateAt)
 	case *SubQuery:
+		if op.FilterWithOffsets != nil {
+			// the filter evaluated on the vtgate needs the columns of the outer query
+			return false
+		}
 		for _, offset := range op.Vars {
 			if offset >= truncateAt {
 				return false
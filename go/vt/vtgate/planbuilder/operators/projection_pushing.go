@@ -96,6 +96,11 @@ func tryPushProjection(
 			return p, NoRewrite
 		}
 		return pushProjectionInVindex(ctx, p, src)
+	case *JSONTable:
+		if !p.canPush(ctx) {
+			return p, NoRewrite
+		}
+		return pushProjectionInJSONTable(ctx, p, src)
 	case *SubQueryContainer:
 		if !p.canPush(ctx) {
 			return p, NoRewrite
@@ -166,6 +171,27 @@ func pushProjectionInVindex(
 	return src, Rewrote("push projection into vindex")
 }
 
+func pushProjectionInJSONTable(
+	ctx *plancontext.PlanningContext,
+	p *Projection,
+	src *JSONTable,
+) (Operator, *ApplyResult) {
+	ap, err := p.GetAliasedProjections()
+	if err != nil {
+		return p, NoRewrite
+	}
+	for _, pe := range ap {
+		// the table function can only produce its own columns
+		if _, isCol := pe.EvalExpr.(*sqlparser.ColName); !isCol || !pe.isSameInAndOut(ctx) {
+			return p, NoRewrite
+		}
+	}
+	for _, pe := range ap {
+		src.AddColumn(ctx, true, false, aeWrap(pe.EvalExpr))
+	}
+	return src, Rewrote("push projection into JSON_TABLE")
+}
+
 func pushProjectionToOuterContainer(ctx *plancontext.PlanningContext, p *Projection, src *SubQueryContainer) (Operator, *ApplyResult) {
 	ap, err := p.GetAliasedProjections()
 	if err != nil {
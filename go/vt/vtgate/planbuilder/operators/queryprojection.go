@@ -25,6 +25,7 @@ import (
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -85,6 +86,17 @@ type (
 		SubQueryExpression []*SubQuery // Subqueries associated with this aggregation
 
 		PushedDown bool // Whether the aggregation has been pushed down to the next layer
+
+		// DistinctCols are filled in during offset planning, when the distinct values are tracked using hashing
+		DistinctCols []engine.CheckCol
+
+		// ConcatCols and OrderBy are filled in during offset planning, when a GROUP_CONCAT is evaluated on the vtgate
+		ConcatCols []int
+		OrderBy    evalengine.Comparison
+
+		// StatisticsCols are the offsets of the count and the average that are merged with the population variance
+		// returned by the aggregator below, when a VARIANCE or STDDEV is pushed down
+		StatisticsCols []int
 	}
 )
 
@@ -450,6 +462,14 @@ func (qp *QueryProjection) extractAggr(
 			addAggr(aggrFunc)
 			return false
 		}
+		if sqlparser.IsGroupingFunc(node) {
+			ae := aeWrap(ex)
+			if ex == aliasedExpr.Expr {
+				ae = aliasedExpr
+			}
+			addAggr(NewAggr(opcode.AggregateGrouping, nil, ae, ae.ColumnName()))
+			return false
+		}
 		if ctx.IsAggr(node) {
 			// If we are here, we have a function that is an aggregation but not parsed into an AggrFunc.
 			// This is the case for UDFs - we have to be careful with these because we can't evaluate them in VTGate.
@@ -53,6 +53,12 @@ func pushDerived(ctx *plancontext.PlanningContext, op *Horizon) (Operator, *Appl
 }
 
 func optimizeJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
+	if vars := append(lateralVarsFor(ctx, op.RHS), op.LHSVars...); len(vars) > 0 {
+		return planLateralJoin(ctx, op, vars)
+	}
+	if newOp, result := planJSONTableJoin(ctx, op); newOp != nil {
+		return newOp, result
+	}
 	return mergeOrJoin(ctx, op.LHS, op.RHS, sqlparser.SplitAndExpression(nil, op.Predicate), op.JoinType)
 }
 
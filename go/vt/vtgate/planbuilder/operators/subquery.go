@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strings"
 
 	"vitess.io/vitess/go/slice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
@@ -52,6 +55,13 @@ type SubQuery struct {
 	// correlated stores whether this subquery is correlated or not.
 	// We use this information to fail the planning if we are unable to merge the subquery with a route.
 	correlated bool
+	// outerRefs is set when the subquery uses columns from the outer query outside the
+	// predicates that can be turned into bind variables, e.g. in the SELECT expressions.
+	outerRefs bool
+
+	// Fields related to subqueries that are evaluated on the vtgate, once for every outer row:
+	FilterPredicate   sqlparser.Expr  // The filter predicate, with the subquery replaced by arguments.
+	FilterWithOffsets evalengine.Expr // The filter predicate, with columns from the outer side replaced by offsets.
 
 	// IsArgument is set to true if the subquery puts the
 	IsArgument bool
@@ -69,6 +79,25 @@ func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
 			sq.Vars[lhsExpr.Name] = offset
 		}
 	}
+
+	if sq.FilterPredicate == nil {
+		return nil
+	}
+
+	cfg := &evalengine.Config{
+		ResolveType: ctx.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	}
+	rewritten := useOffsets(ctx, sq.FilterPredicate, sq)
+	eexpr, err := evalengine.Translate(rewritten, cfg)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), evalengine.ErrTranslateExprNotSupported) {
+			panic(vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s: %s", evalengine.ErrTranslateExprNotSupported, sqlparser.String(sq.FilterPredicate)))
+		}
+		panic(err)
+	}
+	sq.FilterWithOffsets = eexpr
 	return nil
 }
 
@@ -210,25 +239,31 @@ func (sq *SubQuery) GetMergePredicates() []sqlparser.Expr {
 func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Operator {
 	// We can allow uncorrelated queries even when subquery isn't the top level construct,
 	// like if its underneath an Aggregator, because they will be pulled out and run separately.
-	if !sq.TopLevel && sq.correlated {
+	if !sq.TopLevel && (sq.correlated || sq.isQuantified()) {
 		panic(subqueryNotAtTopErr)
 	}
-	if sq.correlated && sq.FilterType != opcode.PulloutExists {
-		panic(correlatedSubqueryErr)
-	}
 	if sq.IsArgument {
-		if len(sq.GetMergePredicates()) > 0 {
+		if sq.correlated || len(sq.GetMergePredicates()) > 0 {
 			// this means that we have a correlated subquery on our hands
 			panic(correlatedSubqueryErr)
 		}
+		if sq.isQuantified() {
+			panic(vterrors.VT12001("ANY/ALL comparison with a subquery that is not used as a filter"))
+		}
 		sq.SubqueryValueName = sq.ArgName
 		return outer
 	}
 	return sq.settleFilter(ctx, outer)
 }
 
-var correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
+var correlatedSubqueryErr = vterrors.VT12001("correlated subquery that is not used as a filter")
 var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
+var outerRefsErr = vterrors.VT12001("correlated subquery using outer columns outside of its WHERE and HAVING predicates")
+
+// isQuantified returns true for ANY/ALL comparisons that can't be rewritten to IN or NOT IN
+func (sq *SubQuery) isQuantified() bool {
+	return sq.FilterType == opcode.PulloutAny || sq.FilterType == opcode.PulloutAll
+}
 
 func (sq *SubQuery) addLimit() {
 	// for a correlated subquery, we can add a limit 1 to the subquery
@@ -236,13 +271,13 @@ func (sq *SubQuery) addLimit() {
 }
 
 func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operator) Operator {
-	if len(sq.Predicates) > 0 {
-		if sq.FilterType != opcode.PulloutExists {
-			panic(correlatedSubqueryErr)
-		}
+	if len(sq.Predicates) > 0 && sq.FilterType == opcode.PulloutExists {
 		sq.addLimit()
 		return outer
 	}
+	if sq.correlated && sq.outerRefs {
+		panic(outerRefsErr)
+	}
 
 	hasValuesArg := func() string {
 		s := ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
@@ -251,6 +286,23 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 	}
 	post := func(cursor *sqlparser.CopyOnWriteCursor) {
 		node := cursor.Node()
+		// ANY/ALL comparisons are either rewritten to IN and NOT IN, or evaluated
+		// by comparing against one subquery row at a time.
+		if compExpr, isCompExpr := node.(*sqlparser.ComparisonExpr); isCompExpr && compExpr.Modifier != sqlparser.Missing && sq.isArgument(compExpr.Right) {
+			compExpr = &sqlparser.ComparisonExpr{
+				Operator: compExpr.Operator,
+				Left:     compExpr.Left,
+				Right:    compExpr.Right,
+			}
+			switch sq.FilterType {
+			case opcode.PulloutIn:
+				compExpr.Operator = sqlparser.InOp
+			case opcode.PulloutNotIn:
+				compExpr.Operator = sqlparser.NotInOp
+			}
+			cursor.Replace(compExpr)
+			node = compExpr
+		}
 		// For IN and NOT IN type filters, we have to add a Expression that checks if we got any rows back or not
 		// for correctness. That expression should be ANDed with the expression that has the IN/NOT IN comparison.
 		if compExpr, isCompExpr := node.(*sqlparser.ComparisonExpr); sq.FilterType.NeedsListArg() && isCompExpr {
@@ -279,6 +331,25 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 	}
 	rhsPred := sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
 
+	if sq.correlated || sq.isQuantified() {
+		// The filter can't be sent down with the outer query, so it is evaluated on the vtgate,
+		// running the subquery for the correlation values of every outer row.
+		switch sq.FilterType {
+		case opcode.PulloutNotExists:
+			sq.FilterType = opcode.PulloutExists
+			rhsPred = sqlparser.NewNotExpr(sqlparser.NewArgument(hasValuesArg()))
+		case opcode.PulloutExists:
+			rhsPred = sqlparser.NewArgument(hasValuesArg())
+		}
+		if sq.FilterType == opcode.PulloutExists {
+			sq.addLimit()
+		} else {
+			sq.SubqueryValueName = sq.ArgName
+		}
+		sq.FilterPredicate = rhsPred
+		return outer
+	}
+
 	var predicates []sqlparser.Expr
 	switch sq.FilterType {
 	case opcode.PulloutExists:
@@ -309,6 +380,17 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 	return newFilter(outer, predicates...)
 }
 
+// isArgument returns true if the expression is the argument that replaced this subquery
+func (sq *SubQuery) isArgument(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case sqlparser.ListArg:
+		return string(expr) == sq.ArgName
+	case *sqlparser.Argument:
+		return expr.Name == sq.ArgName
+	}
+	return false
+}
+
 func dontEnterSubqueries(node, _ sqlparser.SQLNode) bool {
 	if _, ok := node.(*sqlparser.Subquery); ok {
 		return false
@@ -170,6 +170,7 @@ func createSubquery(
 
 	predicates, joinCols := sqc.inspectStatement(ctx, subq.Select)
 	correlated := !ctx.SemTable.RecursiveDeps(subq).IsEmpty()
+	outerRefs := correlated && usesOuterColumns(ctx, subq.Select, subqID)
 
 	opInner := translateQueryToOp(ctx, subq.Select)
 
@@ -185,9 +186,29 @@ func createSubquery(
 		TopLevel:         topLevel,
 		JoinColumns:      joinCols,
 		correlated:       correlated,
+		outerRefs:        outerRefs,
 	}
 }
 
+// usesOuterColumns returns true if the statement still references columns from outside of it.
+// This is checked after the correlated predicates have been rewritten to use bind variables.
+func usesOuterColumns(ctx *plancontext.PlanningContext, stmt sqlparser.TableStatement, subqID semantics.TableSet) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Subquery:
+			// nested subqueries are planned by their own operators
+			return false, nil
+		case *sqlparser.ColName:
+			if !ctx.SemTable.RecursiveDeps(node).IsSolvedBy(subqID) {
+				found = true
+			}
+		}
+		return !found, nil
+	}, stmt)
+	return found
+}
+
 func (sqb *SubQueryBuilder) inspectWhere(
 	ctx *plancontext.PlanningContext,
 	in *sqlparser.Where,
@@ -275,6 +296,20 @@ func createComparisonSubQuery(
 	case sqlparser.NotInOp:
 		filterType = opcode.PulloutNotIn
 	}
+	if parent.Right == subq {
+		switch {
+		case parent.Modifier == sqlparser.Any && parent.Operator == sqlparser.EqualOp:
+			// = ANY is the same as IN
+			filterType = opcode.PulloutIn
+		case parent.Modifier == sqlparser.All && parent.Operator == sqlparser.NotEqualOp:
+			// <> ALL is the same as NOT IN
+			filterType = opcode.PulloutNotIn
+		case parent.Modifier == sqlparser.Any:
+			filterType = opcode.PulloutAny
+		case parent.Modifier == sqlparser.All:
+			filterType = opcode.PulloutAll
+		}
+	}
 
 	subquery := createSubquery(ctx, original, subq, outerID, parent, name, filterType, false)
 
@@ -333,6 +368,12 @@ func getOpCodeFromParent(parent sqlparser.SQLNode) *opcode.PulloutOpcode {
 		case sqlparser.NotInOp:
 			code = opcode.PulloutNotIn
 		}
+		switch parent.Modifier {
+		case sqlparser.Any:
+			code = opcode.PulloutAny
+		case sqlparser.All:
+			code = opcode.PulloutAll
+		}
 	}
 	return &code
 }
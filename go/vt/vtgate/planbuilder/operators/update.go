@@ -46,6 +46,10 @@ type (
 		// On merging this information will be lost, so subquery merge is blocked.
 		SubQueriesArgOnChangedVindex []string
 
+		// MigrateRows is set when the primary vindex columns are updated,
+		// which means that the updated rows have to be moved to other shards.
+		MigrateRows bool
+
 		VerifyAll bool
 
 		noColumns
@@ -371,7 +375,7 @@ func createUpdateOperator(ctx *plancontext.PlanningContext, updStmt *sqlparser.U
 		Name:   name,
 	}
 
-	cvv, ovq, subQueriesArgOnChangedVindex := getUpdateVindexInformation(ctx, updStmt, targetTbl, assignments)
+	cvv, ovq, subQueriesArgOnChangedVindex, migrateRows := getUpdateVindexInformation(ctx, updStmt, targetTbl, assignments)
 
 	updOp := &Update{
 		DMLCommon: &DMLCommon{
@@ -382,6 +386,7 @@ func createUpdateOperator(ctx *plancontext.PlanningContext, updStmt *sqlparser.U
 		Assignments:                  assignments,
 		ChangedVindexValues:          cvv,
 		SubQueriesArgOnChangedVindex: subQueriesArgOnChangedVindex,
+		MigrateRows:                  migrateRows,
 		VerifyAll:                    ctx.VerifyAllFKs,
 	}
 
@@ -403,14 +408,20 @@ func getUpdateVindexInformation(
 	updStmt *sqlparser.Update,
 	table TargetTable,
 	assignments []SetExpr,
-) (map[string]*engine.VindexValues, *sqlparser.Select, []string) {
+) (map[string]*engine.VindexValues, *sqlparser.Select, []string, bool) {
 	if !table.VTable.Keyspace.Sharded {
-		return nil, nil, nil
+		return nil, nil, nil, false
 	}
 
 	primaryVindex := getVindexInformation(table.ID, table.VTable)
-	changedVindexValues, ownedVindexQuery, subQueriesArgOnChangedVindex := buildChangedVindexesValues(ctx, updStmt, table.VTable, primaryVindex.Columns, assignments)
-	return changedVindexValues, ownedVindexQuery, subQueriesArgOnChangedVindex
+	changedVindexValues, ownedVindexQuery, subQueriesArgOnChangedVindex, migrateRows := buildChangedVindexesValues(ctx, updStmt, table.VTable, primaryVindex.Columns, assignments)
+	if migrateRows && (len(ctx.SemTable.GetChildForeignKeysForTableSet(table.ID)) > 0 || len(ctx.SemTable.GetParentForeignKeysForTableSet(table.ID)) > 0) {
+		panic(vterrors.VT12001(fmt.Sprintf("you cannot UPDATE primary vindex columns of a table with foreign keys; invalid update on vindex: %v", primaryVindex.Name)))
+	}
+	if migrateRows {
+		checkRowMigration(table.VTable)
+	}
+	return changedVindexValues, ownedVindexQuery, subQueriesArgOnChangedVindex, migrateRows
 }
 
 func buildFkOperator(ctx *plancontext.PlanningContext, updOp Operator, updClone *sqlparser.Update, parentFks []vindexes.ParentFKInfo, childFks []vindexes.ChildFKInfo, targetTbl TargetTable) Operator {
@@ -1034,7 +1045,7 @@ func buildChangedVindexesValues(
 	table *vindexes.BaseTable,
 	ksidCols []sqlparser.IdentifierCI,
 	assignments []SetExpr,
-) (changedVindexes map[string]*engine.VindexValues, ovq *sqlparser.Select, subQueriesArgOnChangedVindex []string) {
+) (changedVindexes map[string]*engine.VindexValues, ovq *sqlparser.Select, subQueriesArgOnChangedVindex []string, migrateRows bool) {
 	changedVindexes = make(map[string]*engine.VindexValues)
 	selExprs, offset := initialQuery(ksidCols, table)
 	for i, vindex := range table.ColumnVindexes {
@@ -1049,7 +1060,12 @@ func buildChangedVindexesValues(
 			continue
 		}
 		if i == 0 {
-			panic(vterrors.VT12001(fmt.Sprintf("you cannot UPDATE primary vindex columns; invalid update on vindex: %v", vindex.Name)))
+			// The rows will be moved to the shards of their new primary vindex values,
+			// which takes care of the changes to all the other vindexes as well.
+			migrateRows = true
+		}
+		if migrateRows {
+			continue
 		}
 		if _, ok := vindex.Vindex.(vindexes.Lookup); !ok {
 			panic(vterrors.VT12001(fmt.Sprintf("you can only UPDATE lookup vindexes; invalid update on vindex: %v", vindex.Name)))
@@ -1063,8 +1079,11 @@ func buildChangedVindexesValues(
 		}
 		offset++
 	}
+	if migrateRows {
+		return nil, nil, subQueriesArgOnChangedVindex, true
+	}
 	if len(changedVindexes) == 0 {
-		return nil, nil, nil
+		return nil, nil, nil, false
 	}
 	// generate rest of the owned vindex query.
 	ovq = &sqlparser.Select{
@@ -1073,7 +1092,25 @@ func buildChangedVindexesValues(
 		Limit:       update.Limit,
 		Lock:        sqlparser.ForUpdateLock,
 	}
-	return changedVindexes, ovq, subQueriesArgOnChangedVindex
+	return changedVindexes, ovq, subQueriesArgOnChangedVindex, false
+}
+
+// checkRowMigration fails the planning when the rows of the table cannot be moved between shards.
+// The rows are read and inserted on their new shards with the full column list of the table,
+// without the generated columns, which cannot be inserted. The new values of the generated
+// columns are computed by MySQL, so they cannot be used to find the new shards of the rows.
+func checkRowMigration(table *vindexes.BaseTable) {
+	if !table.ColumnListAuthoritative {
+		panic(vterrors.VT12001(fmt.Sprintf("you cannot UPDATE primary vindex columns of a table without an authoritative column list; invalid update on table: %s", table.Name.String())))
+	}
+	for _, cv := range table.ColumnVindexes {
+		for _, vcol := range cv.Columns {
+			idx := slices.IndexFunc(table.Columns, func(col vindexes.Column) bool { return col.Name.Equal(vcol) })
+			if idx >= 0 && table.Columns[idx].Generated {
+				panic(vterrors.VT12001(fmt.Sprintf("you cannot UPDATE primary vindex columns of a table with a vindex on a generated column; invalid update on vindex: %v", cv.Name)))
+			}
+		}
+	}
 }
 
 func initialQuery(ksidCols []sqlparser.IdentifierCI, table *vindexes.BaseTable) (*sqlparser.SelectExprs, int) {
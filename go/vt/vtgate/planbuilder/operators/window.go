@@ -0,0 +1,414 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+type (
+	// Window evaluates window functions at the vtgate level. All the functions
+	// of a single Window operator share the same PARTITION BY and ORDER BY clauses.
+	// The input is sorted by the partitioning columns followed by the ordering columns
+	// by an Ordering that is placed under the Window when it is created.
+	//
+	// The results of the window functions are the first columns of the output,
+	// followed by all the columns of the input.
+	Window struct {
+		unaryOperator
+
+		PartitionBy []sqlparser.Expr
+		OrderBy     []OrderBy
+		Functions   []*WindowFunc
+
+		// These are only filled in during offset planning
+		PartitionOffsets  []int
+		PartitionWOffsets []int
+		OrderOffsets      []int
+		OrderWOffsets     []int
+	}
+
+	// WindowFunc is a single window function call evaluated by a Window operator
+	WindowFunc struct {
+		Original sqlparser.Expr
+
+		// ArgOffset is the offset of the argument of the function on the input, or -1 if there is none
+		ArgOffset int
+
+		// DefaultOffset is the offset of the default value of LAG/LEAD on the input, or -1 if there is none
+		DefaultOffset int
+	}
+)
+
+func newWindow(src Operator, partitionBy []sqlparser.Expr, orderBy []OrderBy) *Window {
+	var order []OrderBy
+	for _, expr := range partitionBy {
+		order = append(order, OrderBy{
+			Inner:          &sqlparser.Order{Expr: expr, Direction: sqlparser.AscOrder},
+			SimplifiedExpr: expr,
+		})
+	}
+	order = append(order, orderBy...)
+	if len(order) > 0 {
+		src = newOrdering(src, order)
+	}
+
+	return &Window{
+		unaryOperator: newUnaryOp(src),
+		PartitionBy:   partitionBy,
+		OrderBy:       orderBy,
+	}
+}
+
+func (w *Window) Clone(inputs []Operator) Operator {
+	klone := *w
+	klone.Source = inputs[0]
+	klone.PartitionBy = slices.Clone(w.PartitionBy)
+	klone.OrderBy = slices.Clone(w.OrderBy)
+	klone.Functions = slice.Map(w.Functions, func(fn *WindowFunc) *WindowFunc {
+		kopy := *fn
+		return &kopy
+	})
+	klone.PartitionOffsets = slices.Clone(w.PartitionOffsets)
+	klone.PartitionWOffsets = slices.Clone(w.PartitionWOffsets)
+	klone.OrderOffsets = slices.Clone(w.OrderOffsets)
+	klone.OrderWOffsets = slices.Clone(w.OrderWOffsets)
+	return &klone
+}
+
+func (w *Window) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	// predicates can't be pushed under a window, since that would change
+	// the rows that are part of each partition
+	return newFilter(w, expr)
+}
+
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, expr *sqlparser.AliasedExpr) int {
+	if offset := w.findFunc(ctx, expr.Expr); offset >= 0 {
+		return offset
+	}
+	return len(w.Functions) + w.Source.AddColumn(ctx, reuse, gb, expr)
+}
+
+func (w *Window) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if offset < len(w.Functions) {
+		panic(vterrors.VT12001(fmt.Sprintf("weight_string of window function '%s'", sqlparser.String(w.Functions[offset].Original))))
+	}
+	return len(w.Functions) + w.Source.AddWSColumn(ctx, offset-len(w.Functions), underRoute)
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	if offset := w.findFunc(ctx, expr); offset >= 0 {
+		return offset
+	}
+	offset := w.Source.FindCol(ctx, expr, underRoute)
+	if offset < 0 {
+		return offset
+	}
+	return len(w.Functions) + offset
+}
+
+func (w *Window) findFunc(ctx *plancontext.PlanningContext, expr sqlparser.Expr) int {
+	for offset, fn := range w.Functions {
+		if ctx.SemTable.EqualsExprWithDeps(fn.Original, expr) {
+			return offset
+		}
+	}
+	return -1
+}
+
+func (w *Window) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	cols := slice.Map(w.Functions, func(fn *WindowFunc) *sqlparser.AliasedExpr {
+		return aeWrap(fn.Original)
+	})
+	return append(cols, w.Source.GetColumns(ctx)...)
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) []sqlparser.SelectExpr {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+func (w *Window) GetOrdering(ctx *plancontext.PlanningContext) []OrderBy {
+	return w.Source.GetOrdering(ctx)
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	for _, expr := range w.PartitionBy {
+		offset, wsOffset := w.addSortColumn(ctx, expr)
+		w.PartitionOffsets = append(w.PartitionOffsets, offset)
+		w.PartitionWOffsets = append(w.PartitionWOffsets, wsOffset)
+	}
+	for _, order := range w.OrderBy {
+		offset, wsOffset := w.addSortColumn(ctx, order.SimplifiedExpr)
+		w.OrderOffsets = append(w.OrderOffsets, offset)
+		w.OrderWOffsets = append(w.OrderWOffsets, wsOffset)
+	}
+
+	for _, fn := range w.Functions {
+		fn.ArgOffset, fn.DefaultOffset = -1, -1
+		if arg := windowFuncArg(fn.Original); arg != nil {
+			fn.ArgOffset = w.Source.AddColumn(ctx, true, false, aeWrap(arg))
+		}
+		if lagLead, ok := fn.Original.(*sqlparser.LagLeadExpr); ok && lagLead.Default != nil {
+			fn.DefaultOffset = w.Source.AddColumn(ctx, true, false, aeWrap(lagLead.Default))
+		}
+	}
+	return nil
+}
+
+func (w *Window) addSortColumn(ctx *plancontext.PlanningContext, expr sqlparser.Expr) (int, int) {
+	offset := w.Source.AddColumn(ctx, true, false, aeWrap(expr))
+	if !ctx.NeedsWeightString(expr) {
+		return offset, -1
+	}
+	return offset, w.Source.AddWSColumn(ctx, offset, false)
+}
+
+func (w *Window) ShortDescription() string {
+	funcs := slice.Map(w.Functions, func(fn *WindowFunc) string {
+		return sqlparser.String(fn.Original)
+	})
+	desc := strings.Join(funcs, ", ")
+	if len(w.PartitionBy) > 0 {
+		desc += " partition by " + strings.Join(slice.Map(w.PartitionBy, func(e sqlparser.Expr) string {
+			return sqlparser.String(e)
+		}), ", ")
+	}
+	if len(w.OrderBy) > 0 {
+		desc += " order by " + strings.Join(slice.Map(w.OrderBy, func(o OrderBy) string {
+			return sqlparser.String(o.Inner)
+		}), ", ")
+	}
+	return desc
+}
+
+// windowFuncArg returns the argument of a window function that needs to be fetched from the input
+func windowFuncArg(expr sqlparser.Expr) sqlparser.Expr {
+	switch fn := expr.(type) {
+	case *sqlparser.FirstOrLastValueExpr:
+		return fn.Expr
+	case *sqlparser.NTHValueExpr:
+		return fn.Expr
+	case *sqlparser.LagLeadExpr:
+		return fn.Expr
+	case *sqlparser.CountStar:
+		return nil
+	case sqlparser.AggrFunc:
+		return fn.GetArg()
+	}
+	return nil
+}
+
+// collectWindowFuncs returns all the distinct window function calls found in the given nodes.
+// Window functions inside subqueries belong to the subquery, and are not returned.
+func collectWindowFuncs(ctx *plancontext.PlanningContext, nodes ...sqlparser.SQLNode) []sqlparser.Expr {
+	var funcs []sqlparser.Expr
+	for _, node := range nodes {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			switch node := node.(type) {
+			case *sqlparser.Subquery:
+				return false, nil
+			case sqlparser.Expr:
+				if !sqlparser.IsWindowFunc(node) {
+					return true, nil
+				}
+				if !slices.ContainsFunc(funcs, func(e sqlparser.Expr) bool {
+					return ctx.SemTable.EqualsExprWithDeps(e, node)
+				}) {
+					funcs = append(funcs, node)
+				}
+				return false, nil
+			}
+			return true, nil
+		}, node)
+	}
+	return funcs
+}
+
+// horizonWindowFuncs returns the window functions used in the SELECT and ORDER BY clauses of a query
+func horizonWindowFuncs(ctx *plancontext.PlanningContext, qp *QueryProjection) []sqlparser.Expr {
+	var nodes []sqlparser.SQLNode
+	for _, expr := range qp.SelectExprs {
+		nodes = append(nodes, expr.Col)
+	}
+	for _, order := range qp.OrderExprs {
+		nodes = append(nodes, order.SimplifiedExpr)
+	}
+	return collectWindowFuncs(ctx, nodes...)
+}
+
+// canPushWindowFuncs returns true if the window functions can be evaluated by MySQL.
+// This is the case when all the rows of every partition are guaranteed to be on the same shard.
+func canPushWindowFuncs(ctx *plancontext.PlanningContext, src Operator, funcs []sqlparser.Expr) bool {
+	if len(funcs) == 0 {
+		return true
+	}
+	rb, isRoute := src.(*Route)
+	if !isRoute {
+		return false
+	}
+	if rb.IsSingleShard() {
+		return true
+	}
+	for _, fn := range funcs {
+		spec := sqlparser.GetOverClause(fn).WindowSpec
+		if spec == nil {
+			return false
+		}
+		partitionedByVindex := slices.ContainsFunc(spec.PartitionClause, func(expr sqlparser.Expr) bool {
+			return exprHasUniqueVindex(ctx, expr)
+		})
+		if !partitionedByVindex {
+			return false
+		}
+	}
+	return true
+}
+
+// planWindowFuncs adds the Window operators needed to evaluate the window functions
+// of the projection at the vtgate level, between the projection and its input.
+func planWindowFuncs(ctx *plancontext.PlanningContext, qp *QueryProjection, op Operator) Operator {
+	proj, ok := op.(*Projection)
+	if !ok {
+		panic(vterrors.VT13001(fmt.Sprintf("expected a projection, got %T", op)))
+	}
+	ap, ok := proj.Columns.(AliasedProjections)
+	if !ok {
+		panic(vterrors.VT09015())
+	}
+
+	for _, order := range qp.OrderExprs {
+		if containsWindowAvg(order.SimplifiedExpr) {
+			panic(vterrors.VT12001("ORDER BY on a windowed AVG on a sharded keyspace"))
+		}
+	}
+
+	var nodes []sqlparser.SQLNode
+	for _, pe := range ap {
+		pe.EvalExpr = splitWindowAvg(ctx, pe.EvalExpr)
+		nodes = append(nodes, pe.EvalExpr)
+	}
+	for _, order := range qp.OrderExprs {
+		nodes = append(nodes, order.SimplifiedExpr)
+	}
+
+	var windows []*Window
+	for _, fn := range collectWindowFuncs(ctx, nodes...) {
+		over := sqlparser.GetOverClause(fn)
+		spec := over.WindowSpec
+		if !over.WindowName.IsEmpty() || spec == nil || !spec.Name.IsEmpty() {
+			panic(vterrors.VT12001("named windows in window functions on a sharded keyspace"))
+		}
+		checkWindowFrame(spec.FrameClause)
+
+		idx := slices.IndexFunc(windows, func(w *Window) bool {
+			return ctx.SemTable.ASTEquals().SliceOfExpr(w.PartitionBy, spec.PartitionClause) &&
+				ctx.SemTable.ASTEquals().OrderBy(windowOrderClause(w), spec.OrderClause)
+		})
+		if idx < 0 {
+			var orderBy []OrderBy
+			for _, order := range spec.OrderClause {
+				orderBy = append(orderBy, OrderBy{Inner: order, SimplifiedExpr: order.Expr})
+			}
+			windows = append(windows, newWindow(nil, spec.PartitionClause, orderBy))
+			idx = len(windows) - 1
+		}
+		windows[idx].Functions = append(windows[idx].Functions, &WindowFunc{
+			Original:      fn,
+			ArgOffset:     -1,
+			DefaultOffset: -1,
+		})
+	}
+
+	// every window is evaluated on top of the previous one, after sorting the input again
+	src := proj.Source
+	for _, w := range windows {
+		if ordering, ok := w.Source.(*Ordering); ok {
+			ordering.Source = src
+		} else {
+			w.Source = src
+		}
+		src = w
+	}
+	proj.Source = src
+	return proj
+}
+
+func windowOrderClause(w *Window) sqlparser.OrderBy {
+	return slice.Map(w.OrderBy, func(o OrderBy) *sqlparser.Order {
+		return o.Inner
+	})
+}
+
+func checkWindowFrame(frame *sqlparser.FrameClause) {
+	if frame == nil || frame.Unit != sqlparser.FrameRangeType {
+		return
+	}
+	for _, point := range []*sqlparser.FramePoint{frame.Start, frame.End} {
+		if point == nil {
+			continue
+		}
+		if point.Type == sqlparser.ExprPrecedingType || point.Type == sqlparser.ExprFollowingType {
+			panic(vterrors.VT12001("RANGE frame with an offset in window functions on a sharded keyspace"))
+		}
+	}
+}
+
+func containsWindowAvg(expr sqlparser.Expr) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if avg, ok := node.(*sqlparser.Avg); ok && avg.OverClause != nil {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
+// splitWindowAvg rewrites AVG window functions into a division between
+// a SUM and a COUNT window function over the same window
+func splitWindowAvg(ctx *plancontext.PlanningContext, expr sqlparser.Expr) sqlparser.Expr {
+	return sqlparser.CopyOnRewrite(expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		avg, ok := cursor.Node().(*sqlparser.Avg)
+		if !ok || avg.OverClause == nil {
+			return
+		}
+		if avg.Distinct {
+			panic(vterrors.VT12001("AVG(distinct <>)"))
+		}
+		sumExpr := &sqlparser.Sum{Arg: avg.Arg, OverClause: avg.OverClause}
+		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}, OverClause: sqlparser.Clone(avg.OverClause)}
+		// the new expressions depend on the same tables as the AVG, but have different types
+		for _, e := range []sqlparser.Expr{sumExpr, countExpr} {
+			ctx.SemTable.Recursive[e] = ctx.SemTable.RecursiveDeps(avg)
+			ctx.SemTable.Direct[e] = ctx.SemTable.DirectDeps(avg)
+		}
+		cursor.Replace(&sqlparser.BinaryExpr{
+			Operator: sqlparser.DivOp,
+			Left:     sumExpr,
+			Right:    countExpr,
+		})
+	}, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+}
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"strings"
 	"testing"
 
@@ -80,7 +81,7 @@ func (s *planTestSuite) TestPlan() {
 	vw, err := vschemawrapper.NewVschemaWrapper(env, vschema, TestBuilder)
 	require.NoError(s.T(), err)
 
-	s.addPKs(vschema, "user", []string{"user", "music"})
+	s.addPKs(vschema, "user", []string{"user", "music", "movable_user"})
 	s.addPKsProvided(vschema, "user", []string{"user_extra"}, []string{"id", "user_id"})
 	s.addPKsProvided(vschema, "ordering", []string{"order"}, []string{"oid", "region_id"})
 	s.addPKsProvided(vschema, "ordering", []string{"order_event"}, []string{"oid", "ename"})
@@ -300,7 +301,7 @@ func (s *planTestSuite) TestOne() {
 	require.NoError(s.T(), err)
 
 	s.setFks(vschema)
-	s.addPKs(vschema, "user", []string{"user", "music"})
+	s.addPKs(vschema, "user", []string{"user", "music", "movable_user"})
 	s.addPKs(vschema, "main", []string{"unsharded"})
 	s.addPKsProvided(vschema, "user", []string{"user_extra"}, []string{"id", "user_id"})
 	s.addPKsProvided(vschema, "ordering", []string{"order"}, []string{"oid", "region_id"})
@@ -618,6 +619,15 @@ func loadSchema(t testing.TB, filename string, setCollation bool) *vindexes.VSch
 			require.NoError(t, err)
 			err = vschema.AddUDF(ks.Keyspace.Name, "udf_aggr")
 			require.NoError(t, err)
+
+			// generated columns only come from the schema tracker, not from the vschema
+			for _, name := range []string{"gen_col_tbl", "gen_vindex_tbl"} {
+				tbl, ok := ks.Tables[name]
+				if !ok {
+					continue
+				}
+				tbl.Columns[slices.IndexFunc(tbl.Columns, func(col vindexes.Column) bool { return col.Name.EqualString("val_x2") })].Generated = true
+			}
 		}
 
 		// setting a default value to all the text columns in the tables of this keyspace
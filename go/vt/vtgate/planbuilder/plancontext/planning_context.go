@@ -360,7 +360,8 @@ func (ctx *PlanningContext) IsAggr(e sqlparser.SQLNode) bool {
 	case sqlparser.AggrFunc:
 		return true
 	case *sqlparser.FuncExpr:
-		return node.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
+		// GROUPING() is not an aggregation, but it can only be evaluated together with the aggregation
+		return sqlparser.IsGroupingFunc(node) || node.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
 	}
 
 	return false
@@ -374,6 +375,11 @@ func (ctx *PlanningContext) ContainsAggr(e sqlparser.SQLNode) (hasAggr bool) {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case sqlparser.AggrFunc:
+			if sqlparser.IsWindowFunc(node) {
+				// aggregate functions with an OVER clause are window functions,
+				// they do not group rows, but their arguments might
+				return true, nil
+			}
 			hasAggr = true
 			return false, io.EOF
 		case *sqlparser.Subquery:
@@ -53,10 +53,36 @@ CREATE TABLE name_user_vdx
 
 CREATE TABLE samecolvin
 (
-    col VARCHAR(255),
+    col    VARCHAR(255),
+    secret VARCHAR(255) INVISIBLE,
     PRIMARY KEY (col)
 );
 
+CREATE TABLE movable_user
+(
+    id   BIGINT,
+    name VARCHAR(255),
+    val  BIGINT,
+    col  BIGINT,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE gen_col_tbl
+(
+    id     BIGINT,
+    val    BIGINT,
+    val_x2 BIGINT AS (val * 2) STORED,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE gen_vindex_tbl
+(
+    id     BIGINT,
+    val    BIGINT,
+    val_x2 BIGINT AS (val * 2) VIRTUAL,
+    PRIMARY KEY (id)
+);
+
 CREATE TABLE multicolvin
 (
     kid      INT,
@@ -73,6 +73,9 @@ var (
 	mysqlSlowConnectWarnThreshold time.Duration
 	mysqlConnBufferPooling        bool
 
+	mysqlCompressionAlgorithms []string
+	mysqlZlibCompressionLevel  = mysql.DefaultZlibCompressionLevel
+
 	mysqlDefaultWorkloadName = "OLTP"
 	mysqlDefaultWorkload     int32
 	mysqlDrainOnTerm         bool
@@ -85,7 +88,7 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&mysqlServerBindAddress, "mysql_server_bind_address", mysqlServerBindAddress, "Binds on this address when listening to MySQL binary protocol. Useful to restrict listening to 'localhost' only for instance.")
 	fs.StringVar(&mysqlServerSocketPath, "mysql_server_socket_path", mysqlServerSocketPath, "This option specifies the Unix socket file to use when listening for local connections. By default it will be empty and it won't listen to a unix socket")
 	fs.StringVar(&mysqlTCPVersion, "mysql_tcp_version", mysqlTCPVersion, "Select tcp, tcp4, or tcp6 to control the socket type.")
-	fs.StringVar(&mysqlAuthServerImpl, "mysql_auth_server_impl", mysqlAuthServerImpl, "Which auth server implementation to use. Options: none, ldap, clientcert, static, vault.")
+	fs.StringVar(&mysqlAuthServerImpl, "mysql_auth_server_impl", mysqlAuthServerImpl, "Which auth server implementation to use. Options: none, ldap, clientcert, static, vault, jwt.")
 	fs.BoolVar(&mysqlAllowClearTextWithoutTLS, "mysql_allow_clear_text_without_tls", mysqlAllowClearTextWithoutTLS, "If set, the server will allow the use of a clear text password over non-SSL connections.")
 	fs.BoolVar(&mysqlProxyProtocol, "proxy_protocol", mysqlProxyProtocol, "Enable HAProxy PROXY protocol on MySQL listener socket")
 	fs.BoolVar(&mysqlServerRequireSecureTransport, "mysql_server_require_secure_transport", mysqlServerRequireSecureTransport, "Reject insecure connections but only if mysql_server_ssl_cert and mysql_server_ssl_key are provided")
@@ -101,6 +104,8 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&mysqlQueryTimeout, "mysql_server_query_timeout", mysqlQueryTimeout, "mysql query timeout")
 	fs.BoolVar(&mysqlConnBufferPooling, "mysql-server-pool-conn-read-buffers", mysqlConnBufferPooling, "If set, the server will pool incoming connection read buffers")
 	fs.DurationVar(&mysqlKeepAlivePeriod, "mysql-server-keepalive-period", mysqlKeepAlivePeriod, "TCP period between keep-alives")
+	fs.StringSliceVar(&mysqlCompressionAlgorithms, "mysql-server-compression-algorithms", mysqlCompressionAlgorithms, "Compression algorithms of the MySQL protocol the server allows on TCP connections. Options: zlib, zstd. Connections are not compressed if not set.")
+	fs.IntVar(&mysqlZlibCompressionLevel, "mysql-server-zlib-compression-level", mysqlZlibCompressionLevel, "Compression level of the responses sent to clients using zlib compression. With zstd, the level requested by the client is used.")
 	fs.DurationVar(&mysqlServerFlushDelay, "mysql_server_flush_delay", mysqlServerFlushDelay, "Delay after which buffered response will be flushed to the client.")
 	fs.StringVar(&mysqlDefaultWorkloadName, "mysql_default_workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
 	fs.BoolVar(&mysqlDrainOnTerm, "mysql-server-drain-onterm", mysqlDrainOnTerm, "If set, the server waits for --onterm_timeout for already connected clients to complete their in flight work")
@@ -138,8 +143,13 @@ func (vh *vtgateHandler) numConnections() int {
 }
 
 func (vh *vtgateHandler) ComResetConnection(c *mysql.Conn) {
+	vh.closeSession(vh.session(c))
+}
+
+// closeSession rolls back the transaction of the session and releases its
+// reserved connections.
+func (vh *vtgateHandler) closeSession(session *vtgatepb.Session) {
 	ctx := context.Background()
-	session := vh.session(c)
 	if session.InTransaction {
 		defer vh.busyConnections.Add(-1)
 	}
@@ -149,6 +159,25 @@ func (vh *vtgateHandler) ComResetConnection(c *mysql.Conn) {
 	}
 }
 
+// ComChangeUser gives the new user a new default session. The previous session
+// is only closed once the change is done, so that it is kept as it was, with
+// any open transaction, if the new user cannot use the requested schema. The
+// caller IDs used by the table ACLs are derived from the connection user on
+// every query.
+func (vh *vtgateHandler) ComChangeUser(c *mysql.Conn) func(success bool) {
+	prevSession := vh.session(c)
+	c.ClientData = nil
+	return func(success bool) {
+		if !success {
+			// the new session was only used to try the new schema
+			vh.closeSession(vh.session(c))
+			c.ClientData = prevSession
+			return
+		}
+		vh.closeSession(prevSession)
+	}
+}
+
 func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
 	// Rollback if there is an ongoing transaction. Ignore error.
 	defer func() {
@@ -250,6 +279,8 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		}
 	}()
 
+	session.Options.QueryAttributes = c.QueryAttributes
+
 	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
 		session, err := vh.vtg.StreamExecute(ctx, vh, session, query, make(map[string]*querypb.BindVariable), callback)
 		if err != nil {
@@ -356,7 +387,11 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		}
 	}()
 
-	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
+	session.Options.QueryAttributes = c.QueryAttributes
+
+	// With a cursor, the client fetches the rows in batches, so they are
+	// streamed instead of buffered.
+	if session.Options.Workload == querypb.ExecuteOptions_OLAP || prepare.CursorType != mysql.CursorTypeNoCursor {
 		_, err := vh.vtg.StreamExecute(ctx, vh, session, prepare.PrepareStmt, prepare.BindVars, callback)
 		if err != nil {
 			return sqlerror.NewSQLErrorFromError(err)
@@ -550,6 +585,10 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
 		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		srv.tcpListener.CompressionAlgorithms, srv.tcpListener.ZlibCompressionLevel, err = parseCompressionFlags(mysqlCompressionAlgorithms, mysqlZlibCompressionLevel)
+		if err != nil {
+			log.Exitf("mysql.NewListener failed: %v", err)
+		}
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
@@ -568,6 +607,25 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 	return srv
 }
 
+// parseCompressionFlags validates the compression algorithms and level of the mysql server flags.
+func parseCompressionFlags(names []string, zlibLevel int) ([]mysql.CompressionAlgorithm, int, error) {
+	var algorithms []mysql.CompressionAlgorithm
+	for _, name := range names {
+		algorithm, err := mysql.ParseCompressionAlgorithm(strings.TrimSpace(name))
+		if err != nil {
+			return nil, 0, err
+		}
+		if algorithm != mysql.CompressionNone {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	zlibLevel, err := mysql.CompressionLevel(mysql.CompressionZlib, zlibLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	return algorithms, zlibLevel, nil
+}
+
 // newMysqlUnixSocket creates a new unix socket mysql listener. If a socket file already exists, attempts
 // to clean it up.
 func newMysqlUnixSocket(address string, authServer mysql.AuthServer, handler mysql.Handler) (*mysql.Listener, error) {
@@ -36,6 +36,7 @@ import (
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/trace"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/tlstest"
 	"vitess.io/vitess/go/vt/vtenv"
 )
@@ -267,6 +268,24 @@ func TestDefaultWorkloadOLAP(t *testing.T) {
 	}
 }
 
+func TestParseCompressionFlags(t *testing.T) {
+	algorithms, level, err := parseCompressionFlags([]string{"zstd", " zlib"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []mysql.CompressionAlgorithm{mysql.CompressionZstd, mysql.CompressionZlib}, algorithms)
+	assert.Equal(t, mysql.DefaultZlibCompressionLevel, level)
+
+	algorithms, level, err = parseCompressionFlags(nil, 9)
+	require.NoError(t, err)
+	assert.Empty(t, algorithms)
+	assert.Equal(t, 9, level)
+
+	_, _, err = parseCompressionFlags([]string{"lz4"}, 0)
+	assert.ErrorContains(t, err, "unknown compression algorithm: lz4")
+
+	_, _, err = parseCompressionFlags([]string{"zlib"}, 10)
+	assert.ErrorContains(t, err, "compression level 10 is not valid for zlib")
+}
+
 func TestInitTLSConfigWithoutServerCA(t *testing.T) {
 	testInitTLSConfig(t, false)
 }
@@ -422,3 +441,104 @@ func TestGracefulShutdownWithTransaction(t *testing.T) {
 
 	require.True(t, mysqlConn.IsMarkedForClose())
 }
+
+func TestComChangeUser(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.connections[1] = mysqlConn
+
+	err = vh.ComQuery(mysqlConn, "BEGIN", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	session := vh.session(mysqlConn)
+	require.True(t, session.InTransaction)
+	require.EqualValues(t, 1, vh.busyConnections.Load())
+
+	// A failed change keeps the previous session with its transaction.
+	done := vh.ComChangeUser(mysqlConn)
+	assert.NotSame(t, session, vh.session(mysqlConn))
+	done(false)
+	assert.Same(t, session, vh.session(mysqlConn))
+	assert.True(t, session.InTransaction)
+	assert.EqualValues(t, 1, vh.busyConnections.Load())
+
+	done = vh.ComChangeUser(mysqlConn)
+	done(true)
+	assert.False(t, session.InTransaction)
+	assert.EqualValues(t, 0, vh.busyConnections.Load())
+
+	// The new user gets a new session.
+	newSession := vh.session(mysqlConn)
+	assert.NotSame(t, session, newSession)
+	assert.NotEqual(t, session.SessionUUID, newSession.SessionUUID)
+	assert.False(t, newSession.InTransaction)
+}
+
+func TestComStmtExecuteWithCursor(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	mysqlConn := mysql.GetTestConn()
+	mysqlConn.UserData = &mysql.StaticUserData{}
+
+	prepare := &mysql.PrepareData{
+		PrepareStmt: "select id from user where id = 1",
+		BindVars:    map[string]*querypb.BindVariable{},
+		CursorType:  mysql.CursorTypeReadOnly,
+	}
+	var results []*sqltypes.Result
+	err := vh.ComStmtExecute(mysqlConn, prepare, func(result *sqltypes.Result) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The results are streamed, so the fields come first on their own.
+	require.Len(t, results, 2)
+	assert.NotEmpty(t, results[0].Fields)
+	assert.Empty(t, results[0].Rows)
+	assert.Empty(t, results[1].Fields)
+	assert.NotEmpty(t, results[1].Rows)
+}
+
+func TestComQueryWithQueryAttributes(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.UserData = &mysql.StaticUserData{}
+
+	mysqlConn.QueryAttributes = map[string]string{sqlparser.QueryAttributeWorkloadName: "checkout", sqlparser.QueryAttributePriority: "20"}
+	err = vh.ComQuery(mysqlConn, "select id from user", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	session := vh.session(mysqlConn)
+	assert.Equal(t, mysqlConn.QueryAttributes, session.Options.QueryAttributes)
+	assert.Equal(t, "checkout", session.Options.WorkloadName)
+	assert.Equal(t, "20", session.Options.Priority)
+
+	// The query attributes only apply to the query that sent them.
+	mysqlConn.QueryAttributes = nil
+	err = vh.ComQuery(mysqlConn, "select id from user", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Nil(t, session.Options.QueryAttributes)
+	assert.Empty(t, session.Options.Priority)
+}
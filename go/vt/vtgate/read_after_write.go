@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+)
+
+// In read_after_write_auto mode, vtgate records the position of a shard's
+// primary in the session after each write to that shard. Later reads of the
+// shard that target another tablet type are then routed, in order of
+// preference, to:
+//  1. a tablet whose health-streamed replication position covers the recorded one,
+//  2. any tablet, which waits for the position for up to read_after_write_timeout,
+//  3. the primary.
+//
+// The positions travel with the session, so they are honored across shards
+// and across vtgates.
+
+// shardPositionQuery returns the set of GTIDs executed by the primary.
+const shardPositionQuery = "select @@global.gtid_executed"
+
+type readAfterWritePositionKey struct{}
+
+// withReadAfterWritePosition returns a context that restricts the tablets the
+// gateway picks to those that have replicated up to the given position.
+func withReadAfterWritePosition(ctx context.Context, pos replication.Position) context.Context {
+	return context.WithValue(ctx, readAfterWritePositionKey{}, pos)
+}
+
+func readAfterWritePositionFromContext(ctx context.Context) (replication.Position, bool) {
+	pos, ok := ctx.Value(readAfterWritePositionKey{}).(replication.Position)
+	return pos, ok
+}
+
+// tabletsAtPosition returns the tablets whose last reported replication
+// position is at least the given position.
+func tabletsAtPosition(tablets []*discovery.TabletHealth, pos replication.Position) []*discovery.TabletHealth {
+	var res []*discovery.TabletHealth
+	for _, th := range tablets {
+		encoded := th.Stats.GetReplicationPosition()
+		if encoded == "" {
+			continue
+		}
+		tabletPos, err := replication.DecodePosition(encoded)
+		if err != nil {
+			continue
+		}
+		if tabletPos.AtLeast(pos) {
+			res = append(res, th)
+		}
+	}
+	return res
+}
+
+// hasTabletAtPosition returns true if a healthy tablet for the target has
+// replicated up to the given position.
+func (gw *TabletGateway) hasTabletAtPosition(target *querypb.Target, pos replication.Position) bool {
+	return len(tabletsAtPosition(gw.hc.GetHealthyTabletStats(target), pos)) > 0
+}
+
+// readAfterWrite adjusts a read of a non-primary tablet so that it observes
+// the writes the session made to the shard. It returns the context, the shard
+// and the options to execute the read with.
+func (stc *ScatterConn) readAfterWrite(ctx context.Context, rs *srvtopo.ResolvedShard, session *econtext.SafeSession, info *shardActionInfo, opts *querypb.ExecuteOptions) (context.Context, *srvtopo.ResolvedShard, *querypb.ExecuteOptions) {
+	target := rs.Target
+	if target.TabletType == topodatapb.TabletType_PRIMARY || info.actionNeeded != nothing || info.transactionID != 0 || info.reservedID != 0 {
+		return ctx, rs, opts
+	}
+	encoded, ok := session.GetShardPosition(target.Keyspace, target.Shard)
+	if !ok {
+		return ctx, rs, opts
+	}
+	if encoded != "" {
+		pos, err := replication.DecodePosition(encoded)
+		if err == nil {
+			if stc.gateway != nil && stc.gateway.hasTabletAtPosition(target, pos) {
+				return withReadAfterWritePosition(ctx, pos), rs, opts
+			}
+			if timeout := session.GetReadAfterWriteTimeout(); timeout > 0 {
+				if opts != nil {
+					opts = opts.CloneVT()
+				} else {
+					opts = &querypb.ExecuteOptions{}
+				}
+				opts.WaitForPosition = encoded
+				opts.WaitForPositionTimeout = timeout
+				return ctx, rs, opts
+			}
+		}
+	}
+	primaryTarget := target.CloneVT()
+	primaryTarget.TabletType = topodatapb.TabletType_PRIMARY
+	return ctx, &srvtopo.ResolvedShard{Target: primaryTarget, Gateway: rs.Gateway}, opts
+}
+
+// recordShardPosition stores the position of the shard's primary in the
+// session, after a write was committed to it. The position is taken from the
+// GTIDs that session_track_gtids added to the result of the write; the primary
+// is only queried for its position when they are missing, e.g. for commits or
+// when session tracking is disabled in MySQL. If the position cannot be read,
+// an empty position is stored so that reads go to the primary.
+func recordShardPosition(ctx context.Context, qs queryservice.QueryService, target *querypb.Target, session *econtext.SafeSession, qr *sqltypes.Result) {
+	if target.TabletType != topodatapb.TabletType_PRIMARY || !session.ReadAfterWriteAuto() {
+		return
+	}
+	position, err := trackedShardPosition(qr)
+	if position == "" {
+		position, err = queryShardPosition(ctx, qs, target)
+	}
+	if err != nil {
+		log.Warningf("Unable to read the position of %s/%s, reads will be sent to the primary: %v", target.Keyspace, target.Shard, err)
+	}
+	session.SetShardPosition(target.Keyspace, target.Shard, position)
+}
+
+// trackedShardPosition returns the position made of the GTIDs that MySQL
+// tracked in the session state of the write's result. This is enough for reads
+// to observe the write, since a replica that applied these GTIDs has the write.
+func trackedShardPosition(qr *sqltypes.Result) (string, error) {
+	if qr == nil || qr.SessionStateChanges == "" {
+		return "", nil
+	}
+	gtidSet, err := replication.ParseMysql56GTIDSet(qr.SessionStateChanges)
+	if err != nil {
+		return "", err
+	}
+	return replication.EncodePosition(replication.Position{GTIDSet: gtidSet}), nil
+}
+
+func queryShardPosition(ctx context.Context, qs queryservice.QueryService, target *querypb.Target) (string, error) {
+	qr, err := qs.Execute(ctx, target, shardPositionQuery, nil, 0, 0, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 {
+		return "", vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for %s: %v", shardPositionQuery, qr.Rows)
+	}
+	gtidSet, err := replication.ParseMysql56GTIDSet(qr.Rows[0][0].ToString())
+	if err != nil {
+		return "", err
+	}
+	return replication.EncodePosition(replication.Position{GTIDSet: gtidSet}), nil
+}
+
+// recordCommitPositions records the position of every shard the committed
+// transaction wrote to. The commit does not return the GTIDs it produced, so
+// the primaries are queried for their positions, all at the same time.
+func (txc *TxConn) recordCommitPositions(ctx context.Context, session *econtext.SafeSession) {
+	if !session.ReadAfterWriteAuto() || txc.tabletGateway == nil {
+		return
+	}
+	var targets []*querypb.Target
+	for _, sessions := range [][]*vtgatepb.Session_ShardSession{session.PreSessions, session.ShardSessions, session.PostSessions} {
+		for _, shardSession := range sessions {
+			if shardSession.RowsAffected {
+				targets = append(targets, shardSession.Target)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	_ = txc.runTargets(targets, func(target *querypb.Target) error {
+		recordShardPosition(ctx, txc.tabletGateway, target, session, nil)
+		return nil
+	})
+}
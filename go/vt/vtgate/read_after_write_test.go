@@ -0,0 +1,213 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/srvtopo"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+const (
+	rawGTIDSet       = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+	rawOldGTIDSet    = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-3"
+	shardPosition    = "MySQL56/" + rawGTIDSet
+	oldShardPosition = "MySQL56/" + rawOldGTIDSet
+)
+
+func positionResult(gtidSet string) *sqltypes.Result {
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields("@@global.gtid_executed", "varchar"), gtidSet)
+}
+
+func setReplicationPosition(t *testing.T, hc *discovery.FakeHealthCheck, sbc *sandboxconn.SandboxConn, position string) {
+	t.Helper()
+	th, err := hc.GetTabletHealthByAlias(sbc.Tablet().Alias)
+	require.NoError(t, err)
+	th.Stats.ReplicationPosition = position
+}
+
+func TestReadAfterWriteAuto(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	keyspace := "TestReadAfterWriteAuto"
+	createSandbox(keyspace)
+	hc := discovery.NewFakeHealthCheck(nil)
+	sc := newTestScatterConn(ctx, hc, newSandboxForCells(ctx, []string{"aa"}), "aa")
+	primary := hc.AddTestTablet("aa", "0", 1, keyspace, "0", topodatapb.TabletType_PRIMARY, true, 1, nil)
+	replica1 := hc.AddTestTablet("aa", "1", 1, keyspace, "0", topodatapb.TabletType_REPLICA, true, 1, nil)
+	replica2 := hc.AddTestTablet("aa", "2", 1, keyspace, "0", topodatapb.TabletType_REPLICA, true, 1, nil)
+
+	res := srvtopo.NewResolver(newSandboxForCells(ctx, []string{"aa"}), sc.gateway, "aa")
+	primaryRss, err := res.ResolveDestination(ctx, keyspace, topodatapb.TabletType_PRIMARY, key.DestinationShard("0"))
+	require.NoError(t, err)
+	replicaRss, err := res.ResolveDestination(ctx, keyspace, topodatapb.TabletType_REPLICA, key.DestinationShard("0"))
+	require.NoError(t, err)
+
+	session := econtext.NewSafeSession(&vtgatepb.Session{Autocommit: true})
+	read := func() {
+		t.Helper()
+		primary.ExecCount.Store(0)
+		replica1.ExecCount.Store(0)
+		replica2.ExecCount.Store(0)
+		primary.Options, replica1.Options, replica2.Options = nil, nil, nil
+		_, errs := sc.ExecuteMultiShard(ctx, nil, replicaRss, []*querypb.BoundQuery{{Sql: "select 1"}}, session, false, false, nullResultsObserver{}, false)
+		require.Empty(t, errs)
+	}
+
+	// Without auto mode, writes neither read nor record a position.
+	primary.SetResults([]*sqltypes.Result{{RowsAffected: 1}})
+	_, errs := sc.ExecuteMultiShard(ctx, nil, primaryRss, []*querypb.BoundQuery{{Sql: "update t set a = 1"}}, session, true, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	_, ok := session.GetShardPosition(keyspace, "0")
+	assert.False(t, ok)
+	require.Len(t, primary.Queries, 1)
+	assert.Equal(t, "update t set a = 1", primary.Queries[0].Sql)
+
+	// An autocommitted write records the position of the primary.
+	session.SetReadAfterWriteAuto(true)
+	primary.SetResults([]*sqltypes.Result{{RowsAffected: 1}, positionResult(rawGTIDSet)})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, primaryRss, []*querypb.BoundQuery{{Sql: "update t set a = 1"}}, session, true, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	position, ok := session.GetShardPosition(keyspace, "0")
+	require.True(t, ok)
+	assert.Equal(t, shardPosition, position)
+	assert.Equal(t, shardPositionQuery, primary.Queries[len(primary.Queries)-1].Sql)
+
+	// The GTIDs tracked in the result of the write are used without querying the primary.
+	primary.Queries = nil
+	primary.SetResults([]*sqltypes.Result{{RowsAffected: 1, SessionStateChanges: rawGTIDSet}})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, primaryRss, []*querypb.BoundQuery{{Sql: "update t set a = 1"}}, session, true, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	position, ok = session.GetShardPosition(keyspace, "0")
+	require.True(t, ok)
+	assert.Equal(t, shardPosition, position)
+	require.Len(t, primary.Queries, 1)
+	assert.Equal(t, "update t set a = 1", primary.Queries[0].Sql)
+
+	// No replica has caught up, so the read goes to the primary.
+	setReplicationPosition(t, hc, replica1, oldShardPosition)
+	read()
+	assert.EqualValues(t, 1, primary.ExecCount.Load())
+	assert.EqualValues(t, 0, replica1.ExecCount.Load()+replica2.ExecCount.Load())
+
+	// Only the replica that caught up is used.
+	setReplicationPosition(t, hc, replica2, shardPosition)
+	for range 10 {
+		read()
+		assert.EqualValues(t, 0, primary.ExecCount.Load())
+		assert.EqualValues(t, 0, replica1.ExecCount.Load())
+		assert.EqualValues(t, 1, replica2.ExecCount.Load())
+	}
+
+	// With a timeout, a replica that did not catch up waits for the position.
+	setReplicationPosition(t, hc, replica2, oldShardPosition)
+	session.SetReadAfterWriteTimeout(2)
+	read()
+	assert.EqualValues(t, 0, primary.ExecCount.Load())
+	options := append(replica1.Options, replica2.Options...)
+	require.Len(t, options, 1)
+	assert.Equal(t, shardPosition, options[0].WaitForPosition)
+	assert.EqualValues(t, 2, options[0].WaitForPositionTimeout)
+	assert.Nil(t, session.Options, "the session options must not carry the position")
+
+	// A write whose position cannot be read sends reads to the primary.
+	session.SetReadAfterWriteTimeout(0)
+	primary.SetResults([]*sqltypes.Result{{RowsAffected: 1}, {}})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, primaryRss, []*querypb.BoundQuery{{Sql: "update t set a = 1"}}, session, true, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	position, ok = session.GetShardPosition(keyspace, "0")
+	require.True(t, ok)
+	assert.Empty(t, position)
+	setReplicationPosition(t, hc, replica2, shardPosition)
+	read()
+	assert.EqualValues(t, 1, primary.ExecCount.Load())
+
+	// Disabling auto mode forgets the positions.
+	session.SetReadAfterWriteAuto(false)
+	_, ok = session.GetShardPosition(keyspace, "0")
+	assert.False(t, ok)
+	read()
+	assert.EqualValues(t, 0, primary.ExecCount.Load())
+	assert.EqualValues(t, 1, replica1.ExecCount.Load()+replica2.ExecCount.Load())
+}
+
+func TestReadAfterWriteAutoCommit(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, sbc1, _, _, rss01 := newTestTxConnEnv(t, ctx, "TestReadAfterWriteAutoCommit")
+	session := econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	session.SetReadAfterWriteAuto(true)
+
+	sbc0.SetResults([]*sqltypes.Result{{RowsAffected: 1}, positionResult(rawGTIDSet)})
+	sbc1.SetResults([]*sqltypes.Result{{RowsAffected: 0}})
+	_, errs := sc.ExecuteMultiShard(ctx, nil, rss01, twoQueries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+
+	// Nothing is recorded before the commit.
+	_, ok := session.GetShardPosition("TestReadAfterWriteAutoCommit", "0")
+	assert.False(t, ok)
+
+	require.NoError(t, sc.txConn.Commit(ctx, session))
+	assert.EqualValues(t, 2, sbc0.ExecCount.Load())
+
+	// Only the shard the transaction wrote to is recorded.
+	position, ok := session.GetShardPosition("TestReadAfterWriteAutoCommit", "0")
+	require.True(t, ok)
+	assert.Equal(t, shardPosition, position)
+	_, ok = session.GetShardPosition("TestReadAfterWriteAutoCommit", "1")
+	assert.False(t, ok)
+	assert.EqualValues(t, 1, sbc1.ExecCount.Load())
+
+	// The positions of all the shards the transaction wrote to are read.
+	sbc0.ExecCount.Store(0)
+	sbc1.ExecCount.Store(0)
+	sbc0.SetResults([]*sqltypes.Result{{RowsAffected: 1}, positionResult(rawGTIDSet)})
+	sbc1.SetResults([]*sqltypes.Result{{RowsAffected: 1}, positionResult(rawOldGTIDSet)})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss01, twoQueries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	require.NoError(t, sc.txConn.Commit(ctx, session))
+	position, ok = session.GetShardPosition("TestReadAfterWriteAutoCommit", "0")
+	require.True(t, ok)
+	assert.Equal(t, shardPosition, position)
+	position, ok = session.GetShardPosition("TestReadAfterWriteAutoCommit", "1")
+	require.True(t, ok)
+	assert.Equal(t, oldShardPosition, position)
+	assert.EqualValues(t, 2, sbc0.ExecCount.Load())
+	assert.EqualValues(t, 2, sbc1.ExecCount.Load())
+
+	// Without auto mode, the commit does not read the positions.
+	session = econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	sbc0.ExecCount.Store(0)
+	sbc0.SetResults([]*sqltypes.Result{{RowsAffected: 1}})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss01[:1], twoQueries[:1], session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	require.NoError(t, sc.txConn.Commit(ctx, session))
+	assert.EqualValues(t, 1, sbc0.ExecCount.Load())
+}
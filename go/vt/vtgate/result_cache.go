@@ -0,0 +1,381 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vthash"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// resultCacheEntryOverhead approximates the memory used by the bookkeeping of
+// an entry, on top of the size of its result.
+const resultCacheEntryOverhead = 128
+
+// resultCache is an LRU cache of the results of SELECT queries that opted in
+// with the RESULT_CACHE_TTL directive. Its capacity is in bytes. Entries
+// expire after their TTL, and are invalidated when one of the tables they
+// read from changes.
+//
+// A query may read a table before it is invalidated, and cache its result
+// after. So every invalidation increments the generation of the cache, and
+// records it for the tables or the keyspace it invalidated: a result is only
+// cached if none of its tables were invalidated since the generation that
+// was current when its query started.
+type resultCache struct {
+	mu sync.Mutex
+
+	// list & entries contain *resultCacheEntry objects.
+	list    *list.List
+	entries map[PlanCacheKey]*list.Element
+	// tables maps a keyspace qualified table name to the keys of the
+	// entries that read from it.
+	tables map[string]map[PlanCacheKey]struct{}
+
+	// generation is incremented by every invalidation.
+	generation uint64
+	// tableGenerations and keyspaceGenerations are the generations of the
+	// last invalidations of the tables and of the keyspaces.
+	tableGenerations    map[string]uint64
+	keyspaceGenerations map[string]uint64
+
+	size          int64
+	capacity      int64
+	evictions     int64
+	invalidations int64
+	hits          int64
+	misses        int64
+
+	now func() time.Time
+	// watch and unwatch, if set, are called with mu held when the cache
+	// starts and stops holding results that read from a table, so that the
+	// changes to the table can be invalidated.
+	watch   func(tables []string)
+	unwatch func(table string)
+	// dropped are the tables whose last entry was removed while mu is held.
+	dropped []string
+}
+
+type resultCacheEntry struct {
+	key     PlanCacheKey
+	result  *sqltypes.Result
+	tables  []string
+	size    int64
+	expires time.Time
+}
+
+// newResultCache creates an empty result cache of the given capacity in bytes.
+func newResultCache(capacity int64) *resultCache {
+	return &resultCache{
+		list:     list.New(),
+		entries:  make(map[PlanCacheKey]*list.Element),
+		tables:   make(map[string]map[PlanCacheKey]struct{}),
+		capacity: capacity,
+		now:      time.Now,
+
+		tableGenerations:    make(map[string]uint64),
+		keyspaceGenerations: make(map[string]uint64),
+	}
+}
+
+// Get returns a copy of the cached result for the key, if it has not expired.
+func (rc *resultCache) Get(key PlanCacheKey) (*sqltypes.Result, bool) {
+	rc.mu.Lock()
+	defer rc.unlock()
+
+	element := rc.entries[key]
+	if element == nil {
+		rc.misses++
+		return nil, false
+	}
+	entry := element.Value.(*resultCacheEntry)
+	if !rc.now().Before(entry.expires) {
+		rc.remove(element)
+		rc.misses++
+		return nil, false
+	}
+	rc.list.MoveToFront(element)
+	rc.hits++
+	return entry.result.Copy(), true
+}
+
+// Generation returns the current generation of the cache, which is to be
+// passed to Set for the result of a query that starts now.
+func (rc *resultCache) Generation() uint64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.generation
+}
+
+// Set caches a copy of the result of a query that read from the given keyspace
+// qualified tables, for the duration of the ttl. Results that do not fit in
+// the cache, or that read from a table that was invalidated since the given
+// generation, are ignored.
+func (rc *resultCache) Set(key PlanCacheKey, result *sqltypes.Result, tables []string, ttl time.Duration, generation uint64) {
+	entry := &resultCacheEntry{
+		key:     key,
+		result:  result.Copy(),
+		tables:  tables,
+		expires: rc.now().Add(ttl),
+	}
+	entry.size = entry.result.CachedSize(true) + resultCacheEntryOverhead
+	if entry.size > rc.capacity {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.unlock()
+
+	if rc.invalidatedSince(tables, generation) {
+		return
+	}
+	if element := rc.entries[key]; element != nil {
+		rc.remove(element)
+	}
+	rc.entries[key] = rc.list.PushFront(entry)
+	var added []string
+	for _, table := range tables {
+		keys := rc.tables[table]
+		if keys == nil {
+			keys = make(map[PlanCacheKey]struct{})
+			rc.tables[table] = keys
+			added = append(added, table)
+		}
+		keys[key] = struct{}{}
+	}
+	rc.size += entry.size
+	for rc.size > rc.capacity {
+		rc.remove(rc.list.Back())
+		rc.evictions++
+	}
+	if rc.watch != nil && len(added) > 0 {
+		rc.watch(added)
+	}
+}
+
+// InvalidateTables removes the entries that read from any of the given
+// keyspace qualified tables.
+func (rc *resultCache) InvalidateTables(tables ...string) {
+	rc.mu.Lock()
+	defer rc.unlock()
+
+	rc.generation++
+	for _, table := range tables {
+		rc.tableGenerations[table] = rc.generation
+		rc.invalidateTable(table)
+	}
+}
+
+// InvalidateKeyspace removes the entries that read from any table of the keyspace.
+func (rc *resultCache) InvalidateKeyspace(keyspace string) {
+	rc.mu.Lock()
+	defer rc.unlock()
+
+	rc.generation++
+	rc.keyspaceGenerations[keyspace] = rc.generation
+	prefix := keyspace + "."
+	for table := range rc.tables {
+		if strings.HasPrefix(table, prefix) {
+			rc.invalidateTable(table)
+		}
+	}
+}
+
+func (rc *resultCache) invalidateTable(table string) {
+	for key := range rc.tables[table] {
+		if element := rc.entries[key]; element != nil {
+			rc.remove(element)
+			rc.invalidations++
+		}
+	}
+}
+
+// invalidatedSince returns true if any of the tables was invalidated after the
+// given generation.
+func (rc *resultCache) invalidatedSince(tables []string, generation uint64) bool {
+	for _, table := range tables {
+		keyspace, _, _ := strings.Cut(table, ".")
+		if rc.tableGenerations[table] > generation || rc.keyspaceGenerations[keyspace] > generation {
+			return true
+		}
+	}
+	return false
+}
+
+func (rc *resultCache) remove(element *list.Element) {
+	entry := rc.list.Remove(element).(*resultCacheEntry)
+	delete(rc.entries, entry.key)
+	for _, table := range entry.tables {
+		keys := rc.tables[table]
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(rc.tables, table)
+			rc.dropped = append(rc.dropped, table)
+		}
+	}
+	rc.size -= entry.size
+}
+
+// unlock calls unwatch with the tables that no longer have cached results,
+// and releases mu. A table whose last entry was replaced is still watched.
+func (rc *resultCache) unlock() {
+	if rc.unwatch != nil {
+		for _, table := range rc.dropped {
+			if _, ok := rc.tables[table]; !ok {
+				rc.unwatch(table)
+			}
+		}
+	}
+	rc.dropped = nil
+	rc.mu.Unlock()
+}
+
+// Len returns the number of entries in the cache.
+func (rc *resultCache) Len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.list.Len()
+}
+
+// UsedCapacity returns the size of the cache in bytes.
+func (rc *resultCache) UsedCapacity() int64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.size
+}
+
+// MaxCapacity returns the capacity of the cache in bytes.
+func (rc *resultCache) MaxCapacity() int64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.capacity
+}
+
+// Evictions returns the number of entries evicted to make room for new ones.
+func (rc *resultCache) Evictions() int64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.evictions
+}
+
+// Invalidations returns the number of entries removed because a table they
+// read from changed.
+func (rc *resultCache) Invalidations() int64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.invalidations
+}
+
+// Hits returns the number of lookups that found a result.
+func (rc *resultCache) Hits() int64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.hits
+}
+
+// Misses returns the number of lookups that did not find a result.
+func (rc *resultCache) Misses() int64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.misses
+}
+
+// invalidateResultCache removes the cached results of the tables written by a
+// transaction that ended.
+func (e *Executor) invalidateResultCache(tables []string) {
+	if e.resultCache != nil && len(tables) > 0 {
+		e.resultCache.InvalidateTables(tables...)
+	}
+}
+
+// resultCacheKey returns the key of the result of the plan in the result
+// cache, and whether the result can be cached at all.
+func (e *Executor) resultCacheKey(
+	ctx context.Context,
+	safeSession *econtext.SafeSession,
+	plan *engine.Plan,
+	vcursor *econtext.VCursorImpl,
+	bindVars map[string]*querypb.BindVariable,
+) (PlanCacheKey, bool) {
+	if e.resultCache == nil || vcursor.ResultCacheTTL() <= 0 || plan.Type != sqlparser.StmtSelect {
+		return PlanCacheKey{}, false
+	}
+	// Reads inside a transaction or a reserved connection may depend on its state.
+	if safeSession.InTransaction() || safeSession.InReservedConn() {
+		return PlanCacheKey{}, false
+	}
+
+	hasher := vthash.New256()
+	vcursor.KeyForPlan(ctx, plan.Original, hasher)
+	// The caller is part of the key so that cached results do not bypass table ACLs.
+	writeResultCacheKeyPart(hasher, "+User:", callerid.ImmediateCallerIDFromContext(ctx).GetUsername())
+	for _, name := range slices.Sorted(maps.Keys(bindVars)) {
+		bv := bindVars[name]
+		writeResultCacheKeyPart(hasher, "+BindVar:", name, bv.Type.String(), string(bv.Value))
+		for _, value := range bv.Values {
+			writeResultCacheKeyPart(hasher, "+Value:", value.Type.String(), string(value.Value))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(safeSession.SystemVariables)) {
+		writeResultCacheKeyPart(hasher, "+SysVar:", name, safeSession.SystemVariables[name])
+	}
+
+	var key PlanCacheKey
+	hasher.Sum(key[:0])
+	return key, true
+}
+
+// writeResultCacheKeyPart writes length prefixed values, so that the values of
+// different keys cannot be confused.
+func writeResultCacheKeyPart(hasher *vthash.Hasher256, prefix string, values ...string) {
+	_, _ = hasher.WriteString(prefix)
+	for _, value := range values {
+		_, _ = hasher.Write(binary.BigEndian.AppendUint32(nil, uint32(len(value))))
+		_, _ = hasher.WriteString(value)
+	}
+}
+
+func initResultCacheStats(rc *resultCache) {
+	stats.NewGaugeFunc("ResultCacheLength", "Result cache length", func() int64 {
+		return int64(rc.Len())
+	})
+	stats.NewGaugeFunc("ResultCacheSize", "Result cache size", func() int64 {
+		return rc.UsedCapacity()
+	})
+	stats.NewGaugeFunc("ResultCacheCapacity", "Result cache capacity", func() int64 {
+		return rc.MaxCapacity()
+	})
+	stats.NewCounterFunc("ResultCacheEvictions", "Result cache evictions", rc.Evictions)
+	stats.NewCounterFunc("ResultCacheInvalidations", "Result cache invalidations", rc.Invalidations)
+	stats.NewCounterFunc("ResultCacheHits", "Result cache hits", rc.Hits)
+	stats.NewCounterFunc("ResultCacheMisses", "Result cache misses", rc.Misses)
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/log"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+const (
+	// resultCacheRetryDelay is how long the invalidator waits before
+	// restarting a failed stream.
+	resultCacheRetryDelay = 5 * time.Second
+	// resultCacheIdleTimeout is how long the stream of a keyspace keeps
+	// running once none of its tables have cached results.
+	resultCacheIdleTimeout = time.Minute
+)
+
+// vstreamFunc streams the changes of a set of shards, like vstreamManager.VStream.
+type vstreamFunc func(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error
+
+// resultCacheInvalidator removes the results of the result cache that read
+// from tables that changed. For every keyspace with cached results, it
+// streams the row events of the cached tables from the primaries, and it
+// also listens to the schema changes reported by the tablets' health streams.
+// The stream of a keyspace only restarts when a table that it does not
+// stream yet is cached, and stops once the keyspace has no cached results.
+//
+// Invalidation is best effort: a change made right before a table's stream
+// starts, or while it is reconnecting, may be missed, in which case the
+// entries expire after their TTL. Writes made through this vtgate are
+// invalidated by the executor directly.
+type resultCacheInvalidator struct {
+	cache       *resultCache
+	vstream     vstreamFunc
+	retryDelay  time.Duration
+	idleTimeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	keyspaces map[string]*resultCacheKeyspace
+}
+
+// resultCacheKeyspace is the stream of row events of a keyspace.
+type resultCacheKeyspace struct {
+	// tables are the tables of the keyspace that have cached results.
+	tables map[string]struct{}
+	// streamed are the tables in the filter of the stream. The tables that
+	// stopped being cached are only removed from it when the stream restarts.
+	streamed map[string]struct{}
+	// vgtid is the position of the last event received, from which the
+	// stream restarts when new tables are watched.
+	vgtid  *binlogdatapb.VGtid
+	cancel context.CancelFunc
+	// idle stops the stream when none of the tables have cached results.
+	idle *time.Timer
+}
+
+func newResultCacheInvalidator(cache *resultCache, vstream vstreamFunc) *resultCacheInvalidator {
+	ctx, cancel := context.WithCancel(context.Background())
+	inv := &resultCacheInvalidator{
+		cache:       cache,
+		vstream:     vstream,
+		retryDelay:  resultCacheRetryDelay,
+		idleTimeout: resultCacheIdleTimeout,
+		ctx:         ctx,
+		cancel:      cancel,
+		keyspaces:   make(map[string]*resultCacheKeyspace),
+	}
+	cache.watch = inv.Watch
+	cache.unwatch = inv.Unwatch
+	return inv
+}
+
+// Start listens to the schema changes reported by the health check.
+func (inv *resultCacheInvalidator) Start(hc discovery.HealthCheck) {
+	ch := hc.Subscribe()
+	go func() {
+		defer hc.Unsubscribe(ch)
+		for {
+			select {
+			case <-inv.ctx.Done():
+				return
+			case th := <-ch:
+				inv.handleHealth(th)
+			}
+		}
+	}()
+}
+
+// Stop stops all the streams.
+func (inv *resultCacheInvalidator) Stop() {
+	inv.cancel()
+}
+
+func (inv *resultCacheInvalidator) handleHealth(th *discovery.TabletHealth) {
+	if th == nil || th.Stats == nil || th.Target == nil {
+		return
+	}
+	tables := make([]string, 0, len(th.Stats.TableSchemaChanged)+len(th.Stats.ViewSchemaChanged))
+	for _, table := range th.Stats.TableSchemaChanged {
+		tables = append(tables, th.Target.Keyspace+"."+table)
+	}
+	for _, view := range th.Stats.ViewSchemaChanged {
+		tables = append(tables, th.Target.Keyspace+"."+view)
+	}
+	if len(tables) > 0 {
+		inv.cache.InvalidateTables(tables...)
+	}
+}
+
+// Watch makes sure that the row events of the given keyspace qualified tables
+// are streamed, restarting the stream of their keyspace if it does not
+// stream them yet.
+func (inv *resultCacheInvalidator) Watch(tables []string) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if inv.ctx.Err() != nil {
+		return
+	}
+	changed := make(map[string]*resultCacheKeyspace)
+	for _, qualified := range tables {
+		keyspace, table, ok := strings.Cut(qualified, ".")
+		if !ok {
+			continue
+		}
+		ks := inv.keyspaces[keyspace]
+		if ks == nil {
+			ks = &resultCacheKeyspace{tables: make(map[string]struct{})}
+			inv.keyspaces[keyspace] = ks
+		}
+		ks.tables[table] = struct{}{}
+		if ks.idle != nil {
+			ks.idle.Stop()
+			ks.idle = nil
+		}
+		if _, ok := ks.streamed[table]; !ok {
+			changed[keyspace] = ks
+		}
+	}
+	for keyspace, ks := range changed {
+		if ks.cancel != nil {
+			ks.cancel()
+		}
+		ks.streamed = maps.Clone(ks.tables)
+		var ctx context.Context
+		ctx, ks.cancel = context.WithCancel(inv.ctx)
+		go inv.stream(ctx, keyspace, ks, ks.filter())
+	}
+}
+
+// Unwatch forgets a keyspace qualified table that no longer has cached
+// results. The stream of its keyspace stops after idleTimeout if none of its
+// tables are cached again in the meantime.
+func (inv *resultCacheInvalidator) Unwatch(qualified string) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	keyspace, table, ok := strings.Cut(qualified, ".")
+	if !ok {
+		return
+	}
+	ks := inv.keyspaces[keyspace]
+	if ks == nil {
+		return
+	}
+	delete(ks.tables, table)
+	if len(ks.tables) > 0 || ks.idle != nil {
+		return
+	}
+	var idle *time.Timer
+	idle = time.AfterFunc(inv.idleTimeout, func() {
+		inv.mu.Lock()
+		defer inv.mu.Unlock()
+		if ks.idle != idle {
+			// A table was watched again.
+			return
+		}
+		if ks.cancel != nil {
+			ks.cancel()
+		}
+		delete(inv.keyspaces, keyspace)
+	})
+	ks.idle = idle
+}
+
+func (ks *resultCacheKeyspace) filter() *binlogdatapb.Filter {
+	filter := &binlogdatapb.Filter{}
+	for _, table := range slices.Sorted(maps.Keys(ks.streamed)) {
+		filter.Rules = append(filter.Rules, &binlogdatapb.Rule{
+			Match:  table,
+			Filter: "select * from " + sqlescape.EscapeID(table),
+		})
+	}
+	return filter
+}
+
+func (inv *resultCacheInvalidator) stream(ctx context.Context, keyspace string, ks *resultCacheKeyspace, filter *binlogdatapb.Filter) {
+	for {
+		inv.mu.Lock()
+		vgtid := ks.vgtid
+		inv.mu.Unlock()
+		if vgtid == nil {
+			vgtid = &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: keyspace, Gtid: "current"}}}
+		}
+		err := inv.vstream(ctx, topodatapb.TabletType_PRIMARY, vgtid, filter, &vtgatepb.VStreamFlags{}, func(events []*binlogdatapb.VEvent) error {
+			inv.handleEvents(ctx, keyspace, ks, events)
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf("Result cache invalidation stream for keyspace %s failed, retrying in %v: %v", keyspace, inv.retryDelay, err)
+		// Changes may be missed until the stream is back.
+		inv.cache.InvalidateKeyspace(keyspace)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(inv.retryDelay):
+		}
+	}
+}
+
+func (inv *resultCacheInvalidator) handleEvents(ctx context.Context, keyspace string, ks *resultCacheKeyspace, events []*binlogdatapb.VEvent) {
+	for _, event := range events {
+		switch event.Type {
+		case binlogdatapb.VEventType_ROW:
+			inv.cache.InvalidateTables(event.RowEvent.TableName)
+		case binlogdatapb.VEventType_DDL:
+			inv.cache.InvalidateKeyspace(keyspace)
+		case binlogdatapb.VEventType_VGTID:
+			inv.mu.Lock()
+			// A canceled stream must not overwrite the position of its replacement.
+			if ctx.Err() == nil {
+				ks.vgtid = event.Vgtid
+			}
+			inv.mu.Unlock()
+		}
+	}
+}
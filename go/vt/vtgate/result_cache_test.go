@@ -0,0 +1,286 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/discovery"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func resultCacheTestKey(i byte) PlanCacheKey {
+	return PlanCacheKey{i}
+}
+
+func resultCacheTestResult(value string) *sqltypes.Result {
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields("a", "varchar"), value)
+}
+
+func TestResultCache(t *testing.T) {
+	now := time.Now()
+	rc := newResultCache(1024 * 1024)
+	rc.now = func() time.Time { return now }
+
+	_, ok := rc.Get(resultCacheTestKey(1))
+	assert.False(t, ok)
+
+	result := resultCacheTestResult("1")
+	rc.Set(resultCacheTestKey(1), result, []string{"ks.t1"}, time.Second, rc.Generation())
+	rc.Set(resultCacheTestKey(2), resultCacheTestResult("2"), []string{"ks.t1", "ks.t2"}, time.Minute, rc.Generation())
+	rc.Set(resultCacheTestKey(3), resultCacheTestResult("3"), []string{"other.t1"}, time.Minute, rc.Generation())
+	assert.Equal(t, 3, rc.Len())
+
+	got, ok := rc.Get(resultCacheTestKey(1))
+	require.True(t, ok)
+	assert.Equal(t, result, got)
+	// Callers get their own copy of the result.
+	got.Rows = nil
+	got, ok = rc.Get(resultCacheTestKey(1))
+	require.True(t, ok)
+	assert.Equal(t, result, got)
+
+	// Entries expire after their TTL.
+	now = now.Add(time.Second)
+	_, ok = rc.Get(resultCacheTestKey(1))
+	assert.False(t, ok)
+	assert.Equal(t, 2, rc.Len())
+
+	rc.InvalidateTables("ks.t2")
+	_, ok = rc.Get(resultCacheTestKey(2))
+	assert.False(t, ok)
+	_, ok = rc.Get(resultCacheTestKey(3))
+	assert.True(t, ok)
+
+	rc.Set(resultCacheTestKey(4), resultCacheTestResult("4"), []string{"ks.t3"}, time.Minute, rc.Generation())
+	rc.InvalidateKeyspace("ks")
+	_, ok = rc.Get(resultCacheTestKey(4))
+	assert.False(t, ok)
+	_, ok = rc.Get(resultCacheTestKey(3))
+	assert.True(t, ok)
+
+	rc.InvalidateKeyspace("other")
+	assert.Zero(t, rc.Len())
+	assert.Zero(t, rc.UsedCapacity())
+	assert.Empty(t, rc.tables)
+	assert.EqualValues(t, 3, rc.Invalidations())
+	assert.EqualValues(t, 4, rc.Hits())
+	assert.EqualValues(t, 4, rc.Misses())
+}
+
+func TestResultCacheCapacity(t *testing.T) {
+	size := resultCacheTestResult("1").CachedSize(true) + resultCacheEntryOverhead
+	rc := newResultCache(2 * size)
+
+	rc.Set(resultCacheTestKey(1), resultCacheTestResult("1"), []string{"ks.t"}, time.Minute, rc.Generation())
+	rc.Set(resultCacheTestKey(2), resultCacheTestResult("2"), []string{"ks.t"}, time.Minute, rc.Generation())
+	assert.Equal(t, 2*size, rc.UsedCapacity())
+
+	// The least recently used entry is evicted first.
+	_, ok := rc.Get(resultCacheTestKey(1))
+	require.True(t, ok)
+	rc.Set(resultCacheTestKey(3), resultCacheTestResult("3"), []string{"ks.t"}, time.Minute, rc.Generation())
+	_, ok = rc.Get(resultCacheTestKey(2))
+	assert.False(t, ok)
+	_, ok = rc.Get(resultCacheTestKey(1))
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, rc.Evictions())
+	assert.Equal(t, 2*size, rc.UsedCapacity())
+
+	// Replacing an entry does not count it twice.
+	rc.Set(resultCacheTestKey(1), resultCacheTestResult("5"), []string{"ks.t"}, time.Minute, rc.Generation())
+	assert.Equal(t, 2, rc.Len())
+	assert.Equal(t, 2*size, rc.UsedCapacity())
+
+	// Results larger than the cache are not cached.
+	rc.Set(resultCacheTestKey(4), resultCacheTestResult(strings.Repeat("x", 4096)), []string{"ks.t"}, time.Minute, rc.Generation())
+	_, ok = rc.Get(resultCacheTestKey(4))
+	assert.False(t, ok)
+	assert.Equal(t, 2, rc.Len())
+}
+
+func TestResultCacheInvalidatedDuringQuery(t *testing.T) {
+	rc := newResultCache(1024 * 1024)
+
+	// The table was invalidated while the query ran, so its result may be stale.
+	generation := rc.Generation()
+	rc.InvalidateTables("ks.t1")
+	rc.Set(resultCacheTestKey(1), resultCacheTestResult("1"), []string{"ks.t1", "ks.t2"}, time.Minute, generation)
+	_, ok := rc.Get(resultCacheTestKey(1))
+	assert.False(t, ok)
+
+	// The invalidation of other tables does not matter.
+	rc.Set(resultCacheTestKey(2), resultCacheTestResult("2"), []string{"ks.t2"}, time.Minute, generation)
+	_, ok = rc.Get(resultCacheTestKey(2))
+	assert.True(t, ok)
+
+	generation = rc.Generation()
+	rc.InvalidateKeyspace("ks")
+	rc.Set(resultCacheTestKey(3), resultCacheTestResult("3"), []string{"ks.t3"}, time.Minute, generation)
+	_, ok = rc.Get(resultCacheTestKey(3))
+	assert.False(t, ok)
+
+	// Queries that start after the invalidation are cached.
+	rc.Set(resultCacheTestKey(4), resultCacheTestResult("4"), []string{"ks.t1"}, time.Minute, rc.Generation())
+	_, ok = rc.Get(resultCacheTestKey(4))
+	assert.True(t, ok)
+}
+
+type fakeResultCacheStream struct {
+	ctx    context.Context
+	vgtid  *binlogdatapb.VGtid
+	filter *binlogdatapb.Filter
+	send   func(events []*binlogdatapb.VEvent) error
+}
+
+func TestResultCacheInvalidator(t *testing.T) {
+	streams := make(chan *fakeResultCacheStream, 10)
+	rc := newResultCache(1024 * 1024)
+	inv := newResultCacheInvalidator(rc, func(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error {
+		assert.Equal(t, topodatapb.TabletType_PRIMARY, tabletType)
+		streams <- &fakeResultCacheStream{ctx: ctx, vgtid: vgtid, filter: filter, send: send}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	defer inv.Stop()
+
+	rc.Set(resultCacheTestKey(1), resultCacheTestResult("1"), []string{"ks.t1"}, time.Minute, rc.Generation())
+	stream := <-streams
+	assert.Equal(t, "current", stream.vgtid.ShardGtids[0].Gtid)
+	assert.Equal(t, "ks", stream.vgtid.ShardGtids[0].Keyspace)
+	require.Len(t, stream.filter.Rules, 1)
+	assert.Equal(t, "t1", stream.filter.Rules[0].Match)
+
+	// Tables that are already watched do not restart the stream.
+	rc.Set(resultCacheTestKey(2), resultCacheTestResult("2"), []string{"ks.t1"}, time.Minute, rc.Generation())
+	assert.Empty(t, streams)
+
+	vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "ks", Shard: "0", Gtid: "pos"}}}
+	require.NoError(t, stream.send([]*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "ks.t1"}},
+		{Type: binlogdatapb.VEventType_VGTID, Vgtid: vgtid},
+	}))
+	assert.Zero(t, rc.Len())
+
+	// A new table restarts the stream from the last position, without the
+	// tables that are no longer cached.
+	rc.Set(resultCacheTestKey(3), resultCacheTestResult("3"), []string{"ks.t2"}, time.Minute, rc.Generation())
+	old := stream
+	stream = <-streams
+	assert.Error(t, old.ctx.Err())
+	assert.Equal(t, vgtid, stream.vgtid)
+	require.Len(t, stream.filter.Rules, 1)
+	assert.Equal(t, "t2", stream.filter.Rules[0].Match)
+
+	rc.Set(resultCacheTestKey(4), resultCacheTestResult("4"), []string{"ks.t1"}, time.Minute, rc.Generation())
+	stream = <-streams
+	require.Len(t, stream.filter.Rules, 2)
+	assert.Equal(t, "t1", stream.filter.Rules[0].Match)
+	assert.Equal(t, "t2", stream.filter.Rules[1].Match)
+
+	// A DDL invalidates the whole keyspace.
+	require.NoError(t, stream.send([]*binlogdatapb.VEvent{{Type: binlogdatapb.VEventType_DDL}}))
+	assert.Zero(t, rc.Len())
+
+	// Schema changes reported by the health check invalidate their tables.
+	rc.Set(resultCacheTestKey(5), resultCacheTestResult("5"), []string{"ks.t1"}, time.Minute, rc.Generation())
+	rc.Set(resultCacheTestKey(6), resultCacheTestResult("6"), []string{"ks.t2"}, time.Minute, rc.Generation())
+	inv.handleHealth(&discovery.TabletHealth{
+		Target: &querypb.Target{Keyspace: "ks", Shard: "0", TabletType: topodatapb.TabletType_PRIMARY},
+		Stats:  &querypb.RealtimeStats{TableSchemaChanged: []string{"t2"}},
+	})
+	_, ok := rc.Get(resultCacheTestKey(5))
+	assert.True(t, ok)
+	_, ok = rc.Get(resultCacheTestKey(6))
+	assert.False(t, ok)
+	assert.Empty(t, streams)
+
+	// The stream stops once the keyspace has no cached results.
+	inv.idleTimeout = time.Millisecond
+	rc.InvalidateKeyspace("ks")
+	assert.Eventually(t, func() bool { return stream.ctx.Err() != nil }, 5*time.Second, time.Millisecond)
+	assert.Empty(t, streams)
+}
+
+func TestExecutorResultCache(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+	executor.resultCache = newResultCache(1024 * 1024)
+	session := &vtgatepb.Session{TargetString: KsTestUnsharded, Autocommit: true}
+
+	sbclookup.SetResults([]*sqltypes.Result{resultCacheTestResult("1")})
+	query := "select /*vt+ RESULT_CACHE_TTL=1m */ a from t where id = 1"
+	want, err := executorExec(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	require.Len(t, sbclookup.Queries, 1)
+
+	got, err := executorExec(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Len(t, sbclookup.Queries, 1)
+
+	// Different bind variables are cached separately.
+	_, err = executorExec(ctx, executor, session, "select /*vt+ RESULT_CACHE_TTL=1m */ a from t where id = 2", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 2)
+
+	// Queries without the directive are not cached.
+	_, err = executorExec(ctx, executor, session, "select a from t where id = 1", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, session, "select a from t where id = 1", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 4)
+
+	// Writes through the executor invalidate the results of their tables.
+	_, err = executorExec(ctx, executor, session, "update t set a = 2 where id = 1", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 5)
+	_, err = executorExec(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 6)
+
+	// Reads inside a transaction are not cached.
+	txSession := &vtgatepb.Session{TargetString: KsTestUnsharded}
+	_, err = executorExec(ctx, executor, txSession, "begin", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, txSession, query, nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 7)
+
+	// Writes inside a transaction are invalidated when it commits.
+	_, err = executorExec(ctx, executor, txSession, "update t set a = 3 where id = 1", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 8)
+	_, err = executorExec(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 8)
+	_, err = executorExec(ctx, executor, txSession, "commit", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 9)
+}
@@ -195,6 +195,7 @@ func (stc *ScatterConn) ExecuteMultiShard(
 			if opts == nil && fetchLastInsertID {
 				opts = &querypb.ExecuteOptions{FetchLastInsertId: fetchLastInsertID}
 			}
+			ctx, rs, opts := stc.readAfterWrite(ctx, rs, session, info, opts)
 
 			if autocommit {
 				// As this is auto-commit, the transactionID is supposed to be zero.
@@ -274,6 +275,10 @@ func (stc *ScatterConn) ExecuteMultiShard(
 			if err != nil {
 				return newInfo, err
 			}
+			if transactionID == 0 && innerqr != nil && innerqr.RowsAffected > 0 && session.ReadAfterWriteAuto() {
+				// The write was autocommitted, and the session reads its writes.
+				recordShardPosition(ctx, rs.Gateway, rs.Target, session, innerqr)
+			}
 			mu.Lock()
 			defer mu.Unlock()
 
@@ -422,6 +427,7 @@ func (stc *ScatterConn) StreamExecuteMulti(
 			if opts == nil && fetchLastInsertID {
 				opts = &querypb.ExecuteOptions{FetchLastInsertId: fetchLastInsertID}
 			}
+			ctx, rs, opts := stc.readAfterWrite(ctx, rs, session, info, opts)
 
 			if autocommit {
 				// As this is auto-commit, the transactionID is supposed to be zero.
@@ -689,7 +695,16 @@ func (stc *ScatterConn) multiGoTransaction(
 		if err != nil {
 			return
 		}
+		retryDone, err := stc.txConn.waitForFailoverEnd(ctx, rs.Target, session, info)
+		if err != nil {
+			return
+		}
+		if retryDone != nil {
+			defer retryDone()
+		}
+		transactionID := info.transactionID
 		info, err = action(rs, i, info)
+		err = stc.txConn.failoverError(rs.Target, transactionID, err)
 		if info == nil {
 			return
 		}
@@ -385,6 +385,7 @@ func getColumns(tblSpec *sqlparser.TableSpec) []vindexes.Column {
 				CollationName: colCollation,
 				Default:       column.Type.Options.Default,
 				Invisible:     column.Type.Invisible(),
+				Generated:     column.Type.Options.As != nil,
 				Size:          int32(size),
 				Scale:         int32(scale),
 				Nullable:      nullable,
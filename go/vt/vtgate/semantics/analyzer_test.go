@@ -947,17 +947,14 @@ func TestInvalidQueries(t *testing.T) {
 		sql:  "select (select sql_calc_found_rows id from a) as t",
 		serr: "Incorrect usage/placement of 'SQL_CALC_FOUND_ROWS'",
 	}, {
-		sql:  "select id from t1 natural join t2",
-		serr: "VT12001: unsupported: natural join",
+		sql:             "select 1 from t natural join t1",
+		notUnshardedErr: "VT09015: schema tracking required",
 	}, {
 		sql: "select * from music where user_id IN (select sql_calc_found_rows * from music limit 10)",
 		err: &SQLCalcFoundRowsUsageError{},
 	}, {
 		sql:  "select is_free_lock('xyz') from user",
 		serr: "is_free_lock('xyz') allowed only with dual",
-	}, {
-		sql: "SELECT * FROM JSON_TABLE('[ {\"c1\": null} ]','$[*]' COLUMNS( c1 INT PATH '$.c1' ERROR ON ERROR )) as jt",
-		err: &JSONTablesError{},
 	}, {
 		sql:             "select does_not_exist from t1",
 		notUnshardedErr: "column 'does_not_exist' not found in table 't1'",
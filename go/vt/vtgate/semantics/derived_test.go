@@ -263,3 +263,47 @@ func BenchmarkAnalyzeDerivedTableQueries(b *testing.B) {
 		}
 	}
 }
+
+func TestScopingWLateralDerivedTables(t *testing.T) {
+	queries := []struct {
+		query         string
+		errorMessage  string
+		recursiveDeps TableSet
+		directDeps    TableSet
+	}{
+		{
+			query:         "select t.x from t1, lateral (select t1.id as x from t2) as t",
+			recursiveDeps: TS0,
+			directDeps:    TS2,
+		}, {
+			query:         "select t.uid from t1 join lateral (select uid from t2 where t2.uid = t1.id) as t on true",
+			recursiveDeps: TS1,
+			directDeps:    TS2,
+		}, {
+			query:         "select t.x from t1 left join lateral (select t1.id + t2.uid as x from t2) as t on true",
+			recursiveDeps: MergeTableSets(TS0, TS1),
+			directDeps:    TS2,
+		}, {
+			query:        "select t.x from t1, (select t1.id as x from t2) as t",
+			errorMessage: "column 't1.id' not found",
+		}}
+	for _, query := range queries {
+		t.Run(query.query, func(t *testing.T) {
+			parse, err := sqlparser.NewTestParser().Parse(query.query)
+			require.NoError(t, err)
+			st, err := Analyze(parse, "user", fakeSchemaInfo())
+			switch {
+			case query.errorMessage != "" && err != nil:
+				require.EqualError(t, err, query.errorMessage)
+				return
+			case query.errorMessage != "":
+				require.EqualError(t, st.NotUnshardedErr, query.errorMessage)
+				return
+			}
+			require.NoError(t, err)
+			sel := parse.(*sqlparser.Select)
+			assert.Equal(t, query.recursiveDeps, st.RecursiveDeps(extract(sel, 0)), "RecursiveDeps")
+			assert.Equal(t, query.directDeps, st.DirectDeps(extract(sel, 0)), "DirectDeps")
+		})
+	}
+}
@@ -173,13 +173,20 @@ func rewriteNotExpr(cursor *sqlparser.Cursor, node *sqlparser.NotExpr) {
 		return
 	}
 	cmp.Operator = cmp.Operator.Inverse()
+	cmp.Modifier = cmp.Modifier.Inverse()
 	cursor.Replace(cmp)
 }
 
 func (r *earlyRewriter) handleJoinTableExprUp(join *sqlparser.JoinTableExpr) error {
 	// this rewriting is done in the `up` phase, because we need the scope to have been
 	// filled in with the available tables
-	if len(join.Condition.Using) == 0 {
+	if join.Join.IsNatural() {
+		err := rewriteNaturalJoin(r.binder, join)
+		if err != nil {
+			return err
+		}
+	}
+	if join.Condition == nil || len(join.Condition.Using) == 0 {
 		return nil
 	}
 
@@ -948,6 +955,86 @@ func rewriteJoinUsing(b *binder, join *sqlparser.JoinTableExpr) error {
 	return nil
 }
 
+// rewriteNaturalJoin rewrites a NATURAL JOIN into the equivalent JOIN with a USING clause
+// listing all the columns the two sides have in common. The column lists have to be authoritative,
+// so we know exactly which columns the tables have.
+//
+// For example, if t1 has the columns (id, col1, col2) and t2 has the columns (col1, id, col3), the query:
+//
+//	SELECT * FROM t1 NATURAL LEFT JOIN t2
+//
+// is rewritten to:
+//
+//	SELECT * FROM t1 LEFT JOIN t2 USING (id, col1)
+func rewriteNaturalJoin(b *binder, join *sqlparser.JoinTableExpr) error {
+	lhs, err := naturalJoinColumns(b, join.LeftExpr)
+	if err != nil {
+		return err
+	}
+	rhs, err := naturalJoinColumns(b, join.RightExpr)
+	if err != nil {
+		return err
+	}
+
+	var using sqlparser.Columns
+	for _, col := range lhs {
+		if rhs.FindColumn(col) >= 0 && using.FindColumn(col) < 0 {
+			using = append(using, col)
+		}
+	}
+
+	// NATURAL RIGHT JOIN has already been turned into a NATURAL LEFT JOIN by the early table collector
+	if join.Join == sqlparser.NaturalLeftJoinType {
+		join.Join = sqlparser.LeftJoinType
+	} else {
+		join.Join = sqlparser.NormalJoinType
+	}
+	join.Condition = &sqlparser.JoinCondition{Using: using}
+
+	// the binder has already been over the join, so we bind the new USING columns
+	// here, so they can be used unqualified in the rest of the query
+	return b.bindJoinCondition(join.Condition)
+}
+
+// naturalJoinColumns returns the visible columns of all the tables in a table expression
+func naturalJoinColumns(b *binder, tbl sqlparser.TableExpr) (sqlparser.Columns, error) {
+	switch tbl := tbl.(type) {
+	case *sqlparser.AliasedTableExpr:
+		ts := b.tc.tableSetFor(tbl)
+		tblInfo := b.tc.Tables[ts.TableOffset()]
+		if !tblInfo.authoritative() {
+			return nil, ShardedError{Inner: vterrors.VT09015()}
+		}
+		var cols sqlparser.Columns
+		for _, info := range tblInfo.getColumns(true /* ignoreInvisibleCol */) {
+			cols = append(cols, sqlparser.NewIdentifierCI(info.Name))
+		}
+		return cols, nil
+	case *sqlparser.JoinTableExpr:
+		lhs, err := naturalJoinColumns(b, tbl.LeftExpr)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := naturalJoinColumns(b, tbl.RightExpr)
+		if err != nil {
+			return nil, err
+		}
+		return append(lhs, rhs...), nil
+	case *sqlparser.ParenTableExpr:
+		var cols sqlparser.Columns
+		for _, expr := range tbl.Exprs {
+			exprCols, err := naturalJoinColumns(b, expr)
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, exprCols...)
+		}
+		return cols, nil
+	default:
+		return nil, vterrors.VT12001(fmt.Sprintf("NATURAL JOIN with %s", sqlparser.String(tbl)))
+	}
+}
+
 // buildJoinPredicates constructs the join predicates for a given set of USING columns.
 // It returns a slice of sqlparser.Expr, each representing a join predicate for the given columns.
 func buildJoinPredicates(b *binder, join *sqlparser.JoinTableExpr) ([]sqlparser.Expr, error) {
@@ -174,6 +174,19 @@ func TestExpandStar(t *testing.T) {
 	}, {
 		sql:    "select 1 from t1 join t5 using (b) where b = 12",
 		expSQL: "select 1 from t1 join t5 on t1.b = t5.b where t1.b = 12",
+	}, {
+		sql:      "select * from t1 natural join t5",
+		expSQL:   "select t1.a, t1.b, t1.c from t1 join t5 on t1.a = t5.a and t1.b = t5.b",
+		expanded: "main.t1.a, main.t1.b, main.t1.c",
+	}, {
+		sql:    "select * from t2 natural left join t4",
+		expSQL: "select t2.c1, t2.c2, t4.c4 from t2 left join t4 on t2.c1 = t4.c1",
+	}, {
+		sql:    "select * from t2 natural right join t4",
+		expSQL: "select t4.c1, t4.c4, t2.c2 from t4 left join t2 on t4.c1 = t2.c1",
+	}, {
+		sql:    "select * from t1 natural join t2",
+		expSQL: "select t1.a, t1.b, t1.c, t2.c1, t2.c2 from t1 join t2",
 	}, {
 		sql:    "select * from (select 12) as t",
 		expSQL: "select `12` from (select 12 from dual) as t",
@@ -844,6 +857,9 @@ func TestRewriteNot(t *testing.T) {
 	}, {
 		sql:      "select (not (1 like ('a' is null)))",
 		expected: "select 1 not like ('a' is null) from dual",
+	}, {
+		sql:      "select a from t1 where not a > any (select b from t1)",
+		expected: "select a from t1 where a <= all (select b from t1)",
 	}}
 	for _, tcase := range tcases {
 		t.Run(tcase.sql, func(t *testing.T) {
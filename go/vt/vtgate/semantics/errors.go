@@ -40,11 +40,9 @@ type (
 	MissingInVSchemaError          struct{ Table TableInfo }
 	CantUseOptionHereError         struct{ Msg string }
 	TableNotUpdatableError         struct{ Table string }
-	UnsupportedNaturalJoinError    struct{ JoinExpr *sqlparser.JoinTableExpr }
 	NotSequenceTableError          struct{ Table string }
 	NextWithMultipleTablesError    struct{ CountTables int }
 	LockOnlyWithDualError          struct{ Node *sqlparser.LockingFunc }
-	JSONTablesError                struct{ Table string }
 	QualifiedOrderInUnionError     struct{ Table string }
 	BuggyError                     struct{ Msg string }
 	UnsupportedConstruct           struct{ errString string }
@@ -119,13 +117,6 @@ func (e *UnsupportedMultiTablesInUpdateError) Error() string {
 
 func (e *UnsupportedMultiTablesInUpdateError) unsupported() {}
 
-// UnsupportedNaturalJoinError
-func (e *UnsupportedNaturalJoinError) Error() string {
-	return eprintf(e, "%s", e.JoinExpr.Join.ToString())
-}
-
-func (e *UnsupportedNaturalJoinError) unsupported() {}
-
 // UnionWithSQLCalcFoundRowsError
 func (e *UnionWithSQLCalcFoundRowsError) Error() string {
 	return eprintf(e, "SQL_CALC_FOUND_ROWS not supported with union")
@@ -208,13 +199,6 @@ func (e *QualifiedOrderInUnionError) Error() string {
 	return eprintf(e, "Table `%s` from one of the SELECTs cannot be used in global ORDER clause", e.Table)
 }
 
-// JSONTablesError
-func (e *JSONTablesError) Error() string {
-	return eprintf(e, "json_table expressions")
-}
-
-func (e *JSONTablesError) unsupported() {}
-
 // BuggyError is used for checking conditions that should never occur
 func (e *BuggyError) Error() string {
 	return eprintf(e, e.Msg)
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// JSONTable contains the information about a JSON_TABLE table function.
+type JSONTable struct {
+	// ASTNode is a stand-in for the table function, so it can be identified like any other table
+	ASTNode *sqlparser.AliasedTableExpr
+	Expr    *sqlparser.JSONTableExpr
+	columns []ColumnInfo
+}
+
+var _ TableInfo = (*JSONTable)(nil)
+
+func newJSONTable(node *sqlparser.JSONTableExpr, collationEnv *collations.Environment) *JSONTable {
+	return &JSONTable{
+		ASTNode: &sqlparser.AliasedTableExpr{
+			Expr: sqlparser.NewTableName(node.Alias.String()),
+			As:   node.Alias,
+		},
+		Expr:    node,
+		columns: jsonTableColumns(node.Columns, collationEnv, nil),
+	}
+}
+
+// jsonTableColumns flattens the column definitions, including the ones found in NESTED PATH clauses
+func jsonTableColumns(defs []*sqlparser.JtColumnDefinition, collationEnv *collations.Environment, cols []ColumnInfo) []ColumnInfo {
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			cols = append(cols, ColumnInfo{
+				Name: def.JtOrdinal.Name.String(),
+				Type: evalengine.NewType(sqltypes.Uint32, collations.CollationBinaryID),
+			})
+		case def.JtPath != nil:
+			typ := def.JtPath.Type.SQLType()
+			var collation collations.ID
+			if sqltypes.IsText(typ) {
+				collation = collationEnv.DefaultConnectionCharset()
+			} else {
+				collation = collations.CollationForType(typ, collationEnv.DefaultConnectionCharset())
+			}
+			cols = append(cols, ColumnInfo{
+				Name: def.JtPath.Name.String(),
+				Type: evalengine.NewType(typ, collation),
+			})
+		case def.JtNestedPath != nil:
+			cols = jsonTableColumns(def.JtNestedPath.Columns, collationEnv, cols)
+		}
+	}
+	return cols
+}
+
+// dependencies implements the TableInfo interface
+func (jt *JSONTable) dependencies(colName string, org originable) (dependencies, error) {
+	ts := org.tableSetFor(jt.ASTNode)
+	for _, col := range jt.columns {
+		if strings.EqualFold(col.Name, colName) {
+			return createCertain(ts, ts, col.Type), nil
+		}
+	}
+	return &nothing{}, nil
+}
+
+// IsInfSchema implements the TableInfo interface
+func (jt *JSONTable) IsInfSchema() bool {
+	return false
+}
+
+func (jt *JSONTable) matches(name sqlparser.TableName) bool {
+	return jt.Expr.Alias.String() == name.Name.String() && name.Qualifier.IsEmpty()
+}
+
+func (jt *JSONTable) authoritative() bool {
+	return true
+}
+
+// Name implements the TableInfo interface
+func (jt *JSONTable) Name() (sqlparser.TableName, error) {
+	return sqlparser.NewTableName(jt.Expr.Alias.String()), nil
+}
+
+func (jt *JSONTable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return jt.ASTNode
+}
+
+func (jt *JSONTable) canShortCut() shortCut {
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (jt *JSONTable) GetVindexTable() *vindexes.BaseTable {
+	return nil
+}
+
+func (jt *JSONTable) getColumns(bool) []ColumnInfo {
+	return jt.columns
+}
+
+// GetTables implements the TableInfo interface
+func (jt *JSONTable) getTableSet(org originable) TableSet {
+	return org.tableSetFor(jt.ASTNode)
+}
+
+// GetExprFor implements the TableInfo interface
+func (jt *JSONTable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Unknown column '%s' in 'field list'", s)
+}
+
+// GetMirrorRule implements TableInfo.
+func (jt *JSONTable) GetMirrorRule() *vindexes.MirrorRule {
+	return nil
+}
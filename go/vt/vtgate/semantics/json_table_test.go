@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestJSONTableBinding(t *testing.T) {
+	queries := []struct {
+		query         string
+		errorMessage  string
+		recursiveDeps TableSet
+		typ           sqltypes.Type
+	}{
+		{
+			query:         "select jt.a from json_table('[]', '$[*]' columns(a int path '$.a')) as jt",
+			recursiveDeps: TS0,
+			typ:           sqltypes.Int32,
+		}, {
+			query:         "select b from t1, json_table(t1.id, '$[*]' columns(a int path '$.a', nested path '$.n[*]' columns(b varchar(10) path '$'))) as jt",
+			recursiveDeps: TS1,
+			typ:           sqltypes.VarChar,
+		}, {
+			query:         "select jt.idx from t1, json_table(t1.id, '$[*]' columns(idx for ordinality)) as jt",
+			recursiveDeps: TS1,
+			typ:           sqltypes.Uint32,
+		}, {
+			query:         "select id from t1, json_table(t1.id, '$[*]' columns(a int path '$.a')) as jt",
+			recursiveDeps: TS0,
+			typ:           sqltypes.Int64,
+		}, {
+			query:        "select jt.c from json_table('[]', '$[*]' columns(a int path '$.a')) as jt",
+			errorMessage: "column 'jt.c' not found",
+		}, {
+			query:        "select 1 from json_table(t1.id, '$[*]' columns(a int path '$.a')) as jt, t1",
+			errorMessage: "column 't1.id' not found",
+		}}
+	for _, query := range queries {
+		t.Run(query.query, func(t *testing.T) {
+			parse, err := sqlparser.NewTestParser().Parse(query.query)
+			require.NoError(t, err)
+			st, err := Analyze(parse, "user", fakeSchemaInfo())
+			switch {
+			case query.errorMessage != "" && err != nil:
+				require.EqualError(t, err, query.errorMessage)
+				return
+			case query.errorMessage != "":
+				require.EqualError(t, st.NotUnshardedErr, query.errorMessage)
+				return
+			}
+			require.NoError(t, err)
+			expr := extract(parse.(*sqlparser.Select), 0)
+			assert.Equal(t, query.recursiveDeps, st.RecursiveDeps(expr))
+			typ, found := st.TypeForExpr(expr)
+			require.True(t, found)
+			assert.Equal(t, query.typ, typ.Type())
+		})
+	}
+}
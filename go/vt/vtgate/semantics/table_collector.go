@@ -77,11 +77,24 @@ func (etc *earlyTableCollector) down(cursor *sqlparser.Cursor) bool {
 		if node.Name.EqualString("last_insert_id") && len(node.Exprs) == 1 {
 			etc.lastInsertIdWithArgument = true
 		}
+	case *sqlparser.JoinTableExpr:
+		handleNaturalRightJoin(node)
 	}
 
 	return true
 }
 
+// handleNaturalRightJoin turns a NATURAL RIGHT JOIN into a NATURAL LEFT JOIN by switching the sides.
+// This has to happen before the tables are collected, so the common columns are taken from
+// the right hand side, and the columns are listed in the same order as MySQL does when expanding stars.
+func handleNaturalRightJoin(join *sqlparser.JoinTableExpr) {
+	if join.Join != sqlparser.NaturalRightJoinType {
+		return
+	}
+	join.LeftExpr, join.RightExpr = join.RightExpr, join.LeftExpr
+	join.Join = sqlparser.NaturalLeftJoinType
+}
+
 func (etc *earlyTableCollector) up(cursor *sqlparser.Cursor) bool {
 	ate, ok := cursor.Node().(*sqlparser.AliasedTableExpr)
 	if !ok {
@@ -130,6 +143,8 @@ func (tc *tableCollector) up(cursor *sqlparser.Cursor) error {
 	switch node := cursor.Node().(type) {
 	case *sqlparser.AliasedTableExpr:
 		return tc.visitAliasedTableExpr(node)
+	case *sqlparser.JSONTableExpr:
+		return tc.visitJSONTableExpr(node)
 	case *sqlparser.Union:
 		return tc.visitUnion(node)
 	case *sqlparser.RowAlias:
@@ -153,6 +168,13 @@ func (tc *tableCollector) visitAliasedTableExpr(node *sqlparser.AliasedTableExpr
 	return nil
 }
 
+func (tc *tableCollector) visitJSONTableExpr(node *sqlparser.JSONTableExpr) error {
+	tableInfo := newJSONTable(node, tc.org.collationEnv())
+	tc.Tables = append(tc.Tables, tableInfo)
+	scope := tc.scoper.currentScope()
+	return scope.addTable(tableInfo)
+}
+
 func (tc *tableCollector) visitUnion(union *sqlparser.Union) error {
 	firstSelect, err := sqlparser.GetFirstSelect(union)
 	if err != nil {
@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -61,10 +62,40 @@ func (t *typer) up(cursor *sqlparser.Cursor) error {
 			}
 		}
 		t.m[node] = code.ResolveType(inputType, t.collationEnv)
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch node.Type {
+		case sqlparser.PercentRankExprType, sqlparser.CumeDistExprType:
+			t.m[node] = evalengine.NewType(sqltypes.Float64, collations.CollationBinaryID)
+		default:
+			t.m[node] = evalengine.NewType(sqltypes.Uint64, collations.CollationBinaryID)
+		}
+	case *sqlparser.NtileExpr:
+		t.m[node] = evalengine.NewType(sqltypes.Uint64, collations.CollationBinaryID)
+	case *sqlparser.FuncExpr:
+		if sqlparser.IsGroupingFunc(node) {
+			t.m[node] = opcode.AggregateGrouping.ResolveType(evalengine.Type{}, t.collationEnv)
+		}
+	case *sqlparser.LagLeadExpr:
+		t.setNullableTypeFrom(node, node.Expr)
+	case *sqlparser.FirstOrLastValueExpr:
+		t.setNullableTypeFrom(node, node.Expr)
+	case *sqlparser.NTHValueExpr:
+		t.setNullableTypeFrom(node, node.Expr)
 	}
 	return nil
 }
 
+// setNullableTypeFrom is used for window functions that return a value from another row,
+// which might not exist, so the result is always nullable
+func (t *typer) setNullableTypeFrom(node, arg sqlparser.Expr) {
+	typ, ok := t.m[arg]
+	if !ok {
+		return
+	}
+	typ.SetNullability(true)
+	t.m[node] = typ
+}
+
 func (t *typer) setTypeFor(node *sqlparser.ColName, typ evalengine.Type) {
 	t.m[node] = typ
 }
@@ -329,6 +329,9 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 		}
 
 		tablets := gw.hc.GetHealthyTabletStats(target)
+		if pos, ok := readAfterWritePositionFromContext(ctx); ok {
+			tablets = tabletsAtPosition(tablets, pos)
+		}
 		if len(tablets) == 0 {
 			// if we have a keyspace event watcher, check if the reason why our primary is not available is that it's currently being resharded
 			// or if a reparent operation is in progress.
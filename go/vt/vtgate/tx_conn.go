@@ -32,7 +32,9 @@ import (
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vtgate/dynamicconfig"
 	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
@@ -146,6 +148,7 @@ func (txc *TxConn) Commit(ctx context.Context, session *econtext.SafeSession) er
 			_ = txc.Release(ctx, session)
 		}
 	}
+	txc.recordCommitPositions(ctx, session)
 	return nil
 }
 
@@ -169,6 +172,57 @@ func (txc *TxConn) queryService(ctx context.Context, alias *topodatapb.TabletAli
 	return txc.tabletGateway.QueryServiceByAlias(ctx, alias, nil)
 }
 
+// transactionBuffer returns the buffer if open transactions should be handled
+// during failovers, see --buffer_in_flight_transactions.
+func (txc *TxConn) transactionBuffer() *buffer.Buffer {
+	if txc.tabletGateway == nil || txc.tabletGateway.buffer == nil {
+		return nil
+	}
+	if !txc.tabletGateway.buffer.GetConfig().InFlightTransactions {
+		return nil
+	}
+	return txc.tabletGateway.buffer
+}
+
+// waitForFailoverEnd holds a statement of an open transaction which does not
+// have a shard session on the target yet, while the target shard is failing over.
+// If it does not return an error, it may return a RetryDoneFunc which must be
+// called after the statement was executed.
+func (txc *TxConn) waitForFailoverEnd(ctx context.Context, target *querypb.Target, session *econtext.SafeSession, info *shardActionInfo) (buffer.RetryDoneFunc, error) {
+	if !session.InTransaction() || info.transactionID != 0 || target.TabletType != topodatapb.TabletType_PRIMARY {
+		return nil, nil
+	}
+	buf := txc.transactionBuffer()
+	if buf == nil {
+		return nil, nil
+	}
+	return buf.WaitForFailoverEnd(ctx, target.Keyspace, target.Shard, txc.tabletGateway.kev, nil)
+}
+
+// failoverError returns a retryable error if err was returned for the
+// transaction open on the target because the target shard failed over:
+// either the tablet reported the reparent, or the requests to the shard are
+// buffered. The returned error aborts the transaction, so that the client can
+// replay it.
+func (txc *TxConn) failoverError(target *querypb.Target, transactionID int64, err error) error {
+	if err == nil || transactionID == 0 || target.TabletType != topodatapb.TabletType_PRIMARY {
+		return err
+	}
+	buf := txc.transactionBuffer()
+	if buf == nil {
+		return err
+	}
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_CLUSTER_EVENT, vtrpcpb.Code_ABORTED, vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_FAILED_PRECONDITION:
+	default:
+		return err
+	}
+	if !buffer.CausedByReparent(err) && !buf.FailoverInProgress(target.Keyspace, target.Shard) {
+		return err
+	}
+	return vterrors.VT10003(topoproto.KeyspaceShardString(target.Keyspace, target.Shard), err)
+}
+
 func (txc *TxConn) commitShard(ctx context.Context, s *vtgatepb.Session_ShardSession, logging *econtext.ExecuteLogger) error {
 	if s.TransactionId == 0 {
 		return nil
@@ -192,27 +246,30 @@ func (txc *TxConn) commitShard(ctx context.Context, s *vtgatepb.Session_ShardSes
 func (txc *TxConn) commitNormal(ctx context.Context, session *econtext.SafeSession) error {
 	// Retain backward compatibility on commit order for the normal session.
 	for i, shardSession := range session.ShardSessions {
+		transactionID := shardSession.TransactionId
 		if err := txc.commitShard(ctx, shardSession, session.GetLogger()); err != nil {
-			if i > 0 {
-				nShards := i
-				elipsis := false
-				if i > nonAtomicCommitWarnMaxShards {
-					nShards = nonAtomicCommitWarnMaxShards
-					elipsis = true
-				}
-				sNames := make([]string, nShards, nShards+1 /*...*/)
-				for j := 0; j < nShards; j++ {
-					sNames[j] = session.ShardSessions[j].Target.Shard
-				}
-				if elipsis {
-					sNames = append(sNames, "...")
-				}
-				session.RecordWarning(&querypb.QueryWarning{
-					Code:    uint32(sqlerror.ERNonAtomicCommit),
-					Message: fmt.Sprintf("multi-db commit failed after committing to %d shards: %s", i, strings.Join(sNames, ", ")),
-				})
-				warnings.Add("NonAtomicCommit", 1)
+			if i == 0 {
+				// Nothing was committed yet, so the transaction can be replayed.
+				return txc.failoverError(shardSession.Target, transactionID, err)
+			}
+			nShards := i
+			elipsis := false
+			if i > nonAtomicCommitWarnMaxShards {
+				nShards = nonAtomicCommitWarnMaxShards
+				elipsis = true
+			}
+			sNames := make([]string, nShards, nShards+1 /*...*/)
+			for j := 0; j < nShards; j++ {
+				sNames[j] = session.ShardSessions[j].Target.Shard
+			}
+			if elipsis {
+				sNames = append(sNames, "...")
 			}
+			session.RecordWarning(&querypb.QueryWarning{
+				Code:    uint32(sqlerror.ERNonAtomicCommit),
+				Message: fmt.Sprintf("multi-db commit failed after committing to %d shards: %s", i, strings.Join(sNames, ", ")),
+			})
+			warnings.Add("NonAtomicCommit", 1)
 			return err
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,6 +40,7 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 )
 
@@ -277,6 +279,205 @@ func TestTxConnCommitFailureBeforeNonAtomicCommitMaxShards(t *testing.T) {
 	require.Equal(t, nonAtomicCommitCount+1, warnings.Counts()["NonAtomicCommit"])
 }
 
+func newTestTransactionBuffer(t *testing.T, sc *ScatterConn, inFlightTransactions bool) *buffer.Buffer {
+	cfg := buffer.NewDefaultConfig()
+	cfg.Enabled = true
+	cfg.InFlightTransactions = inFlightTransactions
+	buf := buffer.New(cfg)
+	sc.gateway.buffer = buf
+	t.Cleanup(buf.Shutdown)
+	return buf
+}
+
+func TestTxConnFailoverInterruptedTransaction(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, sbc1, rss0, _, rss01 := newTestTxConnEnv(t, ctx, "TestTxConn")
+	newTestTransactionBuffer(t, sc, true)
+
+	session := econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	_, errs := sc.ExecuteMultiShard(ctx, nil, rss01, twoQueries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	require.Len(t, session.ShardSessions, 2)
+
+	// The transaction on shard 0 was lost because the shard failed over.
+	sbc0.MustFailCodes[vtrpcpb.Code_CLUSTER_EVENT] = 1
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "VT10003: transaction was interrupted by a failover of shard TestTxConn/0 and has been rolled back")
+	assert.Equal(t, vtrpcpb.Code_ABORTED, vterrors.Code(errs[0]))
+	sqlErr := sqlerror.NewSQLErrorFromError(errs[0]).(*sqlerror.SQLError)
+	assert.Equal(t, sqlerror.ERTxInterruptedByFailover, sqlErr.Number())
+	assert.Equal(t, sqlerror.SSTxRollback, sqlErr.SQLState())
+
+	// The whole transaction has been rolled back, so that it can be replayed.
+	assert.Empty(t, session.ShardSessions)
+	assert.EqualValues(t, 1, sbc1.RollbackCount.Load())
+
+	// Errors which are unrelated to a failover are returned as they are.
+	session = econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	sbc0.MustFailCodes[vtrpcpb.Code_INVALID_ARGUMENT] = 1
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Len(t, errs, 1)
+	assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(errs[0]))
+	assert.Len(t, session.ShardSessions, 1)
+
+	// Errors of a shard which is not failing over are returned as they are,
+	// even if they abort the transaction.
+	sbc0.MustFailCodes[vtrpcpb.Code_ABORTED] = 1
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Len(t, errs, 1)
+	assert.NotContains(t, errs[0].Error(), "VT10003")
+	assert.Equal(t, vtrpcpb.Code_ABORTED, vterrors.Code(errs[0]))
+
+	session = econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	sbc0.EphemeralShardErr = vterrors.New(vtrpcpb.Code_CLUSTER_EVENT, buffer.ClusterEventReshardingInProgress)
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Len(t, errs, 1)
+	assert.NotContains(t, errs[0].Error(), "VT10003")
+
+	// A commit of the first shard can be replayed as well.
+	session = econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	sbc0.MustFailCodes[vtrpcpb.Code_CLUSTER_EVENT] = 1
+	err := sc.txConn.Commit(ctx, session)
+	require.ErrorContains(t, err, "VT10003: transaction was interrupted by a failover of shard TestTxConn/0")
+}
+
+func TestTxConnFailoverInterruptedTransactionWhileBuffering(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, sbc1, rss0, rss1, rss01 := newTestTxConnEnv(t, ctx, "TestTxConn")
+	buf := newTestTransactionBuffer(t, sc, true)
+
+	// The transactions are open on both shards before the failover.
+	session := econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	_, errs := sc.ExecuteMultiShard(ctx, nil, rss01, twoQueries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	session2 := econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss01, twoQueries, session2, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+
+	// Shard 0 fails over while the requests to it are buffered.
+	failoverErr := vterrors.New(vtrpcpb.Code_CLUSTER_EVENT, buffer.ClusterEventReparentInProgress)
+	bufferedDone := make(chan struct{})
+	go func() {
+		defer close(bufferedDone)
+		retryDone, err := buf.WaitForFailoverEnd(ctx, "TestTxConn", "0", nil, failoverErr)
+		assert.NoError(t, err)
+		if retryDone != nil {
+			retryDone()
+		}
+	}()
+	require.Eventually(t, func() bool {
+		return buf.FailoverInProgress("TestTxConn", "0")
+	}, 5*time.Second, 10*time.Millisecond)
+	defer func() {
+		buf.HandleKeyspaceEvent(&discovery.KeyspaceEvent{
+			Keyspace: "TestTxConn",
+			Shards: []discovery.ShardEvent{{
+				Tablet:  sbc0.Tablet().Alias,
+				Target:  rss0[0].Target,
+				Serving: true,
+			}},
+		})
+		<-bufferedDone
+	}()
+
+	// An error of shard 1, which is not failing over, is returned as it is.
+	sbc1.MustFailCodes[vtrpcpb.Code_ABORTED] = 1
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss1, queries, session, false, false, nullResultsObserver{}, false)
+	require.Len(t, errs, 1)
+	assert.NotContains(t, errs[0].Error(), "VT10003")
+
+	// The transaction lost on shard 0 is interrupted by the failover.
+	sbc0.MustFailCodes[vtrpcpb.Code_ABORTED] = 1
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session2, false, false, nullResultsObserver{}, false)
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "VT10003: transaction was interrupted by a failover of shard TestTxConn/0")
+}
+
+func TestTxConnFailoverInterruptedTransactionDisabled(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, _, rss0, _, _ := newTestTxConnEnv(t, ctx, "TestTxConn")
+	newTestTransactionBuffer(t, sc, false)
+
+	session := econtext.NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	_, errs := sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+
+	sbc0.MustFailCodes[vtrpcpb.Code_CLUSTER_EVENT] = 1
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Len(t, errs, 1)
+	assert.Equal(t, vtrpcpb.Code_CLUSTER_EVENT, vterrors.Code(errs[0]))
+	assert.Len(t, session.ShardSessions, 1)
+}
+
+func TestTxConnHoldDuringFailover(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, _, rss0, _, _ := newTestTxConnEnv(t, ctx, "TestTxConn")
+	buf := newTestTransactionBuffer(t, sc, true)
+
+	// The session holds a reserved connection on shard 0, so that the gateway
+	// does not buffer the statement starting the transaction by itself.
+	session := econtext.NewSafeSession(&vtgatepb.Session{InReservedConn: true})
+	_, errs := sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+	require.Empty(t, errs)
+	require.Len(t, session.ShardSessions, 1)
+	session.Session.InTransaction = true
+
+	// Start a failover of shard 0 with a request which gets buffered.
+	failoverErr := vterrors.New(vtrpcpb.Code_CLUSTER_EVENT, buffer.ClusterEventReparentInProgress)
+	bufferedDone := make(chan struct{})
+	go func() {
+		defer close(bufferedDone)
+		retryDone, err := buf.WaitForFailoverEnd(ctx, "TestTxConn", "0", nil, failoverErr)
+		assert.NoError(t, err)
+		if retryDone != nil {
+			retryDone()
+		}
+	}()
+	require.Eventually(t, func() bool {
+		return buf.FailoverInProgress("TestTxConn", "0")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	execDone := make(chan []error)
+	go func() {
+		_, errs := sc.ExecuteMultiShard(ctx, nil, rss0, queries, session, false, false, nullResultsObserver{}, false)
+		execDone <- errs
+	}()
+
+	// The statement which starts the transaction on shard 0 is held during the failover.
+	select {
+	case <-execDone:
+		t.Fatal("statement was not held during the failover")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.EqualValues(t, 0, sbc0.BeginCount.Load())
+
+	buf.HandleKeyspaceEvent(&discovery.KeyspaceEvent{
+		Keyspace: "TestTxConn",
+		Shards: []discovery.ShardEvent{{
+			Tablet:  sbc0.Tablet().Alias,
+			Target:  rss0[0].Target,
+			Serving: true,
+		}},
+	})
+	require.Empty(t, <-execDone)
+	<-bufferedDone
+
+	assert.EqualValues(t, 1, sbc0.BeginCount.Load())
+	assert.Len(t, session.ShardSessions, 1)
+	assert.False(t, buf.FailoverInProgress("TestTxConn", "0"))
+}
+
 func TestTxConnCommitSuccess(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
@@ -220,7 +220,9 @@ type Column struct {
 	Default       sqlparser.Expr         `json:"default,omitempty"`
 
 	// Invisible marks this as a column that will not be automatically included in `*` projections
-	Invisible bool  `json:"invisible,omitempty"`
+	Invisible bool `json:"invisible,omitempty"`
+	// Generated marks this as a virtual or stored generated column, whose values cannot be inserted
+	Generated bool  `json:"generated,omitempty"`
 	Size      int32 `json:"size,omitempty"`
 	Scale     int32 `json:"scale,omitempty"`
 	Nullable  bool  `json:"nullable,omitempty"`
@@ -234,6 +236,7 @@ func (col *Column) MarshalJSON() ([]byte, error) {
 		Name      string   `json:"name"`
 		Type      string   `json:"type,omitempty"`
 		Invisible bool     `json:"invisible,omitempty"`
+		Generated bool     `json:"generated,omitempty"`
 		Default   string   `json:"default,omitempty"`
 		Size      int32    `json:"size,omitempty"`
 		Scale     int32    `json:"scale,omitempty"`
@@ -243,6 +246,7 @@ func (col *Column) MarshalJSON() ([]byte, error) {
 		Name:      col.Name.String(),
 		Type:      querypb.Type_name[int32(col.Type)],
 		Invisible: col.Invisible,
+		Generated: col.Generated,
 		Size:      col.Size,
 		Scale:     col.Scale,
 		Nullable:  col.Nullable,
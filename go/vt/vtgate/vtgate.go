@@ -70,6 +70,9 @@ var (
 	// plan cache related flag
 	queryPlanCacheMemory int64 = 32 * 1024 * 1024 // 32mb
 
+	// result cache related flag
+	resultCacheMemory int64
+
 	maxMemoryRows   = 300000
 	warnMemoryRows  = 30000
 	maxPayloadSize  int
@@ -171,6 +174,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&truncateErrorLen, "truncate-error-len", truncateErrorLen, "truncate errors sent to client if they are longer than this value (0 means do not truncate)")
 	fs.IntVar(&streamBufferSize, "stream_buffer_size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
 	fs.Int64Var(&queryPlanCacheMemory, "gate_query_cache_memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
+	fs.Int64Var(&resultCacheMemory, "result_cache_memory", resultCacheMemory, "gate server result cache size in bytes. SELECT results are only cached when the query carries a /*vt+ RESULT_CACHE_TTL=<duration> */ directive, and are invalidated when the tables they read from change. 0 disables the result cache.")
 	fs.IntVar(&maxMemoryRows, "max_memory_rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
 	fs.IntVar(&warnMemoryRows, "warn_memory_rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	fs.StringVar(&defaultDDLStrategy, "ddl_strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
@@ -366,6 +370,13 @@ func Init(
 		log.Fatalf("error initializing query logger: %v", err)
 	}
 
+	var rci *resultCacheInvalidator
+	if resultCacheMemory > 0 {
+		executor.resultCache = newResultCache(resultCacheMemory)
+		rci = newResultCacheInvalidator(executor.resultCache, vsm.VStream)
+		initResultCacheStats(executor.resultCache)
+	}
+
 	// connect the schema tracker with the vschema manager
 	if enableSchemaChangeSignal {
 		st.RegisterSignalReceiver(executor.vm.Rebuild)
@@ -391,6 +402,9 @@ func Init(
 			st.Start()
 		}
 		tr.Start()
+		if rci != nil {
+			rci.Start(gw.hc)
+		}
 		srv := initMySQLProtocol(vtgateInst)
 		if srv != nil {
 			servenv.OnTermSync(srv.shutdownMysqlProtocolAndDrain)
@@ -402,6 +416,9 @@ func Init(
 			st.Stop()
 		}
 		tr.Stop()
+		if rci != nil {
+			rci.Stop()
+		}
 	})
 	vtgateInst.registerDebugHealthHandler()
 	vtgateInst.registerDebugEnvHandler()
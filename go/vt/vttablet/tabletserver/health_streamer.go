@@ -26,6 +26,8 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	vtschema "vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 
@@ -49,6 +51,10 @@ var (
 	errUnintialized = "tabletserver uninitialized"
 
 	streamHealthBufferSize = uint(20)
+
+	// replicationPositionTimeout bounds the time spent reading the
+	// replication position for a health check.
+	replicationPositionTimeout = 1 * time.Second
 )
 
 func init() {
@@ -84,6 +90,13 @@ type healthStreamer struct {
 	se      *schema.Engine
 	history *history.History
 
+	// mysqld is used to read the replication position of the tablet.
+	// It can be nil, in which case no position is reported.
+	mysqld mysqlctl.MysqlDaemon
+	// refreshPosition asks the goroutine started by Open to read the
+	// replication position again, so that ChangeState never waits on MySQL.
+	refreshPosition chan struct{}
+
 	signalWhenSchemaChange bool
 
 	viewsEnabled bool
@@ -104,6 +117,7 @@ func newHealthStreamer(env tabletenv.Env, alias *topodatapb.TabletAlias, engine
 		},
 
 		history:                history.New(5),
+		refreshPosition:        make(chan struct{}, 1),
 		signalWhenSchemaChange: env.Config().SignalWhenSchemaChange,
 		viewsEnabled:           env.Config().EnableViews,
 		se:                     engine,
@@ -112,8 +126,9 @@ func newHealthStreamer(env tabletenv.Env, alias *topodatapb.TabletAlias, engine
 	return hs
 }
 
-func (hs *healthStreamer) InitDBConfig(target *querypb.Target) {
+func (hs *healthStreamer) InitDBConfig(target *querypb.Target, mysqld mysqlctl.MysqlDaemon) {
 	hs.state.Target = target.CloneVT()
+	hs.mysqld = mysqld
 }
 
 func (hs *healthStreamer) Open() {
@@ -124,6 +139,9 @@ func (hs *healthStreamer) Open() {
 		return
 	}
 	hs.ctx, hs.cancel = context.WithCancel(context.Background())
+	if hs.mysqld != nil {
+		go hs.refreshPositions(hs.ctx)
+	}
 }
 
 func (hs *healthStreamer) Close() {
@@ -219,6 +237,47 @@ func (hs *healthStreamer) ChangeState(tabletType topodatapb.TabletType, ptsTimes
 		lag:        lag,
 		err:        err,
 	})
+
+	// The state carries the last position read, and the new one is
+	// broadcast as soon as it is read.
+	select {
+	case hs.refreshPosition <- struct{}{}:
+	default:
+	}
+}
+
+// refreshPositions reads the replication position of the tablet when
+// ChangeState asks for it, and broadcasts it when it changed, until ctx is
+// done.
+func (hs *healthStreamer) refreshPositions(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hs.refreshPosition:
+		}
+		position := hs.replicationPosition(ctx)
+
+		hs.fieldsMu.Lock()
+		if hs.state.RealtimeStats.ReplicationPosition != position {
+			hs.state.RealtimeStats.ReplicationPosition = position
+			hs.broadCastToClients(hs.state.CloneVT())
+		}
+		hs.fieldsMu.Unlock()
+	}
+}
+
+// replicationPosition returns the encoded replication position of the tablet.
+// It returns an empty string if the position cannot be read.
+func (hs *healthStreamer) replicationPosition(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, replicationPositionTimeout)
+	defer cancel()
+	pos, err := hs.mysqld.PrimaryPosition(ctx)
+	if err != nil {
+		// The health error already reflects an unreachable MySQL.
+		return ""
+	}
+	return replication.EncodePosition(pos)
 }
 
 func (hs *healthStreamer) broadCastToClients(shr *querypb.StreamHealthResponse) {
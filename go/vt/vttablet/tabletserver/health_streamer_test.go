@@ -31,7 +31,9 @@ import (
 
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/vtenv"
@@ -179,6 +181,82 @@ func TestHealthStreamerBroadcast(t *testing.T) {
 		},
 	}
 	assert.Truef(t, proto.Equal(want, shr), "want: %v, got: %v", want, shr)
+
+}
+
+// blockingPositionMysqld blocks the reads of the replication position until
+// release is closed.
+type blockingPositionMysqld struct {
+	*mysqlctl.FakeMysqlDaemon
+	release chan struct{}
+}
+
+func (m *blockingPositionMysqld) PrimaryPosition(ctx context.Context) (replication.Position, error) {
+	select {
+	case <-m.release:
+	case <-ctx.Done():
+		return replication.Position{}, ctx.Err()
+	}
+	return m.FakeMysqlDaemon.PrimaryPosition(ctx)
+}
+
+func TestHealthStreamerReplicationPosition(t *testing.T) {
+	cfg := newConfig(nil)
+	cfg.SignalWhenSchemaChange = false
+	env := tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "ReplTrackerTest")
+	alias := &topodatapb.TabletAlias{
+		Cell: "cell",
+		Uid:  1,
+	}
+	blpFunc = testBlpFunc
+	pos, err := replication.DecodePosition("MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")
+	require.NoError(t, err)
+	mysqld := &blockingPositionMysqld{FakeMysqlDaemon: mysqlctl.NewFakeMysqlDaemon(nil), release: make(chan struct{})}
+	mysqld.SetPrimaryPositionLocked(pos)
+
+	hs := newHealthStreamer(env, alias, &schema.Engine{})
+	hs.InitDBConfig(&querypb.Target{}, mysqld)
+	hs.Open()
+	defer hs.Close()
+
+	ch, cancel := testStream(hs)
+	defer cancel()
+	<-ch
+
+	// The state change is broadcast without waiting for the position.
+	hs.ChangeState(topodatapb.TabletType_REPLICA, time.Time{}, 0, nil, true)
+	shr := <-ch
+	assert.True(t, shr.Serving)
+	assert.Empty(t, shr.RealtimeStats.ReplicationPosition)
+
+	// The position is broadcast once it is read.
+	close(mysqld.release)
+	shr = <-ch
+	want := &querypb.StreamHealthResponse{
+		Target: &querypb.Target{
+			TabletType: topodatapb.TabletType_REPLICA,
+		},
+		TabletAlias: alias,
+		Serving:     true,
+		RealtimeStats: &querypb.RealtimeStats{
+			FilteredReplicationLagSeconds: 1,
+			BinlogPlayersCount:            2,
+			ReplicationPosition:           "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5",
+		},
+	}
+	assert.Truef(t, proto.Equal(want, shr), "want: %v, got: %v", want, shr)
+
+	// The following state changes carry the last position read, and the
+	// position is only broadcast again when it changes.
+	hs.ChangeState(topodatapb.TabletType_REPLICA, time.Time{}, 0, nil, false)
+	shr = <-ch
+	assert.False(t, shr.Serving)
+	assert.Equal(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5", shr.RealtimeStats.ReplicationPosition)
+	select {
+	case shr := <-ch:
+		t.Fatalf("unexpected broadcast: %v", shr)
+	case <-time.After(100 * time.Millisecond):
+	}
 }
 
 func TestReloadSchema(t *testing.T) {
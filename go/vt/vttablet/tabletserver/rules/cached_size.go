@@ -45,7 +45,7 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(256)
+		size += int64(288)
 	}
 	// field Description string
 	size += hack.RuntimeAllocSize(int64(len(cached.Description)))
@@ -79,6 +79,13 @@ func (cached *Rule) CachedSize(alloc bool) int64 {
 			size += elem.CachedSize(false)
 		}
 	}
+	// field queryAttributeConds []vitess.io/vitess/go/vt/vttablet/tabletserver/rules.queryAttributeCond
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.queryAttributeConds)) * int64(40))
+		for _, elem := range cached.queryAttributeConds {
+			size += elem.CachedSize(false)
+		}
+	}
 	return size
 }
 func (cached *Rules) CachedSize(alloc bool) int64 {
@@ -130,3 +137,17 @@ func (cached *namedRegexp) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *queryAttributeCond) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field value vitess.io/vitess/go/vt/vttablet/tabletserver/rules.namedRegexp
+	size += cached.value.CachedSize(false)
+	return size
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"time"
 
@@ -175,13 +176,14 @@ func (qrs *Rules) GetAction(
 	user string,
 	bindVars map[string]*querypb.BindVariable,
 	marginComments sqlparser.MarginComments,
+	queryAttributes map[string]string,
 ) (
 	action Action,
 	cancelCtx context.Context,
 	timeout time.Duration,
 	desc string) {
 	for _, qr := range qrs.rules {
-		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
+		if act := qr.GetAction(ip, user, bindVars, marginComments, queryAttributes); act != QRContinue {
 			return act, qr.cancelCtx, qr.timeout, qr.Description
 		}
 	}
@@ -216,6 +218,9 @@ type Rule struct {
 	// All BindVar conditions have to be fulfilled to make this true (AND)
 	bindVarConds []BindVarCond
 
+	// All query attribute conditions have to be fulfilled to make this true (AND)
+	queryAttributeConds []queryAttributeCond
+
 	// Action to be performed on trigger
 	act Action
 
@@ -272,6 +277,7 @@ func (qr *Rule) Equal(other *Rule) bool {
 		reflect.DeepEqual(qr.plans, other.plans) &&
 		reflect.DeepEqual(qr.tableNames, other.tableNames) &&
 		reflect.DeepEqual(qr.bindVarConds, other.bindVarConds) &&
+		slices.EqualFunc(qr.queryAttributeConds, other.queryAttributeConds, queryAttributeCond.Equal) &&
 		qr.act == other.act)
 }
 
@@ -301,6 +307,10 @@ func (qr *Rule) Copy() (newqr *Rule) {
 		newqr.bindVarConds = make([]BindVarCond, len(qr.bindVarConds))
 		copy(newqr.bindVarConds, qr.bindVarConds)
 	}
+	if qr.queryAttributeConds != nil {
+		newqr.queryAttributeConds = make([]queryAttributeCond, len(qr.queryAttributeConds))
+		copy(newqr.queryAttributeConds, qr.queryAttributeConds)
+	}
 	return newqr
 }
 
@@ -333,6 +343,13 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.bindVarConds != nil {
 		safeEncode(b, `,"BindVarConds":`, qr.bindVarConds)
 	}
+	if qr.queryAttributeConds != nil {
+		queryAttributes := make(map[string]namedRegexp, len(qr.queryAttributeConds))
+		for _, cond := range qr.queryAttributeConds {
+			queryAttributes[cond.name] = cond.value
+		}
+		safeEncode(b, `,"QueryAttributes":`, queryAttributes)
+	}
 	if qr.act != QRContinue {
 		safeEncode(b, `,"Action":`, qr.act)
 	}
@@ -394,6 +411,18 @@ func (qr *Rule) SetTrailingCommentCond(pattern string) (err error) {
 	return
 }
 
+// AddQueryAttributeCond adds a regular expression condition for the value
+// of a query attribute sent by the client. An absent attribute doesn't match.
+// All query attribute conditions have to be satisfied for the Rule to be a match.
+func (qr *Rule) AddQueryAttributeCond(name, pattern string) error {
+	re, err := regexp.Compile(makeExact(pattern))
+	if err != nil {
+		return err
+	}
+	qr.queryAttributeConds = append(qr.queryAttributeConds, queryAttributeCond{name: name, value: namedRegexp{pattern, re}})
+	return nil
+}
+
 // makeExact forces a full string match for the regex instead of substring
 func makeExact(pattern string) string {
 	return fmt.Sprintf("^%s$", pattern)
@@ -486,6 +515,7 @@ func (qr *Rule) GetAction(
 	user string,
 	bindVars map[string]*querypb.BindVariable,
 	marginComments sqlparser.MarginComments,
+	queryAttributes map[string]string,
 ) Action {
 	if qr.cancelCtx != nil {
 		select {
@@ -514,6 +544,11 @@ func (qr *Rule) GetAction(
 			return QRContinue
 		}
 	}
+	for _, qacond := range qr.queryAttributeConds {
+		if !qacond.match(queryAttributes) {
+			return QRContinue
+		}
+	}
 	return qr.act
 }
 
@@ -857,6 +892,25 @@ func getstring(val *querypb.BindVariable) (s string, status int) {
 	return "", QRMismatch
 }
 
+// queryAttributeCond matches the value of a query attribute against a regexp.
+type queryAttributeCond struct {
+	name  string
+	value namedRegexp
+}
+
+// Equal returns true if other is equal to this queryAttributeCond, otherwise false.
+func (qac queryAttributeCond) Equal(other queryAttributeCond) bool {
+	return qac.name == other.name && qac.value.Equal(other.value)
+}
+
+func (qac queryAttributeCond) match(queryAttributes map[string]string) bool {
+	val, ok := queryAttributes[qac.name]
+	if !ok {
+		return false
+	}
+	return qac.value.MatchString(val)
+}
+
 // -----------------------------------------------
 // Support functions for JSON
 
@@ -874,6 +928,7 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 	for k, v := range ruleInfo {
 		var sv string
 		var lv []any
+		var mv map[string]any
 		var ok bool
 		switch k {
 		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment":
@@ -886,6 +941,11 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", k)
 			}
+		case "QueryAttributes":
+			mv, ok = v.(map[string]any)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want json object for %s", k)
+			}
 		default:
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
 		}
@@ -950,6 +1010,17 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 					return nil, err
 				}
 			}
+		case "QueryAttributes":
+			for name, pattern := range mv {
+				pv, ok := pattern.(string)
+				if !ok {
+					return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for QueryAttributes")
+				}
+				err = qr.AddQueryAttributeCond(name, pv)
+				if err != nil {
+					return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "could not set QueryAttributes condition: %v", pv)
+				}
+			}
 		case "Action":
 			switch sv {
 			case "FAIL":
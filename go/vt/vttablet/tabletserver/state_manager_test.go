@@ -823,7 +823,7 @@ func newTestStateManager() *stateManager {
 		rw:                newRequestsWaiter(),
 	}
 	sm.Init(env, &querypb.Target{})
-	sm.hs.InitDBConfig(&querypb.Target{})
+	sm.hs.InitDBConfig(&querypb.Target{}, nil)
 	log.Infof("returning sm: %p", sm)
 	return sm
 }
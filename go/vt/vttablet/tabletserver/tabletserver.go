@@ -33,6 +33,7 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/pools/smartconnpool"
 	"vitess.io/vitess/go/sqltypes"
@@ -120,6 +121,9 @@ type TabletServer struct {
 	lagThrottler *throttle.Throttler
 	tableGC      *gc.TableGC
 
+	// mysqld is used to wait for the replication positions requested by reads.
+	mysqld mysqlctl.MysqlDaemon
+
 	// sm manages state transitions.
 	sm                *stateManager
 	onlineDDLExecutor *onlineddl.Executor
@@ -297,7 +301,8 @@ func (tsv *TabletServer) InitDBConfig(target *querypb.Target, dbcfgs *dbconfigs.
 	tsv.rt.InitDBConfig(target, mysqld)
 	tsv.txThrottler.InitDBConfig(target)
 	tsv.vstreamer.InitDBConfig(target.Keyspace, target.Shard)
-	tsv.hs.InitDBConfig(target)
+	tsv.hs.InitDBConfig(target, mysqld)
+	tsv.mysqld = mysqld
 	tsv.onlineDDLExecutor.InitDBConfig(target.Keyspace, target.Shard, dbcfgs.DBName)
 	tsv.lagThrottler.InitDBConfig(target.Keyspace, target.Shard)
 	tsv.tableGC.InitDBConfig(target.Keyspace, target.Shard, dbcfgs.DBName)
@@ -638,6 +643,32 @@ func (tsv *TabletServer) resolveTargetType(ctx context.Context, target *querypb.
 	return tsv.sm.Target().TabletType, nil
 }
 
+// waitForPosition waits until the tablet has replicated up to the position
+// requested in the options, if any. The wait is skipped on a primary, which
+// has every position it accepted writes for.
+func (tsv *TabletServer) waitForPosition(ctx context.Context, options *querypb.ExecuteOptions) error {
+	encoded := options.GetWaitForPosition()
+	if encoded == "" || tsv.sm.Target().GetTabletType() == topodatapb.TabletType_PRIMARY {
+		return nil
+	}
+	pos, err := replication.DecodePosition(encoded)
+	if err != nil {
+		return vterrors.Wrapf(err, "invalid wait_for_position %q", encoded)
+	}
+	if tsv.mysqld == nil {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot wait for position %v: mysqld is not available", encoded)
+	}
+	if timeout := options.GetWaitForPositionTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout*float64(time.Second)))
+		defer cancel()
+	}
+	if err := tsv.mysqld.WaitSourcePos(ctx, pos); err != nil {
+		return vterrors.Wrapf(err, "failed waiting for position %v", encoded)
+	}
+	return nil
+}
+
 // Commit commits the specified transaction.
 func (tsv *TabletServer) Commit(ctx context.Context, target *querypb.Target, transactionID int64) (newReservedID int64, err error) {
 	err = tsv.execRequest(
@@ -900,6 +931,9 @@ func (tsv *TabletServer) execute(ctx context.Context, target *querypb.Target, sq
 			if bindVariables == nil {
 				bindVariables = make(map[string]*querypb.BindVariable)
 			}
+			if err := tsv.waitForPosition(ctx, options); err != nil {
+				return err
+			}
 			query, comments := sqlparser.SplitMarginComments(sql)
 
 			plan, err := tsv.qe.GetPlan(ctx, logStats, query, skipQueryPlanCache(options))
@@ -1007,6 +1041,9 @@ func (tsv *TabletServer) streamExecute(ctx context.Context, target *querypb.Targ
 			if bindVariables == nil {
 				bindVariables = make(map[string]*querypb.BindVariable)
 			}
+			if err := tsv.waitForPosition(ctx, options); err != nil {
+				return err
+			}
 			query, comments := sqlparser.SplitMarginComments(sql)
 			plan, err := tsv.qe.GetStreamPlan(ctx, logStats, query, skipQueryPlanCache(options))
 			if err != nil {
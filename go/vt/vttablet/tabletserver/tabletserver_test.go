@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/mysql/config"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/streamlog"
@@ -49,6 +50,7 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/tableacl/simpleacl"
@@ -903,6 +905,55 @@ func TestTabletServerRollbackPrepared(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTabletServerWaitForPosition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, tsv := setupTabletServerTest(t, ctx, "")
+	defer tsv.StopService()
+	defer db.Close()
+
+	executeSQL := "select * from test_table limit 1000"
+	db.AddQuery(executeSQL, &sqltypes.Result{})
+
+	const encoded = "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+	pos, err := replication.DecodePosition(encoded)
+	require.NoError(t, err)
+	options := &querypb.ExecuteOptions{WaitForPosition: encoded, WaitForPositionTimeout: 1}
+
+	// The primary does not wait.
+	target := querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, 0, options)
+	require.NoError(t, err)
+
+	tsv.sm.mu.Lock()
+	tsv.sm.target.TabletType = topodatapb.TabletType_REPLICA
+	tsv.sm.mu.Unlock()
+	target.TabletType = topodatapb.TabletType_REPLICA
+
+	// Without mysqld, the position cannot be waited for.
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, 0, options)
+	require.ErrorContains(t, err, "mysqld is not available")
+
+	fmd := mysqlctl.NewFakeMysqlDaemon(nil)
+	tsv.mysqld = fmd
+	fmd.TimeoutHook = func() error {
+		return vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "timed out waiting for position %v", pos)
+	}
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, 0, options)
+	require.ErrorContains(t, err, "failed waiting for position")
+	err = tsv.StreamExecute(ctx, &target, executeSQL, nil, 0, 0, options, func(*sqltypes.Result) error { return nil })
+	require.ErrorContains(t, err, "failed waiting for position")
+
+	fmd.TimeoutHook = nil
+	fmd.WaitPrimaryPositions = []replication.Position{pos}
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, 0, options)
+	require.NoError(t, err)
+
+	options.WaitForPosition = "invalid"
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, 0, options)
+	require.ErrorContains(t, err, "invalid wait_for_position")
+}
+
 func TestTabletServerStreamExecute(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()